@@ -105,7 +105,7 @@ func main() {
 			app.Services.Author.(*services.AuthorService),
 			logger,
 		)
-		logger.Info("MCP server initialized with 2 core tools (KISS approach)")
+		logger.Info("MCP server initialized with core tools (KISS approach)")
 		
 		// Start MCP server in separate goroutine for stdio
 		go func() {
@@ -116,6 +116,23 @@ func main() {
 		}()
 	}
 
+	// Periodically remove expired search cache entries
+	cleanupCtx, stopCleanup := context.WithCancel(ctx)
+	defer stopCleanup()
+	if searchService, ok := app.Services.Search.(*services.SearchService); ok {
+		searchService.StartCacheCleanup(cleanupCtx, 15*time.Minute)
+	}
+
+	// Keep the local store in sync with the configured arXiv sets
+	if harvest := config.Providers.ArXiv.Harvest; harvest.Enabled && app.Harvester != nil {
+		app.Harvester.StartScheduledHarvest(cleanupCtx, harvest.Sets, parseDurationOr(harvest.Interval, 24*time.Hour))
+	}
+
+	// Embed stored papers for semantic search as they arrive
+	if app.Embeddings != nil {
+		app.Embeddings.StartBackgroundEmbedding(cleanupCtx, parseDurationOr(config.Embeddings.Interval, 5*time.Minute))
+	}
+
 	// Start HTTP server in goroutine
 	go func() {
 		logger.Info("Starting SciFIND Backend server",
@@ -140,7 +157,7 @@ func main() {
 	// Log available endpoints
 	logger.Info("Available endpoints",
 		slog.String("health", "/health, /health/live, /health/ready"),
-		slog.String("search", "/v1/search, /v1/search/papers/{provider}/{id}"),
+		slog.String("search", "/v1/search, /v1/search/stream, /v1/search/suggest, /v1/search/papers/{provider}/{id}"),
 		slog.String("papers", "/v1/papers, /v1/papers/{id}"),
 		slog.String("authors", "/v1/authors, /v1/authors/{id}/papers"),
 		slog.String("docs", "/docs"))
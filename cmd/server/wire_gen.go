@@ -10,15 +10,22 @@ import (
 	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
+	"github.com/nats-io/nats.go/jetstream"
 	"log/slog"
 	"scifind-backend/internal/api"
 	"scifind-backend/internal/api/handlers"
 	"scifind-backend/internal/config"
+	"scifind-backend/internal/embeddings"
+	"scifind-backend/internal/errors"
 	"scifind-backend/internal/messaging"
 	"scifind-backend/internal/messaging/embedded"
 	"scifind-backend/internal/providers"
 	"scifind-backend/internal/providers/arxiv"
+	"scifind-backend/internal/providers/crossref"
 	"scifind-backend/internal/providers/exa"
+	"scifind-backend/internal/providers/local"
+	"scifind-backend/internal/providers/openalex"
+	"scifind-backend/internal/providers/pubmed"
 	"scifind-backend/internal/providers/semantic_scholar"
 	"scifind-backend/internal/providers/tavily"
 	"scifind-backend/internal/repository"
@@ -52,15 +59,18 @@ func InitializeApplication(ctx context.Context) (*Application, func(), error) {
 	}
 	client := ProvideMessagingFromEmbedded(manager)
 	container := ProvideRepositories(database, logger)
-	providerManager := ProvideProviderManager(logger)
-	servicesContainer := ProvideServices(container, client, providerManager, logger)
+	providerManager := ProvideProviderManager(configConfig, container, client, logger)
+	embeddingService := ProvideEmbeddingService(configConfig, database, container, logger)
+	servicesContainer := ProvideServices(container, client, providerManager, embeddingService, logger)
+	harvestService := ProvideHarvestService(configConfig, container, client, logger)
 	handlersContainer := ProvideHandlers(servicesContainer, logger)
-	searchService := ProvideConcreteSearchService(container, client, providerManager, logger)
-	paperService := ProvideConcretePaperService(container, client, logger)
+	searchService := ProvideConcreteSearchService(container, client, providerManager, embeddingService, logger)
+	paperService := ProvideConcretePaperService(container, client, embeddingService, logger)
 	authorService := ProvideConcreteAuthorService(container, client, logger)
+	analyticsServiceInterface := ProvideAnalyticsService(servicesContainer)
 	healthHandler := ProvideConcreteHealthHandler(servicesContainer, logger)
-	engine := ProvideRouter(searchService, paperService, authorService, healthHandler, providerManager, logger)
-	application := NewApplication(configConfig, database, client, manager, servicesContainer, handlersContainer, engine, logger)
+	engine := ProvideRouter(searchService, paperService, authorService, analyticsServiceInterface, healthHandler, providerManager, logger)
+	application := NewApplication(configConfig, database, client, manager, servicesContainer, harvestService, embeddingService, handlersContainer, engine, logger)
 	return application, func() {
 	}, nil
 }
@@ -82,15 +92,18 @@ func InitializeDevelopmentApplication(ctx context.Context) (*Application, func()
 	}
 	client := ProvideMessagingFromEmbedded(manager)
 	container := ProvideRepositories(database, logger)
-	providerManager := ProvideProviderManager(logger)
-	servicesContainer := ProvideServices(container, client, providerManager, logger)
+	providerManager := ProvideProviderManager(configConfig, container, client, logger)
+	embeddingService := ProvideEmbeddingService(configConfig, database, container, logger)
+	servicesContainer := ProvideServices(container, client, providerManager, embeddingService, logger)
+	harvestService := ProvideHarvestService(configConfig, container, client, logger)
 	handlersContainer := ProvideHandlers(servicesContainer, logger)
-	searchService := ProvideConcreteSearchService(container, client, providerManager, logger)
-	paperService := ProvideConcretePaperService(container, client, logger)
+	searchService := ProvideConcreteSearchService(container, client, providerManager, embeddingService, logger)
+	paperService := ProvideConcretePaperService(container, client, embeddingService, logger)
 	authorService := ProvideConcreteAuthorService(container, client, logger)
+	analyticsServiceInterface := ProvideAnalyticsService(servicesContainer)
 	healthHandler := ProvideConcreteHealthHandler(servicesContainer, logger)
-	engine := ProvideRouter(searchService, paperService, authorService, healthHandler, providerManager, logger)
-	application := NewApplication(configConfig, database, client, manager, servicesContainer, handlersContainer, engine, logger)
+	engine := ProvideRouter(searchService, paperService, authorService, analyticsServiceInterface, healthHandler, providerManager, logger)
+	application := NewApplication(configConfig, database, client, manager, servicesContainer, harvestService, embeddingService, handlersContainer, engine, logger)
 	return application, func() {
 	}, nil
 }
@@ -112,15 +125,18 @@ func InitializeTestApplication(ctx context.Context) (*Application, func(), error
 	}
 	client := ProvideMessagingFromEmbedded(manager)
 	container := ProvideRepositories(database, logger)
-	providerManager := ProvideProviderManager(logger)
-	servicesContainer := ProvideServices(container, client, providerManager, logger)
+	providerManager := ProvideProviderManager(configConfig, container, client, logger)
+	embeddingService := ProvideEmbeddingService(configConfig, database, container, logger)
+	servicesContainer := ProvideServices(container, client, providerManager, embeddingService, logger)
+	harvestService := ProvideHarvestService(configConfig, container, client, logger)
 	handlersContainer := ProvideHandlers(servicesContainer, logger)
-	searchService := ProvideConcreteSearchService(container, client, providerManager, logger)
-	paperService := ProvideConcretePaperService(container, client, logger)
+	searchService := ProvideConcreteSearchService(container, client, providerManager, embeddingService, logger)
+	paperService := ProvideConcretePaperService(container, client, embeddingService, logger)
 	authorService := ProvideConcreteAuthorService(container, client, logger)
+	analyticsServiceInterface := ProvideAnalyticsService(servicesContainer)
 	healthHandler := ProvideConcreteHealthHandler(servicesContainer, logger)
-	engine := ProvideRouter(searchService, paperService, authorService, healthHandler, providerManager, logger)
-	application := NewApplication(configConfig, database, client, manager, servicesContainer, handlersContainer, engine, logger)
+	engine := ProvideRouter(searchService, paperService, authorService, analyticsServiceInterface, healthHandler, providerManager, logger)
+	application := NewApplication(configConfig, database, client, manager, servicesContainer, harvestService, embeddingService, handlersContainer, engine, logger)
 	return application, func() {
 	}, nil
 }
@@ -134,6 +150,8 @@ type Application struct {
 	Messaging       *messaging.Client
 	EmbeddedManager *embedded.Manager
 	Services        *services.Container
+	Harvester       *services.HarvestService
+	Embeddings      *services.EmbeddingService // nil when embeddings are disabled
 	Handlers        *handlers.Container
 	Router          *gin.Engine
 	Logger          *slog.Logger
@@ -143,7 +161,7 @@ type Application struct {
 func NewApplication(
 	cfg *config.Config,
 	db *repository.Database, messaging2 *messaging.Client,
-	embeddedManager *embedded.Manager, services2 *services.Container, handlers2 *handlers.Container,
+	embeddedManager *embedded.Manager, services2 *services.Container, harvester *services.HarvestService, embeddings2 *services.EmbeddingService, handlers2 *handlers.Container,
 	router *gin.Engine,
 	logger *slog.Logger,
 ) *Application {
@@ -152,6 +170,8 @@ func NewApplication(
 		Database:        db,
 		Messaging:       messaging2,
 		EmbeddedManager: embeddedManager,
+		Harvester:       harvester,
+		Embeddings:      embeddings2,
 		Services:        services2,
 		Handlers:        handlers2,
 		Router:          router,
@@ -174,6 +194,7 @@ var MessagingProviderSet = wire.NewSet(
 
 var ServicesProviderSet = wire.NewSet(
 	ProvideServices,
+	ProvideHarvestService,
 	ProvideProviderManager,
 )
 
@@ -185,6 +206,7 @@ var APIProviderSet = wire.NewSet(
 	ProvideConcreteSearchService,
 	ProvideConcretePaperService,
 	ProvideConcreteAuthorService,
+	ProvideAnalyticsService,
 	ProvideConcreteHealthHandler,
 	ProvideRouter,
 )
@@ -230,34 +252,150 @@ func ProvideMessagingFromEmbedded(embeddedManager *embedded.Manager) *messaging.
 }
 
 // ProvideProviderManager creates a provider manager instance
-func ProvideProviderManager(logger *slog.Logger) providers.ProviderManager {
+func ProvideProviderManager(cfg *config.Config, repos *repository.Container, messagingClient *messaging.Client, logger *slog.Logger) providers.ProviderManager {
 	managerConfig := providers.ManagerConfig{
-		AggregationStrategy: providers.StrategyMerge,
+		AggregationStrategy: aggregationStrategy(cfg),
+		ProviderWeights:     cfg.Providers.Weights,
 		MaxConcurrency:      5,
 		Timeout:             30 * time.Second,
+		RateLimiter:         newRateLimiter(cfg, messagingClient, logger),
+		Cache:               newResultCache(cfg, messagingClient, logger),
+		CacheTTL:            parseDurationOr(cfg.NATS.KVStore.TTL, providers.DefaultCacheTTL),
+		ProviderTimeouts:    providerTimeouts(cfg),
+		SoftDeadline:        parseDurationOr(cfg.Providers.SoftDeadline, 0),
+		Hedging:             cfg.Providers.Hedging.Enabled,
+		HedgeMinSamples:     cfg.Providers.Hedging.MinSamples,
+		CircuitBreaker:      newCircuitBreaker(cfg, logger),
+		Retry:               newRetryPolicy(cfg),
+		SelectionWeights: map[string]float64{
+			providers.ProviderArxiv:           4,
+			providers.ProviderSemanticScholar: 4,
+			providers.ProviderCrossRef:        4,
+			providers.ProviderOpenAlex:        4,
+			providers.ProviderPubMed:          4,
+			providers.ProviderLocal:           4,
+			providers.ProviderExa:             1,
+			providers.ProviderTavily:          1,
+		},
 	}
 	manager := providers.NewManager(logger, managerConfig)
 
-	initializeProviders(manager, logger)
+	initializeProviders(manager, cfg, repos.Paper, logger)
 	return manager
 }
 
+// newCircuitBreaker creates the provider circuit breakers, nil when circuit
+// breaking is disabled
+func newCircuitBreaker(cfg *config.Config, logger *slog.Logger) providers.CircuitBreaker {
+	if !cfg.Circuit.Enabled {
+		return nil
+	}
+	return providers.NewProviderBreakers(errors.CircuitBreakerConfig{
+		FailureThreshold: cfg.Circuit.FailureThreshold,
+		SuccessThreshold: cfg.Circuit.SuccessThreshold,
+		Timeout:          parseDurationOr(cfg.Circuit.Timeout, time.Minute),
+		MaxRequests:      cfg.Circuit.MaxRequests,
+		MinRequestCount:  cfg.Circuit.MinRequestCount,
+		SlidingWindow:    parseDurationOr(cfg.Circuit.SlidingWindow, time.Minute),
+	}, logger)
+}
+
+// newRetryPolicy creates the retry policy of provider calls, retrying
+// nothing when retries are disabled
+func newRetryPolicy(cfg *config.Config) errors.RetryConfig {
+	if !cfg.Retry.Enabled {
+		return errors.RetryConfig{MaxAttempts: 1}
+	}
+	policy := errors.WithExponentialBackoff(cfg.Retry.MaxAttempts,
+		parseDurationOr(cfg.Retry.InitialDelay, time.Second),
+		parseDurationOr(cfg.Retry.MaxDelay, 30*time.Second))
+	if cfg.Retry.BackoffFactor > 0 {
+		policy.BackoffFactor = cfg.Retry.BackoffFactor
+	}
+	policy.Jitter = cfg.Retry.Jitter
+	return policy
+}
+
+// newRateLimiter creates the provider rate limiter, sharing quotas through
+// NATS KV when configured and available
+func newRateLimiter(cfg *config.Config, messagingClient *messaging.Client, logger *slog.Logger) providers.RateLimiter {
+	if cfg.Providers.RateLimiter.Shared && messagingClient != nil {
+		kv, err := messagingClient.KeyValue(context.Background(), cfg.Providers.RateLimiter.Bucket, 0)
+		if err == nil {
+			return providers.NewSharedTokenBucketLimiter(kv, logger)
+		}
+		logger.Warn("Shared rate limiter unavailable, using in-process limiter", slog.String("error", err.Error()))
+	}
+	return providers.NewTokenBucketLimiter(logger)
+}
+
+// newResultCache creates the provider result cache on the NATS KV bucket,
+// keeping results in memory only when the bucket is unavailable
+func newResultCache(cfg *config.Config, messagingClient *messaging.Client, logger *slog.Logger) providers.CacheManager {
+	ttl := parseDurationOr(cfg.NATS.KVStore.TTL, providers.DefaultCacheTTL)
+	cacheConfig := providers.CacheConfig{Enabled: true, TTL: ttl, MaxSize: providers.DefaultCacheMaxSize}
+
+	if cfg.NATS.KVStore.Enabled && messagingClient != nil {
+		kv, err := messagingClient.KeyValue(context.Background(), cfg.NATS.KVStore.Bucket, ttl)
+		if err == nil {
+			return providers.NewCacheManager(kv, cacheConfig, logger)
+		}
+		logger.Warn("Result cache bucket unavailable, using in-memory cache", slog.String("error", err.Error()))
+	}
+	return providers.NewCacheManager(nil, cacheConfig, logger)
+}
+
+// aggregationStrategy returns the configured aggregation strategy, reciprocal
+// rank fusion by default
+func aggregationStrategy(cfg *config.Config) providers.AggregationStrategy {
+	if cfg.Providers.AggregationStrategy == "" {
+		return providers.StrategyReciprocalRank
+	}
+	return providers.AggregationStrategy(cfg.Providers.AggregationStrategy)
+}
+
+// parseDurationOr parses a duration string, returning fallback when it is invalid
+func parseDurationOr(value string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// providerTimeouts returns the search timeout budget of each provider
+func providerTimeouts(cfg *config.Config) map[string]time.Duration {
+	return map[string]time.Duration{
+		providers.ProviderArxiv:           parseDurationOr(cfg.Providers.ArXiv.Timeout, 10*time.Second),
+		providers.ProviderSemanticScholar: parseDurationOr(cfg.Providers.SemanticScholar.Timeout, 15*time.Second),
+		providers.ProviderExa:             parseDurationOr(cfg.Providers.Exa.Timeout, 20*time.Second),
+		providers.ProviderTavily:          parseDurationOr(cfg.Providers.Tavily.Timeout, 25*time.Second),
+		providers.ProviderCrossRef:        parseDurationOr(cfg.Providers.Crossref.Timeout, 15*time.Second),
+		providers.ProviderOpenAlex:        parseDurationOr(cfg.Providers.OpenAlex.Timeout, 15*time.Second),
+		providers.ProviderPubMed:          parseDurationOr(cfg.Providers.PubMed.Timeout, 15*time.Second),
+	}
+}
+
 // initializeProviders sets up all search providers
-func initializeProviders(manager providers.ProviderManager, logger *slog.Logger) {
+func initializeProviders(manager providers.ProviderManager, cfg *config.Config, paperRepo repository.PaperRepository, logger *slog.Logger) {
+	timeouts := providerTimeouts(cfg)
 
 	arxivConfig := providers.ProviderConfig{
 		Enabled:    true,
 		BaseURL:    "https://export.arxiv.org/api/query",
-		Timeout:    10 * time.Second,
+		Timeout:    timeouts[providers.ProviderArxiv],
 		MaxRetries: 3,
 	}
+	if interval, err := time.ParseDuration(cfg.Providers.ArXiv.RateLimit); err == nil {
+		arxivConfig.RateLimit = providers.RateLimitFromInterval(interval)
+	}
 	arxivProvider := arxiv.NewProvider(arxivConfig, logger)
 	manager.RegisterProvider("arxiv", arxivProvider)
+	manager.UpdateProviderConfig("arxiv", arxivConfig)
 
 	ssConfig := providers.ProviderConfig{
 		Enabled:    true,
 		BaseURL:    "https://api.semanticscholar.org/graph/v1",
-		Timeout:    15 * time.Second,
+		Timeout:    timeouts[providers.ProviderSemanticScholar],
 		MaxRetries: 3,
 		APIKey:     "",
 	}
@@ -267,7 +405,7 @@ func initializeProviders(manager providers.ProviderManager, logger *slog.Logger)
 	exaConfig := providers.ProviderConfig{
 		Enabled:    false,
 		BaseURL:    "https://api.exa.ai",
-		Timeout:    20 * time.Second,
+		Timeout:    timeouts[providers.ProviderExa],
 		MaxRetries: 3,
 		APIKey:     "",
 	}
@@ -277,19 +415,124 @@ func initializeProviders(manager providers.ProviderManager, logger *slog.Logger)
 	tavilyConfig := providers.ProviderConfig{
 		Enabled:    false,
 		BaseURL:    "https://api.tavily.com",
-		Timeout:    25 * time.Second,
+		Timeout:    timeouts[providers.ProviderTavily],
 		MaxRetries: 3,
 		APIKey:     "",
 	}
 	tavilyProvider := tavily.NewProvider(tavilyConfig, logger)
 	manager.RegisterProvider("tavily", tavilyProvider)
 
+	// Initialize Crossref provider (a mailto address joins the polite pool)
+	crossrefConfig := providers.ProviderConfig{
+		Enabled:    cfg.Providers.Crossref.Enabled,
+		BaseURL:    cfg.Providers.Crossref.BaseURL,
+		Timeout:    timeouts[providers.ProviderCrossRef],
+		MaxRetries: 3,
+		Custom:     map[string]interface{}{"mailto": cfg.Providers.Crossref.Mailto},
+	}
+	crossrefProvider := crossref.NewProvider(crossrefConfig, logger)
+	manager.RegisterProvider("crossref", crossrefProvider)
+
+	// Initialize OpenAlex provider (a mailto address joins the polite pool)
+	openalexConfig := providers.ProviderConfig{
+		Enabled:    cfg.Providers.OpenAlex.Enabled,
+		BaseURL:    cfg.Providers.OpenAlex.BaseURL,
+		Timeout:    timeouts[providers.ProviderOpenAlex],
+		MaxRetries: 3,
+		Custom:     map[string]interface{}{"mailto": cfg.Providers.OpenAlex.Mailto},
+	}
+	openalexProvider := openalex.NewProvider(openalexConfig, logger)
+	manager.RegisterProvider("openalex", openalexProvider)
+
+	// Initialize PubMed provider (an API key raises the NCBI request limit)
+	pubmedConfig := providers.ProviderConfig{
+		Enabled:    cfg.Providers.PubMed.Enabled,
+		BaseURL:    cfg.Providers.PubMed.BaseURL,
+		Timeout:    timeouts[providers.ProviderPubMed],
+		MaxRetries: 3,
+		APIKey:     cfg.Providers.PubMed.APIKey,
+		Custom:     map[string]interface{}{"email": cfg.Providers.PubMed.Email},
+	}
+	pubmedProvider := pubmed.NewProvider(pubmedConfig, logger)
+	manager.RegisterProvider("pubmed", pubmedProvider)
+
+	// Initialize local provider over the stored papers
+	localConfig := providers.ProviderConfig{
+		Enabled: cfg.Providers.Local.Enabled,
+	}
+	localProvider := local.NewProvider(localConfig, paperRepo, logger)
+	manager.RegisterProvider("local", localProvider)
+
 	logger.Info("Search providers initialized", slog.Int("total_providers", len(manager.GetAllProviders())), slog.Int("enabled_providers", len(manager.GetEnabledProviders())))
 }
 
 // ProvideServices creates service instances
-func ProvideServices(repos *repository.Container, messaging2 *messaging.Client, providerManager providers.ProviderManager, logger *slog.Logger) *services.Container {
-	return services.NewContainer(repos, messaging2, providerManager, logger)
+func ProvideServices(repos *repository.Container, messaging2 *messaging.Client, providerManager providers.ProviderManager, embeddings *services.EmbeddingService, logger *slog.Logger) *services.Container {
+	container := services.NewContainer(repos, messaging2, providerManager, logger)
+	if embeddings != nil {
+		container.Search.(*services.SearchService).EnableSemanticSearch(embeddings)
+		container.Paper.(*services.PaperService).EnableEmbeddingSimilarity(embeddings)
+	}
+	return container
+}
+
+// ProvideHarvestService creates the arXiv OAI-PMH harvest service, keeping
+// its resumption checkpoints in NATS KV when available
+func ProvideHarvestService(cfg *config.Config, repos *repository.Container, messagingClient *messaging.Client, logger *slog.Logger) *services.HarvestService {
+	harvestCfg := cfg.Providers.ArXiv.Harvest
+	harvester := arxiv.NewHarvester(providers.ProviderConfig{
+		Enabled:    harvestCfg.Enabled,
+		BaseURL:    harvestCfg.BaseURL,
+		Timeout:    parseDurationOr(cfg.Providers.ArXiv.Timeout, 30*time.Second),
+		MaxRetries: 3,
+	}, logger)
+
+	var kv jetstream.KeyValue
+	var events services.IndexingEventPublisher
+	if messagingClient != nil {
+		events = messaging.NewEventPublisher(messagingClient, logger)
+		if harvestCfg.Enabled {
+			bucket, err := messagingClient.KeyValue(context.Background(), harvestCfg.Bucket, 0)
+			if err == nil {
+				kv = bucket
+			} else {
+				logger.Warn("Harvest checkpoint bucket unavailable, using in-memory checkpoints", slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	return services.NewHarvestService(harvester, repos.Paper, services.NewHarvestCheckpointStore(kv, logger), events, logger)
+}
+
+// ProvideEmbeddingService creates the paper embedding service, nil when
+// embeddings are disabled
+func ProvideEmbeddingService(cfg *config.Config, db *repository.Database, repos *repository.Container, logger *slog.Logger) *services.EmbeddingService {
+	embeddingsCfg := cfg.Embeddings
+	if !embeddingsCfg.Enabled {
+		return nil
+	}
+
+	var embedder embeddings.Embedder
+	if embeddingsCfg.Provider == "hashing" {
+		embedder = embeddings.NewHashingEmbedder(embeddingsCfg.Dimensions)
+	} else {
+		httpEmbedder, err := embeddings.NewHTTPEmbedder(embeddings.HTTPConfig{
+			BaseURL:    embeddingsCfg.BaseURL,
+			Model:      embeddingsCfg.Model,
+			APIKey:     embeddingsCfg.APIKey,
+			Dimensions: embeddingsCfg.Dimensions,
+			BatchSize:  embeddingsCfg.BatchSize,
+			Timeout:    parseDurationOr(embeddingsCfg.Timeout, 30*time.Second),
+		})
+		if err != nil {
+			logger.Warn("Embedding server misconfigured, semantic search disabled", slog.String("error", err.Error()))
+			return nil
+		}
+		embedder = httpEmbedder
+	}
+
+	index := repository.NewVectorIndex(db.DB, embedder.Dimensions(), logger)
+	return services.NewEmbeddingService(embedder, repos.Paper, index, embeddingsCfg.BatchSize, logger)
 }
 
 // ProvideHandlers creates HTTP handler instances
@@ -298,13 +541,21 @@ func ProvideHandlers(services2 *services.Container, logger *slog.Logger) *handle
 }
 
 // ProvideConcreteSearchService creates a concrete search service
-func ProvideConcreteSearchService(repos *repository.Container, messaging2 *messaging.Client, providerManager providers.ProviderManager, logger *slog.Logger) *services.SearchService {
-	return services.NewSearchService(repos.Search, repos.Paper, messaging2, providerManager, logger).(*services.SearchService)
+func ProvideConcreteSearchService(repos *repository.Container, messaging2 *messaging.Client, providerManager providers.ProviderManager, embeddings *services.EmbeddingService, logger *slog.Logger) *services.SearchService {
+	service := services.NewSearchService(repos.Search, repos.Paper, messaging2, providerManager, logger).(*services.SearchService)
+	if embeddings != nil {
+		service.EnableSemanticSearch(embeddings)
+	}
+	return service
 }
 
 // ProvideConcretePaperService creates a concrete paper service
-func ProvideConcretePaperService(repos *repository.Container, messaging2 *messaging.Client, logger *slog.Logger) *services.PaperService {
-	return services.NewPaperService(repos.Paper, messaging2, logger).(*services.PaperService)
+func ProvideConcretePaperService(repos *repository.Container, messaging2 *messaging.Client, embeddings *services.EmbeddingService, logger *slog.Logger) *services.PaperService {
+	service := services.NewPaperService(repos.Paper, repos.Citation, messaging2, logger).(*services.PaperService)
+	if embeddings != nil {
+		service.EnableEmbeddingSimilarity(embeddings)
+	}
+	return service
 }
 
 // ProvideConcreteAuthorService creates a concrete author service
@@ -312,6 +563,11 @@ func ProvideConcreteAuthorService(repos *repository.Container, messaging2 *messa
 	return services.NewAuthorService(repos.Author, repos.Paper, messaging2, logger).(*services.AuthorService)
 }
 
+// ProvideAnalyticsService exposes the analytics service of the services container
+func ProvideAnalyticsService(services *services.Container) services.AnalyticsServiceInterface {
+	return services.Analytics
+}
+
 // ProvideConcreteHealthHandler creates a concrete health handler
 func ProvideConcreteHealthHandler(services2 *services.Container, logger *slog.Logger) *handlers.HealthHandler {
 	return handlers.NewHealthHandler(services2.Health, logger)
@@ -322,6 +578,7 @@ func ProvideRouter(
 	searchService *services.SearchService,
 	paperService *services.PaperService,
 	authorService *services.AuthorService,
+	analyticsService services.AnalyticsServiceInterface,
 	healthHandler *handlers.HealthHandler,
 	providerManager providers.ProviderManager,
 	logger *slog.Logger,
@@ -330,6 +587,7 @@ func ProvideRouter(
 		searchService,
 		paperService,
 		authorService,
+		analyticsService,
 		healthHandler,
 		logger,
 	)
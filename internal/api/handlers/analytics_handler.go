@@ -1,11 +1,26 @@
 package handlers
 
 import (
+	stderrors "errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
 
 	"scifind-backend/internal/services"
 )
 
+const (
+	// defaultAnalyticsWindow is the time range used when no from/to is given
+	defaultAnalyticsWindow = 24 * time.Hour
+
+	// defaultPopularQueriesLimit is the number of popular queries returned by default
+	defaultPopularQueriesLimit = 10
+)
+
 // AnalyticsHandler handles analytics-related HTTP requests
 type AnalyticsHandler struct {
 	service services.AnalyticsServiceInterface
@@ -18,4 +33,231 @@ func NewAnalyticsHandler(service services.AnalyticsServiceInterface, logger *slo
 		service: service,
 		logger:  logger,
 	}
-}
\ No newline at end of file
+}
+
+// GetSearchMetrics returns aggregate search metrics
+// @Summary Get search metrics
+// @Description Get aggregate search metrics for a time range (default: last 24 hours)
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the time range (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} services.SearchMetrics
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/analytics/metrics [get]
+func (h *AnalyticsHandler) GetSearchMetrics(c *gin.Context) {
+	from, to, ok := h.parseTimeRange(c)
+	if !ok {
+		return
+	}
+
+	metrics, err := h.service.GetSearchMetrics(c.Request.Context(), from, to)
+	if err != nil {
+		h.respondError(c, "Failed to get search metrics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetPopularQueries returns the most frequent search queries
+// @Summary Get popular queries
+// @Description Get the most frequent search queries for a time range (default: last 24 hours)
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of queries to return (default: 10, max: 100)"
+// @Param from query string false "Start of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the time range (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {array} services.PopularQuery
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/analytics/queries/popular [get]
+func (h *AnalyticsHandler) GetPopularQueries(c *gin.Context) {
+	from, to, ok := h.parseTimeRange(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultPopularQueriesLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			h.respondBadRequest(c, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	queries, err := h.service.GetPopularQueries(c.Request.Context(), limit, from, to)
+	if err != nil {
+		h.respondError(c, "Failed to get popular queries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"queries":   queries,
+		"from":      from,
+		"to":        to,
+		"timestamp": time.Now(),
+	})
+}
+
+// GetProviderPerformance returns per provider search performance
+// @Summary Get provider performance
+// @Description Get search performance per provider for a time range (default: last 24 hours)
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the time range (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} map[string]services.ProviderMetrics
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/analytics/providers [get]
+func (h *AnalyticsHandler) GetProviderPerformance(c *gin.Context) {
+	from, to, ok := h.parseTimeRange(c)
+	if !ok {
+		return
+	}
+
+	performance, err := h.service.GetProviderPerformance(c.Request.Context(), from, to)
+	if err != nil {
+		h.respondError(c, "Failed to get provider performance", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"providers": performance,
+		"from":      from,
+		"to":        to,
+		"timestamp": time.Now(),
+	})
+}
+
+// GetUserActivity returns the search activity of a user
+// @Summary Get user activity
+// @Description Get the search activity of a user, with daily activity for a time range (default: last 24 hours)
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param from query string false "Start of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the time range (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} services.UserActivity
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/analytics/users/{id} [get]
+func (h *AnalyticsHandler) GetUserActivity(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		h.respondBadRequest(c, "user ID is required")
+		return
+	}
+
+	from, to, ok := h.parseTimeRange(c)
+	if !ok {
+		return
+	}
+
+	activity, err := h.service.GetUserActivity(c.Request.Context(), userID, from, to)
+	if err != nil {
+		h.respondError(c, "Failed to get user activity", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// RecordEvent records a client side analytics event
+// @Summary Record an analytics event
+// @Description Record a client side analytics event (query, click, filter or export)
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param event body services.AnalyticsEvent true "Analytics event"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/analytics/events [post]
+func (h *AnalyticsHandler) RecordEvent(c *gin.Context) {
+	var event services.AnalyticsEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		h.respondBadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.RecordEvent(c.Request.Context(), &event); err != nil {
+		h.respondError(c, "Failed to record event", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"status":    "accepted",
+		"timestamp": time.Now(),
+	})
+}
+
+// parseTimeRange parses the from and to query parameters, responding with a
+// bad request if they are invalid
+func (h *AnalyticsHandler) parseTimeRange(c *gin.Context) (time.Time, time.Time, bool) {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := parseAnalyticsTime(toStr)
+		if err != nil {
+			h.respondBadRequest(c, fmt.Sprintf("invalid to: %v", err))
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultAnalyticsWindow)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := parseAnalyticsTime(fromStr)
+		if err != nil {
+			h.respondBadRequest(c, fmt.Sprintf("invalid from: %v", err))
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		h.respondBadRequest(c, "from must be before to")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// parseAnalyticsTime accepts RFC3339 timestamps and plain dates
+func parseAnalyticsTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func (h *AnalyticsHandler) respondBadRequest(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:     "Invalid request",
+		Message:   message,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+func (h *AnalyticsHandler) respondError(c *gin.Context, message string, err error) {
+	var validationErr *services.ValidationError
+	if stderrors.As(err, &validationErr) {
+		h.respondBadRequest(c, err.Error())
+		return
+	}
+
+	h.logger.Error(message, slog.String("error", err.Error()))
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:     message,
+		Message:   err.Error(),
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
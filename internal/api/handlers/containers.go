@@ -31,6 +31,11 @@ func NewContainer(services *services.Container, logger *slog.Logger) *Container
 type PaperHandlerInterface interface {
 	ListPapers(c *gin.Context)
 	GetPaper(c *gin.Context)
+	GetSimilarPapers(c *gin.Context)
+	GetCitingPapers(c *gin.Context)
+	GetReferencedPapers(c *gin.Context)
+	GetCitationNeighborhood(c *gin.Context)
+	GetCitationPath(c *gin.Context)
 	CreatePaper(c *gin.Context)
 	UpdatePaper(c *gin.Context)
 	DeletePaper(c *gin.Context)
@@ -38,14 +43,22 @@ type PaperHandlerInterface interface {
 
 type SearchHandlerInterface interface {
 	Search(c *gin.Context)
+	StreamSearch(c *gin.Context)
+	Suggest(c *gin.Context)
 	GetPaper(c *gin.Context)
 	GetProviders(c *gin.Context)
 	GetProviderMetrics(c *gin.Context)
 	ConfigureProvider(c *gin.Context)
+	OpenCircuit(c *gin.Context)
+	ResetCircuit(c *gin.Context)
 }
 
 type AnalyticsHandlerInterface interface {
-	// Add analytics handler methods here
+	GetSearchMetrics(c *gin.Context)
+	GetPopularQueries(c *gin.Context)
+	GetProviderPerformance(c *gin.Context)
+	GetUserActivity(c *gin.Context)
+	RecordEvent(c *gin.Context)
 }
 
 type HealthHandlerInterface interface {
@@ -4,8 +4,10 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"scifind-backend/internal/errors"
 	"scifind-backend/internal/services"
 )
 
@@ -110,6 +112,261 @@ func (h *PaperHandler) GetPaper(c *gin.Context) {
 	c.JSON(http.StatusOK, paper)
 }
 
+// GetSimilarPapers handles GET /v1/papers/:id/similar
+// @Summary Get similar papers
+// @Description Recommend stored papers similar to a paper by embedding similarity, co-citation, bibliographic coupling and shared authors, explaining each recommendation
+// @Tags papers
+// @Accept json
+// @Produce json
+// @Param id path string true "Paper ID"
+// @Param limit query int false "Number of papers to return (default: 10, max: 100)"
+// @Success 200 {string} string "Similar papers with scores and reasons"
+// @Failure 400 {object} object{error=string}
+// @Failure 404 {object} object{error=string}
+// @Failure 500 {object} object{error=string}
+// @Router /v1/papers/{id}/similar [get]
+func (h *PaperHandler) GetSimilarPapers(c *gin.Context) {
+	paperID := c.Param("id")
+	if paperID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "paper ID is required",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultSimilarLimit)))
+	if err != nil || limit < 1 || limit > services.MaxSimilarLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid limit parameter",
+		})
+		return
+	}
+
+	similar, err := h.paperService.GetSimilar(c.Request.Context(), paperID, limit)
+	if err != nil {
+		h.logger.Error("failed to get similar papers",
+			slog.String("paper_id", paperID),
+			slog.String("error", err.Error()),
+		)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "paper not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to get similar papers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"paper_id": paperID,
+		"similar":  similar,
+		"count":    len(similar),
+	})
+}
+
+// GetCitingPapers handles GET /v1/papers/:id/citations
+// @Summary Get papers citing a paper
+// @Description Get a page of the papers citing a paper, stored papers first. Papers only known by the ID a provider named them by are included unresolved.
+// @Tags papers
+// @Accept json
+// @Produce json
+// @Param id path string true "Paper ID"
+// @Param limit query int false "Number of papers to return (default: 20, max: 100)"
+// @Param offset query int false "Number of papers to skip (default: 0)"
+// @Success 200 {string} string "Page of citing papers"
+// @Failure 400 {object} object{error=string}
+// @Failure 404 {object} object{error=string}
+// @Failure 500 {object} object{error=string}
+// @Router /v1/papers/{id}/citations [get]
+func (h *PaperHandler) GetCitingPapers(c *gin.Context) {
+	h.getCitationPage(c, services.CitationDirectionCitations)
+}
+
+// GetReferencedPapers handles GET /v1/papers/:id/references
+// @Summary Get papers a paper cites
+// @Description Get a page of the papers a paper cites, stored papers first. Papers only known by the ID a provider named them by are included unresolved.
+// @Tags papers
+// @Accept json
+// @Produce json
+// @Param id path string true "Paper ID"
+// @Param limit query int false "Number of papers to return (default: 20, max: 100)"
+// @Param offset query int false "Number of papers to skip (default: 0)"
+// @Success 200 {string} string "Page of referenced papers"
+// @Failure 400 {object} object{error=string}
+// @Failure 404 {object} object{error=string}
+// @Failure 500 {object} object{error=string}
+// @Router /v1/papers/{id}/references [get]
+func (h *PaperHandler) GetReferencedPapers(c *gin.Context) {
+	h.getCitationPage(c, services.CitationDirectionReferences)
+}
+
+func (h *PaperHandler) getCitationPage(c *gin.Context, direction services.CitationDirection) {
+	paperID := c.Param("id")
+	if paperID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "paper ID is required",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultCitationPageLimit)))
+	if err != nil || limit < 1 || limit > services.MaxCitationPageLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid limit parameter",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid offset parameter",
+		})
+		return
+	}
+
+	var page *services.CitationPage
+	if direction == services.CitationDirectionCitations {
+		page, err = h.paperService.GetCitingPapers(c.Request.Context(), paperID, limit, offset)
+	} else {
+		page, err = h.paperService.GetReferencedPapers(c.Request.Context(), paperID, limit, offset)
+	}
+	if err != nil {
+		h.logger.Error("failed to get "+string(direction),
+			slog.String("paper_id", paperID),
+			slog.String("error", err.Error()),
+		)
+		h.citationError(c, err, "failed to get "+string(direction))
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetCitationNeighborhood handles GET /v1/papers/:id/neighborhood
+// @Summary Get the citation neighborhood of a paper
+// @Description Get the papers up to a number of citations away from a paper, with the citations between them. Papers only known by the ID a provider named them by end the traversal.
+// @Tags papers
+// @Accept json
+// @Produce json
+// @Param id path string true "Paper ID"
+// @Param depth query int false "Number of citations to follow (default: 1, max: 3)"
+// @Param direction query string false "Citations to follow: references, citations or both (default: both)"
+// @Param max_nodes query int false "Most papers to return (default: 200, max: 1000)"
+// @Success 200 {string} string "Papers and citations of the neighborhood"
+// @Failure 400 {object} object{error=string}
+// @Failure 404 {object} object{error=string}
+// @Failure 500 {object} object{error=string}
+// @Router /v1/papers/{id}/neighborhood [get]
+func (h *PaperHandler) GetCitationNeighborhood(c *gin.Context) {
+	paperID := c.Param("id")
+	if paperID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "paper ID is required",
+		})
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(services.DefaultNeighborhoodDepth)))
+	if err != nil || depth < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid depth parameter",
+		})
+		return
+	}
+
+	maxNodes, err := strconv.Atoi(c.DefaultQuery("max_nodes", strconv.Itoa(services.DefaultNeighborhoodNodes)))
+	if err != nil || maxNodes < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid max_nodes parameter",
+		})
+		return
+	}
+
+	direction := services.CitationDirection(c.DefaultQuery("direction", string(services.CitationDirectionBoth)))
+	graph, err := h.paperService.GetCitationNeighborhood(c.Request.Context(), paperID, depth, direction, maxNodes)
+	if err != nil {
+		h.logger.Error("failed to get citation neighborhood",
+			slog.String("paper_id", paperID),
+			slog.String("error", err.Error()),
+		)
+		h.citationError(c, err, "failed to get citation neighborhood")
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// GetCitationPath handles GET /v1/papers/:id/path/:target
+// @Summary Get the shortest citation path between two papers
+// @Description Find a shortest chain of citations from a paper to another through stored papers
+// @Tags papers
+// @Accept json
+// @Produce json
+// @Param id path string true "Paper ID to start from"
+// @Param target path string true "Paper ID to reach"
+// @Param direction query string false "Citations to follow: references, citations or both (default: both)"
+// @Param max_depth query int false "Most citations on the path (default: 6, max: 10)"
+// @Success 200 {string} string "Papers and citations along the path"
+// @Failure 400 {object} object{error=string}
+// @Failure 404 {object} object{error=string}
+// @Failure 500 {object} object{error=string}
+// @Router /v1/papers/{id}/path/{target} [get]
+func (h *PaperHandler) GetCitationPath(c *gin.Context) {
+	paperID := c.Param("id")
+	targetID := c.Param("target")
+	if paperID == "" || targetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "paper IDs are required",
+		})
+		return
+	}
+
+	maxDepth, err := strconv.Atoi(c.DefaultQuery("max_depth", strconv.Itoa(services.DefaultCitationPathDepth)))
+	if err != nil || maxDepth < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid max_depth parameter",
+		})
+		return
+	}
+
+	direction := services.CitationDirection(c.DefaultQuery("direction", string(services.CitationDirectionBoth)))
+	path, err := h.paperService.GetCitationPath(c.Request.Context(), paperID, targetID, direction, maxDepth)
+	if err != nil {
+		h.logger.Error("failed to get citation path",
+			slog.String("paper_id", paperID),
+			slog.String("target_id", targetID),
+			slog.String("error", err.Error()),
+		)
+		h.citationError(c, err, "failed to get citation path")
+		return
+	}
+
+	c.JSON(http.StatusOK, path)
+}
+
+// citationError responds to a failed citation graph request
+func (h *PaperHandler) citationError(c *gin.Context, err error, message string) {
+	if errors.IsValidationError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": message,
+	})
+}
+
 // CreatePaper handles POST /v1/papers
 // @Summary Create a new paper
 // @Description Create a new paper (currently not implemented)
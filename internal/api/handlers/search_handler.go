@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -12,6 +15,7 @@ import (
 	"github.com/google/uuid"
 
 	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
 	"scifind-backend/internal/providers"
 	"scifind-backend/internal/services"
 )
@@ -36,15 +40,26 @@ func NewSearchHandler(service services.SearchServiceInterface, logger *slog.Logg
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param query query string true "Search query"
+// @Param query query string true "Search query; supports title:, author:, abstract:, year:2019..2022, cat:, AND/OR/NOT, phrases and grouping"
 // @Param limit query int false "Number of results to return (default: 20, max: 100)"
 // @Param offset query int false "Number of results to skip (default: 0)"
-// @Param providers query string false "Comma-separated list of providers (arxiv,semantic_scholar,exa,tavily)"
+// @Param cursor query string false "next_cursor of the previous page; continues each provider where it left off"
+// @Param providers query string false "Comma-separated list of providers (arxiv,semantic_scholar,exa,tavily,crossref,openalex,pubmed,local)"
 // @Param date_from query string false "Start date filter (YYYY-MM-DD)"
 // @Param date_to query string false "End date filter (YYYY-MM-DD)"
 // @Param author query string false "Author filter"
 // @Param journal query string false "Journal filter"
 // @Param category query string false "Category filter"
+// @Param cache query string false "Result cache mode" Enums(bypass,refresh)
+// @Param mode query string false "semantic searches the stored papers by meaning instead of the providers by keywords, hybrid by both" Enums(semantic,hybrid)
+// @Param fusion query string false "How hybrid searches combine the full-text and vector rankings (default: rrf)" Enums(rrf,weighted)
+// @Param alpha query number false "Weight of vector similarity in hybrid searches, 0 to 1 (default: 0.5)"
+// @Param facet_year query string false "Comma-separated publication years to narrow the results to"
+// @Param facet_category query string false "Comma-separated categories to narrow the results to"
+// @Param facet_journal query string false "Comma-separated journals to narrow the results to"
+// @Param facet_author query string false "Comma-separated authors to narrow the results to"
+// @Param facet_language query string false "Comma-separated languages to narrow the results to"
+// @Param facet_provider query string false "Comma-separated source providers to narrow the results to"
 // @Success 200 {object} services.SearchResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -109,13 +124,135 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// StreamSearch streams search results as providers finish
+// @Summary Stream search results
+// @Description Search like /v1/search but emit a Server-Sent Event as each provider finishes, carrying the papers not sent before, followed by a summary event
+// @Tags search
+// @Produce text/event-stream
+// @Param query query string true "Search query; supports title:, author:, abstract:, year:2019..2022, cat:, AND/OR/NOT, phrases and grouping"
+// @Param limit query int false "Number of results per provider (default: 20, max: 100)"
+// @Param providers query string false "Comma-separated list of providers (arxiv,semantic_scholar,exa,tavily,crossref,openalex,pubmed,local)"
+// @Param date_from query string false "Start date filter (YYYY-MM-DD)"
+// @Param date_to query string false "End date filter (YYYY-MM-DD)"
+// @Param author query string false "Author filter"
+// @Param journal query string false "Journal filter"
+// @Param category query string false "Category filter"
+// @Success 200 {object} providers.StreamEvent "provider and summary events"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/search/stream [get]
+func (h *SearchHandler) StreamSearch(c *gin.Context) {
+	requestID := uuid.New().String()
+
+	searchReq, err := h.parseSearchRequest(c, requestID)
+	if err != nil {
+		h.logger.Warn("Invalid search request",
+			slog.String("request_id", requestID),
+			slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:     "Invalid request",
+			Message:   err.Error(),
+			RequestID: requestID,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	events, err := h.service.SearchStream(c.Request.Context(), searchReq)
+	if err != nil {
+		h.logger.Error("Search stream failed",
+			slog.String("request_id", requestID),
+			slog.String("error", err.Error()))
+
+		statusCode := http.StatusInternalServerError
+		if errors.IsValidationError(err) || strings.HasPrefix(err.Error(), "invalid search request") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, ErrorResponse{
+			Error:     "Search failed",
+			Message:   err.Error(),
+			RequestID: requestID,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Header("X-Request-ID", requestID)
+
+	c.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent(string(event.Type), event)
+		return true
+	})
+}
+
+// Suggest returns query-as-you-type suggestions
+// @Summary Get search suggestions
+// @Description Get prefix matched suggestions from past popular queries and stored authors, journals and categories
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param query query string true "Prefix typed so far"
+// @Param limit query int false "Number of suggestions to return (default: 10, max: 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	prefix := c.Query("query")
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:     "Invalid request",
+				Message:   fmt.Sprintf("invalid limit: %v", err),
+				Timestamp: time.Now().Format(time.RFC3339),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := h.service.Suggest(c.Request.Context(), prefix, limit)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		var validationErr *services.ValidationError
+		if stderrors.As(err, &validationErr) {
+			statusCode = http.StatusBadRequest
+		} else {
+			h.logger.Error("Failed to get suggestions", slog.String("error", err.Error()))
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:     "Failed to get suggestions",
+			Message:   err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":       prefix,
+		"suggestions": suggestions,
+		"timestamp":   time.Now(),
+	})
+}
+
 // GetPaper retrieves a specific paper by provider and ID
 // @Summary Get a specific paper
 // @Description Retrieve a specific paper by provider name and paper ID
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param provider path string true "Provider name" Enums(arxiv,semantic_scholar,exa,tavily)
+// @Param provider path string true "Provider name" Enums(arxiv,semantic_scholar,exa,tavily,crossref,openalex,pubmed,local)
 // @Param id path string true "Paper ID"
 // @Success 200 {object} services.PaperResponse
 // @Failure 400 {object} ErrorResponse
@@ -246,7 +383,7 @@ func (h *SearchHandler) GetProviderMetrics(c *gin.Context) {
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param provider path string true "Provider name" Enums(arxiv,semantic_scholar,exa,tavily)
+// @Param provider path string true "Provider name" Enums(arxiv,semantic_scholar,exa,tavily,crossref,openalex,pubmed,local)
 // @Param config body providers.ProviderConfig true "Provider configuration"
 // @Success 200 {object} services.ProviderConfigResponse
 // @Failure 400 {object} ErrorResponse
@@ -318,8 +455,75 @@ func (h *SearchHandler) ConfigureProvider(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// OpenCircuit forces a provider's circuit breaker open
+// @Summary Open a provider circuit
+// @Description Force the circuit breaker of a provider open, taking it out of searches until the circuit is reset
+// @Tags search
+// @Produce json
+// @Param provider path string true "Provider name" Enums(arxiv,semantic_scholar,exa,tavily,crossref,openalex,pubmed,local)
+// @Success 200 {object} services.ProviderConfigResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/search/providers/{provider}/circuit/open [post]
+func (h *SearchHandler) OpenCircuit(c *gin.Context) {
+	h.updateCircuit(c, h.service.OpenCircuit, "Provider circuit opened")
+}
+
+// ResetCircuit closes a provider's circuit breaker
+// @Summary Reset a provider circuit
+// @Description Close the circuit breaker of a provider and forget its recorded failures
+// @Tags search
+// @Produce json
+// @Param provider path string true "Provider name" Enums(arxiv,semantic_scholar,exa,tavily,crossref,openalex,pubmed,local)
+// @Success 200 {object} services.ProviderConfigResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/search/providers/{provider}/circuit/reset [post]
+func (h *SearchHandler) ResetCircuit(c *gin.Context) {
+	h.updateCircuit(c, h.service.ResetCircuit, "Provider circuit reset")
+}
+
 // Helper methods
 
+// updateCircuit applies a circuit breaker change and responds with the
+// provider's new status
+func (h *SearchHandler) updateCircuit(c *gin.Context, update func(ctx context.Context, name string) error, message string) {
+	provider := c.Param("provider")
+	if err := update(c.Request.Context(), provider); err != nil {
+		h.logger.Error("Failed to update provider circuit",
+			slog.String("provider", provider),
+			slog.String("error", err.Error()))
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to update provider circuit",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var providerStatus providers.ProviderStatus
+	if status, err := h.service.GetProviderStatus(c.Request.Context()); err == nil {
+		providerStatus, _ = status[provider].(providers.ProviderStatus)
+	}
+
+	c.JSON(http.StatusOK, &services.ProviderConfigResponse{
+		ProviderName: provider,
+		Status:       providerStatus,
+		Message:      message,
+		Timestamp:    time.Now(),
+	})
+}
+
+
 func (h *SearchHandler) parseSearchRequest(c *gin.Context, requestID string) (*services.SearchRequest, error) {
 	req := &services.SearchRequest{
 		RequestID: requestID,
@@ -345,6 +549,9 @@ func (h *SearchHandler) parseSearchRequest(c *gin.Context, requestID string) (*s
 		req.Offset = offset
 	}
 
+	// Continue an earlier search
+	req.Cursor = c.Query("cursor")
+
 	// Parse providers
 	if providersStr := c.Query("providers"); providersStr != "" {
 		providers := splitAndTrim(providersStr, ",")
@@ -381,6 +588,28 @@ func (h *SearchHandler) parseSearchRequest(c *gin.Context, requestID string) (*s
 		req.Filters["category"] = category
 	}
 
+	// Parse cache and search modes
+	req.CacheMode = services.CacheMode(c.Query("cache"))
+	req.Mode = services.SearchMode(c.Query("mode"))
+	req.Fusion = services.FusionMethod(c.Query("fusion"))
+	if alphaStr := c.Query("alpha"); alphaStr != "" {
+		alpha, err := strconv.ParseFloat(alphaStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alpha: %v", err)
+		}
+		req.Alpha = &alpha
+	}
+
+	// Parse facet selections, applied to the merged results
+	for _, facet := range models.FacetNames {
+		if values := splitAndTrim(c.Query("facet_"+facet), ","); len(values) > 0 {
+			if req.FacetFilters == nil {
+				req.FacetFilters = make(map[string][]string)
+			}
+			req.FacetFilters[facet] = values
+		}
+	}
+
 	if subject := c.Query("subject"); subject != "" {
 		req.Filters["subject"] = subject
 	}
@@ -18,6 +18,7 @@ func NewRouter(
 	searchService *services.SearchService,
 	paperService *services.PaperService,
 	authorService *services.AuthorService,
+	analyticsService services.AnalyticsServiceInterface,
 	healthHandler *handlers.HealthHandler,
 	logger *slog.Logger,
 ) *gin.Engine {
@@ -47,10 +48,14 @@ func NewRouter(
 		{
 			searchHandler := handlers.NewSearchHandler(searchService, logger)
 			search.GET("", searchHandler.Search)
+			search.GET("/stream", searchHandler.StreamSearch)
+			search.GET("/suggest", searchHandler.Suggest)
 			search.GET("/papers/:provider/:id", searchHandler.GetPaper)
 			search.GET("/providers", searchHandler.GetProviders)
 			search.GET("/providers/metrics", searchHandler.GetProviderMetrics)
 			search.PUT("/providers/:provider/configure", searchHandler.ConfigureProvider)
+			search.POST("/providers/:provider/circuit/open", searchHandler.OpenCircuit)
+			search.POST("/providers/:provider/circuit/reset", searchHandler.ResetCircuit)
 		}
 
 		// Paper endpoints
@@ -60,6 +65,11 @@ func NewRouter(
 			papers.GET("", paperHandler.ListPapers)
 			papers.POST("", paperHandler.CreatePaper)
 			papers.GET("/:id", paperHandler.GetPaper)
+			papers.GET("/:id/similar", paperHandler.GetSimilarPapers)
+			papers.GET("/:id/citations", paperHandler.GetCitingPapers)
+			papers.GET("/:id/references", paperHandler.GetReferencedPapers)
+			papers.GET("/:id/neighborhood", paperHandler.GetCitationNeighborhood)
+			papers.GET("/:id/path/:target", paperHandler.GetCitationPath)
 			papers.PUT("/:id", paperHandler.UpdatePaper)
 			papers.DELETE("/:id", paperHandler.DeletePaper)
 		}
@@ -72,6 +82,17 @@ func NewRouter(
 			authors.GET("/:id", authorHandler.GetAuthor)
 			authors.GET("/:id/papers", authorHandler.GetAuthorPapers)
 		}
+
+		// Analytics endpoints
+		analytics := v1.Group("/analytics")
+		{
+			analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, logger)
+			analytics.GET("/metrics", analyticsHandler.GetSearchMetrics)
+			analytics.GET("/queries/popular", analyticsHandler.GetPopularQueries)
+			analytics.GET("/providers", analyticsHandler.GetProviderPerformance)
+			analytics.GET("/users/:id", analyticsHandler.GetUserActivity)
+			analytics.POST("/events", analyticsHandler.RecordEvent)
+		}
 	}
 
 	// Swagger documentation endpoints
@@ -92,6 +113,7 @@ func NewRouter(
 				"search":  "/v1/search",
 				"papers":  "/v1/papers",
 				"authors": "/v1/authors",
+				"analytics": "/v1/analytics",
 			},
 			"mcp_server": gin.H{
 				"description": "This server also supports Model Context Protocol",
@@ -48,6 +48,15 @@ type Config struct {
 			BaseURL   string `mapstructure:"base_url"`
 			RateLimit string `mapstructure:"rate_limit"`
 			Timeout   string `mapstructure:"timeout"`
+
+			// Harvest mirrors arXiv sets into the local store through OAI-PMH
+			Harvest struct {
+				Enabled  bool     `mapstructure:"enabled"`
+				BaseURL  string   `mapstructure:"base_url"`
+				Sets     []string `mapstructure:"sets"`     // e.g. cs, physics:hep-th
+				Interval string   `mapstructure:"interval"` // time between scheduled harvests
+				Bucket   string   `mapstructure:"bucket"`   // NATS KV bucket holding the checkpoints
+			} `mapstructure:"harvest"`
 		} `mapstructure:"arxiv"`
 
 		SemanticScholar struct {
@@ -70,8 +79,70 @@ type Config struct {
 			BaseURL string `mapstructure:"base_url"`
 			Timeout string `mapstructure:"timeout"`
 		} `mapstructure:"tavily"`
+
+		Crossref struct {
+			Enabled bool   `mapstructure:"enabled"`
+			Mailto  string `mapstructure:"mailto"` // contact address for the Crossref polite pool
+			BaseURL string `mapstructure:"base_url"`
+			Timeout string `mapstructure:"timeout"`
+		} `mapstructure:"crossref"`
+
+		OpenAlex struct {
+			Enabled bool   `mapstructure:"enabled"`
+			Mailto  string `mapstructure:"mailto"` // contact address for the OpenAlex polite pool
+			BaseURL string `mapstructure:"base_url"`
+			Timeout string `mapstructure:"timeout"`
+		} `mapstructure:"openalex"`
+
+		PubMed struct {
+			Enabled bool   `mapstructure:"enabled"`
+			APIKey  string `mapstructure:"api_key"` // raises the NCBI limit from 3 to 10 requests per second
+			Email   string `mapstructure:"email"`   // contact address NCBI asks E-utilities clients to send
+			BaseURL string `mapstructure:"base_url"`
+			Timeout string `mapstructure:"timeout"`
+		} `mapstructure:"pubmed"`
+
+		// Local searches the papers stored in our own database
+		Local struct {
+			Enabled bool `mapstructure:"enabled"`
+		} `mapstructure:"local"`
+
+		RateLimiter struct {
+			Shared bool   `mapstructure:"shared"`
+			Bucket string `mapstructure:"bucket"`
+		} `mapstructure:"rate_limiter"`
+
+		// AggregationStrategy combines the providers' results; the provider
+		// selection weights only apply to weighted_random
+		AggregationStrategy string `mapstructure:"aggregation_strategy" validate:"omitempty,oneof=merge first fastest best_quality round_robin weighted_random rrf"`
+
+		// Weights scales each provider's ranking in fused results; providers
+		// without a weight get 1
+		Weights map[string]float64 `mapstructure:"weights" validate:"omitempty,dive,gt=0"`
+
+		// SoftDeadline returns the results that arrived once it passed,
+		// listing the providers still running; empty waits for all of them
+		SoftDeadline string `mapstructure:"soft_deadline"`
+
+		Hedging struct {
+			Enabled    bool `mapstructure:"enabled"`
+			MinSamples int  `mapstructure:"min_samples"` // latencies needed before requests are hedged
+		} `mapstructure:"hedging"`
 	} `mapstructure:"providers"`
 
+	// Embeddings powers the semantic search mode over the stored papers
+	Embeddings struct {
+		Enabled    bool   `mapstructure:"enabled"`
+		Provider   string `mapstructure:"provider" validate:"omitempty,oneof=http hashing"`
+		BaseURL    string `mapstructure:"base_url"` // OpenAI compatible embedding server, serving /v1/embeddings
+		Model      string `mapstructure:"model"`
+		APIKey     string `mapstructure:"api_key"`
+		Dimensions int    `mapstructure:"dimensions"`
+		BatchSize  int    `mapstructure:"batch_size"`
+		Timeout    string `mapstructure:"timeout"`
+		Interval   string `mapstructure:"interval"` // time between runs embedding new papers
+	} `mapstructure:"embeddings"`
+
 	Logging struct {
 		Level     string `mapstructure:"level" validate:"oneof=debug info warn error"`
 		Format    string `mapstructure:"format" validate:"oneof=json text"`
@@ -332,6 +403,11 @@ func setDefaults() {
 	viper.SetDefault("providers.arxiv.base_url", "https://export.arxiv.org/api/query")
 	viper.SetDefault("providers.arxiv.rate_limit", "3s")
 	viper.SetDefault("providers.arxiv.timeout", "30s")
+	viper.SetDefault("providers.arxiv.harvest.enabled", false)
+	viper.SetDefault("providers.arxiv.harvest.base_url", "https://oaipmh.arxiv.org/oai")
+	viper.SetDefault("providers.arxiv.harvest.sets", []string{"cs"})
+	viper.SetDefault("providers.arxiv.harvest.interval", "24h")
+	viper.SetDefault("providers.arxiv.harvest.bucket", "scifind-harvest")
 	
 	viper.SetDefault("providers.semantic_scholar.enabled", true)
 	viper.SetDefault("providers.semantic_scholar.base_url", "https://api.semanticscholar.org/graph/v1")
@@ -344,6 +420,37 @@ func setDefaults() {
 	viper.SetDefault("providers.tavily.enabled", false)
 	viper.SetDefault("providers.tavily.base_url", "https://api.tavily.com")
 	viper.SetDefault("providers.tavily.timeout", "15s")
+	
+	viper.SetDefault("providers.crossref.enabled", true)
+	viper.SetDefault("providers.crossref.base_url", "https://api.crossref.org")
+	viper.SetDefault("providers.crossref.timeout", "15s")
+
+	viper.SetDefault("providers.openalex.enabled", true)
+	viper.SetDefault("providers.openalex.base_url", "https://api.openalex.org")
+	viper.SetDefault("providers.openalex.timeout", "15s")
+
+	viper.SetDefault("providers.pubmed.enabled", true)
+	viper.SetDefault("providers.pubmed.base_url", "https://eutils.ncbi.nlm.nih.gov/entrez/eutils")
+	viper.SetDefault("providers.pubmed.timeout", "15s")
+
+	viper.SetDefault("providers.local.enabled", true)
+
+	viper.SetDefault("providers.rate_limiter.shared", false)
+	viper.SetDefault("providers.rate_limiter.bucket", "scifind-ratelimits")
+	viper.SetDefault("providers.aggregation_strategy", "rrf")
+	viper.SetDefault("providers.soft_deadline", "")
+	viper.SetDefault("providers.hedging.enabled", false)
+	viper.SetDefault("providers.hedging.min_samples", 20)
+
+	// Embedding defaults
+	viper.SetDefault("embeddings.enabled", false)
+	viper.SetDefault("embeddings.provider", "http")
+	viper.SetDefault("embeddings.base_url", "http://localhost:8081")
+	viper.SetDefault("embeddings.model", "nomic-embed-text")
+	viper.SetDefault("embeddings.dimensions", 768)
+	viper.SetDefault("embeddings.batch_size", 32)
+	viper.SetDefault("embeddings.timeout", "30s")
+	viper.SetDefault("embeddings.interval", "5m")
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -0,0 +1,66 @@
+package embeddings
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"scifind-backend/internal/models"
+)
+
+// Embedder turns texts into embedding vectors of a fixed dimension. Texts
+// with similar meanings have embeddings with a high cosine similarity.
+type Embedder interface {
+	// Embed returns the embedding of each text, in the order of the texts
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Dimensions returns the length of the embeddings
+	Dimensions() int
+}
+
+// PaperText returns the text of a paper that is embedded: its title,
+// abstract and keywords
+func PaperText(paper *models.Paper) string {
+	parts := []string{paper.Title}
+	if paper.Abstract != nil && *paper.Abstract != "" {
+		parts = append(parts, *paper.Abstract)
+	}
+	if len(paper.Keywords) > 0 {
+		parts = append(parts, strings.Join(paper.Keywords, ", "))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Normalize scales an embedding to unit length in place, so cosine
+// similarities reduce to dot products. Zero vectors are left alone.
+func Normalize(embedding []float32) {
+	var norm float64
+	for _, v := range embedding {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for i, v := range embedding {
+		embedding[i] = float32(float64(v) / norm)
+	}
+}
+
+// Cosine returns the cosine similarity of two embeddings, 0 when their
+// lengths differ or either is a zero vector
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
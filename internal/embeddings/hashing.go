@@ -0,0 +1,67 @@
+package embeddings
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// DefaultHashingDimensions is the embedding length of a HashingEmbedder
+// created without one
+const DefaultHashingDimensions = 256
+
+// HashingEmbedder embeds texts by hashing their words into a fixed number of
+// buckets. It needs no model and is deterministic, so texts sharing words
+// are similar and nothing else is; meant for tests and development.
+type HashingEmbedder struct {
+	dimensions int
+}
+
+// NewHashingEmbedder creates a hashing embedder with embeddings of the given
+// length
+func NewHashingEmbedder(dimensions int) *HashingEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultHashingDimensions
+	}
+	return &HashingEmbedder{dimensions: dimensions}
+}
+
+// Dimensions returns the length of the embeddings
+func (e *HashingEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Embed returns the normalized word counts of each text, each word counted
+// in the bucket of its hash with the sign of its hash to spread collisions
+func (e *HashingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embedding := make([]float32, e.dimensions)
+		for _, word := range words(text) {
+			h := fnv.New64a()
+			h.Write([]byte(word))
+			sum := h.Sum64()
+
+			if sum>>63 == 0 {
+				embedding[sum%uint64(e.dimensions)]++
+			} else {
+				embedding[sum%uint64(e.dimensions)]--
+			}
+		}
+		Normalize(embedding)
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+// words splits a text into its lowercase words
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,157 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+)
+
+const (
+	// DefaultBatchSize is the number of texts sent per embedding request
+	DefaultBatchSize = 32
+
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// HTTPConfig configures an HTTPEmbedder
+type HTTPConfig struct {
+	BaseURL    string        // e.g. http://localhost:8081, without the /v1/embeddings path
+	Model      string        // model name sent with each request
+	APIKey     string        // optional bearer token
+	Dimensions int           // length of the embeddings the model returns
+	BatchSize  int           // texts per request
+	Timeout    time.Duration // timeout of each request
+}
+
+// HTTPEmbedder requests embeddings from a server with the OpenAI compatible
+// /v1/embeddings API, such as llama.cpp, Ollama, vLLM or Hugging Face
+// text-embeddings-inference running locally
+type HTTPEmbedder struct {
+	config     HTTPConfig
+	httpClient *http.Client
+}
+
+// NewHTTPEmbedder creates an embedder for the server at config.BaseURL
+func NewHTTPEmbedder(config HTTPConfig) (*HTTPEmbedder, error) {
+	if config.BaseURL == "" {
+		return nil, errors.NewValidationError("embedding server URL is required", "base_url", config.BaseURL)
+	}
+	if config.Dimensions <= 0 {
+		return nil, errors.NewValidationError("embedding dimensions must be positive", "dimensions", config.Dimensions)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHTTPTimeout
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &HTTPEmbedder{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Dimensions returns the length of the embeddings
+func (e *HTTPEmbedder) Dimensions() int {
+	return e.config.Dimensions
+}
+
+// embeddingRequest is the body of an embedding request
+type embeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the body of an embedding response
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns the normalized embedding of each text, requested in batches
+// of config.BatchSize texts
+func (e *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.config.BatchSize {
+		end := min(start+e.config.BatchSize, len(texts))
+		batch, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+// embedBatch requests the embeddings of one batch of texts
+func (e *HTTPEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingRequest{Model: e.config.Model, Input: texts})
+	if err != nil {
+		return nil, errors.NewSerializationError("failed to encode embedding request", texts)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.BaseURL+"/v1/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.NewNetworkError("embedding request failed", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.NewNetworkError("failed to read embedding response", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, errors.NewNetworkError(fmt.Sprintf("embedding server unavailable: %d", resp.StatusCode), nil)
+	default:
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("embedding server error: %d %s", resp.StatusCode, strings.TrimSpace(string(respBody))),
+			"status",
+			resp.StatusCode,
+		)
+	}
+
+	var parsed embeddingResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, errors.NewSerializationError("failed to decode embedding response", string(respBody))
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, errors.NewInternalError(fmt.Sprintf("embedding server returned %d embeddings for %d texts", len(parsed.Data), len(texts)), nil)
+	}
+
+	// Servers may return the embeddings out of order
+	embeddings := make([][]float32, len(texts))
+	for _, data := range parsed.Data {
+		if data.Index < 0 || data.Index >= len(texts) || embeddings[data.Index] != nil {
+			return nil, errors.NewInternalError(fmt.Sprintf("embedding server returned an unexpected index %d", data.Index), nil)
+		}
+		if len(data.Embedding) != e.config.Dimensions {
+			return nil, errors.NewInternalError(fmt.Sprintf("embedding server returned %d dimensions, expected %d", len(data.Embedding), e.config.Dimensions), nil)
+		}
+		Normalize(data.Embedding)
+		embeddings[data.Index] = data.Embedding
+	}
+	return embeddings, nil
+}
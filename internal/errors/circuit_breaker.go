@@ -64,6 +64,12 @@ type CircuitBreaker struct {
 	stateChanged time.Time
 	logger       *slog.Logger
 	
+	// Half-open trial requests and their successes, and whether the circuit
+	// was opened by hand and stays open until reset
+	halfOpenRequests  int
+	halfOpenSuccesses int
+	forcedOpen        bool
+	
 	// Callbacks
 	onStateChange func(from, to CircuitBreakerState)
 }
@@ -111,23 +117,88 @@ func (cb *CircuitBreaker) Execute(fn func() error) error {
 	return err
 }
 
-// Allow checks if requests should be allowed through
+// ExecuteIgnoring works like Execute, but errors for which ignore returns
+// true are returned without being recorded as a success or a failure, and
+// free the half-open trial they took
+func (cb *CircuitBreaker) ExecuteIgnoring(fn func() error, ignore func(error) bool) error {
+	if !cb.Allow() {
+		cb.recordCircuitOpen()
+		return NewCircuitBreakerError(cb.config.Name)
+	}
+	
+	start := time.Now()
+	err := fn()
+	duration := time.Since(start)
+	
+	if err != nil && ignore(err) {
+		cb.release()
+		return err
+	}
+	cb.Record(err == nil, duration)
+	return err
+}
+
+// release returns a half-open trial request that was not recorded
+func (cb *CircuitBreaker) release() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	
+	if cb.state == StateHalfOpen && cb.halfOpenRequests > 0 {
+		cb.halfOpenRequests--
+	}
+}
+
+// Allow checks if requests should be allowed through. An open circuit turns
+// half-open once its timeout passed and lets MaxRequests trial requests through.
 func (cb *CircuitBreaker) Allow() bool {
-	cb.mutex.RLock()
-	defer cb.mutex.RUnlock()
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
 	
 	switch cb.state {
 	case StateClosed:
 		return true
 	case StateOpen:
-		return cb.shouldAttemptReset()
+		if cb.forcedOpen || !cb.shouldAttemptReset() {
+			return false
+		}
+		cb.setState(StateHalfOpen)
+		cb.halfOpenRequests++
+		return true
 	case StateHalfOpen:
-		return cb.canProcessHalfOpenRequest()
+		if !cb.canProcessHalfOpenRequest() {
+			return false
+		}
+		cb.halfOpenRequests++
+		return true
 	default:
 		return false
 	}
 }
 
+// ForceOpen opens the circuit until Reset is called
+func (cb *CircuitBreaker) ForceOpen() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	
+	cb.forcedOpen = true
+	if cb.state != StateOpen {
+		cb.setState(StateOpen)
+	}
+}
+
+// Reset closes the circuit and forgets the failures recorded so far
+func (cb *CircuitBreaker) Reset() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	
+	cb.forcedOpen = false
+	cb.failures = NewRollingWindow(cb.config.SlidingWindow)
+	cb.metrics.CurrentFailureRate = 0
+	if cb.state != StateClosed {
+		cb.setState(StateClosed)
+	}
+}
+
 // Record records the result of a request
 func (cb *CircuitBreaker) Record(success bool, duration time.Duration) {
 	cb.mutex.Lock()
@@ -172,14 +243,16 @@ func (cb *CircuitBreaker) shouldAttemptReset() bool {
 
 // canProcessHalfOpenRequest checks if we can process requests in half-open state
 func (cb *CircuitBreaker) canProcessHalfOpenRequest() bool {
-	return cb.metrics.TotalRequests < int64(cb.config.MaxRequests)
+	return cb.halfOpenRequests < max(cb.config.MaxRequests, 1)
 }
 
 // onSuccess handles successful requests
 func (cb *CircuitBreaker) onSuccess() {
 	if cb.state == StateHalfOpen {
-		successCount := cb.failures.GetSuccessCount()
-		if successCount >= cb.config.SuccessThreshold {
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses >= cb.config.SuccessThreshold {
+			// Failures from before the circuit opened no longer count
+			cb.failures = NewRollingWindow(cb.config.SlidingWindow)
 			cb.setState(StateClosed)
 		}
 	}
@@ -226,6 +299,8 @@ func (cb *CircuitBreaker) setState(newState CircuitBreakerState) {
 	cb.state = newState
 	cb.stateChanged = time.Now()
 	cb.metrics.StateChanges++
+	cb.halfOpenRequests = 0
+	cb.halfOpenSuccesses = 0
 	
 	if cb.onStateChange != nil {
 		cb.onStateChange(oldState, newState)
@@ -252,8 +327,11 @@ func (cb *CircuitBreaker) GetState() CircuitBreakerState {
 	return cb.state
 }
 
-// SetOnStateChange sets the state change callback
+// SetOnStateChange sets the state change callback. The callback runs while
+// the breaker is locked and must not call back into it.
 func (cb *CircuitBreaker) SetOnStateChange(callback func(from, to CircuitBreakerState)) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
 	cb.onStateChange = callback
 }
 
@@ -292,8 +370,8 @@ func (rw *RollingWindow) Record(isFailure bool) {
 
 // GetFailureCount returns the number of failures in the current window
 func (rw *RollingWindow) GetFailureCount() int {
-	rw.mutex.RLock()
-	defer rw.mutex.RUnlock()
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
 	
 	rw.evictOldBuckets(time.Now())
 	
@@ -306,8 +384,8 @@ func (rw *RollingWindow) GetFailureCount() int {
 
 // GetTotalCount returns the total number of requests in the current window
 func (rw *RollingWindow) GetTotalCount() int {
-	rw.mutex.RLock()
-	defer rw.mutex.RUnlock()
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
 	
 	rw.evictOldBuckets(time.Now())
 	
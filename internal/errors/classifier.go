@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strings"
 )
@@ -360,11 +361,25 @@ func IsValidationError(err error) bool {
 		return false
 	}
 	
-	if sciErr, ok := err.(*SciFindError); ok {
+	// Validation errors are often wrapped with request context
+	var sciErr *SciFindError
+	if stderrors.As(err, &sciErr) {
 		return sciErr.Type == ErrorTypeValidation
 	}
 	
 	classifier := NewErrorClassifier()
 	classifiedErr := classifier.Classify(err)
 	return classifiedErr.Type == ErrorTypeValidation
-}
\ No newline at end of file
+}
+
+// IsUnsupportedQueryError checks if an error reports a query feature a provider cannot translate
+func IsUnsupportedQueryError(err error) bool {
+	var sciErr *SciFindError
+	return stderrors.As(err, &sciErr) && sciErr.Code == "UNSUPPORTED_QUERY"
+}
+
+// IsCircuitOpenError checks if an error reports a call rejected by an open circuit breaker
+func IsCircuitOpenError(err error) bool {
+	var sciErr *SciFindError
+	return stderrors.As(err, &sciErr) && sciErr.Type == ErrorTypeCircuitBreaker
+}
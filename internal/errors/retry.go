@@ -77,6 +77,10 @@ func (re *RetryExecutor) Execute(ctx context.Context, operation string, fn func(
 		classifiedErr := re.classifier.Classify(err)
 		
 		if !re.shouldRetry(classifiedErr, attempts) {
+			if attempts == 1 {
+				// Errors that are not retried are returned unchanged
+				return err
+			}
 			break
 		}
 		
@@ -148,6 +152,10 @@ func (re *RetryExecutor) ExecuteWithCallback(ctx context.Context, operation stri
 		classifiedErr := re.classifier.Classify(err)
 		
 		if !re.shouldRetry(classifiedErr, attempts) {
+			if attempts == 1 {
+				// Errors that are not retried are returned unchanged
+				return err
+			}
 			break
 		}
 		
@@ -374,6 +382,10 @@ func (re *RetryExecutor) executeWithCustomRetryLogic(ctx context.Context, operat
 		lastErr = err
 		
 		if !shouldRetry(err) {
+			if attempts == 1 {
+				// Errors that are not retried are returned unchanged
+				return err
+			}
 			break
 		}
 		
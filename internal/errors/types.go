@@ -356,3 +356,14 @@ func NewHealthCheckError(message string, component string) *SciFindError {
 		WithStatusCode(http.StatusServiceUnavailable).
 		Build()
 }
+
+// NewUnsupportedQueryError creates an error for a query feature a provider cannot translate
+func NewUnsupportedQueryError(provider string, feature string) *SciFindError {
+	return NewError(ErrorTypePermanent, "UNSUPPORTED_QUERY", fmt.Sprintf("%s does not support %s", provider, feature)).
+		WithComponent(fmt.Sprintf("%s_provider", provider)).
+		WithDetail("provider", provider).
+		WithDetail("feature", feature).
+		WithStatusCode(http.StatusUnprocessableEntity).
+		Retryable(false).
+		Build()
+}
@@ -124,6 +124,7 @@ type SearchParams struct {
 	Filters   map[string]string `json:"filters,omitempty"`
 	Limit     int               `json:"limit,omitempty"`
 	Offset    int               `json:"offset,omitempty"`
+	Cursor    string            `json:"cursor,omitempty"`
 	SortBy    string            `json:"sort_by,omitempty"`
 	SortOrder string            `json:"sort_order,omitempty"`
 }
@@ -153,6 +154,7 @@ func (s *MCPServer) handleSearch(ctx context.Context, params json.RawMessage) (i
 		Filters:   searchParams.Filters,
 		Limit:     searchParams.Limit,
 		Offset:    searchParams.Offset,
+		Cursor:    searchParams.Cursor,
 	}
 
 	// Execute search
@@ -282,7 +284,7 @@ func getSearchRequestSchema() interface{} {
 				"description": "List of search providers to use",
 				"items": map[string]interface{}{
 					"type": "string",
-					"enum": []string{"arxiv", "semantic_scholar", "exa", "tavily"},
+					"enum": []string{"arxiv", "semantic_scholar", "exa", "tavily", "crossref", "openalex", "pubmed", "local"},
 				},
 			},
 			"filters": map[string]interface{}{
@@ -302,6 +304,10 @@ func getSearchRequestSchema() interface{} {
 				"minimum":     0,
 				"default":     0,
 			},
+			"cursor": map[string]interface{}{
+				"type":        "string",
+				"description": "next_cursor of the previous page, continues each provider where it left off",
+			},
 		},
 		"required": []string{"query"},
 	}
@@ -323,6 +329,14 @@ func getSearchResponseSchema() interface{} {
 				"type":        "string",
 				"description": "Search duration",
 			},
+			"has_more": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Whether a following page exists",
+			},
+			"next_cursor": map[string]interface{}{
+				"type":        "string",
+				"description": "Opaque token to pass as cursor for the following page",
+			},
 			"providers": map[string]interface{}{
 				"type":        "object",
 				"description": "Provider-specific results",
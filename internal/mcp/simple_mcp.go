@@ -52,7 +52,10 @@ func (s *SimpleMCPServer) registerSimpleTools() {
 	// Simple search tool
 	searchTool := mcp.NewTool("search",
 		mcp.WithDescription("Search scientific papers"),
-		mcp.WithString("query", mcp.Required()),
+		mcp.WithString("query", mcp.Required(),
+			mcp.Description("Search query; supports title:, author:, abstract:, year:2019..2022, cat:, AND/OR/NOT, phrases and grouping")),
+		mcp.WithString("cursor",
+			mcp.Description("next_cursor of a previous search with the same query, returns the following page")),
 	)
 	s.server.AddTool(searchTool, s.handleSearch)
 
@@ -63,7 +66,23 @@ func (s *SimpleMCPServer) registerSimpleTools() {
 	)
 	s.server.AddTool(getPaperTool, s.handleGetPaper)
 
-	s.logger.Info("Registered 2 MCP tools: search, get_paper")
+	// Query suggestion tool
+	suggestTool := mcp.NewTool("suggest",
+		mcp.WithDescription("Suggest search queries, authors, journals and categories for a prefix"),
+		mcp.WithString("prefix", mcp.Required()),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of suggestions (default 10, max 50)")),
+	)
+	s.server.AddTool(suggestTool, s.handleSuggest)
+
+	// Similar papers tool
+	similarTool := mcp.NewTool("similar_papers",
+		mcp.WithDescription("Recommend stored papers similar to a paper by content, co-citation, shared references and shared authors, with the reasons for each"),
+		mcp.WithString("id", mcp.Required(), mcp.Description("ID of the paper to find similar papers for")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of papers (default 10, max 100)")),
+	)
+	s.server.AddTool(similarTool, s.handleSimilarPapers)
+
+	s.logger.Info("Registered 4 MCP tools: search, get_paper, suggest, similar_papers")
 }
 
 // handleSearch processes search requests
@@ -86,6 +105,9 @@ func (s *SimpleMCPServer) handleSearch(ctx context.Context, request mcp.CallTool
 		Limit:  10, // Keep it simple
 		Offset: 0,
 	}
+	if cursor, ok := argsMap["cursor"].(string); ok {
+		searchReq.Cursor = cursor
+	}
 
 	// Execute search
 	result, err := s.searchService.Search(ctx, searchReq)
@@ -133,6 +155,72 @@ func (s *SimpleMCPServer) handleGetPaper(ctx context.Context, request mcp.CallTo
 	return mcp.NewToolResultText(string(resultJSON)), nil
 }
 
+// handleSuggest processes suggestion requests
+func (s *SimpleMCPServer) handleSuggest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Extract arguments safely
+	argsMap, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("invalid arguments format"), nil
+	}
+
+	// Get prefix parameter
+	prefix, ok := argsMap["prefix"].(string)
+	if !ok || prefix == "" {
+		return mcp.NewToolResultError("prefix parameter required"), nil
+	}
+
+	limit := 0
+	if value, ok := argsMap["limit"].(float64); ok {
+		limit = int(value)
+	}
+
+	suggestions, err := s.searchService.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.Error("MCP suggest failed", slog.String("error", err.Error()))
+		return mcp.NewToolResultError(fmt.Sprintf("suggest failed: %v", err)), nil
+	}
+
+	// Return JSON result
+	resultJSON, _ := json.Marshal(suggestions)
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleSimilarPapers processes similar papers requests
+func (s *SimpleMCPServer) handleSimilarPapers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Extract arguments safely
+	argsMap, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("invalid arguments format"), nil
+	}
+
+	// Get ID parameter
+	paperID, ok := argsMap["id"].(string)
+	if !ok || paperID == "" {
+		return mcp.NewToolResultError("id parameter required"), nil
+	}
+
+	limit := 0
+	if value, ok := argsMap["limit"].(float64); ok {
+		limit = int(value)
+	}
+
+	similar, err := s.paperService.GetSimilar(ctx, paperID, limit)
+	if err != nil {
+		s.logger.Error("MCP similar papers failed",
+			slog.String("paper_id", paperID),
+			slog.String("error", err.Error()))
+		return mcp.NewToolResultError(fmt.Sprintf("similar papers failed: %v", err)), nil
+	}
+
+	s.logger.Info("MCP similar papers completed",
+		slog.String("paper_id", paperID),
+		slog.Int("results", len(similar)))
+
+	// Return JSON result
+	resultJSON, _ := json.Marshal(similar)
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
 // ServeStdio starts the MCP server via stdio
 func (s *SimpleMCPServer) ServeStdio() error {
 	s.logger.Info("Starting simple MCP server via stdio")
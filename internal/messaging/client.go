@@ -172,6 +172,24 @@ func (c *Client) GetStreamInfo(streamName string) (*jetstream.StreamInfo, error)
 	return info, nil
 }
 
+// KeyValue returns the JetStream key-value bucket with the given name,
+// creating it with the given TTL if it does not exist
+func (c *Client) KeyValue(ctx context.Context, bucket string, ttl time.Duration) (jetstream.KeyValue, error) {
+	if c.js == nil {
+		return nil, fmt.Errorf("JetStream context is nil")
+	}
+
+	kv, err := c.js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket: bucket,
+		TTL:    ttl,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key-value bucket %s: %w", bucket, err)
+	}
+
+	return kv, nil
+}
+
 // Close closes the NATS connection
 func (c *Client) Close() error {
 	if c.conn != nil {
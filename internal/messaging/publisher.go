@@ -294,6 +294,26 @@ func (p *EventPublisher) PublishIndexingStarted(ctx context.Context, provider st
 	return nil
 }
 
+// PublishIndexingProgress publishes an indexing progress event
+func (p *EventPublisher) PublishIndexingProgress(ctx context.Context, provider string, papersIndexed int, metadata map[string]interface{}) error {
+	event := map[string]interface{}{
+		"provider":       provider,
+		"papers_indexed": papersIndexed,
+		"timestamp":      currentTimestamp(),
+		"metadata":       metadata,
+	}
+	
+	if err := p.client.PublishAsync(ctx, SubjectIndexingProgress, event); err != nil {
+		return fmt.Errorf("failed to publish indexing progress event: %w", err)
+	}
+	
+	p.logger.Debug("Published indexing progress event",
+		slog.String("provider", provider),
+		slog.Int("papers_indexed", papersIndexed))
+	
+	return nil
+}
+
 // PublishIndexingCompleted publishes an indexing completed event
 func (p *EventPublisher) PublishIndexingCompleted(ctx context.Context, provider string, papersIndexed int, duration time.Duration, metadata map[string]interface{}) error {
 	event := map[string]interface{}{
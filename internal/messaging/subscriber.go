@@ -296,6 +296,22 @@ func (s *EventSubscriber) OnIndexingStarted(ctx context.Context, handler func(pr
 	})
 }
 
+// OnIndexingProgress registers a handler for indexing progress events
+func (s *EventSubscriber) OnIndexingProgress(ctx context.Context, handler func(provider string, papersIndexed int, metadata map[string]interface{}) error) error {
+	return s.Subscribe(ctx, SubjectIndexingProgress, func(ctx context.Context, msg *Message) error {
+		var event map[string]interface{}
+		if err := msg.Unmarshal(&event); err != nil {
+			return errors.NewSerializationError("unmarshal_indexing_progress", err)
+		}
+		
+		provider, _ := event["provider"].(string)
+		papersIndexed, _ := event["papers_indexed"].(float64) // JSON numbers are float64
+		metadata, _ := event["metadata"].(map[string]interface{})
+		
+		return handler(provider, int(papersIndexed), metadata)
+	})
+}
+
 // OnIndexingCompleted registers a handler for indexing completed events
 func (s *EventSubscriber) OnIndexingCompleted(ctx context.Context, handler func(provider string, papersIndexed int, durationMs int64, metadata map[string]interface{}) error) error {
 	return s.Subscribe(ctx, SubjectIndexingCompleted, func(ctx context.Context, msg *Message) error {
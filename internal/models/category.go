@@ -19,7 +19,7 @@ type Category struct {
 	Children []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
 	
 	// Classification metadata
-	Source      string `json:"source" gorm:"type:varchar(100);not null" validate:"required,oneof=arxiv acm ieee manual"`
+	Source      string `json:"source" gorm:"type:varchar(100);not null" validate:"required,oneof=arxiv acm ieee mesh manual"`
 	SourceCode  string `json:"source_code" gorm:"type:varchar(100);not null" validate:"required"`
 	IsActive    bool   `json:"is_active" gorm:"default:true;index"`
 	
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// PaperCitation is an edge of the citation graph: the citing paper cites the
+// cited paper. Each end is the ID of a stored paper when resolved, otherwise
+// the normalized external ID the provider named it by, until a paper known
+// by that ID is stored.
+type PaperCitation struct {
+	CitingID         string    `json:"citing_id" gorm:"primaryKey;type:varchar(255)"`
+	CitedID          string    `json:"cited_id" gorm:"primaryKey;type:varchar(255);index"`
+	CitingResolved   bool      `json:"citing_resolved" gorm:"not null;default:false"`
+	CitedResolved    bool      `json:"cited_resolved" gorm:"not null;default:false"`
+	CitingExternalID string    `json:"citing_external_id,omitempty" gorm:"type:varchar(255)"` // as named by the provider
+	CitedExternalID  string    `json:"cited_external_id,omitempty" gorm:"type:varchar(255)"`
+	Source           string    `json:"source" gorm:"type:varchar(100)"` // provider the edge came from
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for GORM
+func (PaperCitation) TableName() string {
+	return "paper_citations"
+}
+
+// NormalizeCitationID normalizes an external paper ID for comparison, as
+// DOIs are case insensitive
+func NormalizeCitationID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
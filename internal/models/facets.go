@@ -0,0 +1,157 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Facet names accepted as facet selections
+const (
+	FacetYear     = "year"
+	FacetCategory = "category"
+	FacetJournal  = "journal"
+	FacetAuthor   = "author"
+	FacetLanguage = "language"
+	FacetProvider = "provider"
+)
+
+// FacetNames lists all supported facets
+var FacetNames = []string{FacetYear, FacetCategory, FacetJournal, FacetAuthor, FacetLanguage, FacetProvider}
+
+// IsValidFacet returns true if name is a supported facet
+func IsValidFacet(name string) bool {
+	for _, facet := range FacetNames {
+		if facet == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSearchFacets counts the facet values of a result set
+func NewSearchFacets(papers []Paper) *SearchFacets {
+	facets := &SearchFacets{
+		Categories: make(map[string]int),
+		Authors:    make(map[string]int),
+		Journals:   make(map[string]int),
+		Years:      make(map[string]int),
+		Languages:  make(map[string]int),
+		Providers:  make(map[string]int),
+	}
+
+	for i := range papers {
+		paper := &papers[i]
+		for _, facet := range FacetNames {
+			counts := facets.counts(facet)
+			for _, value := range paper.FacetValues(facet) {
+				counts[value]++
+			}
+		}
+	}
+
+	return facets
+}
+
+// counts returns the count map of a facet
+func (f *SearchFacets) counts(facet string) map[string]int {
+	switch facet {
+	case FacetYear:
+		return f.Years
+	case FacetCategory:
+		return f.Categories
+	case FacetJournal:
+		return f.Journals
+	case FacetAuthor:
+		return f.Authors
+	case FacetLanguage:
+		return f.Languages
+	case FacetProvider:
+		return f.Providers
+	default:
+		return nil
+	}
+}
+
+// FacetValues returns the distinct values of a facet for the paper
+func (p *Paper) FacetValues(facet string) []string {
+	var values []string
+	switch facet {
+	case FacetYear:
+		if p.PublishedAt != nil {
+			values = append(values, strconv.Itoa(p.PublishedAt.Year()))
+		}
+	case FacetCategory:
+		for _, category := range p.Categories {
+			if category.SourceCode != "" {
+				values = append(values, category.SourceCode)
+			} else if category.Name != "" {
+				values = append(values, category.Name)
+			}
+		}
+	case FacetJournal:
+		if p.Journal != nil && strings.TrimSpace(*p.Journal) != "" {
+			values = append(values, strings.TrimSpace(*p.Journal))
+		}
+	case FacetAuthor:
+		for _, author := range p.Authors {
+			if name := strings.TrimSpace(author.Name); name != "" {
+				values = append(values, name)
+			}
+		}
+	case FacetLanguage:
+		if p.Language != "" {
+			values = append(values, p.Language)
+		}
+	case FacetProvider:
+		for _, provider := range p.GetSourceProviders() {
+			if provider != "" {
+				values = append(values, provider)
+			}
+		}
+	}
+
+	return uniqueStrings(values)
+}
+
+// MatchesFacets returns true if the paper matches the facet selections. A
+// paper matches a facet when it has any of the selected values, and must
+// match every selected facet.
+func (p *Paper) MatchesFacets(selections map[string][]string) bool {
+	for facet, selected := range selections {
+		if len(selected) == 0 {
+			continue
+		}
+		if !containsFold(p.FacetValues(facet), selected) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether any value equals any candidate, ignoring case
+func containsFold(values, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if strings.EqualFold(value, strings.TrimSpace(candidate)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// uniqueStrings removes duplicates while preserving order
+func uniqueStrings(values []string) []string {
+	if len(values) < 2 {
+		return values
+	}
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -12,6 +12,8 @@ type Paper struct {
 	ID      string  `json:"id" gorm:"primaryKey;type:varchar(50)" validate:"required"`
 	DOI     *string `json:"doi,omitempty" gorm:"uniqueIndex;type:varchar(255)" validate:"omitempty,doi"`
 	ArxivID *string `json:"arxiv_id,omitempty" gorm:"uniqueIndex;type:varchar(50)" validate:"omitempty,arxiv_id"`
+	PMID    *string `json:"pmid,omitempty" gorm:"uniqueIndex;type:varchar(20)" validate:"omitempty,numeric"`
+	PMCID   *string `json:"pmcid,omitempty" gorm:"uniqueIndex;type:varchar(20)" validate:"omitempty,startswith=PMC"`
 
 	// Core metadata
 	Title    string   `json:"title" gorm:"type:text;not null" validate:"required,min=1,max=1000"`
@@ -46,11 +48,14 @@ type Paper struct {
 	// Indexing and search
 	SearchVector *string   `json:"-" gorm:"type:text"` // SQLite compatible - no tsvector or gin index
 	Embedding    []float32 `json:"-" gorm:"serializer:json"`
+	Snippet      string    `json:"snippet,omitempty" gorm:"->;-:migration"` // matched text with <mark> highlights, set by text searches
+	Score        *float64  `json:"score,omitempty" gorm:"->;-:migration"`   // relevance to the query, set by ranked text and vector searches
 
 	// Source tracking
-	SourceProvider string  `json:"source_provider" gorm:"type:varchar(100);not null;index" validate:"required,oneof=arxiv semantic_scholar exa tavily manual"`
-	SourceID       string  `json:"source_id" gorm:"type:varchar(255);not null;index" validate:"required"`
-	SourceURL      *string `json:"source_url,omitempty" gorm:"type:varchar(2048)" validate:"omitempty,url"`
+	SourceProvider string        `json:"source_provider" gorm:"type:varchar(100);not null;index" validate:"required,oneof=arxiv semantic_scholar exa tavily crossref openalex pubmed manual"`
+	SourceID       string        `json:"source_id" gorm:"type:varchar(255);not null;index" validate:"required"`
+	SourceURL      *string       `json:"source_url,omitempty" gorm:"type:varchar(2048)" validate:"omitempty,url"`
+	Sources        []PaperSource `json:"sources,omitempty" gorm:"serializer:json"`
 
 	// Quality and processing
 	QualityScore    float64 `json:"quality_score" gorm:"default:0;index" validate:"min=0,max=1"`
@@ -124,6 +129,25 @@ func (p *Paper) GetCategoryNames() []string {
 	return names
 }
 
+// GetIdentifiers returns the IDs other papers may refer to this paper by:
+// the ID, source ID, DOI, arXiv ID, PMID and PMCID, without duplicates
+func (p *Paper) GetIdentifiers() []string {
+	var identifiers []string
+	for _, id := range []*string{&p.ID, &p.SourceID, p.DOI, p.ArxivID, p.PMID, p.PMCID} {
+		if id == nil || *id == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range identifiers {
+			duplicate = duplicate || existing == *id
+		}
+		if !duplicate {
+			identifiers = append(identifiers, *id)
+		}
+	}
+	return identifiers
+}
+
 // IsCompleted returns true if processing is completed
 func (p *Paper) IsCompleted() bool {
 	return p.ProcessingState == "completed"
@@ -236,11 +260,44 @@ func (p *Paper) UpdateQualityScore() {
 	p.QualityScore = score
 }
 
+// AddSource records a provider the paper was retrieved from if not already present
+func (p *Paper) AddSource(source PaperSource) {
+	for _, existing := range p.Sources {
+		if existing.Provider == source.Provider && existing.SourceID == source.SourceID {
+			return
+		}
+	}
+	p.Sources = append(p.Sources, source)
+}
+
+// GetSourceProviders returns the names of all providers that supplied the paper
+func (p *Paper) GetSourceProviders() []string {
+	if len(p.Sources) == 0 {
+		return []string{p.SourceProvider}
+	}
+	providers := make([]string, 0, len(p.Sources))
+	seen := make(map[string]bool)
+	for _, source := range p.Sources {
+		if !seen[source.Provider] {
+			seen[source.Provider] = true
+			providers = append(providers, source.Provider)
+		}
+	}
+	return providers
+}
+
 // generatePaperID generates a unique paper ID
 func generatePaperID(provider, sourceID string) string {
 	return provider + "_" + sourceID
 }
 
+// PaperSource records the provenance of a paper record from a single provider
+type PaperSource struct {
+	Provider  string  `json:"provider"`
+	SourceID  string  `json:"source_id"`
+	SourceURL *string `json:"source_url,omitempty"`
+}
+
 // PaperFilter represents filters for paper queries
 type PaperFilter struct {
 	IDs            []string   `json:"ids,omitempty"`
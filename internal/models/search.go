@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"scifind-backend/internal/errors"
@@ -9,7 +10,7 @@ import (
 // SearchRequest represents a search query request
 type SearchRequest struct {
 	Query       string            `json:"query" validate:"required,min=1,max=1000"`
-	Providers   []string          `json:"providers" validate:"omitempty,dive,oneof=arxiv semantic_scholar exa tavily"`
+	Providers   []string          `json:"providers" validate:"omitempty,dive,oneof=arxiv semantic_scholar exa tavily crossref openalex pubmed local"`
 	Categories  []string          `json:"categories" validate:"omitempty,dive,min=1,max=100"`
 	DateRange   *DateRange        `json:"date_range,omitempty" validate:"omitempty"`
 	Language    string            `json:"language" validate:"omitempty,len=2"`
@@ -116,6 +117,14 @@ type SearchSuggestion struct {
 	Type  string  `json:"type"` // query, author, journal, category
 }
 
+// Search suggestion types
+const (
+	SuggestionTypeQuery    = "query"
+	SuggestionTypeAuthor   = "author"
+	SuggestionTypeJournal  = "journal"
+	SuggestionTypeCategory = "category"
+)
+
 // DefaultSearchRequest returns a default search request
 func DefaultSearchRequest() SearchRequest {
 	return SearchRequest{
@@ -220,15 +229,23 @@ func generateSearchQueryID() string {
 
 // SearchHistory represents a stored search query
 type SearchHistory struct {
-	ID          string    `json:"id" gorm:"primaryKey;type:varchar(50)"`
-	Query       string    `json:"query" gorm:"type:text;not null"`
-	UserID      *string   `json:"user_id,omitempty" gorm:"type:varchar(50);index"`
-	ResultCount int       `json:"result_count" gorm:"default:0"`
-	Duration    int64     `json:"duration"` // milliseconds
-	Providers   []string  `json:"providers" gorm:"serializer:json"`
-	Filters     string    `json:"filters" gorm:"type:text"` // SQLite compatible - no jsonb
-	RequestedAt time.Time `json:"requested_at" gorm:"index"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(50)"`
+	Query           string    `json:"query" gorm:"type:text;not null"`
+	UserID          *string   `json:"user_id,omitempty" gorm:"type:varchar(50);index"`
+	ResultCount     int       `json:"result_count" gorm:"default:0"`
+	Duration        int64     `json:"duration"` // milliseconds
+	Providers       []string  `json:"providers" gorm:"serializer:json"`
+	ProvidersFailed []string  `json:"providers_failed,omitempty" gorm:"serializer:json"`
+	Filters         string    `json:"filters" gorm:"type:text"` // SQLite compatible - no jsonb
+	CacheHit        bool      `json:"cache_hit" gorm:"default:false"`
+	Error           string    `json:"error,omitempty" gorm:"type:text"` // empty for successful searches
+	RequestedAt     time.Time `json:"requested_at" gorm:"index"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Succeeded returns true if the search completed without error
+func (sh *SearchHistory) Succeeded() bool {
+	return sh.Error == ""
 }
 
 // TableName returns the table name for GORM
@@ -266,6 +283,48 @@ func (sc *SearchCache) IncrementAccess() {
 	sc.LastAccess = time.Now()
 }
 
+// QuerySuggestion stores the suggestion weight of a past search query
+type QuerySuggestion struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(64)"` // hash of the normalized query
+	Query           string    `json:"query" gorm:"type:text;not null"`
+	Weight          float64   `json:"weight" gorm:"default:0;index"`
+	SearchCount     int64     `json:"search_count" gorm:"default:0"`
+	LastResultCount int       `json:"last_result_count" gorm:"default:0"`
+	LastSearchedAt  time.Time `json:"last_searched_at" gorm:"index"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for GORM
+func (QuerySuggestion) TableName() string {
+	return "query_suggestions"
+}
+
+// suggestionHalfLife is the time after which a query's suggestion weight halves
+const suggestionHalfLife = 7 * 24 * time.Hour
+
+// RecordSearch decays the weight for the time since the last search and
+// boosts it for a search that returned results. Searches without results
+// only update the counters so dead-end queries are not suggested.
+func (qs *QuerySuggestion) RecordSearch(resultCount int, at time.Time) {
+	qs.Weight = qs.DecayedWeight(at)
+	if resultCount > 0 {
+		qs.Weight++
+	}
+	qs.SearchCount++
+	qs.LastResultCount = resultCount
+	qs.LastSearchedAt = at
+}
+
+// DecayedWeight returns the weight decayed for the time since the last search
+func (qs *QuerySuggestion) DecayedWeight(at time.Time) float64 {
+	if qs.LastSearchedAt.IsZero() || !at.After(qs.LastSearchedAt) {
+		return qs.Weight
+	}
+	elapsed := at.Sub(qs.LastSearchedAt)
+	return qs.Weight * math.Pow(0.5, float64(elapsed)/float64(suggestionHalfLife))
+}
+
 // generateRandomString generates a random string of specified length
 func generateRandomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
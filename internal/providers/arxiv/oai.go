@@ -0,0 +1,378 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+const (
+	// DefaultOAIBaseURL is the arXiv OAI-PMH endpoint
+	DefaultOAIBaseURL = "https://oaipmh.arxiv.org/oai"
+
+	oaiMetadataPrefix   = "arXiv"
+	oaiDateFormat       = "2006-01-02"
+	oaiRequestsPerMin   = 20 // arXiv asks bulk clients to wait 3 seconds between requests
+	oaiDefaultRetries   = 3
+	oaiMaxRetryAfter    = 10 * time.Minute
+	oaiHarvesterLimiter = "arxiv_oai"
+)
+
+// OAI-PMH error codes
+const (
+	oaiErrorNoRecords      = "noRecordsMatch"
+	oaiErrorBadToken       = "badResumptionToken"
+	oaiRecordStatusDeleted = "deleted"
+)
+
+// oaiSetPattern matches arXiv set specs such as cs or physics:hep-th
+var oaiSetPattern = regexp.MustCompile(`^[a-z-]+(:[a-zA-Z.-]+)*$`)
+
+// OAIResponse represents an OAI-PMH ListRecords response
+type OAIResponse struct {
+	XMLName     xml.Name       `xml:"OAI-PMH"`
+	Errors      []OAIError     `xml:"error"`
+	ListRecords OAIListRecords `xml:"ListRecords"`
+}
+
+// OAIError is an OAI-PMH protocol error
+type OAIError struct {
+	Code    string `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+// OAIListRecords holds one page of harvested records
+type OAIListRecords struct {
+	Records         []OAIRecord        `xml:"record"`
+	ResumptionToken OAIResumptionToken `xml:"resumptionToken"`
+}
+
+// OAIResumptionToken continues a harvest. An empty token marks the last page.
+type OAIResumptionToken struct {
+	Token            string `xml:",chardata"`
+	Cursor           int    `xml:"cursor,attr"`
+	CompleteListSize int    `xml:"completeListSize,attr"`
+}
+
+// OAIRecord is a harvested record
+type OAIRecord struct {
+	Header   OAIHeader      `xml:"header"`
+	Metadata OAIArxivRecord `xml:"metadata>arXiv"`
+}
+
+// OAIHeader identifies a record. Deleted records carry status="deleted".
+type OAIHeader struct {
+	Status     string   `xml:"status,attr"`
+	Identifier string   `xml:"identifier"`
+	Datestamp  string   `xml:"datestamp"`
+	SetSpecs   []string `xml:"setSpec"`
+}
+
+// OAIArxivRecord is the arXiv metadata format of a record
+type OAIArxivRecord struct {
+	ID         string           `xml:"id"`
+	Created    string           `xml:"created"`
+	Updated    string           `xml:"updated"`
+	Authors    []OAIArxivAuthor `xml:"authors>author"`
+	Title      string           `xml:"title"`
+	Categories string           `xml:"categories"`
+	Comments   string           `xml:"comments"`
+	JournalRef string           `xml:"journal-ref"`
+	DOI        string           `xml:"doi"`
+	License    string           `xml:"license"`
+	Abstract   string           `xml:"abstract"`
+}
+
+// OAIArxivAuthor is an author of an arXiv record
+type OAIArxivAuthor struct {
+	KeyName      string   `xml:"keyname"`
+	ForeNames    string   `xml:"forenames"`
+	Suffix       string   `xml:"suffix"`
+	Affiliations []string `xml:"affiliation"`
+}
+
+// HarvestRequest selects the records to harvest. A resumption token
+// continues an earlier harvest and takes precedence over the other fields.
+type HarvestRequest struct {
+	Set             string
+	From            *time.Time
+	Until           *time.Time
+	ResumptionToken string
+}
+
+// HarvestPage is one page of harvested papers
+type HarvestPage struct {
+	Papers           []models.Paper
+	Deleted          []string // arXiv IDs withdrawn from the archive
+	ResumptionToken  string   // empty on the last page
+	Cursor           int
+	CompleteListSize int
+}
+
+// Harvester walks the arXiv OAI-PMH ListRecords endpoint for bulk ingestion
+type Harvester struct {
+	config     providers.ProviderConfig
+	httpClient *http.Client
+	logger     *slog.Logger
+	limiter    *providers.TokenBucketLimiter
+}
+
+// NewHarvester creates a new OAI-PMH harvester. Without explicit rate limits
+// requests are spaced three seconds apart as arXiv asks of bulk clients.
+func NewHarvester(config providers.ProviderConfig, logger *slog.Logger) *Harvester {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultOAIBaseURL
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = oaiDefaultRetries
+	}
+
+	limits := config.RateLimit
+	if limits.RequestsPerSecond <= 0 && limits.RequestsPerMinute <= 0 && limits.RequestsPerHour <= 0 {
+		limits = providers.RateLimitConfig{RequestsPerMinute: oaiRequestsPerMin, BurstSize: 1}
+	}
+	limiter := providers.NewTokenBucketLimiter(logger)
+	_ = limiter.UpdateLimits(oaiHarvesterLimiter, limits)
+
+	return &Harvester{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		logger:     logger,
+		limiter:    limiter,
+	}
+}
+
+// ListRecords fetches one page of records
+func (h *Harvester) ListRecords(ctx context.Context, req HarvestRequest) (*HarvestPage, error) {
+	params := url.Values{}
+	params.Set("verb", "ListRecords")
+	if req.ResumptionToken != "" {
+		params.Set("resumptionToken", req.ResumptionToken)
+	} else {
+		params.Set("metadataPrefix", oaiMetadataPrefix)
+		if req.Set != "" {
+			if !oaiSetPattern.MatchString(req.Set) {
+				return nil, errors.NewValidationError("invalid arXiv set, expected e.g. cs or physics:hep-th", "set", req.Set)
+			}
+			params.Set("set", req.Set)
+		}
+		if req.From != nil {
+			params.Set("from", req.From.UTC().Format(oaiDateFormat))
+		}
+		if req.Until != nil {
+			params.Set("until", req.Until.UTC().Format(oaiDateFormat))
+		}
+	}
+
+	body, err := h.makeRequest(ctx, h.config.BaseURL+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var response OAIResponse
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse ArXiv OAI-PMH response: %w", err)
+	}
+
+	for _, oaiErr := range response.Errors {
+		switch oaiErr.Code {
+		case oaiErrorNoRecords:
+			return &HarvestPage{}, nil
+		case oaiErrorBadToken:
+			return nil, errors.NewValidationError("ArXiv OAI-PMH resumption token expired or invalid", "resumption_token", req.ResumptionToken)
+		default:
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("ArXiv OAI-PMH error %s: %s", oaiErr.Code, strings.TrimSpace(oaiErr.Message)),
+				"request",
+				oaiErr.Code,
+			)
+		}
+	}
+
+	token := response.ListRecords.ResumptionToken
+	page := &HarvestPage{
+		Papers:           make([]models.Paper, 0, len(response.ListRecords.Records)),
+		ResumptionToken:  strings.TrimSpace(token.Token),
+		Cursor:           token.Cursor,
+		CompleteListSize: token.CompleteListSize,
+	}
+
+	for _, record := range response.ListRecords.Records {
+		if record.Header.Status == oaiRecordStatusDeleted {
+			page.Deleted = append(page.Deleted, oaiArxivID(record.Header.Identifier))
+			continue
+		}
+		paper, err := convertOAIRecord(record.Metadata)
+		if err != nil {
+			h.logger.Warn("Failed to convert ArXiv OAI-PMH record",
+				slog.String("identifier", record.Header.Identifier),
+				slog.String("error", err.Error()))
+			continue
+		}
+		page.Papers = append(page.Papers, *paper)
+	}
+
+	return page, nil
+}
+
+// makeRequest makes an OAI-PMH request. arXiv answers 503 with Retry-After
+// for flow control, those requests are retried after the requested delay.
+func (h *Harvester) makeRequest(ctx context.Context, reqURL string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if err := h.limiter.Wait(ctx, oaiHarvesterLimiter); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("User-Agent", "SciFIND-Backend/1.0")
+
+		resp, err := h.httpClient.Do(req)
+		if err != nil {
+			return nil, errors.NewNetworkError("ArXiv OAI-PMH request failed", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return body, nil
+		case resp.StatusCode == http.StatusServiceUnavailable && attempt < h.config.MaxRetries:
+			retryAfter := oaiRetryAfter(resp.Header.Get("Retry-After"))
+			h.logger.Info("ArXiv OAI-PMH asked to retry later",
+				slog.Duration("retry_after", retryAfter),
+				slog.Int("attempt", attempt+1))
+			select {
+			case <-time.After(retryAfter):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		case resp.StatusCode == http.StatusServiceUnavailable:
+			return nil, errors.NewNetworkError(
+				fmt.Sprintf("ArXiv OAI-PMH unavailable after %d retries", h.config.MaxRetries),
+				nil,
+			)
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("ArXiv OAI-PMH client error: %d %s", resp.StatusCode, strings.TrimSpace(string(body))),
+				"status",
+				resp.StatusCode,
+			)
+		default:
+			return nil, errors.NewInternalError(
+				fmt.Sprintf("ArXiv OAI-PMH returned unexpected status %d", resp.StatusCode),
+				nil,
+			)
+		}
+	}
+}
+
+// oaiRetryAfter parses a Retry-After header in seconds, capping the delay
+func oaiRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 10 * time.Second
+	}
+	return min(time.Duration(seconds)*time.Second, oaiMaxRetryAfter)
+}
+
+// oaiArxivID extracts the arXiv ID from an identifier like oai:arXiv.org:1706.03762
+func oaiArxivID(identifier string) string {
+	return strings.TrimPrefix(strings.TrimSpace(identifier), "oai:arXiv.org:")
+}
+
+// convertOAIRecord converts an arXiv metadata record to our Paper model
+func convertOAIRecord(record OAIArxivRecord) (*models.Paper, error) {
+	arxivID := strings.TrimSpace(record.ID)
+	if arxivID == "" {
+		return nil, fmt.Errorf("record ID is required")
+	}
+	title := strings.Join(strings.Fields(record.Title), " ")
+	if title == "" {
+		return nil, fmt.Errorf("record title is required")
+	}
+
+	authors := make([]models.Author, 0, len(record.Authors))
+	for _, oaiAuthor := range record.Authors {
+		name := strings.Join(strings.Fields(oaiAuthor.ForeNames+" "+oaiAuthor.KeyName+" "+oaiAuthor.Suffix), " ")
+		if name == "" {
+			continue
+		}
+		author := models.Author{Name: name}
+		if len(oaiAuthor.Affiliations) > 0 {
+			if affiliation := strings.TrimSpace(oaiAuthor.Affiliations[0]); affiliation != "" {
+				author.Affiliation = &affiliation
+			}
+		}
+		authors = append(authors, author)
+	}
+
+	terms := strings.Fields(record.Categories)
+	categories := make([]models.Category, 0, len(terms))
+	for _, term := range terms {
+		categories = append(categories, models.Category{
+			ID:         "arxiv_" + term,
+			Name:       categoryName(term),
+			Source:     "arxiv",
+			SourceCode: term,
+			IsActive:   true,
+		})
+	}
+
+	// The created date is the submission date of the first version
+	var publishedAt *time.Time
+	if created, err := time.Parse(oaiDateFormat, strings.TrimSpace(record.Created)); err == nil {
+		publishedAt = &created
+	}
+
+	absURL := "https://arxiv.org/abs/" + arxivID
+	pdfURL := "https://arxiv.org/pdf/" + arxivID
+	paper := &models.Paper{
+		ID:              "arxiv_" + arxivID,
+		ArxivID:         &arxivID,
+		Title:           title,
+		Authors:         authors,
+		Categories:      categories,
+		PublishedAt:     publishedAt,
+		URL:             &absURL,
+		PDFURL:          &pdfURL,
+		Language:        "en",
+		SourceProvider:  providerName,
+		SourceID:        arxivID,
+		SourceURL:       &absURL,
+		ProcessingState: "completed",
+	}
+
+	if abstract := strings.TrimSpace(record.Abstract); abstract != "" {
+		paper.Abstract = &abstract
+	}
+	// Records occasionally list several DOIs separated by spaces
+	if dois := strings.Fields(record.DOI); len(dois) > 0 {
+		doi := strings.ToLower(dois[0])
+		paper.DOI = &doi
+	}
+	if journalRef := strings.Join(strings.Fields(record.JournalRef), " "); journalRef != "" {
+		paper.Journal = &journalRef
+	}
+
+	paper.UpdateQualityScore()
+
+	return paper, nil
+}
@@ -76,12 +76,15 @@ func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
 
 		MaxResults:     maxResults,
 		MaxQueryLength: 1000,
-		RateLimit:      180, // 3 requests per second, be conservative
+		RateLimit:      20, // one request every 3 seconds per arXiv API terms
 
 		SupportsRealtime:    true,
 		SupportsExactMatch:  true,
 		SupportsFuzzySearch: false,
 		SupportsWildcards:   false,
+
+		QueryFields:          []string{"title", "abstract", "author", "year", "cat"},
+		SupportsBooleanQuery: true,
 	}
 }
 
@@ -261,9 +264,16 @@ func (p *Provider) buildQuery(query *providers.SearchQuery) (string, error) {
 	var parts []string
 
 	// Main search query
-	if query.Query != "" {
-		// Search in title and abstract by default
-		parts = append(parts, fmt.Sprintf("(ti:\"%s\" OR abs:\"%s\")", query.Query, query.Query))
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return "", err
+	}
+	if parsed != nil {
+		translated, err := translateQuery(parsed)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, translated)
 	}
 
 	// Author filter
@@ -409,7 +419,7 @@ func (p *Provider) convertEntry(entry ArxivEntry) (*models.Paper, error) {
 	for _, catData := range entry.Categories {
 		category := models.Category{
 			ID:         "arxiv_" + catData.Term,
-			Name:       p.getCategoryName(catData.Term),
+			Name:       categoryName(catData.Term),
 			Source:     "arxiv",
 			SourceCode: catData.Term,
 			IsActive:   true,
@@ -519,7 +529,8 @@ func extractArxivID(entryID string) string {
 	return id
 }
 
-func (p *Provider) getCategoryName(term string) string {
+// categoryName returns the readable name of an ArXiv category code
+func categoryName(term string) string {
 	// Map ArXiv category codes to readable names
 	categoryNames := map[string]string{
 		"cs.AI":            "Artificial Intelligence",
@@ -0,0 +1,97 @@
+package arxiv
+
+import (
+	"fmt"
+	"strings"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// queryFieldPrefixes maps grammar fields to ArXiv search prefixes
+var queryFieldPrefixes = map[string]string{
+	providers.QueryFieldTitle:    "ti",
+	providers.QueryFieldAbstract: "abs",
+	providers.QueryFieldAuthor:   "au",
+	providers.QueryFieldCategory: "cat",
+}
+
+// translateQuery converts a query AST to ArXiv search_query syntax. ArXiv
+// only knows the binary ANDNOT, so negated terms must sit in a conjunction
+// with at least one positive term.
+func translateQuery(node *providers.QueryNode) (string, error) {
+	switch node.Op {
+	case providers.QueryTerm:
+		return translateTerm(node), nil
+	case providers.QueryOr:
+		parts := make([]string, 0, len(node.Children))
+		for _, child := range node.Children {
+			if child.Op == providers.QueryNot {
+				return "", errors.NewUnsupportedQueryError(providerName, "NOT inside OR")
+			}
+			part, err := translateQuery(child)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "(" + strings.Join(parts, " OR ") + ")", nil
+	case providers.QueryAnd:
+		var positive, negative []string
+		for _, child := range node.Children {
+			target := &positive
+			if child.Op == providers.QueryNot {
+				target = &negative
+				child = child.Children[0]
+				if child.Op == providers.QueryNot {
+					return "", errors.NewUnsupportedQueryError(providerName, "nested NOT")
+				}
+			}
+			part, err := translateQuery(child)
+			if err != nil {
+				return "", err
+			}
+			*target = append(*target, part)
+		}
+		if len(positive) == 0 {
+			return "", errors.NewUnsupportedQueryError(providerName, "NOT without a positive term")
+		}
+
+		translated := strings.Join(positive, " AND ")
+		for _, part := range negative {
+			translated += " ANDNOT " + part
+		}
+		return "(" + translated + ")", nil
+	default:
+		// A NOT that is not part of a conjunction, the query or a NOT itself
+		if node.Children[0].Op == providers.QueryNot {
+			return "", errors.NewUnsupportedQueryError(providerName, "nested NOT")
+		}
+		return "", errors.NewUnsupportedQueryError(providerName, "NOT without a positive term")
+	}
+}
+
+// translateTerm converts a single term. Unfielded terms search title and
+// abstract, as plain queries always did.
+func translateTerm(term *providers.QueryNode) string {
+	if term.Field == providers.QueryFieldYear {
+		from, to := "*", "*"
+		if term.YearFrom != 0 {
+			from = fmt.Sprintf("%04d0101", term.YearFrom)
+		}
+		if term.YearTo != 0 {
+			to = fmt.Sprintf("%04d1231", term.YearTo)
+		}
+		return fmt.Sprintf("submittedDate:[%s TO %s]", from, to)
+	}
+
+	value := term.Value
+	if term.Phrase {
+		value = fmt.Sprintf("\"%s\"", term.Value)
+	}
+
+	if prefix, ok := queryFieldPrefixes[term.Field]; ok {
+		return prefix + ":" + value
+	}
+	return fmt.Sprintf("(ti:%s OR abs:%s)", value, value)
+}
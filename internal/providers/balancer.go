@@ -0,0 +1,220 @@
+package providers
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"scifind-backend/internal/errors"
+)
+
+// loadBalancer keeps the selection state used by the round-robin and
+// weighted-random strategies across requests
+type loadBalancer struct {
+	mu           sync.Mutex
+	lastSelected string
+	weights      map[string]float64
+	rng          *rand.Rand
+}
+
+func newLoadBalancer(weights map[string]float64) *loadBalancer {
+	copied := make(map[string]float64, len(weights))
+	for name, weight := range weights {
+		copied[name] = weight
+	}
+	return &loadBalancer{
+		weights: copied,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// setWeight sets the selection weight of a provider; zero or less resets it
+func (lb *loadBalancer) setWeight(name string, weight float64) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if weight <= 0 {
+		delete(lb.weights, name)
+		return
+	}
+	lb.weights[name] = weight
+}
+
+// roundRobinOrder returns the providers in the order they should be tried,
+// starting with the provider after the one selected by the previous request.
+// The first provider is reserved for this request, so concurrent requests
+// start from different providers.
+func (lb *loadBalancer) roundRobinOrder(providers []SearchProvider) []SearchProvider {
+	sorted := sortProvidersByName(providers)
+	if len(sorted) == 0 {
+		return sorted
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	// Wraps around to the first provider when none sorts after the last selection
+	start := 0
+	for i, provider := range sorted {
+		if provider.Name() > lb.lastSelected {
+			start = i
+			break
+		}
+	}
+	lb.lastSelected = sorted[start].Name()
+
+	return append(sorted[start:], sorted[:start]...)
+}
+
+// weightedOrder returns the providers ordered by weighted random sampling
+// without replacement. Providers without a weight default to 1.
+func (lb *loadBalancer) weightedOrder(providers []SearchProvider) []SearchProvider {
+	remaining := sortProvidersByName(providers)
+	ordered := make([]SearchProvider, 0, len(remaining))
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	for len(remaining) > 0 {
+		total := 0.0
+		for _, provider := range remaining {
+			total += lb.weightOf(provider.Name())
+		}
+
+		target := lb.rng.Float64() * total
+		idx := len(remaining) - 1
+		for i, provider := range remaining {
+			target -= lb.weightOf(provider.Name())
+			if target < 0 {
+				idx = i
+				break
+			}
+		}
+
+		ordered = append(ordered, remaining[idx])
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+	}
+
+	return ordered
+}
+
+func (lb *loadBalancer) weightOf(name string) float64 {
+	if weight, ok := lb.weights[name]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// searchRoundRobin cycles through providers, remembering the last selection
+// across requests
+func (m *Manager) searchRoundRobin(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
+	available, skipped := m.availableProviders(providers)
+	return m.searchBalanced(ctx, query, m.balancer.roundRobinOrder(available), skipped, StrategyRoundRobin)
+}
+
+// searchWeightedRandom picks a provider at random proportionally to its
+// selection weight, falling back to the others on failure
+func (m *Manager) searchWeightedRandom(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
+	available, skipped := m.availableProviders(providers)
+	return m.searchBalanced(ctx, query, m.balancer.weightedOrder(available), skipped, StrategyWeightedRandom)
+}
+
+// searchBalanced tries providers in the given order until one succeeds
+func (m *Manager) searchBalanced(ctx context.Context, query *SearchQuery, ordered []SearchProvider, skipped map[string]string, strategy AggregationStrategy) (*AggregatedResult, error) {
+	if len(ordered) == 0 {
+		return nil, errors.NewValidationError("No available providers", "providers", skipped)
+	}
+
+	var failed []string
+	var providerErrors []ProviderError
+	for _, provider := range ordered {
+		result, err := m.searchProvider(ctx, provider, query)
+		if err != nil {
+			m.logger.Debug("Provider search failed, trying next",
+				slog.String("strategy", string(strategy)),
+				slog.String("provider", provider.Name()),
+				slog.String("error", err.Error()))
+			failed = append(failed, provider.Name())
+			providerErrors = append(providerErrors, ProviderError{
+				Provider:  provider.Name(),
+				Error:     err,
+				Type:      classifyError(err),
+				Retryable: isRetryableError(err),
+			})
+			continue
+		}
+
+		aggregated := m.wrapSingleResult(result, provider.Name(), query)
+		aggregated.AggregationStrategy = string(strategy)
+		aggregated.RequestedProviders = append(getProviderNames(ordered), sortedKeys(skipped)...)
+		aggregated.FailedProviders = failed
+		aggregated.Errors = providerErrors
+		aggregated.PartialFailure = len(failed) > 0
+		aggregated.StrategyMetadata = map[string]interface{}{
+			"selected_provider": provider.Name(),
+			"candidates":        getProviderNames(ordered),
+			"skipped_providers": skipped,
+			"attempts":          len(failed) + 1,
+		}
+
+		return aggregated, nil
+	}
+
+	return nil, errors.NewInternalError("All providers failed", providerErrors[len(providerErrors)-1].Error)
+}
+
+// availableProviders filters out providers whose circuit is open or that are
+// currently rate limited. Skipped providers are returned with the reason.
+func (m *Manager) availableProviders(providers []SearchProvider) ([]SearchProvider, map[string]string) {
+	available := make([]SearchProvider, 0, len(providers))
+	skipped := make(map[string]string)
+	now := time.Now()
+
+	for _, provider := range providers {
+		status := provider.GetStatus()
+		switch {
+		case CircuitState(status.CircuitState) == CircuitOpen:
+			skipped[provider.Name()] = "circuit_open"
+		case m.circuitBreaker != nil && m.circuitBreaker.GetState(provider.Name()) == CircuitOpen:
+			skipped[provider.Name()] = "circuit_open"
+		case status.RateLimited && (status.ResetTime == nil || status.ResetTime.After(now)):
+			skipped[provider.Name()] = "rate_limited"
+		case m.isThrottled(provider.Name()):
+			skipped[provider.Name()] = "rate_limited"
+		default:
+			available = append(available, provider)
+		}
+	}
+
+	return available, skipped
+}
+
+// isThrottled reports whether the rate limiter has no tokens left for the provider
+func (m *Manager) isThrottled(name string) bool {
+	if m.rateLimit == nil {
+		return false
+	}
+	limits := m.rateLimit.GetLimits(name)
+	return limits.Limit > 0 && limits.Remaining == 0
+}
+
+func sortProvidersByName(providers []SearchProvider) []SearchProvider {
+	sorted := make([]SearchProvider, len(providers))
+	copy(sorted, providers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,369 @@
+package providers
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"scifind-backend/internal/errors"
+)
+
+const (
+	// cacheKeyPrefix prefixes all search result keys
+	cacheKeyPrefix = "search."
+
+	// DefaultCacheTTL is used when no TTL is configured
+	DefaultCacheTTL = time.Hour
+
+	// DefaultCacheMaxSize bounds the in-memory fallback cache
+	DefaultCacheMaxSize = 1000
+)
+
+// CacheKey returns the cache key of a query for a provider. Queries that only
+// differ in case, whitespace, filter or list ordering share the same key.
+func CacheKey(query *SearchQuery, provider string) string {
+	var b strings.Builder
+
+	b.WriteString(strings.Join(strings.Fields(strings.ToLower(query.Query)), " "))
+	fmt.Fprintf(&b, "|limit=%d|offset=%d", query.Limit, query.Offset)
+	if query.Cursor != "" {
+		fmt.Fprintf(&b, "|cursor=%s", query.Cursor)
+	}
+	fmt.Fprintf(&b, "|sort=%s:%s", strings.ToLower(query.SortBy), strings.ToLower(query.SortOrder))
+	fmt.Fprintf(&b, "|lang=%s|fulltext=%t", strings.ToLower(query.Language), query.IncludeFullText)
+	if query.DateFrom != nil {
+		fmt.Fprintf(&b, "|from=%s", query.DateFrom.UTC().Format("2006-01-02"))
+	}
+	if query.DateTo != nil {
+		fmt.Fprintf(&b, "|to=%s", query.DateTo.UTC().Format("2006-01-02"))
+	}
+	fmt.Fprintf(&b, "|categories=%s", normalizedList(query.Categories))
+	fmt.Fprintf(&b, "|authors=%s", normalizedList(query.Authors))
+
+	filterKeys := make([]string, 0, len(query.Filters))
+	for key := range query.Filters {
+		filterKeys = append(filterKeys, key)
+	}
+	sort.Strings(filterKeys)
+	for _, key := range filterKeys {
+		fmt.Fprintf(&b, "|f:%s=%s", strings.ToLower(key), strings.ToLower(strings.TrimSpace(query.Filters[key])))
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return cacheKeyPrefix + provider + "." + hex.EncodeToString(sum[:16])
+}
+
+func normalizedList(values []string) string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, ",")
+}
+
+// KVCacheManager implements CacheManager on a JetStream KV bucket. When the
+// bucket is unavailable, results are kept in an in-memory LRU cache instead.
+type KVCacheManager struct {
+	kv       jetstream.KeyValue
+	fallback *lruCache
+	ttl      time.Duration
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	hits   int64
+	misses int64
+}
+
+// NewCacheManager creates a search result cache. kv may be nil, in which case
+// only the in-memory cache is used.
+func NewCacheManager(kv jetstream.KeyValue, config CacheConfig, logger *slog.Logger) *KVCacheManager {
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultCacheMaxSize
+	}
+
+	return &KVCacheManager{
+		kv:       kv,
+		fallback: newLRUCache(maxSize),
+		ttl:      ttl,
+		logger:   logger,
+	}
+}
+
+// Get returns the cached result for key, or a not found error on a miss
+func (c *KVCacheManager) Get(ctx context.Context, key string) (*CachedResult, error) {
+	data, ok := c.load(ctx, key)
+	if !ok {
+		c.recordLookup(false)
+		return nil, errors.NewNotFoundError("cache entry", key)
+	}
+
+	var cached CachedResult
+	if err := json.Unmarshal(data, &cached); err != nil {
+		c.recordLookup(false)
+		return nil, errors.NewSerializationError("failed to decode cached result", key)
+	}
+	if time.Now().After(cached.ExpiresAt) {
+		c.recordLookup(false)
+		_ = c.Delete(ctx, key)
+		return nil, errors.NewNotFoundError("cache entry", key)
+	}
+
+	c.recordLookup(true)
+	cached.HitCount = c.fallback.touch(key)
+	return &cached, nil
+}
+
+// Set stores a successful search result under key
+func (c *KVCacheManager) Set(ctx context.Context, key string, result *SearchResult, ttl time.Duration) error {
+	if result == nil {
+		return errors.NewValidationError("Cannot cache nil result", "result", nil)
+	}
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
+	stored := *result
+	stored.Error = nil
+	stored.CacheHit = false
+
+	now := time.Now()
+	data, err := json.Marshal(CachedResult{
+		Result:    &stored,
+		CachedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return errors.NewSerializationError("failed to encode search result", key)
+	}
+
+	// The in-memory copy keeps lookups working during a NATS outage
+	c.fallback.set(key, data)
+
+	if c.kv != nil {
+		if _, err := c.kv.Put(ctx, key, data); err != nil {
+			c.logger.Warn("Cache store unavailable, using in-memory cache",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// Delete removes a single key
+func (c *KVCacheManager) Delete(ctx context.Context, key string) error {
+	c.fallback.delete(key)
+	if c.kv != nil {
+		if err := c.kv.Delete(ctx, key); err != nil && !stderrors.Is(err, jetstream.ErrKeyNotFound) {
+			return errors.NewMessagingError("failed to delete cache entry", map[string]interface{}{"key": key, "error": err.Error()})
+		}
+	}
+	return nil
+}
+
+// Clear removes all keys matching a glob pattern such as "search.arxiv.*".
+// An empty pattern clears the whole cache.
+func (c *KVCacheManager) Clear(ctx context.Context, pattern string) error {
+	if pattern == "" {
+		pattern = "*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errors.NewValidationError("Invalid cache key pattern", "pattern", pattern)
+	}
+
+	removed := c.fallback.deleteMatching(pattern)
+	purged := 0
+
+	if c.kv != nil {
+		keys, err := c.kv.Keys(ctx)
+		if err != nil && !stderrors.Is(err, jetstream.ErrNoKeysFound) {
+			return errors.NewMessagingError("failed to list cache keys", map[string]interface{}{"pattern": pattern, "error": err.Error()})
+		}
+		for _, key := range keys {
+			if matched, _ := path.Match(pattern, key); !matched {
+				continue
+			}
+			if err := c.kv.Purge(ctx, key); err != nil {
+				return errors.NewMessagingError("failed to purge cache entry", map[string]interface{}{"key": key, "error": err.Error()})
+			}
+			purged++
+		}
+	}
+
+	c.logger.Info("Cache cleared",
+		slog.String("pattern", pattern),
+		slog.Int("memory_entries", removed),
+		slog.Int("kv_entries", purged))
+	return nil
+}
+
+// GetStats returns cache performance statistics
+func (c *KVCacheManager) GetStats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Size:      c.fallback.len(),
+		MaxSize:   c.fallback.maxSize,
+		Evictions: c.fallback.evictionCount(),
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// load reads the raw entry from the KV bucket, falling back to memory
+func (c *KVCacheManager) load(ctx context.Context, key string) ([]byte, bool) {
+	if c.kv != nil {
+		entry, err := c.kv.Get(ctx, key)
+		switch {
+		case err == nil:
+			return entry.Value(), true
+		case stderrors.Is(err, jetstream.ErrKeyNotFound), stderrors.Is(err, jetstream.ErrKeyDeleted):
+			return nil, false
+		default:
+			c.logger.Debug("Cache store unavailable, reading in-memory cache",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+		}
+	}
+	return c.fallback.get(key)
+}
+
+func (c *KVCacheManager) recordLookup(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+// lruCache is a size bounded in-memory cache of serialized entries
+type lruCache struct {
+	mu        sync.Mutex
+	maxSize   int
+	entries   map[string]*list.Element
+	order     *list.List
+	evictions int64
+}
+
+type lruEntry struct {
+	key  string
+	data []byte
+	hits int
+}
+
+func newLRUCache(maxSize int) *lruCache {
+	return &lruCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (l *lruCache) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruEntry).data, true
+}
+
+// touch increments and returns the hit count of key
+func (l *lruCache) touch(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return 1
+	}
+	entry := element.Value.(*lruEntry)
+	entry.hits++
+	return entry.hits
+}
+
+func (l *lruCache) set(key string, data []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*lruEntry).data = data
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, data: data})
+	for l.order.Len() > l.maxSize {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+		l.evictions++
+	}
+}
+
+func (l *lruCache) delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.order.Remove(element)
+		delete(l.entries, key)
+	}
+}
+
+func (l *lruCache) deleteMatching(pattern string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+	for key, element := range l.entries {
+		if matched, _ := path.Match(pattern, key); matched {
+			l.order.Remove(element)
+			delete(l.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (l *lruCache) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *lruCache) evictionCount() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.evictions
+}
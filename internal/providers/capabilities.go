@@ -0,0 +1,196 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+)
+
+// dateLayout compares publication dates by day, the granularity providers
+// filter dates at
+const dateLayout = "2006-01-02"
+
+// FilterSupport reports how a provider applied the filters of a query.
+// Filters the provider cannot apply itself are removed from its query and
+// applied by the manager to the papers it returns.
+type FilterSupport struct {
+	Native       []string `json:"native,omitempty"`        // applied by the provider
+	PostFiltered []string `json:"post_filtered,omitempty"` // applied by the manager
+	Dropped      int      `json:"dropped,omitempty"`       // papers removed by the post-filters
+}
+
+// NegotiateFilters checks a query against the capabilities of a provider. It
+// returns the query to send to the provider, without the filters the
+// provider does not support, with author and category filters in Filters and
+// the limit capped at its MaxResults, and how each filter is applied; the
+// support is nil when the query has no filters. Queries the provider cannot
+// run at all are rejected with an unsupported query error.
+func NegotiateFilters(provider string, caps ProviderCapabilities, query *SearchQuery) (*SearchQuery, *FilterSupport, error) {
+	if caps.MaxQueryLength > 0 && utf8.RuneCountInString(query.Query) > caps.MaxQueryLength {
+		return nil, nil, errors.NewUnsupportedQueryError(provider, fmt.Sprintf("queries longer than %d characters", caps.MaxQueryLength))
+	}
+
+	negotiated := *query
+	if caps.MaxResults > 0 && negotiated.Limit > caps.MaxResults {
+		negotiated.Limit = caps.MaxResults
+	}
+
+	var support FilterSupport
+	negotiate := func(filter string, present, native bool) bool {
+		switch {
+		case !present:
+		case native:
+			support.Native = append(support.Native, filter)
+		default:
+			support.PostFiltered = append(support.PostFiltered, filter)
+			return false
+		}
+		return true
+	}
+
+	if !negotiate(FilterDateFrom, query.DateFrom != nil, caps.SupportsDateFilter) {
+		negotiated.DateFrom = nil
+	}
+	if !negotiate(FilterDateTo, query.DateTo != nil, caps.SupportsDateFilter) {
+		negotiated.DateTo = nil
+	}
+
+	// Providers read author and category filters from Filters
+	authors := authorFilter(query)
+	if !negotiate(FilterAuthor, len(authors) > 0, caps.SupportsAuthFilter) {
+		authors = nil
+	}
+	categories := categoryFilter(query)
+	if !negotiate(FilterCategory, len(categories) > 0, caps.SupportsCategoryFilter) {
+		categories = nil
+	}
+	negotiated.Authors, negotiated.Categories = nil, nil
+	negotiated.Filters = withFilter(negotiated.Filters, FilterAuthor, authors)
+	negotiated.Filters = withFilter(negotiated.Filters, FilterCategory, categories)
+
+	if len(support.Native) == 0 && len(support.PostFiltered) == 0 {
+		return &negotiated, nil, nil
+	}
+	return &negotiated, &support, nil
+}
+
+// postFilter applies the filters the provider left to the manager to its
+// result. The provider's result is not modified.
+func postFilter(query *SearchQuery, support *FilterSupport, result *SearchResult) *SearchResult {
+	if support == nil || result == nil {
+		return result
+	}
+
+	filtered := *result
+	applied := *support
+	filtered.Filters = &applied
+	if len(support.PostFiltered) == 0 {
+		return &filtered
+	}
+
+	filtered.Papers = make([]models.Paper, 0, len(result.Papers))
+	for i := range result.Papers {
+		if matchesFilters(&result.Papers[i], query, support.PostFiltered) {
+			filtered.Papers = append(filtered.Papers, result.Papers[i])
+		}
+	}
+
+	applied.Dropped = len(result.Papers) - len(filtered.Papers)
+	filtered.ResultCount = len(filtered.Papers)
+	filtered.TotalCount = max(result.TotalCount-applied.Dropped, len(filtered.Papers))
+	return &filtered
+}
+
+// matchesFilters reports whether a paper satisfies the given query filters.
+// Papers lacking the data a filter needs, like a publication date, do not.
+func matchesFilters(paper *models.Paper, query *SearchQuery, filters []string) bool {
+	for _, filter := range filters {
+		switch filter {
+		case FilterDateFrom:
+			if paper.PublishedAt == nil || paper.PublishedAt.UTC().Format(dateLayout) < query.DateFrom.UTC().Format(dateLayout) {
+				return false
+			}
+		case FilterDateTo:
+			if paper.PublishedAt == nil || paper.PublishedAt.UTC().Format(dateLayout) > query.DateTo.UTC().Format(dateLayout) {
+				return false
+			}
+		case FilterAuthor:
+			if !matchesAuthor(paper, authorFilter(query)) {
+				return false
+			}
+		case FilterCategory:
+			if !matchesCategory(paper, categoryFilter(query)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesAuthor reports whether any author of the paper has a name
+// containing one of the given names
+func matchesAuthor(paper *models.Paper, names []string) bool {
+	for _, author := range paper.Authors {
+		authorName := strings.ToLower(author.Name)
+		for _, name := range names {
+			if strings.Contains(authorName, strings.ToLower(name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesCategory reports whether the paper is in one of the given categories
+func matchesCategory(paper *models.Paper, categories []string) bool {
+	for _, category := range paper.Categories {
+		for _, wanted := range categories {
+			if strings.EqualFold(category.SourceCode, wanted) || strings.EqualFold(category.Name, wanted) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authorFilter returns the authors a query is restricted to
+func authorFilter(query *SearchQuery) []string {
+	return append(splitFilter(query.Filters[FilterAuthor]), query.Authors...)
+}
+
+// categoryFilter returns the categories a query is restricted to
+func categoryFilter(query *SearchQuery) []string {
+	return append(splitFilter(query.Filters[FilterCategory]), query.Categories...)
+}
+
+// splitFilter splits a comma separated filter value
+func splitFilter(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// withFilter returns a copy of the filters with the given filter set to the
+// comma separated values, or removed when there are none
+func withFilter(filters map[string]string, name string, values []string) map[string]string {
+	if _, ok := filters[name]; !ok && len(values) == 0 {
+		return filters
+	}
+	updated := make(map[string]string, len(filters)+1)
+	for key, value := range filters {
+		if key != name {
+			updated[key] = value
+		}
+	}
+	if len(values) > 0 {
+		updated[name] = strings.Join(values, ",")
+	}
+	return updated
+}
@@ -0,0 +1,134 @@
+package providers
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"scifind-backend/internal/errors"
+)
+
+// ProviderBreakers implements CircuitBreaker with a rolling-window breaker
+// per provider, created on first use from a shared configuration
+type ProviderBreakers struct {
+	breakers *errors.CircuitBreakerManager
+	config   errors.CircuitBreakerConfig
+	logger   *slog.Logger
+
+	mu sync.Mutex // serializes breaker creation
+
+	countsMu sync.Mutex
+	opened   map[string]int64
+	closed   map[string]int64
+}
+
+// NewProviderBreakers creates the provider circuit breakers
+func NewProviderBreakers(config errors.CircuitBreakerConfig, logger *slog.Logger) *ProviderBreakers {
+	if config.SlidingWindow <= 0 {
+		config.SlidingWindow = time.Minute
+	}
+	return &ProviderBreakers{
+		breakers: errors.NewCircuitBreakerManager(logger),
+		config:   config,
+		logger:   logger,
+		opened:   make(map[string]int64),
+		closed:   make(map[string]int64),
+	}
+}
+
+// breaker returns the breaker of a provider, counting its transitions
+func (b *ProviderBreakers) breaker(provider string) *errors.CircuitBreaker {
+	if breaker, ok := b.breakers.Get(provider); ok {
+		return breaker
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if breaker, ok := b.breakers.Get(provider); ok {
+		return breaker
+	}
+	breaker := b.breakers.GetOrCreate(provider, b.config)
+	breaker.SetOnStateChange(func(from, to errors.CircuitBreakerState) {
+		b.countsMu.Lock()
+		defer b.countsMu.Unlock()
+		switch to {
+		case errors.StateOpen:
+			b.opened[provider]++
+		case errors.StateClosed:
+			b.closed[provider]++
+		}
+	})
+	return breaker
+}
+
+// Execute runs fn unless the provider's circuit is open. Only failures of
+// the provider count against the circuit: rejected queries, missing papers,
+// rate limits and callers giving up count neither as failures nor as
+// successes, so they cannot close a half-open circuit either.
+func (b *ProviderBreakers) Execute(ctx context.Context, provider string, fn func() error) error {
+	return b.breaker(provider).ExecuteIgnoring(fn, func(err error) bool {
+		return !isProviderFailure(err)
+	})
+}
+
+// GetState returns the circuit state of a provider
+func (b *ProviderBreakers) GetState(provider string) CircuitState {
+	breaker, ok := b.breakers.Get(provider)
+	if !ok {
+		return CircuitClosed
+	}
+	switch breaker.GetState() {
+	case errors.StateOpen:
+		return CircuitOpen
+	case errors.StateHalfOpen:
+		return CircuitHalfOpen
+	default:
+		return CircuitClosed
+	}
+}
+
+// ForceOpen opens the provider's circuit until it is reset
+func (b *ProviderBreakers) ForceOpen(provider string) {
+	b.breaker(provider).ForceOpen()
+	b.logger.Warn("Circuit forced open", slog.String("provider", provider))
+}
+
+// ForceClose closes the provider's circuit
+func (b *ProviderBreakers) ForceClose(provider string) {
+	b.breaker(provider).Reset()
+}
+
+// Reset closes the provider's circuit and forgets its recorded failures
+func (b *ProviderBreakers) Reset(provider string) {
+	b.breaker(provider).Reset()
+	b.logger.Info("Circuit reset", slog.String("provider", provider))
+}
+
+// GetTransitions returns how often the provider's circuit opened and closed
+func (b *ProviderBreakers) GetTransitions(provider string) (opened, closed int64) {
+	b.countsMu.Lock()
+	defer b.countsMu.Unlock()
+	return b.opened[provider], b.closed[provider]
+}
+
+// isProviderFailure reports whether an error says the provider is unhealthy
+func isProviderFailure(err error) bool {
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return false
+	case errors.IsUnsupportedQueryError(err), errors.IsValidationError(err), errors.IsRateLimitError(err):
+		return false
+	case isNotFound(err):
+		return false
+	default:
+		return true
+	}
+}
+
+// isNotFound reports whether an error says the requested resource is missing
+func isNotFound(err error) bool {
+	var sciErr *errors.SciFindError
+	return stderrors.As(err, &sciErr) && sciErr.Code == "NOT_FOUND"
+}
@@ -0,0 +1,604 @@
+package crossref
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+const (
+	defaultBaseURL = "https://api.crossref.org"
+	providerName   = "crossref"
+	maxResults     = 1000  // Crossref rows limit per request
+	maxOffset      = 10000 // Crossref rejects offsets beyond this, use cursors instead
+)
+
+// Provider implements the Crossref search provider
+type Provider struct {
+	config     providers.ProviderConfig
+	httpClient *http.Client
+	logger     *slog.Logger
+	metrics    *providers.ProviderMetrics
+	enabled    bool
+	mailto     string
+}
+
+// NewProvider creates a new Crossref provider. Setting Custom["mailto"] in
+// the config routes requests to the Crossref polite pool.
+func NewProvider(config providers.ProviderConfig, logger *slog.Logger) *Provider {
+	httpClient := &http.Client{
+		Timeout: config.Timeout,
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	return &Provider{
+		config:     config,
+		httpClient: httpClient,
+		logger:     logger,
+		metrics:    &providers.ProviderMetrics{},
+		enabled:    config.Enabled,
+		mailto:     configMailto(config),
+	}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// IsEnabled returns whether the provider is enabled
+func (p *Provider) IsEnabled() bool {
+	return p.enabled
+}
+
+// GetCapabilities returns provider capabilities
+func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
+	return providers.ProviderCapabilities{
+		SupportsFullText:       false,
+		SupportsDateFilter:     true,
+		SupportsAuthFilter:     true,
+		SupportsCategoryFilter: false, // Crossref subjects are too sparse to filter on
+		SupportsSort:           true,
+
+		SupportedFields:    []string{"title", "author", "journal", "doi", "year"},
+		SupportedLanguages: []string{"en"},
+		SupportedFormats:   []string{"json"},
+
+		MaxResults:     maxResults,
+		MaxQueryLength: 1000,
+		RateLimit:      3000, // 50 requests per second in the polite pool
+
+		SupportsRealtime:    true,
+		SupportsExactMatch:  false, // Crossref scores terms, phrases are not matched exactly
+		SupportsFuzzySearch: true,
+		SupportsWildcards:   false,
+
+		QueryFields:          []string{"author", "year"},
+		SupportsBooleanQuery: false,
+	}
+}
+
+// Search performs a search using the Crossref works API
+func (p *Provider) Search(ctx context.Context, query *providers.SearchQuery) (*providers.SearchResult, error) {
+	start := time.Now()
+
+	// Build request URL
+	reqURL, err := p.buildSearchURL(query)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to build Crossref query: %w", err)
+	}
+
+	// Make HTTP request
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("Crossref API request failed: %w", err)
+	}
+
+	// Parse response
+	var searchResponse CrossrefSearchResponse
+	if err := json.Unmarshal(response, &searchResponse); err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to parse Crossref response: %w", err)
+	}
+
+	papers := make([]models.Paper, 0, len(searchResponse.Message.Items))
+	for _, work := range searchResponse.Message.Items {
+		paper, err := p.convertWork(work)
+		if err != nil {
+			p.logger.Warn("Failed to convert Crossref work",
+				slog.String("doi", work.DOI),
+				slog.String("error", err.Error()))
+			continue
+		}
+		papers = append(papers, *paper)
+	}
+
+	duration := time.Since(start)
+	p.updateMetrics(true, duration, nil)
+
+	totalCount := searchResponse.Message.TotalResults
+	result := &providers.SearchResult{
+		Papers:      papers,
+		TotalCount:  totalCount,
+		ResultCount: len(papers),
+		Query:       query.Query,
+		Provider:    providerName,
+		Duration:    duration,
+		CacheHit:    false,
+		RequestID:   query.RequestID,
+		Timestamp:   time.Now(),
+		Success:     true,
+		HasMore:     query.Offset+len(searchResponse.Message.Items) < totalCount,
+	}
+	if query.Cursor != "" {
+		// Cursor pages carry no position, a short page is the last one
+		result.HasMore = len(searchResponse.Message.Items) >= pageSize(query.Limit)
+	}
+
+	// Crossref keeps returning a cursor after the last page, only pass it on while there is more
+	if result.HasMore && searchResponse.Message.NextCursor != "" {
+		nextCursor := searchResponse.Message.NextCursor
+		result.NextCursor = &nextCursor
+	}
+
+	p.logger.Debug("Crossref search completed",
+		slog.String("query", query.Query),
+		slog.Int("results", len(papers)),
+		slog.Int("total", totalCount),
+		slog.Duration("duration", duration))
+
+	return result, nil
+}
+
+// GetPaper retrieves a specific paper by DOI
+func (p *Provider) GetPaper(ctx context.Context, id string) (*models.Paper, error) {
+	start := time.Now()
+
+	doi := NormalizeDOI(id)
+	if doi == "" {
+		return nil, errors.NewValidationError("DOI is required", "id", id)
+	}
+
+	reqURL := p.withMailto(fmt.Sprintf("%s/works/%s", p.config.BaseURL, url.PathEscape(doi)), url.Values{})
+
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("Crossref API request failed: %w", err)
+	}
+
+	var workResponse CrossrefWorkResponse
+	if err := json.Unmarshal(response, &workResponse); err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to parse Crossref response: %w", err)
+	}
+
+	paper, err := p.convertWork(workResponse.Message)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to convert paper: %w", err)
+	}
+
+	p.updateMetrics(true, time.Since(start), nil)
+	return paper, nil
+}
+
+// HealthCheck checks if the Crossref API is accessible
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+
+	params := url.Values{}
+	params.Set("query", "test")
+	params.Set("rows", "1")
+	_, err := p.makeRequest(ctx, p.withMailto(p.config.BaseURL+"/works", params))
+	if err != nil {
+		return errors.NewHealthCheckError("Health check failed: "+err.Error(), providerName)
+	}
+
+	p.logger.Debug("Crossref health check passed", slog.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// GetStatus returns the current provider status
+func (p *Provider) GetStatus() providers.ProviderStatus {
+	return providers.ProviderStatus{
+		Name:            providerName,
+		Enabled:         p.enabled,
+		Healthy:         true,
+		LastCheck:       time.Now(),
+		CircuitState:    "closed",
+		RateLimited:     false,
+		AvgResponseTime: p.calculateAvgResponseTime(),
+		SuccessRate:     p.calculateSuccessRate(),
+		APIVersion:      "v1",
+		LastUpdated:     time.Now(),
+	}
+}
+
+// GetMetrics returns provider metrics
+func (p *Provider) GetMetrics() providers.ProviderMetrics {
+	return *p.metrics
+}
+
+// Configure updates the provider configuration
+func (p *Provider) Configure(config providers.ProviderConfig) error {
+	if err := p.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	p.config = config
+	p.enabled = config.Enabled
+	p.mailto = configMailto(config)
+
+	// Update HTTP client timeout
+	p.httpClient.Timeout = config.Timeout
+
+	p.logger.Info("Crossref provider configured",
+		slog.Bool("enabled", config.Enabled),
+		slog.Bool("polite_pool", p.mailto != ""),
+		slog.Duration("timeout", config.Timeout))
+
+	return nil
+}
+
+// ValidateConfig validates the provider configuration
+func (p *Provider) ValidateConfig(config providers.ProviderConfig) error {
+	if config.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if config.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
+
+	return ValidateCrossrefConfig(config.Custom)
+}
+
+// buildSearchURL builds the works search URL. The first page starts cursor
+// paging so later pages can continue from NextCursor; explicit offsets are
+// used when no cursor is given.
+func (p *Provider) buildSearchURL(query *providers.SearchQuery) (string, error) {
+	params := url.Values{}
+	params.Set("query", query.Query)
+
+	params.Set("rows", strconv.Itoa(pageSize(query.Limit)))
+
+	switch {
+	case query.Cursor != "":
+		params.Set("cursor", query.Cursor)
+	case query.Offset > maxOffset:
+		return "", errors.NewValidationError(
+			fmt.Sprintf("Crossref offsets are limited to %d, page with cursors instead", maxOffset),
+			"offset",
+			query.Offset,
+		)
+	case query.Offset > 0:
+		params.Set("offset", strconv.Itoa(query.Offset))
+	default:
+		params.Set("cursor", "*")
+	}
+
+	var filters []string
+
+	// Query grammar
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return "", err
+	}
+	if parsed != nil {
+		translated, err := translateQuery(parsed)
+		if err != nil {
+			return "", err
+		}
+		params.Set("query", translated.text)
+		if len(translated.authors) > 0 {
+			params.Set("query.author", strings.Join(translated.authors, " "))
+		}
+		filters = append(filters, translated.filters...)
+	}
+
+	// Filters
+	if authors, ok := query.Filters[providers.FilterAuthor]; ok && authors != "" {
+		params.Set("query.author", strings.TrimSpace(params.Get("query.author")+" "+strings.ReplaceAll(authors, ",", " ")))
+	}
+	if journal, ok := query.Filters[providers.FilterJournal]; ok && journal != "" {
+		params.Set("query.container-title", journal)
+	}
+	if query.DateFrom != nil {
+		filters = append(filters, "from-pub-date:"+query.DateFrom.Format("2006-01-02"))
+	}
+	if query.DateTo != nil {
+		filters = append(filters, "until-pub-date:"+query.DateTo.Format("2006-01-02"))
+	}
+	if len(filters) > 0 {
+		params.Set("filter", strings.Join(filters, ","))
+	}
+
+	// Sorting
+	switch query.SortBy {
+	case providers.SortDate:
+		params.Set("sort", SortPublished)
+	case providers.SortCitations:
+		params.Set("sort", SortCitations)
+	}
+	if params.Get("sort") != "" && (query.SortOrder == "asc" || query.SortOrder == "desc") {
+		params.Set("order", query.SortOrder)
+	}
+
+	return p.withMailto(p.config.BaseURL+"/works", params), nil
+}
+
+// withMailto adds the polite pool mailto parameter and encodes the URL
+func (p *Provider) withMailto(baseURL string, params url.Values) string {
+	if p.mailto != "" {
+		params.Set("mailto", p.mailto)
+	}
+	if len(params) == 0 {
+		return baseURL
+	}
+	return baseURL + "?" + params.Encode()
+}
+
+// makeRequest makes an HTTP request to the Crossref API
+func (p *Provider) makeRequest(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Crossref identifies polite clients by the mailto in the User-Agent as well
+	userAgent := "SciFIND-Backend/1.0"
+	if p.mailto != "" {
+		userAgent += " (mailto:" + p.mailto + ")"
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.NewNotFoundError("Work not found in Crossref", reqURL)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter := resp.Header.Get("Retry-After")
+		var retryDuration time.Duration
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			retryDuration = time.Duration(seconds) * time.Second
+		}
+		return nil, errors.NewRateLimitError(
+			fmt.Sprintf("Crossref API rate limit exceeded. Retry after: %s", retryAfter),
+			retryDuration,
+		)
+	case resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout:
+		return nil, errors.NewNetworkError(
+			fmt.Sprintf("Crossref API unavailable: %d", resp.StatusCode),
+			nil,
+		)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("Crossref API client error: %d %s", resp.StatusCode, strings.TrimSpace(string(body))),
+			"status",
+			resp.StatusCode,
+		)
+	default:
+		return nil, errors.NewInternalError(
+			fmt.Sprintf("Crossref API returned unexpected status %d", resp.StatusCode),
+			nil,
+		)
+	}
+}
+
+// convertWork converts a Crossref work to our Paper model
+func (p *Provider) convertWork(work CrossrefWork) (*models.Paper, error) {
+	if work.DOI == "" {
+		return nil, fmt.Errorf("work DOI is required")
+	}
+	if len(work.Title) == 0 || strings.TrimSpace(work.Title[0]) == "" {
+		return nil, fmt.Errorf("work title is required")
+	}
+
+	doi := strings.ToLower(work.DOI)
+
+	title := strings.TrimSpace(work.Title[0])
+	if len(work.Subtitle) > 0 && strings.TrimSpace(work.Subtitle[0]) != "" {
+		title += ": " + strings.TrimSpace(work.Subtitle[0])
+	}
+
+	// Convert authors
+	authors := make([]models.Author, 0, len(work.Author))
+	for _, authorData := range work.Author {
+		name := authorData.FullName()
+		if name == "" {
+			continue
+		}
+		author := models.Author{Name: name}
+		if orcid := NormalizeORCID(authorData.ORCID); orcid != "" {
+			author.ORCID = &orcid
+		}
+		if len(authorData.Affiliation) > 0 && authorData.Affiliation[0].Name != "" {
+			affiliation := authorData.Affiliation[0].Name
+			author.Affiliation = &affiliation
+		}
+		authors = append(authors, author)
+	}
+
+	// Publication details
+	var journal, volume, issue, pages *string
+	if len(work.ContainerTitle) > 0 && work.ContainerTitle[0] != "" {
+		journal = &work.ContainerTitle[0]
+	}
+	if work.Volume != "" {
+		volume = &work.Volume
+	}
+	if work.Issue != "" {
+		issue = &work.Issue
+	}
+	if work.Page != "" {
+		pages = &work.Page
+	}
+
+	var abstract *string
+	if cleaned := CleanAbstract(work.Abstract); cleaned != "" {
+		abstract = &cleaned
+	}
+
+	// Links
+	var paperURL, pdfURL *string
+	if work.URL != "" {
+		paperURL = &work.URL
+	}
+	for _, link := range work.Link {
+		if link.ContentType == "application/pdf" && link.URL != "" {
+			pdfURL = &link.URL
+			break
+		}
+	}
+
+	// Reference DOIs
+	var references []string
+	for _, reference := range work.Reference {
+		if reference.DOI != "" {
+			references = append(references, strings.ToLower(reference.DOI))
+		}
+	}
+
+	language := "en"
+	if len(work.Language) == 2 {
+		language = strings.ToLower(work.Language)
+	}
+
+	paper := &models.Paper{
+		ID:              paperID(doi),
+		DOI:             &doi,
+		Title:           title,
+		Abstract:        abstract,
+		Authors:         authors,
+		Journal:         journal,
+		Volume:          volume,
+		Issue:           issue,
+		Pages:           pages,
+		PublishedAt:     work.PublishedAt(),
+		URL:             paperURL,
+		PDFURL:          pdfURL,
+		Keywords:        work.Subject,
+		Language:        language,
+		CitationCount:   work.IsReferencedByCount,
+		References:      references,
+		SourceProvider:  providerName,
+		SourceID:        doi,
+		SourceURL:       paperURL,
+		ProcessingState: "completed",
+	}
+
+	// Calculate quality score
+	paper.UpdateQualityScore()
+
+	return paper, nil
+}
+
+// pageSize returns the rows to request for a query limit
+func pageSize(limit int) int {
+	if limit <= 0 || limit > maxResults {
+		return maxResults
+	}
+	return limit
+}
+
+// paperID derives the paper ID from the DOI, hashing DOIs too long for the ID column
+func paperID(doi string) string {
+	id := providerName + "_" + doi
+	if len(id) <= 50 {
+		return id
+	}
+	sum := sha256.Sum256([]byte(doi))
+	return providerName + "_" + hex.EncodeToString(sum[:16])
+}
+
+// configMailto returns the polite pool contact address from the config
+func configMailto(config providers.ProviderConfig) string {
+	mailto, _ := config.Custom["mailto"].(string)
+	return strings.TrimSpace(mailto)
+}
+
+// Helper methods
+func (p *Provider) updateMetrics(success bool, duration time.Duration, err error) {
+	p.metrics.TotalRequests++
+
+	if success {
+		p.metrics.SuccessfulRequests++
+	} else {
+		p.metrics.FailedRequests++
+
+		// Categorize errors
+		if err != nil {
+			switch {
+			case errors.IsTimeoutError(err):
+				p.metrics.TimeoutErrors++
+			case errors.IsRateLimitError(err):
+				p.metrics.RateLimitErrors++
+			case errors.IsNetworkError(err):
+				p.metrics.NetworkErrors++
+			default:
+				p.metrics.ParseErrors++
+			}
+		}
+	}
+
+	// Update response time statistics
+	if p.metrics.MinResponseTime == 0 || duration < p.metrics.MinResponseTime {
+		p.metrics.MinResponseTime = duration
+	}
+	if duration > p.metrics.MaxResponseTime {
+		p.metrics.MaxResponseTime = duration
+	}
+
+	// Simple moving average for response time
+	if p.metrics.AvgResponseTime == 0 {
+		p.metrics.AvgResponseTime = duration
+	} else {
+		p.metrics.AvgResponseTime = (p.metrics.AvgResponseTime + duration) / 2
+	}
+}
+
+func (p *Provider) calculateAvgResponseTime() time.Duration {
+	return p.metrics.AvgResponseTime
+}
+
+func (p *Provider) calculateSuccessRate() float64 {
+	if p.metrics.TotalRequests == 0 {
+		return 1.0
+	}
+	return float64(p.metrics.SuccessfulRequests) / float64(p.metrics.TotalRequests)
+}
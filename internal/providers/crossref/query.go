@@ -0,0 +1,46 @@
+package crossref
+
+import (
+	"fmt"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// searchParams holds the search text, author query and filters translated
+// from a query AST
+type searchParams struct {
+	text    string
+	authors []string
+	filters []string
+}
+
+// translateQuery converts a query AST to works search parameters. Crossref
+// ranks free text without boolean logic, so only conjunctions are supported;
+// author terms go to query.author and years to publication date filters.
+func translateQuery(node *providers.QueryNode) (*searchParams, error) {
+	terms, ok := node.Conjuncts()
+	if !ok {
+		return nil, errors.NewUnsupportedQueryError(providerName, "boolean operators")
+	}
+
+	params := &searchParams{text: providers.FreeText(terms)}
+	for _, term := range terms {
+		switch term.Field {
+		case "":
+		case providers.QueryFieldAuthor:
+			params.authors = append(params.authors, term.Value)
+		case providers.QueryFieldYear:
+			if term.YearFrom != 0 {
+				params.filters = append(params.filters, fmt.Sprintf("from-pub-date:%04d", term.YearFrom))
+			}
+			if term.YearTo != 0 {
+				params.filters = append(params.filters, fmt.Sprintf("until-pub-date:%04d", term.YearTo))
+			}
+		default:
+			return nil, errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+		}
+	}
+
+	return params, nil
+}
@@ -0,0 +1,171 @@
+package crossref
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CrossrefSearchResponse represents the response of a /works search
+type CrossrefSearchResponse struct {
+	Status      string           `json:"status"`
+	MessageType string           `json:"message-type"`
+	Message     CrossrefWorkList `json:"message"`
+}
+
+// CrossrefWorkResponse represents the response of a /works/{doi} lookup
+type CrossrefWorkResponse struct {
+	Status      string       `json:"status"`
+	MessageType string       `json:"message-type"`
+	Message     CrossrefWork `json:"message"`
+}
+
+// CrossrefWorkList is the message of a /works search response
+type CrossrefWorkList struct {
+	TotalResults int            `json:"total-results"`
+	ItemsPerPage int            `json:"items-per-page"`
+	NextCursor   string         `json:"next-cursor,omitempty"`
+	Items        []CrossrefWork `json:"items"`
+}
+
+// CrossrefWork represents a single work record
+type CrossrefWork struct {
+	DOI                 string              `json:"DOI"`
+	URL                 string              `json:"URL"`
+	Type                string              `json:"type"`
+	Title               []string            `json:"title"`
+	Subtitle            []string            `json:"subtitle,omitempty"`
+	Abstract            string              `json:"abstract,omitempty"`
+	Author              []CrossrefAuthor    `json:"author,omitempty"`
+	ContainerTitle      []string            `json:"container-title,omitempty"`
+	Publisher           string              `json:"publisher,omitempty"`
+	Volume              string              `json:"volume,omitempty"`
+	Issue               string              `json:"issue,omitempty"`
+	Page                string              `json:"page,omitempty"`
+	Language            string              `json:"language,omitempty"`
+	Subject             []string            `json:"subject,omitempty"`
+	Published           *CrossrefDate       `json:"published,omitempty"`
+	PublishedPrint      *CrossrefDate       `json:"published-print,omitempty"`
+	PublishedOnline     *CrossrefDate       `json:"published-online,omitempty"`
+	Issued              *CrossrefDate       `json:"issued,omitempty"`
+	IsReferencedByCount int                 `json:"is-referenced-by-count"`
+	ReferenceCount      int                 `json:"reference-count"`
+	Reference           []CrossrefReference `json:"reference,omitempty"`
+	Link                []CrossrefLink      `json:"link,omitempty"`
+}
+
+// CrossrefAuthor represents a contributor of a work
+type CrossrefAuthor struct {
+	Given       string                `json:"given,omitempty"`
+	Family      string                `json:"family,omitempty"`
+	Name        string                `json:"name,omitempty"` // organizations
+	ORCID       string                `json:"ORCID,omitempty"`
+	Sequence    string                `json:"sequence,omitempty"`
+	Affiliation []CrossrefAffiliation `json:"affiliation,omitempty"`
+}
+
+// CrossrefAffiliation represents an author affiliation
+type CrossrefAffiliation struct {
+	Name string `json:"name"`
+}
+
+// CrossrefDate holds a partial date as [[year, month, day]]
+type CrossrefDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// CrossrefReference represents an entry of a work's reference list
+type CrossrefReference struct {
+	Key          string `json:"key"`
+	DOI          string `json:"DOI,omitempty"`
+	Unstructured string `json:"unstructured,omitempty"`
+}
+
+// CrossrefLink represents a full-text link of a work
+type CrossrefLink struct {
+	URL                 string `json:"URL"`
+	ContentType         string `json:"content-type"`
+	IntendedApplication string `json:"intended-application,omitempty"`
+}
+
+// Time returns the date, filling missing month and day with 1
+func (d *CrossrefDate) Time() *time.Time {
+	if d == nil || len(d.DateParts) == 0 || len(d.DateParts[0]) == 0 || d.DateParts[0][0] == 0 {
+		return nil
+	}
+
+	parts := d.DateParts[0]
+	month, day := 1, 1
+	if len(parts) > 1 && parts[1] >= 1 && parts[1] <= 12 {
+		month = parts[1]
+	}
+	if len(parts) > 2 && parts[2] >= 1 && parts[2] <= 31 {
+		day = parts[2]
+	}
+
+	date := time.Date(parts[0], time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+// PublishedAt returns the earliest known publication date of the work
+func (w *CrossrefWork) PublishedAt() *time.Time {
+	for _, date := range []*CrossrefDate{w.Published, w.PublishedPrint, w.PublishedOnline, w.Issued} {
+		if t := date.Time(); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// FullName returns the display name of an author
+func (a CrossrefAuthor) FullName() string {
+	if a.Name != "" {
+		return strings.TrimSpace(a.Name)
+	}
+	return strings.TrimSpace(strings.TrimSpace(a.Given) + " " + strings.TrimSpace(a.Family))
+}
+
+// Sort fields accepted by the works endpoint
+const (
+	SortRelevance = "relevance"
+	SortPublished = "published"
+	SortCitations = "is-referenced-by-count"
+)
+
+// jatsTagPattern matches the JATS XML tags Crossref abstracts are wrapped in
+var jatsTagPattern = regexp.MustCompile(`<[^>]+>`)
+
+// CleanAbstract strips JATS markup and collapses whitespace
+func CleanAbstract(abstract string) string {
+	text := jatsTagPattern.ReplaceAllString(abstract, " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// NormalizeDOI strips resolver URLs and doi: prefixes and lower cases the DOI
+func NormalizeDOI(id string) string {
+	doi := strings.TrimSpace(id)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:", "crossref_"} {
+		if len(doi) >= len(prefix) && strings.EqualFold(doi[:len(prefix)], prefix) {
+			doi = doi[len(prefix):]
+		}
+	}
+	return strings.ToLower(doi)
+}
+
+// NormalizeORCID extracts the bare ORCID identifier from an ORCID URL
+func NormalizeORCID(orcid string) string {
+	orcid = strings.TrimSpace(orcid)
+	if i := strings.LastIndex(orcid, "/"); i >= 0 {
+		orcid = orcid[i+1:]
+	}
+	return orcid
+}
+
+// ValidateCrossrefConfig validates Crossref provider configuration
+func ValidateCrossrefConfig(config map[string]interface{}) error {
+	if mailto, ok := config["mailto"].(string); ok && mailto != "" && !strings.Contains(mailto, "@") {
+		return fmt.Errorf("crossref mailto must be an email address")
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package providers
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+)
+
+const (
+	// pageTokenVersion is bumped whenever the token layout changes
+	pageTokenVersion = 2
+
+	// maxSeenHashes bounds the dedup state carried by a page token to 2 KB of
+	// the token. Only the most recently returned papers are remembered beyond
+	// that.
+	maxSeenHashes = 384
+)
+
+// PageCursor is the continuation state of an aggregated search. It is handed
+// to clients as an opaque page token and records where every provider left
+// off together with the papers already returned, so the next page continues
+// each provider instead of starting over at offset 0.
+type PageCursor struct {
+	Version   int                       `json:"v"`
+	Query     string                    `json:"q"` // fingerprint of the query the token belongs to
+	Providers map[string]ProviderCursor `json:"p"`
+	Seen      []byte                    `json:"-"` // packed hashes of returned paper identifiers
+}
+
+// ProviderCursor is the paging position of a single provider
+type ProviderCursor struct {
+	Offset int    `json:"o,omitempty"`
+	Cursor string `json:"c,omitempty"` // NextCursor of providers with cursor paging
+	Done   bool   `json:"d,omitempty"`
+}
+
+// EncodePageToken encodes a page cursor as an opaque URL-safe token. The
+// seen hashes follow the JSON state after a dot, so they are only encoded once.
+func EncodePageToken(cursor *PageCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.NewSerializationError("failed to encode page token", cursor)
+	}
+	token := base64.RawURLEncoding.EncodeToString(data)
+	if len(cursor.Seen) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(cursor.Seen)
+	}
+	return token, nil
+}
+
+// DecodePageToken decodes a page token returned by an earlier search
+func DecodePageToken(token string) (*PageCursor, error) {
+	state, seen, _ := strings.Cut(token, ".")
+	data, err := base64.RawURLEncoding.DecodeString(state)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid page token", "cursor", token)
+	}
+
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Version != pageTokenVersion {
+		return nil, errors.NewValidationError("invalid page token", "cursor", token)
+	}
+	if cursor.Seen, err = base64.RawURLEncoding.DecodeString(seen); err != nil || len(cursor.Seen)%4 != 0 {
+		return nil, errors.NewValidationError("invalid page token", "cursor", token)
+	}
+	if cursor.Providers == nil {
+		cursor.Providers = make(map[string]ProviderCursor)
+	}
+	return &cursor, nil
+}
+
+// newPageCursor starts the cursor of the first page of a query
+func newPageCursor(query *SearchQuery) *PageCursor {
+	return &PageCursor{
+		Version:   pageTokenVersion,
+		Query:     queryFingerprint(query),
+		Providers: make(map[string]ProviderCursor),
+	}
+}
+
+// pending returns the providers of the cursor that have more results, in the
+// given order. Providers that were not part of the first page are left out.
+func (c *PageCursor) pending(providers []SearchProvider) []SearchProvider {
+	var remaining []SearchProvider
+	for _, provider := range providers {
+		if state, ok := c.Providers[provider.Name()]; ok && !state.Done {
+			remaining = append(remaining, provider)
+		}
+	}
+	return remaining
+}
+
+// providerQuery returns the query continuing a provider where it left off.
+// On the first page providers start at the offset of the query.
+func (c *PageCursor) providerQuery(query *SearchQuery, provider string) *SearchQuery {
+	continued := *query
+	continued.pageCursor = nil
+
+	state, ok := c.Providers[provider]
+	if !ok {
+		return &continued
+	}
+	continued.PageToken = ""
+	continued.Offset = state.Offset
+	continued.Cursor = state.Cursor
+	return &continued
+}
+
+// advance records the page a provider returned for the query
+func (c *PageCursor) advance(provider string, query *SearchQuery, result *SearchResult) {
+	state, ok := c.Providers[provider]
+	if !ok {
+		state.Offset = query.Offset
+	}
+	// Papers removed by post-filters were still part of the provider's page
+	returned := len(result.Papers)
+	if result.Filters != nil {
+		returned += result.Filters.Dropped
+	}
+	state.Offset += returned
+	state.Cursor = ""
+	if result.NextCursor != nil {
+		state.Cursor = *result.NextCursor
+	}
+	state.Done = !result.HasMore || returned == 0
+	c.Providers[provider] = state
+}
+
+// advanceReturned records how far into its page a provider's papers were
+// returned, for strategies that keep only the best papers of all providers.
+// The page is consumed up to the first paper not returned yet, the rest is
+// fetched again by the next page; providers paging by cursor cannot resume
+// mid-page and fetch the whole page again.
+func (c *PageCursor) advanceReturned(provider string, query *SearchQuery, result *SearchResult) {
+	returned := c.returnedPrefix(result.Papers)
+	if returned == len(result.Papers) {
+		c.advance(provider, query, result)
+		return
+	}
+
+	state, ok := c.Providers[provider]
+	if !ok {
+		state = ProviderCursor{Offset: query.Offset, Cursor: query.Cursor}
+	}
+	if state.Cursor == "" && result.NextCursor == nil {
+		state.Offset += returned
+	}
+	c.Providers[provider] = state
+}
+
+// returnedPrefix returns how many leading papers were returned on this or
+// earlier pages
+func (c *PageCursor) returnedPrefix(papers []models.Paper) int {
+	earlier := c.seenHashes()
+	for i, paper := range papers {
+		if !seenBefore(earlier, paper) {
+			return i
+		}
+	}
+	return len(papers)
+}
+
+// hasMore reports whether any provider has results left
+func (c *PageCursor) hasMore() bool {
+	for _, state := range c.Providers {
+		if !state.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSeen drops papers returned on earlier pages and remembers the rest,
+// keeping at most limit papers unless limit is 0. Papers are recognized by
+// their identifiers, and by title and year unless they carry a DOI, as
+// distinct works may share a title such as "Erratum".
+func (c *PageCursor) filterSeen(papers []models.Paper, limit int) []models.Paper {
+	seen := unpackSeen(c.Seen)
+	earlier := c.seenHashes()
+
+	fresh := make([]models.Paper, 0, len(papers))
+	for _, paper := range papers {
+		if limit > 0 && len(fresh) == limit {
+			break
+		}
+		if seenBefore(earlier, paper) {
+			continue
+		}
+
+		ids, title := paperHashes(paper)
+		seen = append(seen, ids...)
+		if title != 0 {
+			seen = append(seen, title)
+		}
+		fresh = append(fresh, paper)
+	}
+
+	if len(seen) > maxSeenHashes {
+		seen = seen[len(seen)-maxSeenHashes:]
+	}
+	c.Seen = packSeen(seen)
+	return fresh
+}
+
+// seenHashes returns the identifier hashes of the papers returned so far
+func (c *PageCursor) seenHashes() map[uint32]bool {
+	seen := unpackSeen(c.Seen)
+	hashes := make(map[uint32]bool, len(seen))
+	for _, hash := range seen {
+		hashes[hash] = true
+	}
+	return hashes
+}
+
+// seenBefore reports whether a paper is among the seen hashes
+func seenBefore(seen map[uint32]bool, paper models.Paper) bool {
+	ids, title := paperHashes(paper)
+	if title != 0 && isEmpty(paper.DOI) && seen[title] {
+		return true
+	}
+	for _, hash := range ids {
+		if seen[hash] {
+			return true
+		}
+	}
+	return false
+}
+
+// paperHashes hashes the identifiers a paper is matched on across pages,
+// the title hash is 0 for untitled papers
+func paperHashes(paper models.Paper) ([]uint32, uint32) {
+	fp := fingerprintPaper(paper)
+
+	var ids []uint32
+	for _, key := range []string{
+		prefixed("doi:", fp.doi),
+		prefixed("arxiv:", fp.arxivID),
+		prefixed("pmid:", fp.pmid),
+		prefixed("source:", fp.sourceID),
+	} {
+		if key != "" {
+			ids = append(ids, hashKey(key))
+		}
+	}
+
+	var title uint32
+	if fp.title != "" {
+		title = hashKey("title:" + fp.title + "|" + strconv.Itoa(fp.year))
+	}
+	return ids, title
+}
+
+// hashKey hashes an identifier for the dedup state of a page token
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// packSeen packs identifier hashes into 4 bytes each
+func packSeen(hashes []uint32) []byte {
+	data := make([]byte, 4*len(hashes))
+	for i, hash := range hashes {
+		binary.BigEndian.PutUint32(data[4*i:], hash)
+	}
+	return data
+}
+
+// unpackSeen reverses packSeen
+func unpackSeen(data []byte) []uint32 {
+	hashes := make([]uint32, 0, len(data)/4)
+	for i := 0; i+4 <= len(data); i += 4 {
+		hashes = append(hashes, binary.BigEndian.Uint32(data[i:]))
+	}
+	return hashes
+}
+
+// queryFingerprint identifies everything but the page of a query, so a page
+// token cannot be replayed against a different search
+func queryFingerprint(query *SearchQuery) string {
+	key := CacheKey(&SearchQuery{
+		Query:           query.Query,
+		Filters:         query.Filters,
+		SortBy:          query.SortBy,
+		SortOrder:       query.SortOrder,
+		DateFrom:        query.DateFrom,
+		DateTo:          query.DateTo,
+		Categories:      query.Categories,
+		Authors:         query.Authors,
+		IncludeFullText: query.IncludeFullText,
+		Language:        query.Language,
+	}, "page")
+
+	return strings.TrimPrefix(key, cacheKeyPrefix+"page.")
+}
+
+// checkPageCursor verifies that a decoded cursor belongs to the query
+func checkPageCursor(cursor *PageCursor, query *SearchQuery) error {
+	if cursor.Query != queryFingerprint(query) {
+		return errors.NewValidationError(
+			fmt.Sprintf("page token belongs to a different search than %q", query.Query),
+			"cursor",
+			query.PageToken,
+		)
+	}
+	return nil
+}
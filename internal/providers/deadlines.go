@@ -0,0 +1,178 @@
+package providers
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHedgeMinSamples is how many latencies of a provider are recorded
+// before its requests are hedged
+const DefaultHedgeMinSamples = 20
+
+// latencyWindowSize is how many recent latencies are kept per provider
+const latencyWindowSize = 100
+
+// budgetGrace lets provider searches report their own timeout before the
+// search as a whole gives up on them
+const budgetGrace = 50 * time.Millisecond
+
+// latencyWindow keeps the most recent successful response times of a provider
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// add records a response time, replacing the oldest once the window is full
+func (w *latencyWindow) add(latency time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, latency)
+		return
+	}
+	w.samples[w.next] = latency
+	w.next = (w.next + 1) % latencyWindowSize
+}
+
+// p95 returns the 95th percentile of the recorded response times, and false
+// while fewer than minSamples were recorded
+func (w *latencyWindow) p95(minSamples int) (time.Duration, bool) {
+	w.mu.Lock()
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	w.mu.Unlock()
+
+	if len(sorted) == 0 || len(sorted) < minSamples {
+		return 0, false
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)*95+99)/100-1], true
+}
+
+// providerTimeout returns the time budget of a provider search
+func (m *Manager) providerTimeout(name string) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if timeout, ok := m.providerTimeouts[name]; ok {
+		return timeout
+	}
+	return m.timeout
+}
+
+// searchBudget returns how long a search of the given providers may take,
+// the longest of their budgets
+func (m *Manager) searchBudget(providers []SearchProvider) time.Duration {
+	budget := m.timeout
+	if len(providers) > 0 {
+		budget = 0
+	}
+	for _, provider := range providers {
+		if timeout := m.providerTimeout(provider.Name()); timeout > budget {
+			budget = timeout
+		}
+	}
+	return budget + budgetGrace
+}
+
+// recordLatency adds a successful response time to the provider's window
+func (m *Manager) recordLatency(name string, latency time.Duration) {
+	m.mu.Lock()
+	window, ok := m.latencies[name]
+	if !ok {
+		window = &latencyWindow{}
+		m.latencies[name] = window
+	}
+	m.mu.Unlock()
+
+	window.add(latency)
+}
+
+// hedgeDelay returns how long to wait before hedging a request to the
+// provider, and false when requests to it are not hedged
+func (m *Manager) hedgeDelay(name string) (time.Duration, bool) {
+	if !m.hedging {
+		return 0, false
+	}
+
+	m.mu.RLock()
+	window, ok := m.latencies[name]
+	m.mu.RUnlock()
+	if !ok {
+		return 0, false
+	}
+	return window.p95(m.hedgeMinSamples)
+}
+
+type hedgeResult struct {
+	result *SearchResult
+	err    error
+}
+
+// callProvider sends the query to the provider. When hedging is enabled and
+// the provider has not answered within its p95 latency, a duplicate request
+// is sent if the rate limiter has a token to spare; the first successful
+// answer wins and the other request is cancelled.
+func (m *Manager) callProvider(ctx context.Context, provider SearchProvider, query *SearchQuery) (*SearchResult, error) {
+	name := provider.Name()
+	delay, hedge := m.hedgeDelay(name)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so the losing request never blocks
+	results := make(chan hedgeResult, 2)
+	send := func() {
+		start := time.Now()
+		result, err := provider.Search(ctx, query)
+		if err == nil {
+			m.recordLatency(name, time.Since(start))
+		}
+		results <- hedgeResult{result, err}
+	}
+
+	go send()
+	if !hedge {
+		outcome := <-results
+		return outcome.result, outcome.err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case outcome := <-results:
+		return outcome.result, outcome.err
+	case <-timer.C:
+	}
+
+	pending := 1
+	if m.rateLimit == nil || m.rateLimit.Allow(ctx, name) {
+		m.mu.Lock()
+		m.hedgedRequests[name]++
+		m.mu.Unlock()
+
+		m.logger.Debug("Hedging provider request",
+			slog.String("provider", name),
+			slog.Duration("after", delay))
+		go send()
+		pending++
+	}
+
+	var err error
+	for ; pending > 0; pending-- {
+		outcome := <-results
+		if outcome.err == nil {
+			return outcome.result, nil
+		}
+		if err == nil {
+			err = outcome.err
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,391 @@
+package providers
+
+import (
+	"strings"
+	"unicode"
+
+	"scifind-backend/internal/models"
+)
+
+// titleSimilarityThreshold is the minimum token Jaccard similarity for two
+// normalized titles to be considered the same work
+const titleSimilarityThreshold = 0.9
+
+// paperFingerprint holds the normalized matching attributes of a paper
+type paperFingerprint struct {
+	doi      string
+	arxivID  string
+	pmid     string
+	sourceID string
+	title    string
+	tokens   map[string]bool
+	surnames map[string]bool
+	year     int
+}
+
+// resolvePapers performs entity resolution over papers from multiple
+// providers. Records are matched on external identifiers (DOI, arXiv ID,
+// PMID, provider source ID) or on normalized title combined with publication year
+// and author surnames. Matched records are merged field by field into the
+// first occurrence, so the input order is preserved. The returned cluster
+// slice maps every input paper to the index of its merged record.
+func resolvePapers(papers []models.Paper) ([]models.Paper, []int) {
+	fingerprints := make([]paperFingerprint, len(papers))
+	for i := range papers {
+		fingerprints[i] = fingerprintPaper(papers[i])
+	}
+
+	parent := make([]int, len(papers))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	// The identifiers of each cluster, so that records cannot be chained
+	// together into a cluster holding two different DOIs
+	clusterIDs := make([]paperFingerprint, len(papers))
+	copy(clusterIDs, fingerprints)
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri == rj || conflictingIDs(clusterIDs[ri], clusterIDs[rj]) {
+			return
+		}
+		// Keep the earliest record as the cluster root
+		if rj < ri {
+			ri, rj = rj, ri
+		}
+		parent[rj] = ri
+		mergeIDs(&clusterIDs[ri], clusterIDs[rj])
+	}
+
+	// Exact identifier matches
+	byID := make(map[string]int)
+	for i, fp := range fingerprints {
+		for _, id := range []string{prefixed("doi:", fp.doi), prefixed("arxiv:", fp.arxivID), prefixed("pmid:", fp.pmid), prefixed("source:", fp.sourceID)} {
+			if id == "" {
+				continue
+			}
+			if j, ok := byID[id]; ok {
+				union(j, i)
+				continue
+			}
+			byID[id] = i
+		}
+	}
+
+	// Fuzzy title matches
+	for i := 0; i < len(fingerprints); i++ {
+		for j := i + 1; j < len(fingerprints); j++ {
+			if find(i) == find(j) {
+				continue
+			}
+			if fuzzyMatch(fingerprints[i], fingerprints[j]) {
+				union(i, j)
+			}
+		}
+	}
+
+	// Merge clusters in input order
+	clusterIndex := make(map[int]int)
+	clusters := make([]int, len(papers))
+	var merged []models.Paper
+	for i := range papers {
+		root := find(i)
+		idx, exists := clusterIndex[root]
+		if !exists {
+			idx = len(merged)
+			clusterIndex[root] = idx
+			base := papers[i]
+			base.Sources = nil
+			addProvenance(&base, papers[i])
+			merged = append(merged, base)
+		} else {
+			mergePaper(&merged[idx], papers[i])
+		}
+		clusters[i] = idx
+	}
+
+	return merged, clusters
+}
+
+// mergePaper fills missing fields of dst from src and records src provenance
+func mergePaper(dst *models.Paper, src models.Paper) {
+	if isEmpty(dst.DOI) && !isEmpty(src.DOI) {
+		dst.DOI = src.DOI
+	}
+	if isEmpty(dst.ArxivID) && !isEmpty(src.ArxivID) {
+		dst.ArxivID = src.ArxivID
+	}
+	if isEmpty(dst.PMID) && !isEmpty(src.PMID) {
+		dst.PMID = src.PMID
+	}
+	if isEmpty(dst.PMCID) && !isEmpty(src.PMCID) {
+		dst.PMCID = src.PMCID
+	}
+	if isEmpty(dst.Abstract) && !isEmpty(src.Abstract) {
+		dst.Abstract = src.Abstract
+	}
+	if isEmpty(dst.Journal) && !isEmpty(src.Journal) {
+		dst.Journal = src.Journal
+	}
+	if isEmpty(dst.Volume) && !isEmpty(src.Volume) {
+		dst.Volume = src.Volume
+	}
+	if isEmpty(dst.Issue) && !isEmpty(src.Issue) {
+		dst.Issue = src.Issue
+	}
+	if isEmpty(dst.Pages) && !isEmpty(src.Pages) {
+		dst.Pages = src.Pages
+	}
+	if isEmpty(dst.URL) && !isEmpty(src.URL) {
+		dst.URL = src.URL
+	}
+	if isEmpty(dst.PDFURL) && !isEmpty(src.PDFURL) {
+		dst.PDFURL = src.PDFURL
+	}
+	if isEmpty(dst.FullText) && !isEmpty(src.FullText) {
+		dst.FullText = src.FullText
+	}
+	if dst.PublishedAt == nil && src.PublishedAt != nil {
+		dst.PublishedAt = src.PublishedAt
+	}
+	if len(dst.Authors) == 0 && len(src.Authors) > 0 {
+		dst.Authors = src.Authors
+	}
+	if src.CitationCount > dst.CitationCount {
+		dst.CitationCount = src.CitationCount
+	}
+	if src.QualityScore > dst.QualityScore {
+		dst.QualityScore = src.QualityScore
+	}
+
+	for _, keyword := range src.Keywords {
+		dst.AddKeyword(keyword)
+	}
+	for _, ref := range src.References {
+		dst.AddReference(ref)
+	}
+	for _, cit := range src.Citations {
+		if !containsString(dst.Citations, cit) {
+			dst.Citations = append(dst.Citations, cit)
+		}
+	}
+	for _, category := range src.Categories {
+		if !containsCategory(dst.Categories, category) {
+			dst.Categories = append(dst.Categories, category)
+		}
+	}
+
+	addProvenance(dst, src)
+}
+
+// addProvenance records every source of src on dst
+func addProvenance(dst *models.Paper, src models.Paper) {
+	if len(src.Sources) > 0 {
+		for _, source := range src.Sources {
+			dst.AddSource(source)
+		}
+		return
+	}
+	if src.SourceProvider == "" {
+		return
+	}
+	dst.AddSource(models.PaperSource{
+		Provider:  src.SourceProvider,
+		SourceID:  src.SourceID,
+		SourceURL: src.SourceURL,
+	})
+}
+
+func fingerprintPaper(paper models.Paper) paperFingerprint {
+	fp := paperFingerprint{
+		title:    normalizeTitle(paper.Title),
+		surnames: make(map[string]bool),
+		year:     paper.GetYear(),
+	}
+	if !isEmpty(paper.DOI) {
+		fp.doi = normalizeDOI(*paper.DOI)
+	}
+	if !isEmpty(paper.ArxivID) {
+		fp.arxivID = normalizeArxivID(*paper.ArxivID)
+	}
+	if !isEmpty(paper.PMID) {
+		fp.pmid = strings.TrimSpace(*paper.PMID)
+	}
+	if paper.SourceProvider != "" && paper.SourceID != "" {
+		fp.sourceID = paper.SourceProvider + ":" + paper.SourceID
+	}
+
+	fp.tokens = make(map[string]bool)
+	for _, token := range strings.Fields(fp.title) {
+		fp.tokens[token] = true
+	}
+	for _, author := range paper.Authors {
+		if surname := authorSurname(author.Name); surname != "" {
+			fp.surnames[surname] = true
+		}
+	}
+
+	return fp
+}
+
+// conflictingIDs reports whether two records carry different values for the
+// same external identifier and therefore cannot describe the same work
+func conflictingIDs(a, b paperFingerprint) bool {
+	if a.doi != "" && b.doi != "" && a.doi != b.doi {
+		return true
+	}
+	if a.arxivID != "" && b.arxivID != "" && a.arxivID != b.arxivID {
+		return true
+	}
+	if a.pmid != "" && b.pmid != "" && a.pmid != b.pmid {
+		return true
+	}
+	return false
+}
+
+// mergeIDs fills in the external identifiers dst lacks from src
+func mergeIDs(dst *paperFingerprint, src paperFingerprint) {
+	if dst.doi == "" {
+		dst.doi = src.doi
+	}
+	if dst.arxivID == "" {
+		dst.arxivID = src.arxivID
+	}
+	if dst.pmid == "" {
+		dst.pmid = src.pmid
+	}
+}
+
+// fuzzyMatch reports whether two records without a shared identifier are
+// likely the same work
+func fuzzyMatch(a, b paperFingerprint) bool {
+	if a.title == "" || b.title == "" || conflictingIDs(a, b) {
+		return false
+	}
+
+	if a.title != b.title && jaccard(a.tokens, b.tokens) < titleSimilarityThreshold {
+		return false
+	}
+
+	// Preprints and published versions are often a year apart
+	if a.year != 0 && b.year != 0 && absInt(a.year-b.year) > 1 {
+		return false
+	}
+
+	if len(a.surnames) > 0 && len(b.surnames) > 0 {
+		for surname := range a.surnames {
+			if b.surnames[surname] {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// normalizeTitle lowercases a title and strips punctuation and extra whitespace
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func normalizeDOI(doi string) string {
+	doi = strings.ToLower(strings.TrimSpace(doi))
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		doi = strings.TrimPrefix(doi, prefix)
+	}
+	return doi
+}
+
+// normalizeArxivID strips the "arXiv:" prefix and version suffix
+func normalizeArxivID(id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	id = strings.TrimPrefix(id, "arxiv:")
+	if idx := strings.LastIndex(id, "v"); idx > 0 && idx < len(id)-1 {
+		version := id[idx+1:]
+		if strings.IndexFunc(version, func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+			id = id[:idx]
+		}
+	}
+	return id
+}
+
+// authorSurname extracts a normalized surname from "First Last" or "Last, First"
+func authorSurname(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	if idx := strings.Index(name, ","); idx > 0 {
+		return normalizeTitle(name[:idx])
+	}
+	parts := strings.Fields(normalizeTitle(name))
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for token := range a {
+		if b[token] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
+
+func isEmpty(s *string) bool {
+	return s == nil || *s == ""
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCategory(categories []models.Category, category models.Category) bool {
+	for _, c := range categories {
+		if (c.ID != "" && c.ID == category.ID) || strings.EqualFold(c.Name, category.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -80,6 +80,9 @@ func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
 		SupportsExactMatch:  false, // Exa is semantic/neural search
 		SupportsFuzzySearch: true,
 		SupportsWildcards:   false,
+
+		QueryFields:          []string{"year"},
+		SupportsBooleanQuery: false,
 	}
 }
 
@@ -89,6 +92,15 @@ func (p *Provider) Search(ctx context.Context, query *providers.SearchQuery) (*p
 
 	// Build search request
 	searchReq := BuildExaSearchRequest(query.Query, query.Filters, query.Limit, query.Offset)
+	applyDateRange(searchReq, query)
+	parsed, err := query.ParsedQuery()
+	if err == nil && parsed != nil {
+		err = applyQuery(searchReq, parsed)
+	}
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
 
 	// Make API request
 	response, err := p.makeSearchRequest(ctx, searchReq)
@@ -0,0 +1,55 @@
+package exa
+
+import (
+	"fmt"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// applyQuery sets the search text and published date range of a request
+// from a query AST. Exa searches natural language, so only conjunctions of
+// plain terms and year ranges are supported.
+func applyQuery(req *ExaSearchRequest, node *providers.QueryNode) error {
+	terms, ok := node.Conjuncts()
+	if !ok {
+		return errors.NewUnsupportedQueryError(providerName, "boolean operators")
+	}
+
+	for _, term := range terms {
+		switch term.Field {
+		case "":
+		case providers.QueryFieldYear:
+			if term.YearFrom != 0 {
+				startDate := fmt.Sprintf("%04d-01-01", term.YearFrom)
+				req.StartPublishedDate = &startDate
+			}
+			if term.YearTo != 0 {
+				endDate := fmt.Sprintf("%04d-12-31", term.YearTo)
+				req.EndPublishedDate = &endDate
+			}
+		default:
+			return errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+		}
+	}
+
+	req.Query = providers.FreeText(terms)
+	if req.Query == "" {
+		return errors.NewUnsupportedQueryError(providerName, "queries without search terms")
+	}
+
+	return nil
+}
+
+// applyDateRange sets the published date range of a request from the date
+// filters of a query
+func applyDateRange(req *ExaSearchRequest, query *providers.SearchQuery) {
+	if query.DateFrom != nil {
+		startDate := query.DateFrom.Format("2006-01-02")
+		req.StartPublishedDate = &startDate
+	}
+	if query.DateTo != nil {
+		endDate := query.DateTo.Format("2006-01-02")
+		req.EndPublishedDate = &endDate
+	}
+}
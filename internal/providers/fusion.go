@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"sort"
+	"strconv"
+
+	"scifind-backend/internal/models"
+)
+
+// DefaultRRFK is the rank constant used by reciprocal rank fusion. Larger
+// values flatten the difference between top and lower ranked results.
+const DefaultRRFK = 60
+
+// fusedPaper tracks the accumulated fusion score of a resolved paper
+type fusedPaper struct {
+	paper    models.Paper
+	key      string
+	score    float64
+	bestRank int
+}
+
+// fuseReciprocalRank merges per-provider rankings into a single list ordered
+// by reciprocal rank fusion score: sum(weight / (k + rank)) over all
+// providers that returned the paper. Records describing the same work are
+// resolved and merged first. Providers are visited in name order and ties
+// are broken by best rank and then by normalized title, so identical inputs
+// always produce the same output regardless of provider completion order.
+func fuseReciprocalRank(rankings map[string][]models.Paper, weights map[string]float64, k int) []models.Paper {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+
+	providerNames := make([]string, 0, len(rankings))
+	for name := range rankings {
+		providerNames = append(providerNames, name)
+	}
+	sort.Strings(providerNames)
+
+	var all []models.Paper
+	var ranks []int
+	var owners []string
+	for _, name := range providerNames {
+		for i, paper := range rankings[name] {
+			all = append(all, paper)
+			ranks = append(ranks, i+1)
+			owners = append(owners, name)
+		}
+	}
+
+	merged, clusters := resolvePapers(all)
+
+	entries := make([]*fusedPaper, len(merged))
+	for i := range merged {
+		entries[i] = &fusedPaper{
+			paper: merged[i],
+			key:   normalizeTitle(merged[i].Title) + "|" + merged[i].ID,
+		}
+	}
+
+	// Only the best rank of a paper counts within a single provider
+	counted := make(map[string]bool)
+	for i, cluster := range clusters {
+		seenKey := owners[i] + "|" + strconv.Itoa(cluster)
+		if counted[seenKey] {
+			continue
+		}
+		counted[seenKey] = true
+
+		weight := 1.0
+		if w, ok := weights[owners[i]]; ok && w > 0 {
+			weight = w
+		}
+
+		entry := entries[cluster]
+		entry.score += weight / float64(k+ranks[i])
+		if entry.bestRank == 0 || ranks[i] < entry.bestRank {
+			entry.bestRank = ranks[i]
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].score != entries[j].score {
+			return entries[i].score > entries[j].score
+		}
+		if entries[i].bestRank != entries[j].bestRank {
+			return entries[i].bestRank < entries[j].bestRank
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	papers := make([]models.Paper, len(entries))
+	for i, entry := range entries {
+		papers[i] = entry.paper
+	}
+
+	return papers
+}
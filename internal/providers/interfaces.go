@@ -36,17 +36,22 @@ type ProviderManager interface {
 	GetProvider(name string) (SearchProvider, error)
 	GetEnabledProviders() []SearchProvider
 	GetAllProviders() map[string]SearchProvider
+	GetPaper(ctx context.Context, providerName, id string) (*models.Paper, error)
 	
 	// Search operations
 	SearchAll(ctx context.Context, query *SearchQuery) (*AggregatedResult, error)
 	SearchProviders(ctx context.Context, query *SearchQuery, providerNames []string) (*AggregatedResult, error)
+	SearchStream(ctx context.Context, query *SearchQuery, providerNames []string) (<-chan StreamEvent, error)
 	
 	// Health and monitoring
 	HealthCheckAll(ctx context.Context) map[string]error
+	GetProviderStatus() map[string]ProviderStatus
 	GetProviderMetrics() map[string]ProviderMetrics
 	
 	// Configuration
 	UpdateProviderConfig(name string, config ProviderConfig) error
+	OpenCircuit(name string) error
+	ResetCircuit(name string) error
 	
 	// Lifecycle
 	Start(ctx context.Context) error
@@ -77,6 +82,7 @@ type CircuitBreaker interface {
 	ForceOpen(provider string)
 	ForceClose(provider string)
 	Reset(provider string)
+	GetTransitions(provider string) (opened, closed int64)
 }
 
 // Data Structures
@@ -85,11 +91,14 @@ type CircuitBreaker interface {
 type SearchQuery struct {
 	// Core query
 	Query    string            `json:"query"`
+	Parsed   *QueryNode        `json:"parsed,omitempty"` // AST of Query, see ParseQuery
 	Filters  map[string]string `json:"filters,omitempty"`
 	
 	// Pagination
 	Limit    int `json:"limit"`
 	Offset   int `json:"offset"`
+	Cursor   string `json:"cursor,omitempty"` // NextCursor of the previous page, for providers with cursor paging
+	PageToken string `json:"page_token,omitempty"` // NextPageToken of the previous aggregated page, see PageCursor
 	
 	// Sorting
 	SortBy   string `json:"sort_by,omitempty"`
@@ -111,6 +120,9 @@ type SearchQuery struct {
 	// Request context
 	RequestID string `json:"request_id"`
 	UserID    *string `json:"user_id,omitempty"`
+
+	// pageCursor is the decoded PageToken while the manager runs the query
+	pageCursor *PageCursor
 }
 
 // SearchResult represents search results from a provider
@@ -137,6 +149,9 @@ type SearchResult struct {
 	HasMore     bool   `json:"has_more"`
 	NextCursor  *string `json:"next_cursor,omitempty"`
 	
+	// Filters
+	Filters     *FilterSupport `json:"filters,omitempty"` // how the query filters were applied, set by the manager
+	
 	// Status
 	Success     bool   `json:"success"`
 	Error       error  `json:"error,omitempty"`
@@ -154,6 +169,8 @@ type AggregatedResult struct {
 	RequestedProviders []string        `json:"requested_providers"`
 	SuccessfulProviders []string       `json:"successful_providers"`
 	FailedProviders  []string          `json:"failed_providers"`
+	PendingProviders []string          `json:"pending_providers,omitempty"` // still running when the results were returned
+	FilterSupport   map[string]FilterSupport `json:"filter_support,omitempty"` // how each provider applied the query filters
 	
 	// Performance
 	TotalDuration   time.Duration      `json:"total_duration"`
@@ -163,6 +180,11 @@ type AggregatedResult struct {
 	RequestID       string             `json:"request_id"`
 	Timestamp       time.Time          `json:"timestamp"`
 	AggregationStrategy string         `json:"aggregation_strategy"`
+	StrategyMetadata map[string]interface{} `json:"strategy_metadata,omitempty"` // provider selection details
+	
+	// Pagination
+	HasMore         bool               `json:"has_more"`
+	NextPageToken   *string            `json:"next_page_token,omitempty"` // continues every provider where this page left off
 	
 	// Status
 	PartialFailure  bool               `json:"partial_failure"`
@@ -193,6 +215,10 @@ type ProviderCapabilities struct {
 	SupportsExactMatch  bool     `json:"supports_exact_match"`
 	SupportsFuzzySearch bool     `json:"supports_fuzzy_search"`
 	SupportsWildcards   bool     `json:"supports_wildcards"`
+	
+	// Query grammar
+	QueryFields          []string `json:"query_fields"`           // field prefixes the provider can translate
+	SupportsBooleanQuery bool     `json:"supports_boolean_query"` // OR, NOT and nested groups
 }
 
 // ProviderStatus represents the current status of a provider
@@ -224,6 +250,7 @@ type ProviderMetrics struct {
 	SuccessfulRequests int64        `json:"successful_requests"`
 	FailedRequests    int64         `json:"failed_requests"`
 	CachedRequests    int64         `json:"cached_requests"`
+	HedgedRequests    int64         `json:"hedged_requests"`
 	
 	// Performance metrics
 	AvgResponseTime   time.Duration `json:"avg_response_time"`
@@ -375,6 +402,12 @@ const (
 	
 	// Round-robin across providers
 	StrategyRoundRobin AggregationStrategy = "round_robin"
+	
+	// Weighted random selection across providers
+	StrategyWeightedRandom AggregationStrategy = "weighted_random"
+	
+	// Reciprocal rank fusion of per-provider rankings
+	StrategyReciprocalRank AggregationStrategy = "rrf"
 )
 
 // Provider names (constants for consistency)
@@ -385,6 +418,8 @@ const (
 	ProviderTavily          = "tavily"
 	ProviderCrossRef        = "crossref"
 	ProviderPubMed          = "pubmed"
+	ProviderOpenAlex        = "openalex"
+	ProviderLocal           = "local"
 )
 
 // Common search filters
@@ -399,6 +434,8 @@ const (
 	FilterMaxCitations  = "max_citations"
 	FilterPaperType     = "paper_type"
 	FilterOpenAccess    = "open_access"
+	FilterConcept       = "concept"     // OpenAlex concept IDs, comma separated
+	FilterInstitution   = "institution" // OpenAlex institution IDs or ROR IDs, comma separated
 )
 
 // Sort options
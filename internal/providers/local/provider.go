@@ -0,0 +1,281 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/repository"
+)
+
+const (
+	providerName = "local"
+	maxResults   = 100
+)
+
+// Provider searches the papers stored in our own database, so the curated
+// collection and previously imported papers are aggregated with the
+// external results and stay searchable offline
+type Provider struct {
+	config  providers.ProviderConfig
+	repo    repository.PaperRepository
+	logger  *slog.Logger
+	metrics *providers.ProviderMetrics
+	enabled bool
+}
+
+// NewProvider creates a new local provider on top of the paper repository
+func NewProvider(config providers.ProviderConfig, repo repository.PaperRepository, logger *slog.Logger) *Provider {
+	return &Provider{
+		config:  config,
+		repo:    repo,
+		logger:  logger,
+		metrics: &providers.ProviderMetrics{},
+		enabled: config.Enabled,
+	}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// IsEnabled returns whether the provider is enabled
+func (p *Provider) IsEnabled() bool {
+	return p.enabled
+}
+
+// GetCapabilities returns provider capabilities
+func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
+	return providers.ProviderCapabilities{
+		SupportsFullText:       true,
+		SupportsDateFilter:     true,
+		SupportsAuthFilter:     true,
+		SupportsCategoryFilter: true,
+		SupportsSort:           true,
+
+		SupportedFields:    []string{"title", "abstract", "full_text", "author", "category", "journal", "language", "citations"},
+		SupportedLanguages: []string{"en"},
+		SupportedFormats:   []string{"json"},
+
+		MaxResults:     maxResults,
+		MaxQueryLength: 1000,
+		RateLimit:      0, // no external quota
+
+		SupportsRealtime:    true,
+		SupportsExactMatch:  false,
+		SupportsFuzzySearch: false,
+		SupportsWildcards:   false,
+
+		QueryFields:          []string{"title", "author", "year", "cat"},
+		SupportsBooleanQuery: false,
+	}
+}
+
+// Search searches the stored papers. Papers keep the provider they were
+// imported from as their source.
+func (p *Provider) Search(ctx context.Context, query *providers.SearchQuery) (*providers.SearchResult, error) {
+	start := time.Now()
+
+	text, filter, err := translateQuery(query)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > maxResults {
+		limit = maxResults
+	}
+
+	// Full text searches rank by text relevance, other searches honour the sort
+	var papers []models.Paper
+	var total int64
+	if query.IncludeFullText {
+		papers, total, err = p.repo.SearchFullText(ctx, text, filter, limit, query.Offset)
+	} else {
+		papers, total, err = p.repo.Search(ctx, text, filter, paperSort(query), limit, query.Offset)
+	}
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("local search failed: %w", err)
+	}
+
+	duration := time.Since(start)
+	p.updateMetrics(true, duration, nil)
+
+	result := &providers.SearchResult{
+		Papers:      papers,
+		TotalCount:  int(total),
+		ResultCount: len(papers),
+		Query:       query.Query,
+		Provider:    providerName,
+		Duration:    duration,
+		CacheHit:    false,
+		RequestID:   query.RequestID,
+		Timestamp:   time.Now(),
+		Success:     true,
+		HasMore:     int64(query.Offset+len(papers)) < total,
+	}
+
+	p.logger.Debug("Local search completed",
+		slog.String("query", query.Query),
+		slog.Int("results", len(papers)),
+		slog.Int64("total", total),
+		slog.Duration("duration", duration))
+
+	return result, nil
+}
+
+// GetPaper retrieves a stored paper by ID, DOI or ArXiv ID
+func (p *Provider) GetPaper(ctx context.Context, id string) (*models.Paper, error) {
+	start := time.Now()
+
+	id = strings.TrimSpace(id)
+	var paper *models.Paper
+	var err error
+	switch {
+	case strings.HasPrefix(strings.ToLower(id), "doi:"):
+		paper, err = p.repo.GetByDOI(ctx, strings.TrimSpace(id[len("doi:"):]))
+	case strings.HasPrefix(id, "10."):
+		paper, err = p.repo.GetByDOI(ctx, id)
+	case strings.HasPrefix(strings.ToLower(id), "arxiv:"):
+		paper, err = p.repo.GetByArxivID(ctx, strings.TrimSpace(id[len("arxiv:"):]))
+	default:
+		paper, err = p.repo.GetByID(ctx, id)
+	}
+
+	p.updateMetrics(err == nil, time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+	return paper, nil
+}
+
+// HealthCheck checks that the paper store can be queried
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	if _, _, err := p.repo.Search(ctx, "", nil, nil, 1, 0); err != nil {
+		return errors.NewHealthCheckError("Health check failed: "+err.Error(), providerName)
+	}
+	return nil
+}
+
+// GetStatus returns the current provider status
+func (p *Provider) GetStatus() providers.ProviderStatus {
+	return providers.ProviderStatus{
+		Name:            providerName,
+		Enabled:         p.enabled,
+		Healthy:         true,
+		LastCheck:       time.Now(),
+		CircuitState:    "closed",
+		RateLimited:     false,
+		AvgResponseTime: p.calculateAvgResponseTime(),
+		SuccessRate:     p.calculateSuccessRate(),
+		APIVersion:      "v1",
+		LastUpdated:     time.Now(),
+	}
+}
+
+// GetMetrics returns provider metrics
+func (p *Provider) GetMetrics() providers.ProviderMetrics {
+	return *p.metrics
+}
+
+// Configure updates the provider configuration
+func (p *Provider) Configure(config providers.ProviderConfig) error {
+	if err := p.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	p.config = config
+	p.enabled = config.Enabled
+
+	p.logger.Info("Local provider configured", slog.Bool("enabled", config.Enabled))
+
+	return nil
+}
+
+// ValidateConfig validates the provider configuration
+func (p *Provider) ValidateConfig(config providers.ProviderConfig) error {
+	if config.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative")
+	}
+	return nil
+}
+
+// paperSort maps the search sort options to a repository sort
+func paperSort(query *providers.SearchQuery) *models.PaperSort {
+	order := "desc"
+	if query.SortOrder == "asc" {
+		order = "asc"
+	}
+
+	switch query.SortBy {
+	case providers.SortDate:
+		return &models.PaperSort{Field: "published_at", Order: order}
+	case providers.SortCitations:
+		return &models.PaperSort{Field: "citation_count", Order: order}
+	case providers.SortQuality:
+		return &models.PaperSort{Field: "quality_score", Order: order}
+	case providers.SortTitle:
+		return &models.PaperSort{Field: "title", Order: order}
+	default:
+		return &models.PaperSort{Field: "relevance", Order: "desc"}
+	}
+}
+
+// Helper methods
+func (p *Provider) updateMetrics(success bool, duration time.Duration, err error) {
+	p.metrics.TotalRequests++
+
+	if success {
+		p.metrics.SuccessfulRequests++
+	} else {
+		p.metrics.FailedRequests++
+
+		// Categorize errors
+		if err != nil {
+			switch {
+			case errors.IsTimeoutError(err):
+				p.metrics.TimeoutErrors++
+			case errors.IsRateLimitError(err):
+				p.metrics.RateLimitErrors++
+			case errors.IsNetworkError(err):
+				p.metrics.NetworkErrors++
+			default:
+				p.metrics.ParseErrors++
+			}
+		}
+	}
+
+	// Update response time statistics
+	if p.metrics.MinResponseTime == 0 || duration < p.metrics.MinResponseTime {
+		p.metrics.MinResponseTime = duration
+	}
+	if duration > p.metrics.MaxResponseTime {
+		p.metrics.MaxResponseTime = duration
+	}
+
+	// Simple moving average for response time
+	if p.metrics.AvgResponseTime == 0 {
+		p.metrics.AvgResponseTime = duration
+	} else {
+		p.metrics.AvgResponseTime = (p.metrics.AvgResponseTime + duration) / 2
+	}
+}
+
+func (p *Provider) calculateAvgResponseTime() time.Duration {
+	return p.metrics.AvgResponseTime
+}
+
+func (p *Provider) calculateSuccessRate() float64 {
+	if p.metrics.TotalRequests == 0 {
+		return 1.0
+	}
+	return float64(p.metrics.SuccessfulRequests) / float64(p.metrics.TotalRequests)
+}
@@ -0,0 +1,135 @@
+package local
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+// translateQuery converts a search query to the full-text search terms and
+// repository filter. Repository filters are always combined with AND, so
+// only conjunctions are supported; title, author, year and category terms
+// become filters and the remaining terms are searched as text.
+func translateQuery(query *providers.SearchQuery) (string, *models.PaperFilter, error) {
+	filter, err := translateFilters(query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return "", nil, err
+	}
+	if parsed == nil {
+		return "", filter, nil
+	}
+
+	terms, ok := parsed.Conjuncts()
+	if !ok {
+		return "", nil, errors.NewUnsupportedQueryError(providerName, "boolean operators")
+	}
+
+	for _, term := range terms {
+		switch term.Field {
+		case "":
+		case providers.QueryFieldTitle:
+			// Titles are matched as one substring
+			if filter.Title != "" {
+				return "", nil, errors.NewUnsupportedQueryError(providerName, "multiple title terms")
+			}
+			filter.Title = term.Value
+		case providers.QueryFieldAuthor:
+			filter.Authors = append(filter.Authors, term.Value)
+		case providers.QueryFieldCategory:
+			filter.Categories = append(filter.Categories, term.Value)
+		case providers.QueryFieldYear:
+			if term.YearFrom != 0 {
+				from := time.Date(term.YearFrom, time.January, 1, 0, 0, 0, 0, time.UTC)
+				filter.PublishedFrom = later(filter.PublishedFrom, &from)
+			}
+			if term.YearTo != 0 {
+				to := time.Date(term.YearTo+1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+				filter.PublishedTo = earlier(filter.PublishedTo, &to)
+			}
+		default:
+			return "", nil, errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+		}
+	}
+
+	return providers.FreeText(terms), filter, nil
+}
+
+// translateFilters converts the common search filters to a repository filter
+func translateFilters(query *providers.SearchQuery) (*models.PaperFilter, error) {
+	filter := &models.PaperFilter{
+		PublishedFrom: query.DateFrom,
+		PublishedTo:   query.DateTo,
+		Language:      query.Language,
+		Authors:       append([]string(nil), query.Authors...),
+		Categories:    append([]string(nil), query.Categories...),
+	}
+
+	filter.Authors = append(filter.Authors, splitValues(query.Filters[providers.FilterAuthor])...)
+	filter.Categories = append(filter.Categories, splitValues(query.Filters[providers.FilterCategory])...)
+
+	if journal := strings.TrimSpace(query.Filters[providers.FilterJournal]); journal != "" {
+		filter.Journal = journal
+	}
+	if language := strings.TrimSpace(query.Filters[providers.FilterLanguage]); language != "" {
+		filter.Language = language
+	}
+
+	var err error
+	if filter.MinCitations, err = countFilter(query, providers.FilterMinCitations); err != nil {
+		return nil, err
+	}
+	if filter.MaxCitations, err = countFilter(query, providers.FilterMaxCitations); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// countFilter parses a non-negative integer filter, returning nil when unset
+func countFilter(query *providers.SearchQuery, name string) (*int, error) {
+	value := strings.TrimSpace(query.Filters[name])
+	if value == "" {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return nil, errors.NewValidationError(name+" must be a non-negative integer", name, value)
+	}
+	return &count, nil
+}
+
+// splitValues splits a comma separated filter value
+func splitValues(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// later returns the later of two optional times
+func later(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// earlier returns the earlier of two optional times
+func earlier(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,18 +26,105 @@ type Manager struct {
 	aggregationStrategy AggregationStrategy
 	maxConcurrency     int
 	timeout            time.Duration
+	providerWeights    map[string]float64
+	rrfK               int
+	balancer           *loadBalancer
+
+	cacheTTL           time.Duration
+
+	// Retry policy, overridden per provider by UpdateProviderConfig
+	retryConfig     errors.RetryConfig
+	providerRetries map[string]errors.RetryConfig
+	retriers        map[string]*errors.RetryExecutor
+	classifier      *errors.ErrorClassifier
+
+	// Timeout budgets and hedging, see ManagerConfig
+	providerTimeouts map[string]time.Duration
+	softDeadline     time.Duration
+	hedging          bool
+	hedgeMinSamples  int
+
+	// Counters maintained by the manager, keyed by provider
+	rateLimitHits   map[string]int64
+	rateLimitResets map[string]int64
+	cacheHits       map[string]int64
+	hedgedRequests  map[string]int64
+	latencies       map[string]*latencyWindow
 }
 
 // NewManager creates a new provider manager
 func NewManager(logger *slog.Logger, config ManagerConfig) *Manager {
+	weights := make(map[string]float64, len(config.ProviderWeights))
+	for name, weight := range config.ProviderWeights {
+		if weight > 0 {
+			weights[name] = weight
+		}
+	}
+
+	rrfK := config.RRFK
+	if rrfK <= 0 {
+		rrfK = DefaultRRFK
+	}
+
+	timeouts := make(map[string]time.Duration, len(config.ProviderTimeouts))
+	for name, timeout := range config.ProviderTimeouts {
+		if timeout > 0 {
+			timeouts[name] = timeout
+		}
+	}
+
+	hedgeMinSamples := config.HedgeMinSamples
+	if hedgeMinSamples <= 0 {
+		hedgeMinSamples = DefaultHedgeMinSamples
+	}
+
 	return &Manager{
 		providers:           make(map[string]SearchProvider),
 		enabled:             make(map[string]bool),
+		rateLimit:           config.RateLimiter,
+		cache:               config.Cache,
+		circuitBreaker:      config.CircuitBreaker,
 		logger:              logger,
 		aggregationStrategy: config.AggregationStrategy,
 		maxConcurrency:      config.MaxConcurrency,
 		timeout:             config.Timeout,
+		providerWeights:     weights,
+		rrfK:                rrfK,
+		balancer:            newLoadBalancer(config.SelectionWeights),
+		cacheTTL:            config.CacheTTL,
+		retryConfig:         config.Retry,
+		providerRetries:     make(map[string]errors.RetryConfig),
+		retriers:            make(map[string]*errors.RetryExecutor),
+		classifier:          errors.NewErrorClassifier(),
+		providerTimeouts:    timeouts,
+		softDeadline:        config.SoftDeadline,
+		hedging:             config.Hedging,
+		hedgeMinSamples:     hedgeMinSamples,
+		rateLimitHits:       make(map[string]int64),
+		rateLimitResets:     make(map[string]int64),
+		cacheHits:           make(map[string]int64),
+		hedgedRequests:      make(map[string]int64),
+		latencies:           make(map[string]*latencyWindow),
+	}
+}
+
+// SetProviderWeight sets the fusion weight of a provider. A weight of zero or
+// less resets the provider to the default weight of 1.
+func (m *Manager) SetProviderWeight(name string, weight float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if weight <= 0 {
+		delete(m.providerWeights, name)
+		return
 	}
+	m.providerWeights[name] = weight
+}
+
+// SetSelectionWeight sets the weight used by the weighted-random strategy.
+// A weight of zero or less resets the provider to the default weight of 1.
+func (m *Manager) SetSelectionWeight(name string, weight float64) {
+	m.balancer.setWeight(name, weight)
 }
 
 // RegisterProvider registers a search provider
@@ -51,6 +139,17 @@ func (m *Manager) RegisterProvider(name string, provider SearchProvider) error {
 	m.providers[name] = provider
 	m.enabled[name] = provider.IsEnabled()
 
+	// Fall back to the advertised provider quota when no explicit limits exist
+	if m.rateLimit != nil && m.rateLimit.GetLimits(name).Limit == 0 {
+		if perMinute := provider.GetCapabilities().RateLimit; perMinute > 0 {
+			if err := m.rateLimit.UpdateLimits(name, RateLimitConfig{RequestsPerMinute: perMinute}); err != nil {
+				m.logger.Warn("Failed to apply provider rate limit",
+					slog.String("name", name),
+					slog.String("error", err.Error()))
+			}
+		}
+	}
+
 	m.logger.Info("Provider registered",
 		slog.String("name", name),
 		slog.Bool("enabled", provider.IsEnabled()))
@@ -118,9 +217,59 @@ func (m *Manager) SearchAll(ctx context.Context, query *SearchQuery) (*Aggregate
 
 // SearchProviders searches specific providers
 func (m *Manager) SearchProviders(ctx context.Context, query *SearchQuery, providerNames []string) (*AggregatedResult, error) {
-	_ = time.Now() // Removed unused searchStart variable
+	validProviders, err := m.resolveProviders(providerNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Later pages only continue the providers that still have results
+	cursor := newPageCursor(query)
+	if query.PageToken != "" {
+		decoded, err := DecodePageToken(query.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPageCursor(decoded, query); err != nil {
+			return nil, err
+		}
+		cursor = decoded
+		validProviders = cursor.pending(validProviders)
+		if len(validProviders) == 0 {
+			return m.emptyPage(query), nil
+		}
+	}
+	paged := *query
+	paged.pageCursor = cursor
+
+	// Execute searches based on aggregation strategy
+	var result *AggregatedResult
+	switch m.aggregationStrategy {
+	case StrategyFirst:
+		result, err = m.searchFirst(ctx, &paged, validProviders)
+	case StrategyFastest:
+		result, err = m.searchFastest(ctx, &paged, validProviders)
+	case StrategyBestQuality:
+		result, err = m.searchBestQuality(ctx, &paged, validProviders)
+	case StrategyRoundRobin:
+		result, err = m.searchRoundRobin(ctx, &paged, validProviders)
+	case StrategyWeightedRandom:
+		result, err = m.searchWeightedRandom(ctx, &paged, validProviders)
+	case StrategyReciprocalRank:
+		result, err = m.searchReciprocalRank(ctx, &paged, validProviders)
+	default: // StrategyMerge
+		result, err = m.searchMerge(ctx, &paged, validProviders)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.paginate(query, cursor, result)
+	return result, nil
+}
 
-	// Validate providers
+// resolveProviders returns the enabled providers among the given names,
+// skipping unknown and disabled ones
+func (m *Manager) resolveProviders(providerNames []string) ([]SearchProvider, error) {
 	validProviders := make([]SearchProvider, 0, len(providerNames))
 	for _, name := range providerNames {
 		provider, err := m.GetProvider(name)
@@ -140,27 +289,139 @@ func (m *Manager) SearchProviders(ctx context.Context, query *SearchQuery, provi
 	if len(validProviders) == 0 {
 		return nil, errors.NewValidationError("No valid providers available", "providers", providerNames)
 	}
+	return validProviders, nil
+}
 
-	// Execute searches based on aggregation strategy
-	switch m.aggregationStrategy {
-	case StrategyFirst:
-		return m.searchFirst(ctx, query, validProviders)
-	case StrategyFastest:
-		return m.searchFastest(ctx, query, validProviders)
-	case StrategyBestQuality:
-		return m.searchBestQuality(ctx, query, validProviders)
-	case StrategyRoundRobin:
-		return m.searchRoundRobin(ctx, query, validProviders)
-	default: // StrategyMerge
-		return m.searchMerge(ctx, query, validProviders)
+// paginate drops papers already returned on earlier pages, advances the
+// page cursor past the providers' results and sets the token of the next
+// page. Strategies that fan out continue every queried provider, providers
+// that failed are retried from the same position; the other strategies only
+// continue the provider that answered. The best quality strategy keeps the
+// best papers up to the query limit and continues every provider after the
+// papers it returned.
+func (m *Manager) paginate(query *SearchQuery, cursor *PageCursor, result *AggregatedResult) {
+	limit := 0
+	if m.aggregationStrategy == StrategyBestQuality {
+		limit = query.Limit
+	}
+	result.Papers = cursor.filterSeen(result.Papers, limit)
+
+	continuing := result.SuccessfulProviders
+	if fansOut(m.aggregationStrategy) {
+		continuing = result.RequestedProviders
+	}
+
+	successful := make(map[string]bool, len(result.SuccessfulProviders))
+	for _, name := range result.SuccessfulProviders {
+		successful[name] = true
+	}
+	for _, name := range continuing {
+		providerResult := result.ProviderResults[name]
+		switch {
+		case !successful[name] || providerResult == nil:
+			if _, ok := cursor.Providers[name]; !ok {
+				cursor.Providers[name] = ProviderCursor{Offset: query.Offset, Cursor: query.Cursor}
+			}
+		case limit > 0:
+			cursor.advanceReturned(name, query, providerResult)
+		default:
+			cursor.advance(name, query, providerResult)
+		}
+	}
+
+	if fansOut(m.aggregationStrategy) {
+		result.TotalCount = len(result.Papers)
+	}
+
+	result.HasMore = cursor.hasMore()
+	result.NextPageToken = nil
+	if result.HasMore {
+		token, err := EncodePageToken(cursor)
+		if err != nil {
+			m.logger.Warn("Failed to encode page token", slog.String("error", err.Error()))
+			return
+		}
+		result.NextPageToken = &token
+	}
+}
+
+// emptyPage is the result of a page token whose providers are exhausted
+func (m *Manager) emptyPage(query *SearchQuery) *AggregatedResult {
+	return &AggregatedResult{
+		Papers:              []models.Paper{},
+		ProviderResults:     map[string]*SearchResult{},
+		Query:               query.Query,
+		RequestedProviders:  []string{},
+		SuccessfulProviders: []string{},
+		FailedProviders:     []string{},
+		RequestID:           query.RequestID,
+		Timestamp:           time.Now(),
+		AggregationStrategy: string(m.aggregationStrategy),
+		Errors:              []ProviderError{},
 	}
 }
 
 // searchMerge executes searches across all providers and merges results
 func (m *Manager) searchMerge(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
 	searchStart := time.Now()
+	collected := m.collectProviderResults(ctx, query, providers)
+
+	var allPapers []models.Paper
+	for _, name := range collected.successfulProviders {
+		allPapers = append(allPapers, collected.providerResults[name].Papers...)
+	}
+
+	// Deduplicate papers
+	deduplicatedPapers := m.deduplicatePapers(allPapers)
+
+	result := m.buildAggregatedResult(query, providers, collected, deduplicatedPapers, searchStart)
+
+	m.logger.Info("Search completed",
+		slog.String("query", query.Query),
+		slog.Int("total_results", len(deduplicatedPapers)),
+		slog.Int("successful_providers", len(collected.successfulProviders)),
+		slog.Int("failed_providers", len(collected.failedProviders)),
+		slog.Duration("duration", time.Since(searchStart)))
+
+	return result, nil
+}
+
+// searchReciprocalRank executes searches across all providers and fuses the
+// per-provider rankings using reciprocal rank fusion
+func (m *Manager) searchReciprocalRank(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
+	searchStart := time.Now()
+	collected := m.collectProviderResults(ctx, query, providers)
+
+	rankings := make(map[string][]models.Paper, len(collected.successfulProviders))
+	for _, name := range collected.successfulProviders {
+		rankings[name] = collected.providerResults[name].Papers
+	}
+
+	m.mu.RLock()
+	fusedPapers := fuseReciprocalRank(rankings, m.providerWeights, m.rrfK)
+	m.mu.RUnlock()
+
+	result := m.buildAggregatedResult(query, providers, collected, fusedPapers, searchStart)
+
+	m.logger.Info("Search completed",
+		slog.String("query", query.Query),
+		slog.String("strategy", string(StrategyReciprocalRank)),
+		slog.Int("total_results", len(fusedPapers)),
+		slog.Int("successful_providers", len(collected.successfulProviders)),
+		slog.Int("failed_providers", len(collected.failedProviders)),
+		slog.Duration("duration", time.Since(searchStart)))
+
+	return result, nil
+}
+
+// collectProviderResults runs the query against all providers concurrently
+// and gathers their results until every provider answered or the longest
+// provider budget ran out. With a soft deadline the results are returned once
+// it passed and at least one provider succeeded. Providers that had not
+// answered are listed as pending.
+func (m *Manager) collectProviderResults(ctx context.Context, query *SearchQuery, providers []SearchProvider) *collectedResults {
 	resultChan := make(chan *providerResult, len(providers))
-	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	ctx, cancel := context.WithTimeout(ctx, m.searchBudget(providers))
 	defer cancel()
 
 	// Launch searches concurrently
@@ -168,75 +429,81 @@ func (m *Manager) searchMerge(ctx context.Context, query *SearchQuery, providers
 		go m.executeProviderSearch(ctx, provider, query, resultChan)
 	}
 
-	// Collect results
-	providerResults := make(map[string]*SearchResult)
-	var allPapers []models.Paper
-	var totalCount int
-	successfulProviders := make([]string, 0)
-	failedProviders := make([]string, 0)
-	errors := make([]ProviderError, 0)
-	cacheHits := 0
+	collected := newCollectedResults()
+
+	var softDeadline <-chan time.Time
+	if m.softDeadline > 0 {
+		timer := time.NewTimer(m.softDeadline)
+		defer timer.Stop()
+		softDeadline = timer.C
+	}
+	softDeadlinePassed := false
 
-	for i := 0; i < len(providers); i++ {
+collect:
+	for received := 0; received < len(providers); {
 		select {
 		case result := <-resultChan:
-			providerResults[result.providerName] = result.result
-			if result.err != nil {
-				failedProviders = append(failedProviders, result.providerName)
-				errors = append(errors, ProviderError{
-					Provider:  result.providerName,
-					Error:     result.err,
-					Type:      classifyError(result.err),
-					Retryable: isRetryableError(result.err),
-				})
-			} else {
-				successfulProviders = append(successfulProviders, result.providerName)
-				allPapers = append(allPapers, result.result.Papers...)
-				totalCount += result.result.TotalCount
-				if result.result.CacheHit {
-					cacheHits++
-				}
+			received++
+			collected.add(result)
+			if softDeadlinePassed && result.err == nil {
+				break collect
+			}
+		case <-softDeadline:
+			softDeadlinePassed = true
+			softDeadline = nil
+			if len(collected.successfulProviders) > 0 {
+				break collect
 			}
 		case <-ctx.Done():
-			m.logger.Warn("Provider search timeout", slog.String("remaining", fmt.Sprintf("%d", len(providers)-i)))
-			break
+			m.logger.Warn("Provider search timeout", slog.String("remaining", fmt.Sprintf("%d", len(providers)-received)))
+			break collect
 		}
 	}
 
-	// Deduplicate papers
-	deduplicatedPapers := m.deduplicatePapers(allPapers)
+	for _, provider := range providers {
+		if _, done := collected.providerResults[provider.Name()]; !done {
+			collected.pendingProviders = append(collected.pendingProviders, provider.Name())
+		}
+	}
+	if softDeadlinePassed && len(collected.pendingProviders) > 0 {
+		m.logger.Info("Soft deadline passed, returning partial results",
+			slog.Any("pending_providers", collected.pendingProviders))
+	}
+
+	// Keep provider order independent of completion order
+	sort.Strings(collected.successfulProviders)
+	sort.Strings(collected.failedProviders)
+	sort.Strings(collected.pendingProviders)
+
+	return collected
+}
 
-	result := &AggregatedResult{
-		Papers:              deduplicatedPapers,
-		TotalCount:          len(deduplicatedPapers),
-		ProviderResults:     providerResults,
+// buildAggregatedResult assembles the aggregated result for a multi-provider search
+func (m *Manager) buildAggregatedResult(query *SearchQuery, providers []SearchProvider, collected *collectedResults, papers []models.Paper, searchStart time.Time) *AggregatedResult {
+	return &AggregatedResult{
+		Papers:              papers,
+		TotalCount:          len(papers),
+		ProviderResults:     collected.providerResults,
 		Query:               query.Query,
 		RequestedProviders:  getProviderNames(providers),
-		SuccessfulProviders: successfulProviders,
-		FailedProviders:     failedProviders,
+		SuccessfulProviders: collected.successfulProviders,
+		FailedProviders:     collected.failedProviders,
+		PendingProviders:    collected.pendingProviders,
+		FilterSupport:       filterSupport(collected.providerResults),
 		TotalDuration:       time.Since(searchStart),
-		CacheHits:           cacheHits,
+		CacheHits:           collected.cacheHits,
 		RequestID:           query.RequestID,
 		Timestamp:           time.Now(),
 		AggregationStrategy: string(m.aggregationStrategy),
-		PartialFailure:      len(failedProviders) > 0 && len(successfulProviders) > 0,
-		Errors:              errors,
+		PartialFailure:      len(collected.failedProviders)+len(collected.pendingProviders) > 0 && len(collected.successfulProviders) > 0,
+		Errors:              collected.errors,
 	}
-
-	m.logger.Info("Search completed",
-		slog.String("query", query.Query),
-		slog.Int("total_results", len(deduplicatedPapers)),
-		slog.Int("successful_providers", len(successfulProviders)),
-		slog.Int("failed_providers", len(failedProviders)),
-		slog.Duration("duration", time.Since(searchStart)))
-
-	return result, nil
 }
 
 // searchFirst returns the first successful result
 func (m *Manager) searchFirst(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
 	for _, provider := range providers {
-		result, err := provider.Search(ctx, query)
+		result, err := m.searchProvider(ctx, provider, query)
 		if err == nil {
 			return m.wrapSingleResult(result, provider.Name(), query), nil
 		}
@@ -251,13 +518,14 @@ func (m *Manager) searchFirst(ctx context.Context, query *SearchQuery, providers
 // searchFastest returns the fastest result
 func (m *Manager) searchFastest(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
 	resultChan := make(chan *providerResult, 1)
-	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	timeout := m.searchBudget(providers)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Launch all searches
 	for _, provider := range providers {
 		go func(p SearchProvider) {
-			result, err := p.Search(ctx, query)
+			result, err := m.searchProvider(ctx, p, query)
 			select {
 			case resultChan <- &providerResult{p.Name(), result, err}:
 			case <-ctx.Done():
@@ -273,55 +541,103 @@ func (m *Manager) searchFastest(ctx context.Context, query *SearchQuery, provide
 		}
 		return m.wrapSingleResult(result.result, result.providerName, query), nil
 	case <-ctx.Done():
-		return nil, errors.NewTimeoutError("Search timeout", 30*time.Second)
+		return nil, errors.NewTimeoutError("Search timeout", timeout)
 	}
 }
 
-// searchBestQuality returns the highest quality result
+// searchBestQuality returns the merged papers of all providers, best quality
+// first. Pagination keeps the best ones up to the query limit.
 func (m *Manager) searchBestQuality(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
-	// For now, treat this the same as merge and select best papers
 	result, err := m.searchMerge(ctx, query, providers)
 	if err != nil {
 		return nil, err
 	}
 
-	// Sort papers by quality score and take top results
 	m.sortPapersByQuality(result.Papers)
-	if len(result.Papers) > query.Limit {
-		result.Papers = result.Papers[:query.Limit]
+	return result, nil
+}
+
+// HealthCheckAll performs health checks on all providers
+func (m *Manager) HealthCheckAll(ctx context.Context) map[string]error {
+	results := make(map[string]error)
+	for name, provider := range m.GetAllProviders() {
+		results[name] = m.protect(ctx, name, "health_check", func() error {
+			return provider.HealthCheck(ctx)
+		})
 	}
 
-	return result, nil
+	return results
 }
 
-// searchRoundRobin cycles through providers
-func (m *Manager) searchRoundRobin(ctx context.Context, query *SearchQuery, providers []SearchProvider) (*AggregatedResult, error) {
-	// Simple implementation: use the first provider for now
-	// In a real implementation, you'd maintain state about which provider to use next
-	if len(providers) == 0 {
-		return nil, errors.NewValidationError("No providers available", "providers", "none")
+// GetPaper retrieves a paper from a provider within the provider's timeout
+// budget, rate limit, retry policy and circuit breaker
+func (m *Manager) GetPaper(ctx context.Context, providerName, id string) (*models.Paper, error) {
+	provider, err := m.GetProvider(providerName)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := providers[0].Search(ctx, query)
+	timeout := m.providerTimeout(providerName)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var paper *models.Paper
+	err = m.protect(ctx, providerName, "get_paper", func() error {
+		if err := m.acquireRateLimit(ctx, providerName); err != nil {
+			return err
+		}
+		var err error
+		paper, err = provider.GetPaper(ctx, id)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	return paper, nil
+}
 
-	return m.wrapSingleResult(result, providers[0].Name(), query), nil
+// GetProviderStatus returns the status of all providers, with the circuit
+// state kept by the manager's circuit breaker
+func (m *Manager) GetProviderStatus() map[string]ProviderStatus {
+	statuses := make(map[string]ProviderStatus)
+	for name, provider := range m.GetAllProviders() {
+		status := provider.GetStatus()
+		if m.circuitBreaker != nil {
+			status.CircuitState = string(m.circuitBreaker.GetState(name))
+		}
+		statuses[name] = status
+	}
+	return statuses
 }
 
-// HealthCheckAll performs health checks on all providers
-func (m *Manager) HealthCheckAll(ctx context.Context) map[string]error {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// OpenCircuit opens the circuit of a provider until it is reset, taking the
+// provider out of searches
+func (m *Manager) OpenCircuit(name string) error {
+	if err := m.checkCircuit(name); err != nil {
+		return err
+	}
+	m.circuitBreaker.ForceOpen(name)
+	return nil
+}
 
-	results := make(map[string]error)
-	for name, provider := range m.providers {
-		err := provider.HealthCheck(ctx)
-		results[name] = err
+// ResetCircuit closes the circuit of a provider and forgets its failures
+func (m *Manager) ResetCircuit(name string) error {
+	if err := m.checkCircuit(name); err != nil {
+		return err
 	}
+	m.circuitBreaker.Reset(name)
+	return nil
+}
 
-	return results
+// checkCircuit checks that the provider exists and circuit breaking is enabled
+func (m *Manager) checkCircuit(name string) error {
+	if _, err := m.GetProvider(name); err != nil {
+		return err
+	}
+	if m.circuitBreaker == nil {
+		return errors.NewValidationError("circuit breaking is disabled", "provider", name)
+	}
+	return nil
 }
 
 // GetProviderMetrics returns metrics for all providers
@@ -331,7 +647,17 @@ func (m *Manager) GetProviderMetrics() map[string]ProviderMetrics {
 
 	metrics := make(map[string]ProviderMetrics)
 	for name, provider := range m.providers {
-		metrics[name] = provider.GetMetrics()
+		providerMetrics := provider.GetMetrics()
+		providerMetrics.RateLimitHits += m.rateLimitHits[name]
+		providerMetrics.RateLimitResets += m.rateLimitResets[name]
+		providerMetrics.CachedRequests += m.cacheHits[name]
+		providerMetrics.HedgedRequests += m.hedgedRequests[name]
+		if m.circuitBreaker != nil {
+			opened, closed := m.circuitBreaker.GetTransitions(name)
+			providerMetrics.CircuitOpenCount += opened
+			providerMetrics.CircuitCloseCount += closed
+		}
+		metrics[name] = providerMetrics
 	}
 
 	return metrics
@@ -353,6 +679,25 @@ func (m *Manager) UpdateProviderConfig(name string, config ProviderConfig) error
 	}
 
 	m.enabled[name] = config.Enabled
+	if config.Timeout > 0 {
+		m.providerTimeouts[name] = config.Timeout
+	}
+	if m.retryConfig.MaxAttempts > 1 && (config.MaxRetries > 0 || config.RetryDelay > 0) {
+		retry := m.retryConfig
+		if config.MaxRetries > 0 {
+			retry.MaxAttempts = config.MaxRetries + 1
+		}
+		if config.RetryDelay > 0 {
+			retry.InitialDelay = config.RetryDelay
+		}
+		m.providerRetries[name] = retry
+		delete(m.retriers, name)
+	}
+	if m.rateLimit != nil && hasRateLimits(config.RateLimit) {
+		if err := m.rateLimit.UpdateLimits(name, config.RateLimit); err != nil {
+			return err
+		}
+	}
 	m.logger.Info("Provider configuration updated",
 		slog.String("name", name),
 		slog.Bool("enabled", config.Enabled))
@@ -360,6 +705,23 @@ func (m *Manager) UpdateProviderConfig(name string, config ProviderConfig) error
 	return nil
 }
 
+// GetCacheStats returns statistics of the provider result cache
+func (m *Manager) GetCacheStats() CacheStats {
+	if m.cache == nil {
+		return CacheStats{}
+	}
+	return m.cache.GetStats()
+}
+
+// ClearCache removes cached provider results matching a key pattern such as
+// "search.arxiv.*". An empty pattern clears every cached result.
+func (m *Manager) ClearCache(ctx context.Context, pattern string) error {
+	if m.cache == nil {
+		return nil
+	}
+	return m.cache.Clear(ctx, pattern)
+}
+
 // Start initializes all providers
 func (m *Manager) Start(ctx context.Context) error {
 	m.logger.Info("Starting provider manager")
@@ -398,15 +760,101 @@ type providerResult struct {
 	err          error
 }
 
+type collectedResults struct {
+	providerResults     map[string]*SearchResult
+	successfulProviders []string
+	failedProviders     []string
+	pendingProviders    []string // providers that had not answered when collection stopped
+	errors              []ProviderError
+	cacheHits           int
+}
+
+func newCollectedResults() *collectedResults {
+	return &collectedResults{
+		providerResults:     make(map[string]*SearchResult),
+		successfulProviders: make([]string, 0),
+		failedProviders:     make([]string, 0),
+		errors:              make([]ProviderError, 0),
+	}
+}
+
+// add records the outcome of a provider search
+func (c *collectedResults) add(result *providerResult) {
+	c.providerResults[result.providerName] = result.result
+	if result.err != nil {
+		c.failedProviders = append(c.failedProviders, result.providerName)
+		c.errors = append(c.errors, ProviderError{
+			Provider:  result.providerName,
+			Error:     result.err,
+			Type:      classifyError(result.err),
+			Retryable: isRetryableError(result.err),
+		})
+		return
+	}
+
+	c.successfulProviders = append(c.successfulProviders, result.providerName)
+	if result.result.CacheHit {
+		c.cacheHits++
+	}
+}
+
 type ManagerConfig struct {
 	AggregationStrategy AggregationStrategy
 	MaxConcurrency      int
 	Timeout             time.Duration
+
+	// ProviderWeights scales each provider's contribution to fused rankings.
+	// Providers without an entry get a weight of 1.
+	ProviderWeights map[string]float64
+
+	// RRFK is the rank constant used by reciprocal rank fusion (default 60)
+	RRFK int
+
+	// RateLimiter throttles calls to each provider; nil disables rate limiting
+	RateLimiter RateLimiter
+
+	// Cache serves repeated provider queries; nil disables caching
+	Cache CacheManager
+
+	// CacheTTL is how long provider results stay cached (default 1h)
+	CacheTTL time.Duration
+
+	// SelectionWeights biases the weighted-random strategy towards providers
+	// with a higher weight. Providers without an entry get a weight of 1.
+	SelectionWeights map[string]float64
+
+	// CircuitBreaker stops calling providers that keep failing; nil disables
+	// circuit breaking
+	CircuitBreaker CircuitBreaker
+
+	// Retry is the retry policy of provider calls. A MaxAttempts of 1 or less
+	// disables retries. UpdateProviderConfig applies the MaxRetries and
+	// RetryDelay of a provider on top of it.
+	Retry errors.RetryConfig
+
+	// ProviderTimeouts is the time budget of each provider search. Providers
+	// without an entry get Timeout. UpdateProviderConfig replaces the budget
+	// with the configured provider timeout.
+	ProviderTimeouts map[string]time.Duration
+
+	// SoftDeadline makes multi-provider searches return the results that
+	// arrived once it passed, as soon as one provider succeeded. Zero waits
+	// for every provider.
+	SoftDeadline time.Duration
+
+	// Hedging sends a duplicate request to a provider that has not answered
+	// within its p95 latency, if its rate limit allows, and uses whichever
+	// answer arrives first
+	Hedging bool
+
+	// HedgeMinSamples is how many latencies of a provider are needed before
+	// its requests are hedged (default 20)
+	HedgeMinSamples int
 }
 
 func (m *Manager) executeProviderSearch(ctx context.Context, provider SearchProvider, query *SearchQuery, resultChan chan<- *providerResult) {
 	searchStart := time.Now()
-	result, err := provider.Search(ctx, query)
+	result, err := m.searchProvider(ctx, provider, query)
 
 	m.logger.Debug("Provider search completed",
 		slog.String("provider", provider.Name()),
@@ -419,7 +867,114 @@ func (m *Manager) executeProviderSearch(ctx context.Context, provider SearchProv
 	}
 }
 
+// searchProvider calls a single provider. Queries using grammar features the
+// provider does not support are rejected; filters it does not support are
+// applied to the papers it returns, see NegotiateFilters. Cached results are
+// served without calling the provider; otherwise the rate limiter is
+// consulted first and successful results are cached. The call goes through the provider's retry
+// policy and circuit breaker and must finish within its timeout budget.
+func (m *Manager) searchProvider(ctx context.Context, provider SearchProvider, query *SearchQuery) (*SearchResult, error) {
+	name := provider.Name()
+	if query.pageCursor != nil {
+		query = query.pageCursor.providerQuery(query, name)
+	}
+
+	timeout := m.providerTimeout(name)
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return nil, err
+	}
+	caps := provider.GetCapabilities()
+	if err := CheckQuerySupport(name, caps, parsed); err != nil {
+		return nil, err
+	}
+	providerQuery, filterSupport, err := NegotiateFilters(name, caps, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cacheKey string
+	if m.cache != nil {
+		cacheKey = CacheKey(query, name)
+		if cached, err := m.cache.Get(ctx, cacheKey); err == nil && cached.Result != nil {
+			m.mu.Lock()
+			m.cacheHits[name]++
+			m.mu.Unlock()
+
+			result := cached.Result
+			result.CacheHit = true
+			result.RequestID = query.RequestID
+			return result, nil
+		}
+	}
+
+	var result *SearchResult
+	err = m.protect(ctx, name, "search", func() error {
+		if err := m.acquireRateLimit(ctx, name); err != nil {
+			return err
+		}
+		var err error
+		result, err = m.callProvider(ctx, provider, providerQuery)
+		return err
+	})
+	if err != nil {
+		// Report a missed budget as a timeout unless the caller gave up
+		if ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+			return nil, errors.NewTimeoutError("Provider search timeout", timeout)
+		}
+		return nil, err
+	}
+	result = postFilter(query, filterSupport, result)
+
+	if m.cache != nil && result != nil {
+		if err := m.cache.Set(ctx, cacheKey, result, m.cacheTTL); err != nil {
+			m.logger.Warn("Failed to cache search result",
+				slog.String("provider", name),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	return result, nil
+}
+
+// acquireRateLimit takes a token for the provider, blocking while the
+// provider is throttled, and records rate limit hits and resets
+func (m *Manager) acquireRateLimit(ctx context.Context, name string) error {
+	if m.rateLimit == nil || m.rateLimit.Allow(ctx, name) {
+		return nil
+	}
+
+	m.mu.Lock()
+	m.rateLimitHits[name]++
+	m.mu.Unlock()
+
+	m.logger.Debug("Provider rate limited, waiting", slog.String("provider", name))
+
+	if err := m.rateLimit.Wait(ctx, name); err != nil {
+		if !errors.IsRateLimitError(err) {
+			limits := m.rateLimit.GetLimits(name)
+			err = errors.NewRateLimitError("rate limit exceeded for provider "+name, limits.RetryAfter)
+		}
+		return err
+	}
+
+	m.mu.Lock()
+	m.rateLimitResets[name]++
+	m.mu.Unlock()
+
+	return nil
+}
+
 func (m *Manager) wrapSingleResult(result *SearchResult, providerName string, query *SearchQuery) *AggregatedResult {
+	cacheHits := 0
+	if result.CacheHit {
+		cacheHits = 1
+	}
+
 	return &AggregatedResult{
 		Papers:              result.Papers,
 		TotalCount:          result.TotalCount,
@@ -428,8 +983,9 @@ func (m *Manager) wrapSingleResult(result *SearchResult, providerName string, qu
 		RequestedProviders:  []string{providerName},
 		SuccessfulProviders: []string{providerName},
 		FailedProviders:     []string{},
+		FilterSupport:       filterSupport(map[string]*SearchResult{providerName: result}),
 		TotalDuration:       result.Duration,
-		CacheHits:           0,
+		CacheHits:           cacheHits,
 		RequestID:           query.RequestID,
 		Timestamp:           time.Now(),
 		AggregationStrategy: string(m.aggregationStrategy),
@@ -438,25 +994,10 @@ func (m *Manager) wrapSingleResult(result *SearchResult, providerName string, qu
 	}
 }
 
+// deduplicatePapers resolves records describing the same work across
+// providers and merges them into a single paper per work
 func (m *Manager) deduplicatePapers(papers []models.Paper) []models.Paper {
-	seen := make(map[string]bool)
-	var unique []models.Paper
-
-	for _, paper := range papers {
-		// Use DOI for deduplication if available, otherwise use title
-		key := paper.Title
-		if paper.DOI != nil && *paper.DOI != "" {
-			key = *paper.DOI
-		} else if paper.ArxivID != nil && *paper.ArxivID != "" {
-			key = *paper.ArxivID
-		}
-
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, paper)
-		}
-	}
-
+	unique, _ := resolvePapers(papers)
 	return unique
 }
 
@@ -472,6 +1013,32 @@ func (m *Manager) sortPapersByQuality(papers []models.Paper) {
 	}
 }
 
+// fansOut reports whether a strategy queries all providers for every page
+func fansOut(strategy AggregationStrategy) bool {
+	switch strategy {
+	case StrategyFirst, StrategyFastest, StrategyRoundRobin, StrategyWeightedRandom:
+		return false
+	default:
+		return true
+	}
+}
+
+// filterSupport collects how the providers applied the query filters, nil
+// when the query had none
+func filterSupport(results map[string]*SearchResult) map[string]FilterSupport {
+	var support map[string]FilterSupport
+	for name, result := range results {
+		if result == nil || result.Filters == nil {
+			continue
+		}
+		if support == nil {
+			support = make(map[string]FilterSupport)
+		}
+		support[name] = *result.Filters
+	}
+	return support
+}
+
 func getProviderNames(providers []SearchProvider) []string {
 	names := make([]string, len(providers))
 	for i, provider := range providers {
@@ -482,12 +1049,16 @@ func getProviderNames(providers []SearchProvider) []string {
 
 func classifyError(err error) string {
 	switch {
+	case errors.IsCircuitOpenError(err):
+		return "circuit_open"
 	case errors.IsTimeoutError(err):
 		return "timeout"
 	case errors.IsRateLimitError(err):
 		return "rate_limit"
 	case errors.IsNetworkError(err):
 		return "network"
+	case errors.IsUnsupportedQueryError(err):
+		return "unsupported"
 	case errors.IsValidationError(err):
 		return "validation"
 	default:
@@ -0,0 +1,725 @@
+package openalex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+const (
+	defaultBaseURL  = "https://api.openalex.org"
+	providerName    = "openalex"
+	maxResults      = 200   // OpenAlex per-page limit
+	maxOffset       = 10000 // OpenAlex rejects page paging beyond this, use cursors instead
+	maxCitations    = 200   // citing works fetched by GetPaper
+	minConceptScore = 0.3   // concepts below this score are too loosely tagged to keep
+)
+
+// Provider implements the OpenAlex search provider
+type Provider struct {
+	config     providers.ProviderConfig
+	httpClient *http.Client
+	logger     *slog.Logger
+	metrics    *providers.ProviderMetrics
+	enabled    bool
+	mailto     string
+}
+
+// NewProvider creates a new OpenAlex provider. Setting Custom["mailto"] in
+// the config routes requests to the OpenAlex polite pool.
+func NewProvider(config providers.ProviderConfig, logger *slog.Logger) *Provider {
+	httpClient := &http.Client{
+		Timeout: config.Timeout,
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	return &Provider{
+		config:     config,
+		httpClient: httpClient,
+		logger:     logger,
+		metrics:    &providers.ProviderMetrics{},
+		enabled:    config.Enabled,
+		mailto:     configMailto(config),
+	}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// IsEnabled returns whether the provider is enabled
+func (p *Provider) IsEnabled() bool {
+	return p.enabled
+}
+
+// GetCapabilities returns provider capabilities
+func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
+	return providers.ProviderCapabilities{
+		SupportsFullText:       false,
+		SupportsDateFilter:     true,
+		SupportsAuthFilter:     false, // authorship filters need OpenAlex author IDs
+		SupportsCategoryFilter: false, // concepts are filtered by ID, see FilterConcept
+		SupportsSort:           true,
+
+		SupportedFields:    []string{"title", "abstract", "year", "concept", "institution", "citations", "open_access"},
+		SupportedLanguages: []string{"en"},
+		SupportedFormats:   []string{"json"},
+
+		MaxResults:     maxResults,
+		MaxQueryLength: 1000,
+		RateLimit:      600, // 10 requests per second
+
+		SupportsRealtime:    true,
+		SupportsExactMatch:  true,
+		SupportsFuzzySearch: false,
+		SupportsWildcards:   false,
+
+		QueryFields:          []string{"title", "abstract", "year", "cat"},
+		SupportsBooleanQuery: false,
+	}
+}
+
+// Search performs a search using the OpenAlex works API
+func (p *Provider) Search(ctx context.Context, query *providers.SearchQuery) (*providers.SearchResult, error) {
+	start := time.Now()
+
+	searchResponse, works, err := p.fetchWorks(ctx, query)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
+
+	// When no page size holds the whole offset window the page ends early,
+	// the rest of the results come from the start of the following page
+	wanted := pageSize(query.Limit)
+	if query.Cursor == "" && query.Offset > 0 && len(searchResponse.Results) > 0 &&
+		len(works) < wanted && query.Offset+len(works) < searchResponse.Meta.Count {
+		next := *query
+		next.Offset = query.Offset + len(works)
+		next.Limit = wanted - len(works)
+		_, rest, err := p.fetchWorks(ctx, &next)
+		if err != nil {
+			p.updateMetrics(false, time.Since(start), err)
+			return nil, err
+		}
+		works = append(works, rest...)
+	}
+
+	papers := make([]models.Paper, 0, len(works))
+	for _, work := range works {
+		paper, err := p.convertWork(work)
+		if err != nil {
+			p.logger.Warn("Failed to convert OpenAlex work",
+				slog.String("id", work.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		papers = append(papers, *paper)
+	}
+
+	duration := time.Since(start)
+	p.updateMetrics(true, duration, nil)
+
+	totalCount := searchResponse.Meta.Count
+	result := &providers.SearchResult{
+		Papers:      papers,
+		TotalCount:  totalCount,
+		ResultCount: len(papers),
+		Query:       query.Query,
+		Provider:    providerName,
+		Duration:    duration,
+		CacheHit:    false,
+		RequestID:   query.RequestID,
+		Timestamp:   time.Now(),
+		Success:     true,
+		HasMore:     query.Offset+len(works) < totalCount,
+	}
+	if query.Cursor != "" {
+		// Cursor pages carry no position, OpenAlex stops returning a cursor after the last page
+		result.HasMore = searchResponse.Meta.NextCursor != nil && len(searchResponse.Results) > 0
+	}
+
+	if result.HasMore && searchResponse.Meta.NextCursor != nil {
+		nextCursor := *searchResponse.Meta.NextCursor
+		result.NextCursor = &nextCursor
+	}
+
+	p.logger.Debug("OpenAlex search completed",
+		slog.String("query", query.Query),
+		slog.Int("results", len(papers)),
+		slog.Int("total", totalCount),
+		slog.Duration("duration", duration))
+
+	return result, nil
+}
+
+// fetchWorks requests one page of search results and drops the results
+// before the query offset
+func (p *Provider) fetchWorks(ctx context.Context, query *providers.SearchQuery) (*OpenAlexSearchResponse, []OpenAlexWork, error) {
+	// Build request URL
+	reqURL, skip, err := p.buildSearchURL(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build OpenAlex query: %w", err)
+	}
+
+	// Make HTTP request
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("OpenAlex API request failed: %w", err)
+	}
+
+	// Parse response
+	var searchResponse OpenAlexSearchResponse
+	if err := json.Unmarshal(response, &searchResponse); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse OpenAlex response: %w", err)
+	}
+
+	works := searchResponse.Results
+	if skip > 0 {
+		works = works[min(skip, len(works)):]
+	}
+	return &searchResponse, works, nil
+}
+
+// GetPaper retrieves a specific paper by OpenAlex work ID or DOI. The
+// citing works are looked up with a second request.
+func (p *Provider) GetPaper(ctx context.Context, id string) (*models.Paper, error) {
+	start := time.Now()
+
+	key, err := workKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := p.withMailto(p.config.BaseURL+"/works/"+key, url.Values{})
+
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("OpenAlex API request failed: %w", err)
+	}
+
+	var work OpenAlexWork
+	if err := json.Unmarshal(response, &work); err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to parse OpenAlex response: %w", err)
+	}
+
+	paper, err := p.convertWork(work)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to convert paper: %w", err)
+	}
+
+	// Citations are best effort, the paper itself is still useful without them
+	if work.CitedByCount > 0 {
+		citations, err := p.getCitations(ctx, ShortID(work.ID))
+		if err != nil {
+			p.logger.Warn("Failed to fetch OpenAlex citations",
+				slog.String("id", work.ID),
+				slog.String("error", err.Error()))
+		} else {
+			paper.Citations = citations
+		}
+	}
+
+	p.updateMetrics(true, time.Since(start), nil)
+	return paper, nil
+}
+
+// HealthCheck checks if the OpenAlex API is accessible
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+
+	params := url.Values{}
+	params.Set("search", "test")
+	params.Set("per-page", "1")
+	_, err := p.makeRequest(ctx, p.withMailto(p.config.BaseURL+"/works", params))
+	if err != nil {
+		return errors.NewHealthCheckError("Health check failed: "+err.Error(), providerName)
+	}
+
+	p.logger.Debug("OpenAlex health check passed", slog.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// GetStatus returns the current provider status
+func (p *Provider) GetStatus() providers.ProviderStatus {
+	return providers.ProviderStatus{
+		Name:            providerName,
+		Enabled:         p.enabled,
+		Healthy:         true,
+		LastCheck:       time.Now(),
+		CircuitState:    "closed",
+		RateLimited:     false,
+		AvgResponseTime: p.calculateAvgResponseTime(),
+		SuccessRate:     p.calculateSuccessRate(),
+		APIVersion:      "v1",
+		LastUpdated:     time.Now(),
+	}
+}
+
+// GetMetrics returns provider metrics
+func (p *Provider) GetMetrics() providers.ProviderMetrics {
+	return *p.metrics
+}
+
+// Configure updates the provider configuration
+func (p *Provider) Configure(config providers.ProviderConfig) error {
+	if err := p.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	p.config = config
+	p.enabled = config.Enabled
+	p.mailto = configMailto(config)
+
+	// Update HTTP client timeout
+	p.httpClient.Timeout = config.Timeout
+
+	p.logger.Info("OpenAlex provider configured",
+		slog.Bool("enabled", config.Enabled),
+		slog.Bool("polite_pool", p.mailto != ""),
+		slog.Duration("timeout", config.Timeout))
+
+	return nil
+}
+
+// ValidateConfig validates the provider configuration
+func (p *Provider) ValidateConfig(config providers.ProviderConfig) error {
+	if config.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if config.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
+
+	return ValidateOpenAlexConfig(config.Custom)
+}
+
+// buildSearchURL builds the works search URL. The first page starts cursor
+// paging so later pages can continue from NextCursor. OpenAlex pages by page
+// number, so explicit offsets fetch the page holding the offset, sized to
+// also hold the requested results where possible, and return how many
+// leading results to skip.
+func (p *Provider) buildSearchURL(query *providers.SearchQuery) (string, int, error) {
+	params := url.Values{}
+	text := query.Query
+
+	perPage := pageSize(query.Limit)
+	params.Set("per-page", strconv.Itoa(perPage))
+
+	skip := 0
+	switch {
+	case query.Cursor != "":
+		params.Set("cursor", query.Cursor)
+	case query.Offset+perPage > maxOffset:
+		return "", 0, errors.NewValidationError(
+			fmt.Sprintf("OpenAlex offsets are limited to %d, page with cursors instead", maxOffset),
+			"offset",
+			query.Offset,
+		)
+	case query.Offset > 0:
+		if size := offsetPageSize(query.Offset, perPage); size > 0 {
+			perPage = size
+			params.Set("per-page", strconv.Itoa(perPage))
+		}
+		params.Set("page", strconv.Itoa(query.Offset/perPage+1))
+		skip = query.Offset % perPage
+	default:
+		params.Set("cursor", "*")
+	}
+
+	var filters []string
+
+	// Query grammar
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return "", 0, err
+	}
+	if parsed != nil {
+		translated, err := translateQuery(parsed)
+		if err != nil {
+			return "", 0, err
+		}
+		text = translated.text
+		filters = append(filters, translated.filters...)
+	}
+	if strings.TrimSpace(text) != "" {
+		params.Set("search", text)
+	}
+
+	// Filters
+	translatedFilters, err := translateFilters(query)
+	if err != nil {
+		return "", 0, err
+	}
+	filters = append(filters, translatedFilters...)
+	if len(filters) > 0 {
+		params.Set("filter", strings.Join(filters, ","))
+	}
+
+	// Sorting
+	order := "desc"
+	if query.SortOrder == "asc" {
+		order = "asc"
+	}
+	switch query.SortBy {
+	case providers.SortDate:
+		params.Set("sort", SortPublished+":"+order)
+	case providers.SortCitations:
+		params.Set("sort", SortCitations+":"+order)
+	}
+
+	return p.withMailto(p.config.BaseURL+"/works", params), skip, nil
+}
+
+// getCitations returns the IDs of works citing the given work
+func (p *Provider) getCitations(ctx context.Context, workID string) ([]string, error) {
+	params := url.Values{}
+	params.Set("filter", "cites:"+workID)
+	params.Set("select", "id")
+	params.Set("per-page", strconv.Itoa(maxCitations))
+
+	response, err := p.makeRequest(ctx, p.withMailto(p.config.BaseURL+"/works", params))
+	if err != nil {
+		return nil, err
+	}
+
+	var citing OpenAlexSearchResponse
+	if err := json.Unmarshal(response, &citing); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAlex response: %w", err)
+	}
+
+	citations := make([]string, 0, len(citing.Results))
+	for _, work := range citing.Results {
+		citations = append(citations, ShortID(work.ID))
+	}
+	return citations, nil
+}
+
+// withMailto adds the polite pool mailto parameter and encodes the URL
+func (p *Provider) withMailto(baseURL string, params url.Values) string {
+	if p.mailto != "" {
+		params.Set("mailto", p.mailto)
+	}
+	if len(params) == 0 {
+		return baseURL
+	}
+	return baseURL + "?" + params.Encode()
+}
+
+// makeRequest makes an HTTP request to the OpenAlex API
+func (p *Provider) makeRequest(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	userAgent := "SciFIND-Backend/1.0"
+	if p.mailto != "" {
+		userAgent += " (mailto:" + p.mailto + ")"
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.NewNotFoundError("Work not found in OpenAlex", reqURL)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter := resp.Header.Get("Retry-After")
+		var retryDuration time.Duration
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			retryDuration = time.Duration(seconds) * time.Second
+		}
+		return nil, errors.NewRateLimitError(
+			fmt.Sprintf("OpenAlex API rate limit exceeded. Retry after: %s", retryAfter),
+			retryDuration,
+		)
+	case resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout:
+		return nil, errors.NewNetworkError(
+			fmt.Sprintf("OpenAlex API unavailable: %d", resp.StatusCode),
+			nil,
+		)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("OpenAlex API client error: %d %s", resp.StatusCode, strings.TrimSpace(string(body))),
+			"status",
+			resp.StatusCode,
+		)
+	default:
+		return nil, errors.NewInternalError(
+			fmt.Sprintf("OpenAlex API returned unexpected status %d", resp.StatusCode),
+			nil,
+		)
+	}
+}
+
+// convertWork converts an OpenAlex work to our Paper model
+func (p *Provider) convertWork(work OpenAlexWork) (*models.Paper, error) {
+	workID := ShortID(work.ID)
+	if !workIDPattern.MatchString(workID) {
+		return nil, fmt.Errorf("work ID is required")
+	}
+
+	title := strings.TrimSpace(work.Title)
+	if title == "" {
+		title = strings.TrimSpace(work.DisplayName)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("work title is required")
+	}
+
+	// Convert authors, keeping the first institution as affiliation
+	authors := make([]models.Author, 0, len(work.Authorships))
+	for _, authorship := range work.Authorships {
+		name := strings.TrimSpace(authorship.Author.DisplayName)
+		if name == "" {
+			continue
+		}
+		author := models.Author{Name: name}
+		if orcid := normalizeORCID(authorship.Author.ORCID); orcid != "" {
+			author.ORCID = &orcid
+		}
+		var affiliation string
+		if len(authorship.Institutions) > 0 {
+			affiliation = authorship.Institutions[0].DisplayName
+		}
+		if affiliation == "" && len(authorship.RawAffiliationStrings) > 0 {
+			affiliation = authorship.RawAffiliationStrings[0]
+		}
+		if affiliation = strings.TrimSpace(affiliation); affiliation != "" {
+			author.Affiliation = &affiliation
+		}
+		authors = append(authors, author)
+	}
+
+	var doi *string
+	if normalized := NormalizeDOI(work.DOI); normalized != "" {
+		doi = &normalized
+	}
+
+	// Publication details
+	var journal, volume, issue, pages *string
+	if work.PrimaryLocation != nil && work.PrimaryLocation.Source != nil && work.PrimaryLocation.Source.DisplayName != "" {
+		journal = &work.PrimaryLocation.Source.DisplayName
+	}
+	if work.Biblio.Volume != "" {
+		volume = &work.Biblio.Volume
+	}
+	if work.Biblio.Issue != "" {
+		issue = &work.Biblio.Issue
+	}
+	if pageRange := work.Biblio.Pages(); pageRange != "" {
+		pages = &pageRange
+	}
+
+	var publishedAt *time.Time
+	if date, err := time.Parse("2006-01-02", work.PublicationDate); err == nil {
+		publishedAt = &date
+	} else if work.PublicationYear > 0 {
+		date := time.Date(work.PublicationYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+		publishedAt = &date
+	}
+
+	var abstract *string
+	if text := work.Abstract(); text != "" {
+		abstract = &text
+	}
+
+	// Links, preferring the landing page over the OpenAlex record
+	paperURL := work.ID
+	if work.PrimaryLocation != nil && work.PrimaryLocation.LandingPageURL != "" {
+		paperURL = work.PrimaryLocation.LandingPageURL
+	}
+	sourceURL := work.ID
+	var pdfURL *string
+	for _, location := range []*OpenAlexLocation{work.PrimaryLocation, work.BestOALocation} {
+		if location != nil && location.PDFURL != "" {
+			pdfURL = &location.PDFURL
+			break
+		}
+	}
+
+	// Concepts become keywords
+	var keywords []string
+	for _, concept := range work.Concepts {
+		if concept.Score >= minConceptScore && concept.DisplayName != "" {
+			keywords = append(keywords, concept.DisplayName)
+		}
+	}
+
+	references := make([]string, 0, len(work.ReferencedWorks))
+	for _, reference := range work.ReferencedWorks {
+		references = append(references, ShortID(reference))
+	}
+
+	language := "en"
+	if len(work.Language) == 2 {
+		language = strings.ToLower(work.Language)
+	}
+
+	paper := &models.Paper{
+		ID:              providerName + "_" + workID,
+		DOI:             doi,
+		Title:           title,
+		Abstract:        abstract,
+		Authors:         authors,
+		Journal:         journal,
+		Volume:          volume,
+		Issue:           issue,
+		Pages:           pages,
+		PublishedAt:     publishedAt,
+		URL:             &paperURL,
+		PDFURL:          pdfURL,
+		Keywords:        keywords,
+		Language:        language,
+		CitationCount:   work.CitedByCount,
+		References:      references,
+		SourceProvider:  providerName,
+		SourceID:        workID,
+		SourceURL:       &sourceURL,
+		ProcessingState: "completed",
+	}
+
+	// Calculate quality score
+	paper.UpdateQualityScore()
+
+	return paper, nil
+}
+
+// workKey returns the /works path key for an OpenAlex work ID or DOI
+func workKey(id string) (string, error) {
+	if IsWorkID(id) {
+		return ShortID(id), nil
+	}
+
+	doi := NormalizeDOI(id)
+	if !strings.HasPrefix(doi, "10.") {
+		return "", errors.NewValidationError("OpenAlex work ID or DOI is required", "id", id)
+	}
+
+	// Keep the DOI's slashes, OpenAlex resolves doi: keys as paths
+	segments := strings.Split(doi, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "doi:" + strings.Join(segments, "/"), nil
+}
+
+// pageSize returns the per-page value to request for a query limit
+func pageSize(limit int) int {
+	if limit <= 0 || limit > maxResults {
+		return maxResults
+	}
+	return limit
+}
+
+// offsetPageSize returns the smallest page size of at least limit whose page
+// holding the offset holds all limit results, 0 when every size splits them
+func offsetPageSize(offset, limit int) int {
+	for size := limit; size <= maxResults; size++ {
+		if offset/size == (offset+limit-1)/size {
+			return size
+		}
+	}
+	return 0
+}
+
+// normalizeORCID extracts the bare ORCID identifier from an ORCID URL
+func normalizeORCID(orcid string) string {
+	orcid = strings.TrimSpace(orcid)
+	if i := strings.LastIndex(orcid, "/"); i >= 0 {
+		orcid = orcid[i+1:]
+	}
+	return orcid
+}
+
+// configMailto returns the polite pool contact address from the config
+func configMailto(config providers.ProviderConfig) string {
+	mailto, _ := config.Custom["mailto"].(string)
+	return strings.TrimSpace(mailto)
+}
+
+// Helper methods
+func (p *Provider) updateMetrics(success bool, duration time.Duration, err error) {
+	p.metrics.TotalRequests++
+
+	if success {
+		p.metrics.SuccessfulRequests++
+	} else {
+		p.metrics.FailedRequests++
+
+		// Categorize errors
+		if err != nil {
+			switch {
+			case errors.IsTimeoutError(err):
+				p.metrics.TimeoutErrors++
+			case errors.IsRateLimitError(err):
+				p.metrics.RateLimitErrors++
+			case errors.IsNetworkError(err):
+				p.metrics.NetworkErrors++
+			default:
+				p.metrics.ParseErrors++
+			}
+		}
+	}
+
+	// Update response time statistics
+	if p.metrics.MinResponseTime == 0 || duration < p.metrics.MinResponseTime {
+		p.metrics.MinResponseTime = duration
+	}
+	if duration > p.metrics.MaxResponseTime {
+		p.metrics.MaxResponseTime = duration
+	}
+
+	// Simple moving average for response time
+	if p.metrics.AvgResponseTime == 0 {
+		p.metrics.AvgResponseTime = duration
+	} else {
+		p.metrics.AvgResponseTime = (p.metrics.AvgResponseTime + duration) / 2
+	}
+}
+
+func (p *Provider) calculateAvgResponseTime() time.Duration {
+	return p.metrics.AvgResponseTime
+}
+
+func (p *Provider) calculateSuccessRate() float64 {
+	if p.metrics.TotalRequests == 0 {
+		return 1.0
+	}
+	return float64(p.metrics.SuccessfulRequests) / float64(p.metrics.TotalRequests)
+}
@@ -0,0 +1,149 @@
+package openalex
+
+import (
+	"fmt"
+	"strings"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// searchParams holds the search text and works filters translated from a
+// query AST
+type searchParams struct {
+	text    string
+	filters []string
+}
+
+// translateQuery converts a query AST to works search parameters. Filters
+// are always combined with AND, so only conjunctions are supported; title
+// and abstract terms become search filters, years publication_year ranges
+// and categories concept IDs.
+func translateQuery(node *providers.QueryNode) (*searchParams, error) {
+	terms, ok := node.Conjuncts()
+	if !ok {
+		return nil, errors.NewUnsupportedQueryError(providerName, "boolean operators")
+	}
+
+	params := &searchParams{text: providers.FreeText(terms)}
+	for _, term := range terms {
+		switch term.Field {
+		case "":
+		case providers.QueryFieldTitle:
+			params.filters = append(params.filters, "title.search:"+filterValue(term))
+		case providers.QueryFieldAbstract:
+			params.filters = append(params.filters, "abstract.search:"+filterValue(term))
+		case providers.QueryFieldYear:
+			params.filters = append(params.filters, "publication_year:"+yearRange(term.YearFrom, term.YearTo))
+		case providers.QueryFieldCategory:
+			if !conceptIDPattern.MatchString(term.Value) {
+				return nil, errors.NewUnsupportedQueryError(providerName, "category names, use OpenAlex concept IDs")
+			}
+			params.filters = append(params.filters, "concepts.id:"+strings.ToUpper(term.Value))
+		default:
+			return nil, errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+		}
+	}
+
+	return params, nil
+}
+
+// translateFilters converts the common search filters to works filters
+func translateFilters(query *providers.SearchQuery) ([]string, error) {
+	var filters []string
+
+	if query.DateFrom != nil {
+		filters = append(filters, "from_publication_date:"+query.DateFrom.Format("2006-01-02"))
+	}
+	if query.DateTo != nil {
+		filters = append(filters, "to_publication_date:"+query.DateTo.Format("2006-01-02"))
+	}
+
+	if concepts, ok := query.Filters[providers.FilterConcept]; ok && concepts != "" {
+		var ids []string
+		for _, concept := range splitValues(concepts) {
+			if !conceptIDPattern.MatchString(concept) {
+				return nil, errors.NewValidationError("concept filter expects OpenAlex concept IDs", providers.FilterConcept, concept)
+			}
+			ids = append(ids, strings.ToUpper(concept))
+		}
+		filters = append(filters, "concepts.id:"+strings.Join(ids, "|"))
+	}
+
+	if institutions, ok := query.Filters[providers.FilterInstitution]; ok && institutions != "" {
+		var ids, rors []string
+		for _, institution := range splitValues(institutions) {
+			ror := strings.ToLower(institution[strings.LastIndex(institution, "/")+1:])
+			switch {
+			case institutionIDPattern.MatchString(ShortID(institution)):
+				ids = append(ids, ShortID(institution))
+			case rorPattern.MatchString(ror):
+				rors = append(rors, "https://ror.org/"+ror)
+			default:
+				return nil, errors.NewValidationError("institution filter expects OpenAlex institution IDs or ROR IDs", providers.FilterInstitution, institution)
+			}
+		}
+		if len(ids) > 0 {
+			filters = append(filters, "authorships.institutions.id:"+strings.Join(ids, "|"))
+		}
+		if len(rors) > 0 {
+			filters = append(filters, "authorships.institutions.ror:"+strings.Join(rors, "|"))
+		}
+	}
+
+	if openAccess, ok := query.Filters[providers.FilterOpenAccess]; ok && (openAccess == "true" || openAccess == "false") {
+		filters = append(filters, "is_oa:"+openAccess)
+	}
+
+	if minCitations, ok := query.Filters[providers.FilterMinCitations]; ok && minCitations != "" {
+		var count int
+		if _, err := fmt.Sscanf(minCitations, "%d", &count); err != nil || count < 0 {
+			return nil, errors.NewValidationError("min_citations must be a non-negative integer", providers.FilterMinCitations, minCitations)
+		}
+		filters = append(filters, fmt.Sprintf("cited_by_count:>%d", count-1))
+	}
+	if maxCitations, ok := query.Filters[providers.FilterMaxCitations]; ok && maxCitations != "" {
+		var count int
+		if _, err := fmt.Sscanf(maxCitations, "%d", &count); err != nil || count < 0 {
+			return nil, errors.NewValidationError("max_citations must be a non-negative integer", providers.FilterMaxCitations, maxCitations)
+		}
+		filters = append(filters, fmt.Sprintf("cited_by_count:<%d", count+1))
+	}
+
+	return filters, nil
+}
+
+// yearRange formats a year range in publication_year filter syntax
+func yearRange(from, to int) string {
+	switch {
+	case from != 0 && to != 0 && from == to:
+		return fmt.Sprintf("%d", from)
+	case from != 0 && to != 0:
+		return fmt.Sprintf("%d-%d", from, to)
+	case from != 0:
+		return fmt.Sprintf(">%d", from-1)
+	default:
+		return fmt.Sprintf("<%d", to+1)
+	}
+}
+
+// filterValue returns a term value safe to use inside a filter. Commas
+// separate filters, so they are dropped from the value.
+func filterValue(term *providers.QueryNode) string {
+	value := strings.ReplaceAll(term.Value, ",", " ")
+	if term.Phrase {
+		return "\"" + value + "\""
+	}
+	return value
+}
+
+// splitValues splits a comma separated filter value
+func splitValues(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
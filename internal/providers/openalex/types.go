@@ -0,0 +1,206 @@
+package openalex
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OpenAlexSearchResponse represents the response of a /works search
+type OpenAlexSearchResponse struct {
+	Meta    OpenAlexMeta   `json:"meta"`
+	Results []OpenAlexWork `json:"results"`
+}
+
+// OpenAlexMeta holds the paging information of a list response
+type OpenAlexMeta struct {
+	Count      int     `json:"count"`
+	Page       *int    `json:"page"`
+	PerPage    int     `json:"per_page"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// OpenAlexWork represents a single work record
+type OpenAlexWork struct {
+	ID                    string               `json:"id"`
+	DOI                   string               `json:"doi"`
+	Title                 string               `json:"title"`
+	DisplayName           string               `json:"display_name"`
+	PublicationYear       int                  `json:"publication_year"`
+	PublicationDate       string               `json:"publication_date"`
+	Language              string               `json:"language"`
+	Type                  string               `json:"type"`
+	IDs                   OpenAlexIDs          `json:"ids"`
+	PrimaryLocation       *OpenAlexLocation    `json:"primary_location"`
+	BestOALocation        *OpenAlexLocation    `json:"best_oa_location"`
+	Authorships           []OpenAlexAuthorship `json:"authorships"`
+	Biblio                OpenAlexBiblio       `json:"biblio"`
+	CitedByCount          int                  `json:"cited_by_count"`
+	Concepts              []OpenAlexConcept    `json:"concepts"`
+	ReferencedWorks       []string             `json:"referenced_works"`
+	AbstractInvertedIndex map[string][]int     `json:"abstract_inverted_index"`
+}
+
+// OpenAlexIDs holds the external identifiers of a work
+type OpenAlexIDs struct {
+	OpenAlex string `json:"openalex"`
+	DOI      string `json:"doi"`
+	PMID     string `json:"pmid"`
+	PMCID    string `json:"pmcid"`
+}
+
+// OpenAlexLocation represents a place where a work is hosted
+type OpenAlexLocation struct {
+	IsOA           bool            `json:"is_oa"`
+	LandingPageURL string          `json:"landing_page_url"`
+	PDFURL         string          `json:"pdf_url"`
+	Source         *OpenAlexSource `json:"source"`
+}
+
+// OpenAlexSource represents a journal or repository
+type OpenAlexSource struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+// OpenAlexAuthorship links an author to a work and their institutions
+type OpenAlexAuthorship struct {
+	AuthorPosition        string                `json:"author_position"`
+	Author                OpenAlexAuthor        `json:"author"`
+	Institutions          []OpenAlexInstitution `json:"institutions"`
+	RawAffiliationStrings []string              `json:"raw_affiliation_strings"`
+}
+
+// OpenAlexAuthor represents an author
+type OpenAlexAuthor struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	ORCID       string `json:"orcid"`
+}
+
+// OpenAlexInstitution represents an institution of an authorship
+type OpenAlexInstitution struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	ROR         string `json:"ror"`
+	CountryCode string `json:"country_code"`
+}
+
+// OpenAlexBiblio holds volume, issue and page information
+type OpenAlexBiblio struct {
+	Volume    string `json:"volume"`
+	Issue     string `json:"issue"`
+	FirstPage string `json:"first_page"`
+	LastPage  string `json:"last_page"`
+}
+
+// OpenAlexConcept represents a concept tagged on a work
+type OpenAlexConcept struct {
+	ID          string  `json:"id"`
+	DisplayName string  `json:"display_name"`
+	Level       int     `json:"level"`
+	Score       float64 `json:"score"`
+}
+
+// Pages returns the page range of the work
+func (b OpenAlexBiblio) Pages() string {
+	switch {
+	case b.FirstPage != "" && b.LastPage != "" && b.FirstPage != b.LastPage:
+		return b.FirstPage + "-" + b.LastPage
+	case b.FirstPage != "":
+		return b.FirstPage
+	default:
+		return b.LastPage
+	}
+}
+
+// Abstract rebuilds the abstract text from its inverted index
+func (w *OpenAlexWork) Abstract() string {
+	if len(w.AbstractInvertedIndex) == 0 {
+		return ""
+	}
+
+	type position struct {
+		index int
+		word  string
+	}
+	var positions []position
+	for word, indexes := range w.AbstractInvertedIndex {
+		for _, index := range indexes {
+			positions = append(positions, position{index, word})
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].index < positions[j].index })
+
+	words := make([]string, len(positions))
+	for i, p := range positions {
+		words[i] = p.word
+	}
+	return strings.Join(words, " ")
+}
+
+// Sort fields accepted by the works endpoint
+const (
+	SortRelevance = "relevance_score"
+	SortPublished = "publication_date"
+	SortCitations = "cited_by_count"
+)
+
+var (
+	// workIDPattern matches OpenAlex work IDs
+	workIDPattern = regexp.MustCompile(`^[Ww]\d+$`)
+
+	// conceptIDPattern matches OpenAlex concept IDs
+	conceptIDPattern = regexp.MustCompile(`^[Cc]\d+$`)
+
+	// institutionIDPattern matches OpenAlex institution IDs
+	institutionIDPattern = regexp.MustCompile(`^[Ii]\d+$`)
+
+	// rorPattern matches bare ROR identifiers
+	rorPattern = regexp.MustCompile(`^0[a-z0-9]{6}\d{2}$`)
+)
+
+// ShortID strips the https://openalex.org/ prefix from an OpenAlex ID and
+// upper cases the entity letter, so W2741809807 and
+// https://openalex.org/w2741809807 compare equal
+func ShortID(id string) string {
+	id = strings.TrimSpace(id)
+	id = strings.TrimPrefix(id, providerName+"_")
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	if id == "" {
+		return ""
+	}
+	return strings.ToUpper(id[:1]) + id[1:]
+}
+
+// NormalizeDOI strips resolver URLs and doi: prefixes and lower cases the DOI
+func NormalizeDOI(doi string) string {
+	doi = strings.TrimSpace(doi)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		if len(doi) >= len(prefix) && strings.EqualFold(doi[:len(prefix)], prefix) {
+			doi = doi[len(prefix):]
+		}
+	}
+	return strings.ToLower(doi)
+}
+
+// IsWorkID returns true if id is an OpenAlex work ID, with or without URL prefix
+func IsWorkID(id string) bool {
+	id = strings.TrimSpace(id)
+	if strings.Contains(id, "/") && !strings.Contains(strings.ToLower(id), "openalex.org/") {
+		return false
+	}
+	return workIDPattern.MatchString(ShortID(id))
+}
+
+// ValidateOpenAlexConfig validates OpenAlex provider configuration
+func ValidateOpenAlexConfig(config map[string]interface{}) error {
+	if mailto, ok := config["mailto"].(string); ok && mailto != "" && !strings.Contains(mailto, "@") {
+		return fmt.Errorf("openalex mailto must be an email address")
+	}
+	return nil
+}
@@ -0,0 +1,699 @@
+package pubmed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+const (
+	defaultBaseURL = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils"
+	providerName   = "pubmed"
+	toolName       = "scifind"
+	maxResults     = 200  // PMIDs fetched per efetch request
+	maxOffset      = 9999 // esearch only pages through the first 10,000 records
+
+	// NCBI allows 3 requests per second, 10 with an API key
+	defaultRequestsPerSecond = 3
+	apiKeyRequestsPerSecond  = 10
+)
+
+// Provider implements the PubMed search provider on top of the NCBI
+// E-utilities. Every search takes an esearch and an efetch request, so the
+// provider throttles its own requests to the NCBI limits.
+type Provider struct {
+	config     providers.ProviderConfig
+	httpClient *http.Client
+	logger     *slog.Logger
+	metrics    *providers.ProviderMetrics
+	enabled    bool
+	email      string
+	limiter    *providers.TokenBucketLimiter
+}
+
+// NewProvider creates a new PubMed provider. Custom["email"] identifies the
+// client to NCBI as their usage policy asks.
+func NewProvider(config providers.ProviderConfig, logger *slog.Logger) *Provider {
+	httpClient := &http.Client{
+		Timeout: config.Timeout,
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	limiter := providers.NewTokenBucketLimiter(logger)
+	_ = limiter.UpdateLimits(providerName, requestLimits(config))
+
+	return &Provider{
+		config:     config,
+		httpClient: httpClient,
+		logger:     logger,
+		metrics:    &providers.ProviderMetrics{},
+		enabled:    config.Enabled,
+		email:      configEmail(config),
+		limiter:    limiter,
+	}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// IsEnabled returns whether the provider is enabled
+func (p *Provider) IsEnabled() bool {
+	return p.enabled
+}
+
+// GetCapabilities returns provider capabilities
+func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
+	return providers.ProviderCapabilities{
+		SupportsFullText:       false,
+		SupportsDateFilter:     true,
+		SupportsAuthFilter:     true,
+		SupportsCategoryFilter: true, // MeSH headings
+		SupportsSort:           true,
+
+		SupportedFields:    []string{"title", "abstract", "author", "journal", "mesh", "year"},
+		SupportedLanguages: []string{"en"},
+		SupportedFormats:   []string{"xml"},
+
+		MaxResults:     maxResults,
+		MaxQueryLength: 4000,
+		RateLimit:      90, // two requests per search at 3 requests per second
+
+		SupportsRealtime:    true,
+		SupportsExactMatch:  true,
+		SupportsFuzzySearch: false,
+		SupportsWildcards:   true,
+
+		QueryFields:          []string{"title", "author", "year", "cat"},
+		SupportsBooleanQuery: true,
+	}
+}
+
+// Search performs a search with esearch and fetches the matching records with efetch
+func (p *Provider) Search(ctx context.Context, query *providers.SearchQuery) (*providers.SearchResult, error) {
+	start := time.Now()
+
+	// Build request URL
+	reqURL, err := p.buildSearchURL(query)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to build PubMed query: %w", err)
+	}
+
+	// Find matching PMIDs
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("PubMed API request failed: %w", err)
+	}
+
+	var searchResult ESearchResult
+	if err := xml.Unmarshal(response, &searchResult); err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to parse PubMed search response: %w", err)
+	}
+	if searchResult.Error != "" {
+		err := errors.NewValidationError("PubMed rejected the query: "+searchResult.Error, "query", query.Query)
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
+
+	// Fetch the records
+	articles, err := p.fetchArticles(ctx, searchResult.IDs)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("PubMed API request failed: %w", err)
+	}
+
+	papers := make([]models.Paper, 0, len(articles))
+	for _, article := range articles {
+		paper, err := p.convertArticle(article)
+		if err != nil {
+			p.logger.Warn("Failed to convert PubMed article",
+				slog.String("pmid", article.MedlineCitation.PMID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		papers = append(papers, *paper)
+	}
+
+	duration := time.Since(start)
+	p.updateMetrics(true, duration, nil)
+
+	result := &providers.SearchResult{
+		Papers:      papers,
+		TotalCount:  searchResult.Count,
+		ResultCount: len(papers),
+		Query:       query.Query,
+		Provider:    providerName,
+		Duration:    duration,
+		CacheHit:    false,
+		RequestID:   query.RequestID,
+		Timestamp:   time.Now(),
+		Success:     true,
+		HasMore:     query.Offset+len(searchResult.IDs) < searchResult.Count,
+	}
+
+	p.logger.Debug("PubMed search completed",
+		slog.String("query", query.Query),
+		slog.Int("results", len(papers)),
+		slog.Int("total", searchResult.Count),
+		slog.Duration("duration", duration))
+
+	return result, nil
+}
+
+// GetPaper retrieves a specific paper by PMID. PMCIDs and DOIs are resolved
+// to a PMID with an extra esearch request.
+func (p *Provider) GetPaper(ctx context.Context, id string) (*models.Paper, error) {
+	start := time.Now()
+
+	pmid, err := p.resolvePMID(ctx, id)
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
+
+	articles, err := p.fetchArticles(ctx, []string{pmid})
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("PubMed API request failed: %w", err)
+	}
+	if len(articles) == 0 {
+		err := errors.NewNotFoundError("Paper not found in PubMed", pmid)
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
+
+	paper, err := p.convertArticle(articles[0])
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, fmt.Errorf("failed to convert paper: %w", err)
+	}
+
+	p.updateMetrics(true, time.Since(start), nil)
+	return paper, nil
+}
+
+// HealthCheck checks if the E-utilities are accessible
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("term", "test")
+	params.Set("retmax", "1")
+	_, err := p.makeRequest(ctx, p.withClientParams(p.config.BaseURL+"/esearch.fcgi", params))
+	if err != nil {
+		return errors.NewHealthCheckError("Health check failed: "+err.Error(), providerName)
+	}
+
+	p.logger.Debug("PubMed health check passed", slog.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// GetStatus returns the current provider status
+func (p *Provider) GetStatus() providers.ProviderStatus {
+	return providers.ProviderStatus{
+		Name:            providerName,
+		Enabled:         p.enabled,
+		Healthy:         true,
+		LastCheck:       time.Now(),
+		CircuitState:    "closed",
+		RateLimited:     false,
+		AvgResponseTime: p.calculateAvgResponseTime(),
+		SuccessRate:     p.calculateSuccessRate(),
+		APIVersion:      "2.0",
+		LastUpdated:     time.Now(),
+	}
+}
+
+// GetMetrics returns provider metrics
+func (p *Provider) GetMetrics() providers.ProviderMetrics {
+	return *p.metrics
+}
+
+// Configure updates the provider configuration
+func (p *Provider) Configure(config providers.ProviderConfig) error {
+	if err := p.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	p.config = config
+	p.enabled = config.Enabled
+	p.email = configEmail(config)
+
+	// Update HTTP client timeout and request throttling
+	p.httpClient.Timeout = config.Timeout
+	if err := p.limiter.UpdateLimits(providerName, requestLimits(config)); err != nil {
+		return err
+	}
+
+	p.logger.Info("PubMed provider configured",
+		slog.Bool("enabled", config.Enabled),
+		slog.Bool("has_api_key", config.APIKey != ""),
+		slog.Duration("timeout", config.Timeout))
+
+	return nil
+}
+
+// ValidateConfig validates the provider configuration
+func (p *Provider) ValidateConfig(config providers.ProviderConfig) error {
+	if config.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if config.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
+
+	return ValidatePubMedConfig(config.Custom)
+}
+
+// buildSearchURL builds the esearch URL
+func (p *Provider) buildSearchURL(query *providers.SearchQuery) (string, error) {
+	if query.Offset > maxOffset {
+		return "", errors.NewValidationError(
+			fmt.Sprintf("PubMed offsets are limited to %d", maxOffset),
+			"offset",
+			query.Offset,
+		)
+	}
+
+	term := query.Query
+
+	// Query grammar
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return "", err
+	}
+	if parsed != nil {
+		term, err = translateQuery(parsed)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Filters
+	clauses := []string{}
+	if strings.TrimSpace(term) != "" {
+		clauses = append(clauses, term)
+	}
+	clauses = append(clauses, translateFilters(query)...)
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("term", strings.Join(clauses, " AND "))
+	params.Set("retstart", strconv.Itoa(query.Offset))
+	params.Set("retmax", strconv.Itoa(pageSize(query.Limit)))
+
+	if query.DateFrom != nil || query.DateTo != nil {
+		params.Set("datetype", "pdat")
+		params.Set("mindate", "1000/01/01")
+		params.Set("maxdate", "3000/12/31")
+		if query.DateFrom != nil {
+			params.Set("mindate", query.DateFrom.Format("2006/01/02"))
+		}
+		if query.DateTo != nil {
+			params.Set("maxdate", query.DateTo.Format("2006/01/02"))
+		}
+	}
+
+	// Sorting, PubMed has no citation counts to sort by
+	switch query.SortBy {
+	case providers.SortDate:
+		params.Set("sort", SortPublished)
+	default:
+		params.Set("sort", SortRelevance)
+	}
+
+	return p.withClientParams(p.config.BaseURL+"/esearch.fcgi", params), nil
+}
+
+// fetchArticles fetches the records of the given PMIDs with efetch
+func (p *Provider) fetchArticles(ctx context.Context, pmids []string) ([]PubmedArticle, error) {
+	if len(pmids) == 0 {
+		return nil, nil
+	}
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("id", strings.Join(pmids, ","))
+	params.Set("retmode", "xml")
+
+	response, err := p.makeRequest(ctx, p.withClientParams(p.config.BaseURL+"/efetch.fcgi", params))
+	if err != nil {
+		return nil, err
+	}
+
+	var articleSet PubmedArticleSet
+	if err := xml.Unmarshal(response, &articleSet); err != nil {
+		return nil, fmt.Errorf("failed to parse PubMed records: %w", err)
+	}
+	return articleSet.Articles, nil
+}
+
+// resolvePMID returns the PMID for a PMID, PMCID or DOI
+func (p *Provider) resolvePMID(ctx context.Context, id string) (string, error) {
+	pmid := NormalizePMID(id)
+	if pmidPattern.MatchString(pmid) {
+		return pmid, nil
+	}
+
+	doi := strings.TrimSpace(id)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "doi:"} {
+		doi = strings.TrimPrefix(doi, prefix)
+	}
+
+	var term string
+	switch {
+	case pmcidPattern.MatchString(strings.ToUpper(pmid)):
+		term = strings.ToUpper(pmid) + "[pmcid]"
+	case strings.HasPrefix(doi, "10."):
+		term = "\"" + doi + "\"[doi]"
+	default:
+		return "", errors.NewValidationError("PMID, PMCID or DOI is required", "id", id)
+	}
+
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("term", term)
+	params.Set("retmax", "1")
+
+	response, err := p.makeRequest(ctx, p.withClientParams(p.config.BaseURL+"/esearch.fcgi", params))
+	if err != nil {
+		return "", fmt.Errorf("PubMed API request failed: %w", err)
+	}
+
+	var searchResult ESearchResult
+	if err := xml.Unmarshal(response, &searchResult); err != nil {
+		return "", fmt.Errorf("failed to parse PubMed search response: %w", err)
+	}
+	if len(searchResult.IDs) == 0 {
+		return "", errors.NewNotFoundError("Paper not found in PubMed", id)
+	}
+	return searchResult.IDs[0], nil
+}
+
+// withClientParams adds the tool, email and API key parameters NCBI asks
+// clients to send and encodes the URL
+func (p *Provider) withClientParams(baseURL string, params url.Values) string {
+	params.Set("tool", toolName)
+	if p.email != "" {
+		params.Set("email", p.email)
+	}
+	if p.config.APIKey != "" {
+		params.Set("api_key", p.config.APIKey)
+	}
+	return baseURL + "?" + params.Encode()
+}
+
+// makeRequest makes a throttled HTTP request to the E-utilities
+func (p *Provider) makeRequest(ctx context.Context, reqURL string) ([]byte, error) {
+	if err := p.limiter.Wait(ctx, providerName); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "SciFIND-Backend/1.0")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.NewNotFoundError("Resource not found in PubMed", reqURL)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter := resp.Header.Get("Retry-After")
+		retryDuration := time.Second
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			retryDuration = time.Duration(seconds) * time.Second
+		}
+		return nil, errors.NewRateLimitError(
+			fmt.Sprintf("PubMed API rate limit exceeded. Retry after: %s", retryDuration),
+			retryDuration,
+		)
+	case resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout:
+		return nil, errors.NewNetworkError(
+			fmt.Sprintf("PubMed API unavailable: %d", resp.StatusCode),
+			nil,
+		)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("PubMed API client error: %d %s", resp.StatusCode, strings.TrimSpace(string(body))),
+			"status",
+			resp.StatusCode,
+		)
+	default:
+		return nil, errors.NewInternalError(
+			fmt.Sprintf("PubMed API returned unexpected status %d", resp.StatusCode),
+			nil,
+		)
+	}
+}
+
+// convertArticle converts a PubMed record to our Paper model
+func (p *Provider) convertArticle(article PubmedArticle) (*models.Paper, error) {
+	citation := article.MedlineCitation
+	pmid := strings.TrimSpace(citation.PMID)
+	if pmid == "" {
+		return nil, fmt.Errorf("article PMID is required")
+	}
+
+	title := strings.TrimSuffix(citation.Article.Title.Text(), ".")
+	if title == "" {
+		return nil, fmt.Errorf("article title is required")
+	}
+
+	// Convert authors
+	authors := make([]models.Author, 0, len(citation.Article.Authors))
+	for _, authorData := range citation.Article.Authors {
+		name := authorData.FullName()
+		if name == "" {
+			continue
+		}
+		author := models.Author{Name: name}
+		if orcid := authorData.ORCID(); orcid != "" {
+			author.ORCID = &orcid
+		}
+		if len(authorData.Affiliations) > 0 && strings.TrimSpace(authorData.Affiliations[0]) != "" {
+			affiliation := strings.TrimSpace(authorData.Affiliations[0])
+			author.Affiliation = &affiliation
+		}
+		authors = append(authors, author)
+	}
+
+	// MeSH descriptors become categories
+	categories := make([]models.Category, 0, len(citation.MeshHeadings))
+	for _, heading := range citation.MeshHeadings {
+		descriptor := heading.Descriptor
+		if descriptor.UI == "" || descriptor.Name == "" {
+			continue
+		}
+		categories = append(categories, models.Category{
+			ID:         "mesh_" + descriptor.UI,
+			Name:       strings.TrimSpace(descriptor.Name),
+			Source:     "mesh",
+			SourceCode: descriptor.UI,
+			IsActive:   true,
+		})
+	}
+
+	var keywords []string
+	for _, keyword := range citation.Keywords {
+		if text := keyword.Text(); text != "" {
+			keywords = append(keywords, text)
+		}
+	}
+
+	// Identifiers
+	var doi, pmcid *string
+	if value := article.DOI(); value != "" {
+		doi = &value
+	}
+	if value := strings.ToUpper(article.PubmedData.ArticleID("pmc")); pmcidPattern.MatchString(value) {
+		pmcid = &value
+	}
+
+	// Publication details
+	journalData := citation.Article.Journal
+	var journal, volume, issue, pages *string
+	if journalData.Title != "" {
+		journal = &journalData.Title
+	}
+	if journalData.Volume != "" {
+		volume = &journalData.Volume
+	}
+	if journalData.Issue != "" {
+		issue = &journalData.Issue
+	}
+	if citation.Article.Pagination != "" {
+		pages = &citation.Article.Pagination
+	}
+
+	var abstract *string
+	if text := article.Abstract(); text != "" {
+		abstract = &text
+	}
+
+	// Links, articles in PubMed Central are free to read
+	paperURL := fmt.Sprintf("https://pubmed.ncbi.nlm.nih.gov/%s/", pmid)
+	var pdfURL *string
+	if pmcid != nil {
+		link := fmt.Sprintf("https://pmc.ncbi.nlm.nih.gov/articles/%s/pdf/", *pmcid)
+		pdfURL = &link
+	}
+
+	// Referenced PMIDs
+	var references []string
+	for _, reference := range article.PubmedData.References {
+		for _, id := range reference.ArticleIDs {
+			if id.Type == "pubmed" && strings.TrimSpace(id.Value) != "" {
+				references = append(references, strings.TrimSpace(id.Value))
+			}
+		}
+	}
+
+	paper := &models.Paper{
+		ID:              providerName + "_" + pmid,
+		DOI:             doi,
+		PMID:            &pmid,
+		PMCID:           pmcid,
+		Title:           title,
+		Abstract:        abstract,
+		Authors:         authors,
+		Journal:         journal,
+		Volume:          volume,
+		Issue:           issue,
+		Pages:           pages,
+		PublishedAt:     article.PublishedAt(),
+		URL:             &paperURL,
+		PDFURL:          pdfURL,
+		Categories:      categories,
+		Keywords:        keywords,
+		Language:        article.Language(),
+		References:      references,
+		SourceProvider:  providerName,
+		SourceID:        pmid,
+		SourceURL:       &paperURL,
+		ProcessingState: "completed",
+	}
+
+	// Calculate quality score
+	paper.UpdateQualityScore()
+
+	return paper, nil
+}
+
+// pageSize returns the retmax to request for a query limit
+func pageSize(limit int) int {
+	if limit <= 0 || limit > maxResults {
+		return maxResults
+	}
+	return limit
+}
+
+// requestLimits returns the per request limits, honouring explicit limits
+// from the config and otherwise the NCBI policy
+func requestLimits(config providers.ProviderConfig) providers.RateLimitConfig {
+	limits := config.RateLimit
+	if limits.RequestsPerSecond > 0 || limits.RequestsPerMinute > 0 || limits.RequestsPerHour > 0 {
+		return limits
+	}
+
+	perSecond := defaultRequestsPerSecond
+	if config.APIKey != "" {
+		perSecond = apiKeyRequestsPerSecond
+	}
+	return providers.RateLimitConfig{RequestsPerSecond: perSecond, BurstSize: 1}
+}
+
+// configEmail returns the contact address sent to NCBI
+func configEmail(config providers.ProviderConfig) string {
+	email, _ := config.Custom["email"].(string)
+	return strings.TrimSpace(email)
+}
+
+// Helper methods
+func (p *Provider) updateMetrics(success bool, duration time.Duration, err error) {
+	p.metrics.TotalRequests++
+
+	if success {
+		p.metrics.SuccessfulRequests++
+	} else {
+		p.metrics.FailedRequests++
+
+		// Categorize errors
+		if err != nil {
+			switch {
+			case errors.IsTimeoutError(err):
+				p.metrics.TimeoutErrors++
+			case errors.IsRateLimitError(err):
+				p.metrics.RateLimitErrors++
+			case errors.IsNetworkError(err):
+				p.metrics.NetworkErrors++
+			default:
+				p.metrics.ParseErrors++
+			}
+		}
+	}
+
+	// Update response time statistics
+	if p.metrics.MinResponseTime == 0 || duration < p.metrics.MinResponseTime {
+		p.metrics.MinResponseTime = duration
+	}
+	if duration > p.metrics.MaxResponseTime {
+		p.metrics.MaxResponseTime = duration
+	}
+
+	// Simple moving average for response time
+	if p.metrics.AvgResponseTime == 0 {
+		p.metrics.AvgResponseTime = duration
+	} else {
+		p.metrics.AvgResponseTime = (p.metrics.AvgResponseTime + duration) / 2
+	}
+}
+
+func (p *Provider) calculateAvgResponseTime() time.Duration {
+	return p.metrics.AvgResponseTime
+}
+
+func (p *Provider) calculateSuccessRate() float64 {
+	if p.metrics.TotalRequests == 0 {
+		return 1.0
+	}
+	return float64(p.metrics.SuccessfulRequests) / float64(p.metrics.TotalRequests)
+}
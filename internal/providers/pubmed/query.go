@@ -0,0 +1,129 @@
+package pubmed
+
+import (
+	"fmt"
+	"strings"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// queryFieldTags maps grammar fields to PubMed search field tags
+var queryFieldTags = map[string]string{
+	providers.QueryFieldTitle:    "ti",
+	providers.QueryFieldAuthor:   "au",
+	providers.QueryFieldCategory: "mh",
+}
+
+// translateQuery converts a query AST to a PubMed search term. PubMed's NOT
+// is binary like ArXiv's ANDNOT, so negated terms must sit in a conjunction
+// with at least one positive term.
+func translateQuery(node *providers.QueryNode) (string, error) {
+	switch node.Op {
+	case providers.QueryTerm:
+		return translateTerm(node)
+	case providers.QueryOr:
+		parts := make([]string, 0, len(node.Children))
+		for _, child := range node.Children {
+			part, err := translateQuery(child)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "(" + strings.Join(parts, " OR ") + ")", nil
+	case providers.QueryAnd:
+		var positive, negative []string
+		for _, child := range node.Children {
+			target := &positive
+			if child.Op == providers.QueryNot {
+				target = &negative
+				child = child.Children[0]
+			}
+			part, err := translateQuery(child)
+			if err != nil {
+				return "", err
+			}
+			*target = append(*target, part)
+		}
+		if len(positive) == 0 {
+			return "", errors.NewUnsupportedQueryError(providerName, "NOT without a positive term")
+		}
+
+		translated := strings.Join(positive, " AND ")
+		for _, part := range negative {
+			translated += " NOT " + part
+		}
+		return "(" + translated + ")", nil
+	default:
+		return "", errors.NewUnsupportedQueryError(providerName, "NOT without a positive term")
+	}
+}
+
+// translateTerm converts a single term. Years become publication date
+// ranges and categories MeSH headings; unfielded terms use PubMed's
+// automatic term mapping.
+func translateTerm(term *providers.QueryNode) (string, error) {
+	if term.Field == providers.QueryFieldYear {
+		from, to := "1000", "3000"
+		if term.YearFrom != 0 {
+			from = fmt.Sprintf("%04d", term.YearFrom)
+		}
+		if term.YearTo != 0 {
+			to = fmt.Sprintf("%04d", term.YearTo)
+		}
+		return fmt.Sprintf("%s:%s[dp]", from, to), nil
+	}
+
+	value := term.Value
+	if term.Phrase {
+		value = fmt.Sprintf("\"%s\"", term.Value)
+	}
+
+	if term.Field == "" {
+		return value, nil
+	}
+	tag, ok := queryFieldTags[term.Field]
+	if !ok {
+		return "", errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+	}
+	return value + "[" + tag + "]", nil
+}
+
+// translateFilters converts the common search filters to search term clauses
+func translateFilters(query *providers.SearchQuery) []string {
+	var clauses []string
+
+	if clause := fieldClause(query.Filters[providers.FilterAuthor], "au"); clause != "" {
+		clauses = append(clauses, clause)
+	}
+	if clause := fieldClause(query.Filters[providers.FilterCategory], "mh"); clause != "" {
+		clauses = append(clauses, clause)
+	}
+	if journal, ok := query.Filters[providers.FilterJournal]; ok && journal != "" {
+		clauses = append(clauses, fmt.Sprintf("\"%s\"[ta]", strings.TrimSpace(journal)))
+	}
+	if openAccess, ok := query.Filters[providers.FilterOpenAccess]; ok && openAccess == "true" {
+		clauses = append(clauses, "\"free full text\"[sb]")
+	}
+
+	return clauses
+}
+
+// fieldClause ORs the comma separated values of a filter within one field
+func fieldClause(values, tag string) string {
+	var parts []string
+	for _, value := range strings.Split(values, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			parts = append(parts, fmt.Sprintf("\"%s\"[%s]", value, tag))
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	default:
+		return "(" + strings.Join(parts, " OR ") + ")"
+	}
+}
@@ -0,0 +1,353 @@
+package pubmed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ESearchResult represents the response of an esearch request
+type ESearchResult struct {
+	XMLName  xml.Name `xml:"eSearchResult"`
+	Count    int      `xml:"Count"`
+	RetMax   int      `xml:"RetMax"`
+	RetStart int      `xml:"RetStart"`
+	IDs      []string `xml:"IdList>Id"`
+	Error    string   `xml:"ERROR"`
+}
+
+// PubmedArticleSet represents the response of an efetch request
+type PubmedArticleSet struct {
+	XMLName  xml.Name        `xml:"PubmedArticleSet"`
+	Articles []PubmedArticle `xml:"PubmedArticle"`
+}
+
+// PubmedArticle represents a single PubMed record
+type PubmedArticle struct {
+	MedlineCitation MedlineCitation `xml:"MedlineCitation"`
+	PubmedData      PubmedData      `xml:"PubmedData"`
+}
+
+// MedlineCitation holds the bibliographic part of a record
+type MedlineCitation struct {
+	PMID         string        `xml:"PMID"`
+	Article      Article       `xml:"Article"`
+	MeshHeadings []MeshHeading `xml:"MeshHeadingList>MeshHeading"`
+	Keywords     []MarkupText  `xml:"KeywordList>Keyword"`
+}
+
+// Article holds the article metadata of a citation
+type Article struct {
+	Journal     Journal        `xml:"Journal"`
+	Title       MarkupText     `xml:"ArticleTitle"`
+	Pagination  string         `xml:"Pagination>MedlinePgn"`
+	ELocations  []ELocationID  `xml:"ELocationID"`
+	Abstract    []AbstractText `xml:"Abstract>AbstractText"`
+	Authors     []Author       `xml:"AuthorList>Author"`
+	Languages   []string       `xml:"Language"`
+	ArticleDate []PubDate      `xml:"ArticleDate"`
+}
+
+// Journal holds the journal an article appeared in
+type Journal struct {
+	Title           string  `xml:"Title"`
+	ISOAbbreviation string  `xml:"ISOAbbreviation"`
+	Volume          string  `xml:"JournalIssue>Volume"`
+	Issue           string  `xml:"JournalIssue>Issue"`
+	PubDate         PubDate `xml:"JournalIssue>PubDate"`
+}
+
+// PubDate holds a possibly partial date. Month may be numeric or an
+// abbreviated name; MedlineDate holds free text such as "2019 Dec-2020 Jan".
+type PubDate struct {
+	Year        string `xml:"Year"`
+	Month       string `xml:"Month"`
+	Day         string `xml:"Day"`
+	Season      string `xml:"Season"`
+	MedlineDate string `xml:"MedlineDate"`
+}
+
+// ELocationID holds an electronic location such as a DOI
+type ELocationID struct {
+	Type  string `xml:"EIdType,attr"`
+	Valid string `xml:"ValidYN,attr"`
+	Value string `xml:",chardata"`
+}
+
+// AbstractText is one, possibly labelled, section of an abstract
+type AbstractText struct {
+	Label string `xml:"Label,attr"`
+	MarkupText
+}
+
+// Author represents an author or collective of an article
+type Author struct {
+	LastName       string       `xml:"LastName"`
+	ForeName       string       `xml:"ForeName"`
+	Initials       string       `xml:"Initials"`
+	CollectiveName string       `xml:"CollectiveName"`
+	Identifiers    []Identifier `xml:"Identifier"`
+	Affiliations   []string     `xml:"AffiliationInfo>Affiliation"`
+}
+
+// Identifier is an author identifier such as an ORCID
+type Identifier struct {
+	Source string `xml:"Source,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// MeshHeading is a MeSH descriptor with its qualifiers
+type MeshHeading struct {
+	Descriptor MeshTerm   `xml:"DescriptorName"`
+	Qualifiers []MeshTerm `xml:"QualifierName"`
+}
+
+// MeshTerm is a MeSH descriptor or qualifier
+type MeshTerm struct {
+	UI         string `xml:"UI,attr"`
+	MajorTopic string `xml:"MajorTopicYN,attr"`
+	Name       string `xml:",chardata"`
+}
+
+// PubmedData holds the identifiers and references of a record
+type PubmedData struct {
+	ArticleIDs []ArticleID `xml:"ArticleIdList>ArticleId"`
+	References []Reference `xml:"ReferenceList>Reference"`
+}
+
+// ArticleID is an identifier of the article such as pubmed, pmc or doi
+type ArticleID struct {
+	Type  string `xml:"IdType,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Reference is an entry of the article's reference list
+type Reference struct {
+	Citation   string      `xml:"Citation"`
+	ArticleIDs []ArticleID `xml:"ArticleIdList>ArticleId"`
+}
+
+// MarkupText captures element content that may contain inline markup such
+// as <i> or <sup>
+type MarkupText struct {
+	InnerXML string `xml:",innerxml"`
+}
+
+// markupTagPattern matches inline markup tags
+var markupTagPattern = regexp.MustCompile(`<[^>]+>`)
+
+// Text returns the content with markup removed and whitespace collapsed
+func (t MarkupText) Text() string {
+	text := markupTagPattern.ReplaceAllString(t.InnerXML, "")
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// FullName returns the display name of an author
+func (a Author) FullName() string {
+	if a.CollectiveName != "" {
+		return strings.TrimSpace(a.CollectiveName)
+	}
+	first := a.ForeName
+	if first == "" {
+		first = a.Initials
+	}
+	return strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(a.LastName))
+}
+
+// ORCID returns the bare ORCID identifier of the author, if any
+func (a Author) ORCID() string {
+	for _, identifier := range a.Identifiers {
+		if identifier.Source == "ORCID" {
+			orcid := strings.TrimSpace(identifier.Value)
+			if i := strings.LastIndex(orcid, "/"); i >= 0 {
+				orcid = orcid[i+1:]
+			}
+			return orcid
+		}
+	}
+	return ""
+}
+
+// ArticleID returns the identifier of the given type, e.g. "pmc" or "doi"
+func (d PubmedData) ArticleID(idType string) string {
+	for _, id := range d.ArticleIDs {
+		if id.Type == idType {
+			return strings.TrimSpace(id.Value)
+		}
+	}
+	return ""
+}
+
+// DOI returns the DOI of the article from the identifier list or the
+// electronic locations
+func (a *PubmedArticle) DOI() string {
+	if doi := a.PubmedData.ArticleID("doi"); doi != "" {
+		return strings.ToLower(doi)
+	}
+	for _, location := range a.MedlineCitation.Article.ELocations {
+		if location.Type == "doi" && location.Valid != "N" {
+			return strings.ToLower(strings.TrimSpace(location.Value))
+		}
+	}
+	return ""
+}
+
+// Abstract joins the abstract sections, prefixing labelled sections
+func (a *PubmedArticle) Abstract() string {
+	sections := make([]string, 0, len(a.MedlineCitation.Article.Abstract))
+	for _, section := range a.MedlineCitation.Article.Abstract {
+		text := section.Text()
+		if text == "" {
+			continue
+		}
+		if section.Label != "" {
+			text = section.Label + ": " + text
+		}
+		sections = append(sections, text)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// PublishedAt returns the publication date of the article. The journal issue
+// date is preferred, falling back to the electronic publication date.
+func (a *PubmedArticle) PublishedAt() *time.Time {
+	if t := a.MedlineCitation.Article.Journal.PubDate.Time(); t != nil {
+		return t
+	}
+	for _, date := range a.MedlineCitation.Article.ArticleDate {
+		if t := date.Time(); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// medlineDatePattern extracts the leading year and month of a MedlineDate
+var medlineDatePattern = regexp.MustCompile(`^(\d{4})(?:\s+([A-Za-z]{3}))?`)
+
+// seasonMonths maps the seasons PubMed uses instead of months
+var seasonMonths = map[string]time.Month{
+	"winter": time.January,
+	"spring": time.April,
+	"summer": time.July,
+	"fall":   time.October,
+	"autumn": time.October,
+}
+
+// Time returns the date, filling missing month and day with 1
+func (d PubDate) Time() *time.Time {
+	year, month, day := d.Year, d.Month, d.Day
+	if year == "" && d.MedlineDate != "" {
+		match := medlineDatePattern.FindStringSubmatch(strings.TrimSpace(d.MedlineDate))
+		if match == nil {
+			return nil
+		}
+		year, month, day = match[1], match[2], ""
+	}
+
+	y, err := strconv.Atoi(strings.TrimSpace(year))
+	if err != nil || y <= 0 {
+		return nil
+	}
+
+	m := parseMonth(month)
+	if m == 0 {
+		m = seasonMonths[strings.ToLower(strings.TrimSpace(d.Season))]
+	}
+	if m == 0 {
+		m = time.January
+	}
+
+	dd, err := strconv.Atoi(strings.TrimSpace(day))
+	if err != nil || dd < 1 || dd > 31 {
+		dd = 1
+	}
+
+	date := time.Date(y, m, dd, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+// parseMonth parses numeric months and English month abbreviations
+func parseMonth(month string) time.Month {
+	month = strings.TrimSpace(month)
+	if n, err := strconv.Atoi(month); err == nil {
+		if n >= 1 && n <= 12 {
+			return time.Month(n)
+		}
+		return 0
+	}
+	if len(month) < 3 {
+		return 0
+	}
+	if t, err := time.Parse("Jan", strings.ToUpper(month[:1])+strings.ToLower(month[1:3])); err == nil {
+		return t.Month()
+	}
+	return 0
+}
+
+// languageCodes maps the MEDLINE three letter language codes to ISO 639-1
+var languageCodes = map[string]string{
+	"eng": "en",
+	"fre": "fr",
+	"ger": "de",
+	"spa": "es",
+	"ita": "it",
+	"por": "pt",
+	"rus": "ru",
+	"jpn": "ja",
+	"chi": "zh",
+	"dut": "nl",
+	"pol": "pl",
+	"kor": "ko",
+}
+
+// Language returns the ISO 639-1 code of the article language, defaulting to English
+func (a *PubmedArticle) Language() string {
+	for _, language := range a.MedlineCitation.Article.Languages {
+		if code, ok := languageCodes[strings.ToLower(strings.TrimSpace(language))]; ok {
+			return code
+		}
+	}
+	return "en"
+}
+
+// Sort orders accepted by esearch
+const (
+	SortRelevance = "relevance"
+	SortPublished = "pub_date"
+)
+
+var (
+	// pmidPattern matches PubMed identifiers
+	pmidPattern = regexp.MustCompile(`^\d{1,9}$`)
+
+	// pmcidPattern matches PubMed Central identifiers
+	pmcidPattern = regexp.MustCompile(`^PMC\d+$`)
+)
+
+// NormalizePMID strips pmid: and pubmed_ prefixes and PubMed URLs from a PMID
+func NormalizePMID(id string) string {
+	id = strings.TrimSpace(id)
+	id = strings.TrimSuffix(id, "/")
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	for _, prefix := range []string{"pmid:", providerName + "_"} {
+		if len(id) >= len(prefix) && strings.EqualFold(id[:len(prefix)], prefix) {
+			id = id[len(prefix):]
+		}
+	}
+	return strings.TrimSpace(id)
+}
+
+// ValidatePubMedConfig validates PubMed provider configuration
+func ValidatePubMedConfig(config map[string]interface{}) error {
+	if email, ok := config["email"].(string); ok && email != "" && !strings.Contains(email, "@") {
+		return fmt.Errorf("pubmed email must be an email address")
+	}
+	return nil
+}
@@ -0,0 +1,559 @@
+package providers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"scifind-backend/internal/errors"
+)
+
+// Query grammar
+//
+//	query   := or
+//	or      := and ("OR" and)*
+//	and     := unary (["AND"] unary)*
+//	unary   := "NOT" unary | primary
+//	primary := "(" query ")" | field ":" value | word | "\"" phrase "\""
+//	value   := word | "\"" phrase "\"" | "(" query ")"
+//
+// Operators are upper case; adjacent terms are combined with AND. A field
+// prefix in front of a group applies to every term in the group. Years take
+// a single year or a range such as year:2019..2022, year:2019.. or year:..2022.
+
+// Query fields supported by the grammar
+const (
+	QueryFieldTitle    = "title"
+	QueryFieldAuthor   = "author"
+	QueryFieldAbstract = "abstract"
+	QueryFieldYear     = "year"
+	QueryFieldCategory = "cat"
+)
+
+// QueryFields lists all field prefixes of the query grammar
+var QueryFields = []string{QueryFieldTitle, QueryFieldAuthor, QueryFieldAbstract, QueryFieldYear, QueryFieldCategory}
+
+// QueryOp identifies the kind of a query node
+type QueryOp string
+
+const (
+	QueryTerm QueryOp = "term"
+	QueryAnd  QueryOp = "and"
+	QueryOr   QueryOp = "or"
+	QueryNot  QueryOp = "not"
+)
+
+// QueryNode is a node of a parsed query. Terms carry a value and an optional
+// field; AND, OR and NOT nodes combine their children.
+type QueryNode struct {
+	Op       QueryOp      `json:"op"`
+	Field    string       `json:"field,omitempty"`
+	Value    string       `json:"value,omitempty"`
+	Phrase   bool         `json:"phrase,omitempty"`
+	YearFrom int          `json:"year_from,omitempty"` // year terms only, 0 when open
+	YearTo   int          `json:"year_to,omitempty"`   // year terms only, 0 when open
+	Children []*QueryNode `json:"children,omitempty"`
+}
+
+// ParseQuery parses a query string into its AST. A blank query returns nil.
+func ParseQuery(input string) (*QueryNode, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &queryParser{input: input, tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, p.errorf("unexpected %s", tok)
+	}
+
+	return node, nil
+}
+
+// ParseUserQuery parses a query typed by a user. Free text that is not valid
+// grammar, such as an unterminated quote, unbalanced parentheses or a
+// dangling operator, falls back to an AND of its plain words. Malformed
+// field syntax is still rejected, the user clearly meant the grammar.
+func ParseUserQuery(input string) (*QueryNode, error) {
+	node, err := ParseQuery(input)
+	if err == nil {
+		return node, nil
+	}
+
+	// Without quotes and parentheses tokenizing cannot fail
+	plain := strings.Map(func(r rune) rune {
+		if r == '"' || r == '(' || r == ')' {
+			return ' '
+		}
+		return r
+	}, input)
+	tokens, _ := tokenizeQuery(plain)
+
+	var terms []*QueryNode
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenField:
+			return nil, err
+		case tokenWord:
+			terms = append(terms, &QueryNode{Op: QueryTerm, Value: tok.value})
+		}
+	}
+	if len(terms) == 0 {
+		return nil, err
+	}
+	return combineQueryNodes(QueryAnd, terms), nil
+}
+
+// ParsedQuery returns the AST of the query, parsing the query string if the
+// caller did not provide one
+func (q *SearchQuery) ParsedQuery() (*QueryNode, error) {
+	if q.Parsed != nil {
+		return q.Parsed, nil
+	}
+	return ParseUserQuery(q.Query)
+}
+
+// IsLeaf returns true for term nodes
+func (n *QueryNode) IsLeaf() bool {
+	return n.Op == QueryTerm
+}
+
+// Walk calls fn for the node and all of its descendants, depth first
+func (n *QueryNode) Walk(fn func(*QueryNode)) {
+	fn(n)
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// Conjuncts returns the terms of a query that is a single term or an AND of
+// terms. ok is false for queries using OR, NOT or nested groups, which
+// providers without boolean support cannot express.
+func (n *QueryNode) Conjuncts() (terms []*QueryNode, ok bool) {
+	if n.IsLeaf() {
+		return []*QueryNode{n}, true
+	}
+	if n.Op != QueryAnd {
+		return nil, false
+	}
+	for _, child := range n.Children {
+		if !child.IsLeaf() {
+			return nil, false
+		}
+	}
+	return n.Children, true
+}
+
+// String returns the query in canonical grammar syntax
+func (n *QueryNode) String() string {
+	switch n.Op {
+	case QueryTerm:
+		value := n.Value
+		if n.Field == QueryFieldYear {
+			value = n.yearRange()
+		} else if n.Phrase {
+			value = strconv.Quote(n.Value)
+		}
+		if n.Field != "" {
+			return n.Field + ":" + value
+		}
+		return value
+	case QueryNot:
+		return "NOT " + n.Children[0].group(QueryNot)
+	default:
+		parts := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			parts[i] = child.group(n.Op)
+		}
+		return strings.Join(parts, " "+strings.ToUpper(string(n.Op))+" ")
+	}
+}
+
+// group renders the node, wrapped in parentheses when it binds looser than
+// its parent
+func (n *QueryNode) group(parent QueryOp) string {
+	if n.IsLeaf() || n.Op == QueryNot || (n.Op == QueryAnd && parent == QueryOr) {
+		return n.String()
+	}
+	return "(" + n.String() + ")"
+}
+
+// yearRange renders the range of a year term
+func (n *QueryNode) yearRange() string {
+	if n.YearFrom != 0 && n.YearFrom == n.YearTo {
+		return strconv.Itoa(n.YearFrom)
+	}
+
+	var from, to string
+	if n.YearFrom != 0 {
+		from = strconv.Itoa(n.YearFrom)
+	}
+	if n.YearTo != 0 {
+		to = strconv.Itoa(n.YearTo)
+	}
+	return from + ".." + to
+}
+
+// FreeText renders unfielded terms as plain text, quoting phrases
+func FreeText(terms []*QueryNode) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term.Field != "" {
+			continue
+		}
+		if term.Phrase {
+			parts = append(parts, strconv.Quote(term.Value))
+		} else {
+			parts = append(parts, term.Value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CheckQuerySupport returns an unsupported query error if the query uses a
+// feature the provider capabilities do not declare
+func CheckQuerySupport(provider string, caps ProviderCapabilities, node *QueryNode) error {
+	if node == nil {
+		return nil
+	}
+
+	if !caps.SupportsBooleanQuery {
+		if _, ok := node.Conjuncts(); !ok {
+			return errors.NewUnsupportedQueryError(provider, "boolean operators")
+		}
+	}
+
+	var unsupported string
+	node.Walk(func(n *QueryNode) {
+		if unsupported != "" || !n.IsLeaf() {
+			return
+		}
+		if n.Phrase && !caps.SupportsExactMatch {
+			unsupported = "phrase search"
+		} else if n.Field != "" && !containsString(caps.QueryFields, n.Field) {
+			unsupported = n.Field + " field"
+		}
+	})
+	if unsupported != "" {
+		return errors.NewUnsupportedQueryError(provider, unsupported)
+	}
+
+	return nil
+}
+
+// Tokenizer
+
+type queryTokenKind int
+
+const (
+	tokenWord queryTokenKind = iota
+	tokenPhrase
+	tokenField
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type queryToken struct {
+	kind  queryTokenKind
+	value string
+}
+
+func (t *queryToken) String() string {
+	switch t.kind {
+	case tokenPhrase:
+		return strconv.Quote(t.value)
+	case tokenField:
+		return t.value + ":"
+	case tokenLParen:
+		return "'('"
+	case tokenRParen:
+		return "')'"
+	default:
+		return "'" + t.value + "'"
+	}
+}
+
+// tokenizeQuery splits a query into tokens. A colon only starts a field when
+// the prefix is a grammar field, so values like "COVID-19: a review" stay
+// plain words.
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.NewValidationError("unterminated phrase in query", "query", input)
+			}
+			phrase := strings.Join(strings.Fields(string(runes[i+1:end])), " ")
+			if phrase != "" {
+				tokens = append(tokens, queryToken{kind: tokenPhrase, value: phrase})
+			}
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '"' {
+				end++
+			}
+			word := string(runes[i:end])
+			i = end
+
+			if prefix, rest, found := strings.Cut(word, ":"); found && containsString(QueryFields, strings.ToLower(prefix)) {
+				tokens = append(tokens, queryToken{kind: tokenField, value: strings.ToLower(prefix)})
+				if rest != "" {
+					tokens = append(tokens, queryToken{kind: tokenWord, value: rest})
+				}
+				continue
+			}
+
+			switch word {
+			case "AND":
+				tokens = append(tokens, queryToken{kind: tokenAnd, value: word})
+			case "OR":
+				tokens = append(tokens, queryToken{kind: tokenOr, value: word})
+			case "NOT":
+				tokens = append(tokens, queryToken{kind: tokenNot, value: word})
+			default:
+				tokens = append(tokens, queryToken{kind: tokenWord, value: word})
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// Parser
+
+type queryParser struct {
+	input  string
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *queryParser) next() *queryToken {
+	tok := p.peek()
+	if tok != nil {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return errors.NewValidationError("invalid query: "+fmt.Sprintf(format, args...), "query", p.input)
+}
+
+func (p *queryParser) parseOr() (*QueryNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*QueryNode{node}
+	for tok := p.peek(); tok != nil && tok.kind == tokenOr; tok = p.peek() {
+		p.next()
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	return combineQueryNodes(QueryOr, children), nil
+}
+
+func (p *queryParser) parseAnd() (*QueryNode, error) {
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*QueryNode{node}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind == tokenOr || tok.kind == tokenRParen {
+			break
+		}
+		if tok.kind == tokenAnd {
+			p.next()
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	return combineQueryNodes(QueryAnd, children), nil
+}
+
+func (p *queryParser) parseUnary() (*QueryNode, error) {
+	if tok := p.peek(); tok != nil && tok.kind == tokenNot {
+		p.next()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &QueryNode{Op: QueryNot, Children: []*QueryNode{child}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (*QueryNode, error) {
+	tok := p.next()
+	if tok == nil {
+		return nil, p.errorf("unexpected end of query")
+	}
+
+	switch tok.kind {
+	case tokenWord:
+		return &QueryNode{Op: QueryTerm, Value: tok.value}, nil
+	case tokenPhrase:
+		return &QueryNode{Op: QueryTerm, Value: tok.value, Phrase: true}, nil
+	case tokenLParen:
+		return p.parseGroup()
+	case tokenField:
+		return p.parseFieldValue(tok.value)
+	default:
+		return nil, p.errorf("unexpected %s", tok)
+	}
+}
+
+// parseGroup parses a parenthesized query after the opening parenthesis
+func (p *queryParser) parseGroup() (*QueryNode, error) {
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok == nil || tok.kind != tokenRParen {
+		return nil, p.errorf("missing closing parenthesis")
+	}
+	return node, nil
+}
+
+// parseFieldValue parses the value of a field prefix and assigns the field
+// to every term of the value
+func (p *queryParser) parseFieldValue(field string) (*QueryNode, error) {
+	var node *QueryNode
+
+	tok := p.next()
+	switch {
+	case tok == nil:
+		return nil, p.errorf("missing value for %s:", field)
+	case tok.kind == tokenWord:
+		node = &QueryNode{Op: QueryTerm, Value: tok.value}
+	case tok.kind == tokenPhrase:
+		node = &QueryNode{Op: QueryTerm, Value: tok.value, Phrase: true}
+	case tok.kind == tokenLParen:
+		group, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+		node = group
+	default:
+		return nil, p.errorf("missing value for %s:", field)
+	}
+
+	var fieldErr error
+	node.Walk(func(n *QueryNode) {
+		if fieldErr != nil || !n.IsLeaf() {
+			return
+		}
+		if n.Field != "" {
+			fieldErr = p.errorf("nested field %s: inside %s:", n.Field, field)
+			return
+		}
+		n.Field = field
+		if field == QueryFieldYear {
+			fieldErr = p.parseYearRange(n)
+		}
+	})
+	if fieldErr != nil {
+		return nil, fieldErr
+	}
+
+	return node, nil
+}
+
+// parseYearRange sets the year bounds of a year term
+func (p *queryParser) parseYearRange(n *QueryNode) error {
+	from, to, isRange := strings.Cut(n.Value, "..")
+	if n.Phrase || (from == "" && to == "") {
+		return p.errorf("invalid year %q", n.Value)
+	}
+
+	parseYear := func(value string) (int, error) {
+		if value == "" {
+			return 0, nil
+		}
+		year, err := strconv.Atoi(value)
+		if err != nil || len(value) != 4 {
+			return 0, p.errorf("invalid year %q", n.Value)
+		}
+		return year, nil
+	}
+
+	var err error
+	if n.YearFrom, err = parseYear(from); err != nil {
+		return err
+	}
+	if !isRange {
+		n.YearTo = n.YearFrom
+	} else if n.YearTo, err = parseYear(to); err != nil {
+		return err
+	}
+	if n.YearFrom != 0 && n.YearTo != 0 && n.YearFrom > n.YearTo {
+		return p.errorf("invalid year range %q", n.Value)
+	}
+
+	return nil
+}
+
+// combineQueryNodes joins nodes with an operator, flattening nested nodes of
+// the same operator
+func combineQueryNodes(op QueryOp, nodes []*QueryNode) *QueryNode {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	combined := &QueryNode{Op: op}
+	for _, node := range nodes {
+		if node.Op == op {
+			combined.Children = append(combined.Children, node.Children...)
+		} else {
+			combined.Children = append(combined.Children, node)
+		}
+	}
+	return combined
+}
@@ -0,0 +1,331 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"scifind-backend/internal/errors"
+)
+
+const (
+	// rateLimitKeyPrefix prefixes provider bucket keys in the shared KV store
+	rateLimitKeyPrefix = "ratelimit."
+
+	// maxSharedUpdateAttempts bounds optimistic concurrency retries on the KV store
+	maxSharedUpdateAttempts = 5
+)
+
+// TokenBucketLimiter implements RateLimiter with one token bucket per provider.
+// Buckets live in process memory unless a JetStream KV bucket is supplied, in
+// which case bucket state is shared by every instance using the same bucket.
+type TokenBucketLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]RateLimitConfig
+	buckets map[string]*tokenBucket
+	kv      jetstream.KeyValue
+	logger  *slog.Logger
+}
+
+// tokenBucket is the state of a single provider bucket
+type tokenBucket struct {
+	Tokens     float64 `json:"tokens"`
+	LastRefill int64   `json:"last_refill"` // unix nanoseconds
+}
+
+// NewTokenBucketLimiter creates an in-process token bucket rate limiter
+func NewTokenBucketLimiter(logger *slog.Logger) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		limits:  make(map[string]RateLimitConfig),
+		buckets: make(map[string]*tokenBucket),
+		logger:  logger,
+	}
+}
+
+// NewSharedTokenBucketLimiter creates a token bucket rate limiter whose state
+// is shared through a JetStream KV bucket. When the KV store is unreachable
+// the limiter falls back to its in-process buckets.
+func NewSharedTokenBucketLimiter(kv jetstream.KeyValue, logger *slog.Logger) *TokenBucketLimiter {
+	limiter := NewTokenBucketLimiter(logger)
+	limiter.kv = kv
+	return limiter
+}
+
+// RateLimitFromInterval converts a minimum interval between requests, such as
+// the "3s" arXiv policy, into a rate limit configuration
+func RateLimitFromInterval(interval time.Duration) RateLimitConfig {
+	if interval <= 0 {
+		return RateLimitConfig{}
+	}
+	return RateLimitConfig{
+		RequestsPerHour: int(time.Hour / interval),
+		BurstSize:       1,
+		BackoffDuration: interval,
+	}
+}
+
+// Allow reports whether a request to the provider may proceed now and
+// consumes a token if so
+func (l *TokenBucketLimiter) Allow(ctx context.Context, provider string) bool {
+	allowed := false
+	limited := l.update(ctx, provider, func(bucket *tokenBucket, rate, burst float64) {
+		// fn may run more than once when a shared update is retried
+		allowed = bucket.Tokens >= 1
+		if allowed {
+			bucket.Tokens--
+		}
+	})
+	return allowed || !limited
+}
+
+// Wait blocks until a token is available for the provider or the context is done
+func (l *TokenBucketLimiter) Wait(ctx context.Context, provider string) error {
+	var delay time.Duration
+	limited := l.update(ctx, provider, func(bucket *tokenBucket, rate, burst float64) {
+		// Reserve a token; a negative balance is the queue of waiting callers
+		delay = 0
+		bucket.Tokens--
+		if bucket.Tokens < 0 {
+			delay = time.Duration(-bucket.Tokens / rate * float64(time.Second))
+		}
+	})
+	if !limited || delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		// Give the reservation back, it cannot be used in time
+		l.release(ctx, provider)
+		return errors.NewRateLimitError("rate limit wait exceeds deadline for provider "+provider, delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(ctx, provider)
+		return ctx.Err()
+	}
+}
+
+// release gives back a token reserved by Wait, also once ctx is done so
+// cancelled callers leave no debt in the bucket for those queued after them
+func (l *TokenBucketLimiter) release(ctx context.Context, provider string) {
+	l.update(context.WithoutCancel(ctx), provider, func(bucket *tokenBucket, rate, burst float64) {
+		bucket.Tokens = math.Min(bucket.Tokens+1, burst)
+	})
+}
+
+// GetLimits returns the current rate limit state of the provider
+func (l *TokenBucketLimiter) GetLimits(provider string) RateLimitInfo {
+	l.mu.Lock()
+	limits, exists := l.limits[provider]
+	l.mu.Unlock()
+	if !exists {
+		return RateLimitInfo{}
+	}
+
+	rate, burst := bucketParameters(limits)
+	if rate <= 0 {
+		return RateLimitInfo{}
+	}
+
+	snapshot := l.snapshot(context.Background(), provider, rate, burst)
+
+	info := RateLimitInfo{
+		Remaining: int(math.Max(0, math.Floor(snapshot.Tokens))),
+		Limit:     int(burst),
+		ResetTime: time.Now().Add(time.Duration((burst - snapshot.Tokens) / rate * float64(time.Second))),
+	}
+	if snapshot.Tokens < 1 {
+		info.RetryAfter = time.Duration((1 - snapshot.Tokens) / rate * float64(time.Second))
+	}
+	return info
+}
+
+// UpdateLimits sets the limits of the provider and refills its bucket
+func (l *TokenBucketLimiter) UpdateLimits(provider string, limits RateLimitConfig) error {
+	if limits.RequestsPerSecond < 0 || limits.RequestsPerMinute < 0 || limits.RequestsPerHour < 0 || limits.BurstSize < 0 {
+		return errors.NewValidationError("Rate limits cannot be negative", "rate_limit", limits)
+	}
+
+	_, burst := bucketParameters(limits)
+
+	l.mu.Lock()
+	l.limits[provider] = limits
+	l.buckets[provider] = &tokenBucket{Tokens: burst, LastRefill: time.Now().UnixNano()}
+	l.mu.Unlock()
+
+	l.logger.Info("Rate limits updated",
+		slog.String("provider", provider),
+		slog.Int("requests_per_second", limits.RequestsPerSecond),
+		slog.Int("requests_per_minute", limits.RequestsPerMinute),
+		slog.Int("requests_per_hour", limits.RequestsPerHour),
+		slog.Int("burst_size", int(burst)))
+
+	return nil
+}
+
+// update refills the provider bucket and applies fn to it. It returns false
+// when the provider has no limits configured, in which case fn is not called.
+func (l *TokenBucketLimiter) update(ctx context.Context, provider string, fn func(bucket *tokenBucket, rate, burst float64)) bool {
+	l.mu.Lock()
+	limits, exists := l.limits[provider]
+	l.mu.Unlock()
+	if !exists {
+		return false
+	}
+
+	rate, burst := bucketParameters(limits)
+	if rate <= 0 {
+		return false
+	}
+
+	if l.kv != nil {
+		err := l.updateShared(ctx, provider, rate, burst, fn)
+		if err == nil {
+			return true
+		}
+		l.logger.Debug("Shared rate limit store unavailable, using local bucket",
+			slog.String("provider", provider),
+			slog.String("error", err.Error()))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[provider]
+	if !ok {
+		bucket = &tokenBucket{Tokens: burst, LastRefill: time.Now().UnixNano()}
+		l.buckets[provider] = bucket
+	}
+	refill(bucket, rate, burst, time.Now())
+	fn(bucket, rate, burst)
+
+	return true
+}
+
+// snapshot returns the refilled state of the provider bucket without taking
+// a token or writing it back, so reading limits does not contend with the
+// instances spending them
+func (l *TokenBucketLimiter) snapshot(ctx context.Context, provider string, rate, burst float64) tokenBucket {
+	now := time.Now()
+	if l.kv != nil {
+		bucket, _, err := l.getShared(ctx, provider, burst)
+		if err == nil {
+			refill(&bucket, rate, burst, now)
+			return bucket
+		}
+		l.logger.Debug("Shared rate limit store unavailable, using local bucket",
+			slog.String("provider", provider),
+			slog.String("error", err.Error()))
+	}
+
+	l.mu.Lock()
+	bucket := tokenBucket{Tokens: burst, LastRefill: now.UnixNano()}
+	if local, ok := l.buckets[provider]; ok {
+		bucket = *local
+	}
+	l.mu.Unlock()
+
+	refill(&bucket, rate, burst, now)
+	return bucket
+}
+
+// getShared reads the provider bucket and its revision from the KV bucket.
+// A missing bucket is full, with revision 0.
+func (l *TokenBucketLimiter) getShared(ctx context.Context, provider string, burst float64) (tokenBucket, uint64, error) {
+	bucket := tokenBucket{Tokens: burst, LastRefill: time.Now().UnixNano()}
+
+	entry, err := l.kv.Get(ctx, rateLimitKeyPrefix+provider)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(entry.Value(), &bucket); err != nil {
+			return tokenBucket{}, 0, err
+		}
+		return bucket, entry.Revision(), nil
+	case stderrors.Is(err, jetstream.ErrKeyNotFound):
+		return bucket, 0, nil
+	default:
+		return tokenBucket{}, 0, err
+	}
+}
+
+// updateShared applies fn to the provider bucket stored in the KV bucket using
+// optimistic concurrency on the entry revision
+func (l *TokenBucketLimiter) updateShared(ctx context.Context, provider string, rate, burst float64, fn func(bucket *tokenBucket, rate, burst float64)) error {
+	key := rateLimitKeyPrefix + provider
+
+	var lastErr error
+	for attempt := 0; attempt < maxSharedUpdateAttempts; attempt++ {
+		bucket, revision, err := l.getShared(ctx, provider, burst)
+		if err != nil {
+			return err
+		}
+
+		refill(&bucket, rate, burst, time.Now())
+		fn(&bucket, rate, burst)
+
+		data, err := json.Marshal(bucket)
+		if err != nil {
+			return err
+		}
+
+		if revision == 0 {
+			_, err = l.kv.Create(ctx, key, data)
+		} else {
+			_, err = l.kv.Update(ctx, key, data, revision)
+		}
+		if err == nil {
+			return nil
+		}
+		// Another instance updated the bucket first, retry with fresh state
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// refill adds the tokens accrued since the last refill, capped at burst
+func refill(bucket *tokenBucket, rate, burst float64, now time.Time) {
+	elapsed := now.Sub(time.Unix(0, bucket.LastRefill)).Seconds()
+	if elapsed > 0 {
+		bucket.Tokens = math.Min(burst, bucket.Tokens+elapsed*rate)
+	}
+	bucket.LastRefill = now.UnixNano()
+}
+
+// bucketParameters returns the refill rate in tokens per second and the
+// bucket capacity for the given limits
+func bucketParameters(limits RateLimitConfig) (float64, float64) {
+	var rate float64
+	switch {
+	case limits.RequestsPerSecond > 0:
+		rate = float64(limits.RequestsPerSecond)
+	case limits.RequestsPerMinute > 0:
+		rate = float64(limits.RequestsPerMinute) / 60
+	case limits.RequestsPerHour > 0:
+		rate = float64(limits.RequestsPerHour) / 3600
+	}
+
+	burst := float64(limits.BurstSize)
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return rate, burst
+}
+
+// hasRateLimits reports whether the configuration defines any limit
+func hasRateLimits(limits RateLimitConfig) bool {
+	rate, _ := bucketParameters(limits)
+	return rate > 0
+}
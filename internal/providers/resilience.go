@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"context"
+	stderrors "errors"
+
+	"scifind-backend/internal/errors"
+)
+
+// protect runs a provider call through the provider's retry policy and
+// circuit breaker. Every attempt passes the breaker, so an open circuit
+// stops further retries.
+func (m *Manager) protect(ctx context.Context, name, operation string, call func() error) error {
+	attempt := call
+	if m.circuitBreaker != nil {
+		attempt = func() error {
+			return m.circuitBreaker.Execute(ctx, name, call)
+		}
+	}
+
+	retrier := m.retrier(name)
+	if retrier == nil {
+		return attempt()
+	}
+	return retryCause(retrier.Execute(ctx, name+" "+operation, attempt))
+}
+
+// retrier returns the retry executor of a provider, nil when calls to it
+// are not retried
+func (m *Manager) retrier(name string) *errors.RetryExecutor {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if executor, ok := m.retriers[name]; ok {
+		return executor
+	}
+
+	config := m.retryConfig
+	if override, ok := m.providerRetries[name]; ok {
+		config = override
+	}
+	if config.MaxAttempts <= 1 {
+		m.retriers[name] = nil
+		return nil
+	}
+
+	executor := errors.NewRetryExecutor(config, m.classifier, m.logger)
+	m.retriers[name] = executor
+	return executor
+}
+
+// retryCause returns the last error of an exhausted retry, so callers see
+// why the provider failed rather than that retrying gave up
+func retryCause(err error) error {
+	var sciErr *errors.SciFindError
+	if stderrors.As(err, &sciErr) && sciErr.Code == "RETRY_EXHAUSTED" && sciErr.Cause != nil {
+		return sciErr.Cause
+	}
+	return err
+}
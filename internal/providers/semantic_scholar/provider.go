@@ -21,6 +21,7 @@ const (
 	defaultBaseURL = "https://api.semanticscholar.org/graph/v1"
 	providerName   = "semantic_scholar"
 	maxResults     = 10000 // Semantic Scholar API limit
+	maxCitations   = 1000  // references and citing papers fetched by GetPaper, the API page limit
 )
 
 // Provider implements the Semantic Scholar search provider
@@ -66,8 +67,8 @@ func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
 	return providers.ProviderCapabilities{
 		SupportsFullText:       true,
 		SupportsDateFilter:     true,
-		SupportsAuthFilter:     true,
-		SupportsCategoryFilter: true,
+		SupportsAuthFilter:     false, // the search endpoint has no author filter
+		SupportsCategoryFilter: false, // only category terms of the query map to fieldsOfStudy
 		SupportsSort:           true,
 
 		SupportedFields:    []string{"title", "abstract", "author", "venue", "year", "fieldsOfStudy"},
@@ -82,6 +83,9 @@ func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
 		SupportsExactMatch:  true,
 		SupportsFuzzySearch: true,
 		SupportsWildcards:   false,
+
+		QueryFields:          []string{"year", "cat"},
+		SupportsBooleanQuery: false,
 	}
 }
 
@@ -161,10 +165,79 @@ func (p *Provider) GetPaper(ctx context.Context, id string) (*models.Paper, erro
 		return nil, fmt.Errorf("failed to convert paper: %w", err)
 	}
 
+	// References and citations are best effort, the paper itself is still useful without them
+	if paperData.ReferenceCount > 0 {
+		references, err := p.getReferences(ctx, paperData.PaperID)
+		if err != nil {
+			p.logger.Warn("Failed to fetch Semantic Scholar references",
+				slog.String("id", paperData.PaperID),
+				slog.String("error", err.Error()))
+		} else {
+			paper.References = references
+		}
+	}
+	if paperData.CitationCount > 0 {
+		citations, err := p.getCitations(ctx, paperData.PaperID)
+		if err != nil {
+			p.logger.Warn("Failed to fetch Semantic Scholar citations",
+				slog.String("id", paperData.PaperID),
+				slog.String("error", err.Error()))
+		} else {
+			paper.Citations = citations
+		}
+	}
+
 	p.updateMetrics(true, time.Since(start), nil)
 	return paper, nil
 }
 
+// getReferences returns the IDs of the papers the given paper cites
+func (p *Provider) getReferences(ctx context.Context, paperID string) ([]string, error) {
+	reqURL := fmt.Sprintf("%s/paper/%s/references?fields=paperId&limit=%d", p.config.BaseURL, paperID, maxCitations)
+
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var cited SemanticScholarReferencesResponse
+	if err := json.Unmarshal(response, &cited); err != nil {
+		return nil, fmt.Errorf("failed to parse references response: %w", err)
+	}
+
+	// Papers Semantic Scholar could not match have no ID
+	references := make([]string, 0, len(cited.Data))
+	for _, reference := range cited.Data {
+		if reference.CitedPaper.PaperID != "" {
+			references = append(references, reference.CitedPaper.PaperID)
+		}
+	}
+	return references, nil
+}
+
+// getCitations returns the IDs of the papers citing the given paper
+func (p *Provider) getCitations(ctx context.Context, paperID string) ([]string, error) {
+	reqURL := fmt.Sprintf("%s/paper/%s/citations?fields=paperId&limit=%d", p.config.BaseURL, paperID, maxCitations)
+
+	response, err := p.makeRequest(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var citing SemanticScholarCitationsResponse
+	if err := json.Unmarshal(response, &citing); err != nil {
+		return nil, fmt.Errorf("failed to parse citations response: %w", err)
+	}
+
+	citations := make([]string, 0, len(citing.Data))
+	for _, citation := range citing.Data {
+		if citation.CitingPaper.PaperID != "" {
+			citations = append(citations, citation.CitingPaper.PaperID)
+		}
+	}
+	return citations, nil
+}
+
 // HealthCheck checks if the Semantic Scholar API is accessible
 func (p *Provider) HealthCheck(ctx context.Context) error {
 	start := time.Now()
@@ -239,6 +312,24 @@ func (p *Provider) buildURL(endpoint string, query *providers.SearchQuery) (stri
 
 	params := url.Values{}
 	params.Set("query", query.Query)
+
+	parsed, err := query.ParsedQuery()
+	if err != nil {
+		return "", err
+	}
+	if parsed != nil {
+		translated, err := translateQuery(parsed)
+		if err != nil {
+			return "", err
+		}
+		params.Set("query", translated.text)
+		if translated.year != "" {
+			params.Set("year", translated.year)
+		}
+		if len(translated.fieldsOfStudy) > 0 {
+			params.Set("fieldsOfStudy", strings.Join(translated.fieldsOfStudy, ","))
+		}
+	}
 	params.Set("limit", strconv.Itoa(query.Limit))
 	params.Set("offset", strconv.Itoa(query.Offset))
 
@@ -251,12 +342,6 @@ func (p *Provider) buildURL(endpoint string, query *providers.SearchQuery) (stri
 	params.Set("fields", strings.Join(fields, ","))
 
 	// Add filters
-	if authors, ok := query.Filters[providers.FilterAuthor]; ok {
-		// Semantic Scholar doesn't support direct author filtering in the query
-		// We'll filter after getting results
-		_ = authors // Will be used for post-processing filtering
-	}
-
 	if journal, ok := query.Filters[providers.FilterJournal]; ok {
 		// Add venue filter
 		currentQuery := params.Get("query")
@@ -0,0 +1,70 @@
+package semantic_scholar
+
+import (
+	"fmt"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// searchParams holds the search text and filter parameters translated from
+// a query AST
+type searchParams struct {
+	text          string
+	year          string
+	fieldsOfStudy []string
+}
+
+// translateQuery converts a query AST to Semantic Scholar relevance search
+// parameters. The relevance endpoint takes plain text, so only conjunctions
+// are supported; year and category terms become the year and fieldsOfStudy
+// parameters.
+func translateQuery(node *providers.QueryNode) (*searchParams, error) {
+	terms, ok := node.Conjuncts()
+	if !ok {
+		return nil, errors.NewUnsupportedQueryError(providerName, "boolean operators")
+	}
+
+	params := &searchParams{text: providers.FreeText(terms)}
+	for _, term := range terms {
+		switch term.Field {
+		case "":
+		case providers.QueryFieldYear:
+			if params.year != "" {
+				return nil, errors.NewUnsupportedQueryError(providerName, "multiple year ranges")
+			}
+			params.year = formatYearRange(term)
+		case providers.QueryFieldCategory:
+			fields := ValidateFieldsOfStudy([]string{term.Value})
+			if len(fields) == 0 {
+				return nil, errors.NewUnsupportedQueryError(providerName, fmt.Sprintf("category %q", term.Value))
+			}
+			params.fieldsOfStudy = append(params.fieldsOfStudy, fields...)
+		default:
+			return nil, errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+		}
+	}
+
+	if params.text == "" {
+		return nil, errors.NewUnsupportedQueryError(providerName, "queries without search terms")
+	}
+
+	return params, nil
+}
+
+// formatYearRange formats a year term as a year parameter such as
+// 2019-2022, 2019- or -2022
+func formatYearRange(term *providers.QueryNode) string {
+	if term.YearFrom != 0 && term.YearFrom == term.YearTo {
+		return fmt.Sprintf("%d", term.YearFrom)
+	}
+
+	var from, to string
+	if term.YearFrom != 0 {
+		from = fmt.Sprintf("%d", term.YearFrom)
+	}
+	if term.YearTo != 0 {
+		to = fmt.Sprintf("%d", term.YearTo)
+	}
+	return from + "-" + to
+}
@@ -79,17 +79,12 @@ type SemanticScholarCitationsResponse struct {
 	Data   []CitationContext       `json:"data"`
 }
 
-// CitationContext represents citation context information
+// CitationContext represents a paper citing the requested paper
 type CitationContext struct {
-	PaperID      string                `json:"paperId"`
-	CorpusID     int                   `json:"corpusId"`
-	Title        string                `json:"title"`
-	Venue        string                `json:"venue"`
-	Year         int                   `json:"year"`
-	Authors      []SemanticScholarAuthor `json:"authors"`
-	Intent       []string              `json:"intent"`
-	IsInfluential bool                 `json:"isInfluential"`
-	Contexts     []string              `json:"contexts"`
+	CitingPaper   SemanticScholarPaper  `json:"citingPaper"`
+	Intents       []string              `json:"intents"`
+	IsInfluential bool                  `json:"isInfluential"`
+	Contexts      []string              `json:"contexts"`
 }
 
 // SemanticScholarReferencesResponse represents references response
@@ -99,17 +94,12 @@ type SemanticScholarReferencesResponse struct {
 	Data   []ReferenceContext      `json:"data"`
 }
 
-// ReferenceContext represents reference context information
+// ReferenceContext represents a paper the requested paper cites
 type ReferenceContext struct {
-	PaperID      string                `json:"paperId"`
-	CorpusID     int                   `json:"corpusId"`
-	Title        string                `json:"title"`
-	Venue        string                `json:"venue"`
-	Year         int                   `json:"year"`
-	Authors      []SemanticScholarAuthor `json:"authors"`
-	Intent       []string              `json:"intent"`
-	IsInfluential bool                 `json:"isInfluential"`
-	Contexts     []string              `json:"contexts"`
+	CitedPaper    SemanticScholarPaper  `json:"citedPaper"`
+	Intents       []string              `json:"intents"`
+	IsInfluential bool                  `json:"isInfluential"`
+	Contexts      []string              `json:"contexts"`
 }
 
 // SemanticScholarError represents an error response from the API
@@ -0,0 +1,183 @@
+package providers
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+)
+
+// StreamEventType identifies the events of a streamed search
+type StreamEventType string
+
+const (
+	// StreamEventProvider is emitted as each provider finishes
+	StreamEventProvider StreamEventType = "provider"
+
+	// StreamEventSummary is the last event of a stream
+	StreamEventSummary StreamEventType = "summary"
+)
+
+// StreamEvent is an event of a streamed search. Provider events carry the
+// papers of one provider that were not sent in an earlier event; papers
+// describing a work already sent are only counted as duplicates.
+type StreamEvent struct {
+	Type       StreamEventType `json:"type"`
+	Provider   string          `json:"provider,omitempty"`
+	Papers     []models.Paper  `json:"papers,omitempty"`
+	Duplicates int             `json:"duplicates,omitempty"`
+	TotalCount int             `json:"total_count,omitempty"` // results the provider reported in total
+	Duration   time.Duration   `json:"duration,omitempty"`
+	CacheHit   bool            `json:"cache_hit,omitempty"`
+	Filters    *FilterSupport  `json:"filters,omitempty"` // how the provider applied the query filters
+	Error      string          `json:"error,omitempty"`
+	ErrorType  string          `json:"error_type,omitempty"` // see ProviderError.Type
+	Summary    *StreamSummary  `json:"summary,omitempty"`
+}
+
+// StreamSummary describes a finished stream
+type StreamSummary struct {
+	TotalCount          int               `json:"total_count"` // unique papers sent
+	RequestedProviders  []string          `json:"requested_providers"`
+	SuccessfulProviders []string          `json:"successful_providers"`
+	FailedProviders     []string          `json:"failed_providers"`
+	Errors              map[string]string `json:"errors,omitempty"` // error message by provider
+	CacheHits           int               `json:"cache_hits"`
+	TotalDuration       time.Duration     `json:"total_duration"`
+	PartialFailure      bool              `json:"partial_failure"`
+}
+
+// SearchStream searches the providers concurrently and emits an event as
+// soon as each provider finishes instead of waiting for the slowest one,
+// followed by a summary event. Without provider names all enabled providers
+// are searched. Providers still running when their timeout budget expires
+// are reported as failed. The channel is closed after the summary.
+func (m *Manager) SearchStream(ctx context.Context, query *SearchQuery, providerNames []string) (<-chan StreamEvent, error) {
+	if query.PageToken != "" {
+		return nil, errors.NewValidationError("page tokens are not supported by streamed searches", "cursor", query.PageToken)
+	}
+
+	if len(providerNames) == 0 {
+		for _, provider := range m.GetEnabledProviders() {
+			providerNames = append(providerNames, provider.Name())
+		}
+	}
+	providers, err := m.resolveProviders(providerNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// One event per provider plus the summary, sends never block
+	events := make(chan StreamEvent, len(providers)+1)
+	go m.streamResults(ctx, query, providers, events)
+
+	return events, nil
+}
+
+// streamResults runs the provider searches and emits their events
+func (m *Manager) streamResults(ctx context.Context, query *SearchQuery, providers []SearchProvider, events chan<- StreamEvent) {
+	defer close(events)
+
+	searchStart := time.Now()
+	resultChan := make(chan *providerResult, len(providers))
+	searchCtx, cancel := context.WithTimeout(ctx, m.searchBudget(providers))
+	defer cancel()
+
+	for _, provider := range providers {
+		go m.executeProviderSearch(searchCtx, provider, query, resultChan)
+	}
+
+	collected := newCollectedResults()
+	var sent []models.Paper
+	unique := 0
+
+collect:
+	for i := 0; i < len(providers); i++ {
+		select {
+		case result := <-resultChan:
+			collected.add(result)
+
+			event := StreamEvent{Type: StreamEventProvider, Provider: result.providerName}
+			if result.err != nil {
+				event.Error = result.err.Error()
+				event.ErrorType = classifyError(result.err)
+			} else {
+				event.Papers, event.Duplicates = freshPapers(sent, result.result.Papers)
+				event.TotalCount = result.result.TotalCount
+				event.Duration = result.result.Duration
+				event.CacheHit = result.result.CacheHit
+				event.Filters = result.result.Filters
+				sent = append(sent, result.result.Papers...)
+				unique += len(event.Papers)
+			}
+			events <- event
+		case <-searchCtx.Done():
+			break collect
+		}
+	}
+
+	// Report the providers that did not finish in time
+	for _, provider := range providers {
+		name := provider.Name()
+		if _, done := collected.providerResults[name]; !done {
+			collected.add(&providerResult{name, nil, errors.NewTimeoutError("Provider search timeout", m.providerTimeout(name))})
+		}
+	}
+	sort.Strings(collected.successfulProviders)
+	sort.Strings(collected.failedProviders)
+
+	summary := &StreamSummary{
+		TotalCount:          unique,
+		RequestedProviders:  getProviderNames(providers),
+		SuccessfulProviders: collected.successfulProviders,
+		FailedProviders:     collected.failedProviders,
+		CacheHits:           collected.cacheHits,
+		TotalDuration:       time.Since(searchStart),
+		PartialFailure:      len(collected.failedProviders) > 0 && len(collected.successfulProviders) > 0,
+	}
+	if len(collected.errors) > 0 {
+		summary.Errors = make(map[string]string, len(collected.errors))
+		for _, providerErr := range collected.errors {
+			summary.Errors[providerErr.Provider] = providerErr.Error.Error()
+		}
+	}
+	events <- StreamEvent{Type: StreamEventSummary, Summary: summary}
+
+	m.logger.Info("Streamed search completed",
+		slog.String("query", query.Query),
+		slog.Int("total_results", unique),
+		slog.Int("successful_providers", len(collected.successfulProviders)),
+		slog.Int("failed_providers", len(collected.failedProviders)),
+		slog.Duration("duration", summary.TotalDuration))
+}
+
+// freshPapers resolves the papers of a provider against the papers sent so
+// far. It returns the papers describing new works, merged with any other
+// records of the same work in the batch, and how many records were dropped.
+func freshPapers(sent, papers []models.Paper) ([]models.Paper, int) {
+	all := make([]models.Paper, 0, len(sent)+len(papers))
+	all = append(all, sent...)
+	all = append(all, papers...)
+	merged, clusters := resolvePapers(all)
+
+	known := make(map[int]bool, len(sent))
+	for _, cluster := range clusters[:len(sent)] {
+		known[cluster] = true
+	}
+
+	fresh := make([]models.Paper, 0, len(papers))
+	duplicates := 0
+	for _, cluster := range clusters[len(sent):] {
+		if known[cluster] {
+			duplicates++
+			continue
+		}
+		known[cluster] = true
+		fresh = append(fresh, merged[cluster])
+	}
+
+	return fresh, duplicates
+}
@@ -80,6 +80,9 @@ func (p *Provider) GetCapabilities() providers.ProviderCapabilities {
 		SupportsExactMatch:  true,
 		SupportsFuzzySearch: true,
 		SupportsWildcards:   false,
+
+		QueryFields:          []string{},
+		SupportsBooleanQuery: false,
 	}
 }
 
@@ -89,6 +92,14 @@ func (p *Provider) Search(ctx context.Context, query *providers.SearchQuery) (*p
 
 	// Build search request
 	searchReq := BuildTavilySearchRequest(query.Query, query.Filters, query.Limit, query.Offset)
+	parsed, err := query.ParsedQuery()
+	if err == nil && parsed != nil {
+		searchReq.Query, err = translateQuery(parsed)
+	}
+	if err != nil {
+		p.updateMetrics(false, time.Since(start), err)
+		return nil, err
+	}
 
 	// Make API request
 	response, err := p.makeSearchRequest(ctx, searchReq)
@@ -0,0 +1,23 @@
+package tavily
+
+import (
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+)
+
+// translateQuery converts a query AST to Tavily search text. Tavily takes
+// free text only, so fielded terms and boolean operators are unsupported.
+func translateQuery(node *providers.QueryNode) (string, error) {
+	terms, ok := node.Conjuncts()
+	if !ok {
+		return "", errors.NewUnsupportedQueryError(providerName, "boolean operators")
+	}
+
+	for _, term := range terms {
+		if term.Field != "" {
+			return "", errors.NewUnsupportedQueryError(providerName, term.Field+" field")
+		}
+	}
+
+	return providers.FreeText(terms), nil
+}
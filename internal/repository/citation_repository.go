@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// citationLookupChunk is the most external IDs resolved per query
+	citationLookupChunk = 500
+
+	// citationBackfillBatch is the number of papers backfilled at a time
+	citationBackfillBatch = 200
+)
+
+// citationRepository implements CitationRepository interface
+type citationRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewCitationRepository creates a new citation repository
+func NewCitationRepository(db *gorm.DB, logger *slog.Logger) CitationRepository {
+	return &citationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SyncPapers adds the references and citations of stored papers to the
+// citation graph. IDs naming a stored paper are resolved to it, the others
+// are kept as external IDs; edges naming any of the papers by an external
+// ID are resolved to them. Edges are only ever added.
+func (r *citationRepository) SyncPapers(ctx context.Context, papers []models.Paper) error {
+	if len(papers) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return syncPapers(tx, papers)
+	})
+
+	if err != nil {
+		return errors.NewDatabaseError("sync_citations", err)
+	}
+	return nil
+}
+
+// ReplacePaper syncs a stored paper whose references or citations changed.
+// Its edges no longer given by them are dropped, unless the stored paper on
+// their other side gives them.
+func (r *citationRepository) ReplacePaper(ctx context.Context, paper *models.Paper) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := pruneCitations(tx, paper.ID, paper); err != nil {
+			return err
+		}
+		return syncPapers(tx, []models.Paper{*paper})
+	})
+
+	if err != nil {
+		return errors.NewDatabaseError("replace_citations", err)
+	}
+	return nil
+}
+
+// RemovePaper drops the edges of a deleted paper. Those the stored paper on
+// their other side gives are kept with the deleted paper as an external ID.
+func (r *citationRepository) RemovePaper(ctx context.Context, paperID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return pruneCitations(tx, paperID, nil)
+	})
+
+	if err != nil {
+		return errors.NewDatabaseError("remove_citations", err)
+	}
+	return nil
+}
+
+// syncPapers adds the edges given by the papers within the transaction
+func syncPapers(tx *gorm.DB, papers []models.Paper) error {
+	if len(papers) == 0 {
+		return nil
+	}
+
+	for i := range papers {
+		if err := resolveCitations(tx, &papers[i]); err != nil {
+			return err
+		}
+	}
+
+	var externalIDs []string
+	for _, paper := range papers {
+		externalIDs = append(externalIDs, paper.References...)
+		externalIDs = append(externalIDs, paper.Citations...)
+	}
+	resolved, err := resolveCitationIDs(tx, externalIDs)
+	if err != nil {
+		return err
+	}
+
+	// Edges to the IDs as resolved, or as normalized when unknown
+	seen := make(map[[2]string]bool)
+	var edges []models.PaperCitation
+	add := func(edge models.PaperCitation) {
+		key := [2]string{edge.CitingID, edge.CitedID}
+		if edge.CitingID == "" || edge.CitedID == "" || edge.CitingID == edge.CitedID || seen[key] {
+			return
+		}
+		seen[key] = true
+		edges = append(edges, edge)
+	}
+	for _, paper := range papers {
+		for _, reference := range paper.References {
+			id, ok := resolved[models.NormalizeCitationID(reference)]
+			if !ok {
+				id = models.NormalizeCitationID(reference)
+			}
+			add(models.PaperCitation{
+				CitingID:        paper.ID,
+				CitingResolved:  true,
+				CitedID:         id,
+				CitedResolved:   ok,
+				CitedExternalID: reference,
+				Source:          paper.SourceProvider,
+			})
+		}
+		for _, citation := range paper.Citations {
+			id, ok := resolved[models.NormalizeCitationID(citation)]
+			if !ok {
+				id = models.NormalizeCitationID(citation)
+			}
+			add(models.PaperCitation{
+				CitingID:         id,
+				CitingResolved:   ok,
+				CitingExternalID: citation,
+				CitedID:          paper.ID,
+				CitedResolved:    true,
+				Source:           paper.SourceProvider,
+			})
+		}
+	}
+	if len(edges) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(edges, 100).Error
+}
+
+// pruneCitations drops the edges of the stored paper not given by its current
+// references and citations, or all of them when it was deleted, and syncs the
+// stored papers on their other side again to keep the edges they give
+func pruneCitations(tx *gorm.DB, paperID string, current *models.Paper) error {
+	var edges []models.PaperCitation
+	err := tx.Where("(citing_id = ? AND citing_resolved = ?) OR (cited_id = ? AND cited_resolved = ?)",
+		paperID, true, paperID, true).
+		Find(&edges).Error
+	if err != nil {
+		return err
+	}
+
+	var references, citations map[string]bool
+	if current != nil {
+		if references, err = citationTargets(tx, current.References); err != nil {
+			return err
+		}
+		if citations, err = citationTargets(tx, current.Citations); err != nil {
+			return err
+		}
+	}
+
+	var neighbors []string
+	for _, edge := range edges {
+		if edge.CitingID == paperID && edge.CitingResolved {
+			if references[edge.CitedID] {
+				continue
+			}
+			if edge.CitedResolved {
+				neighbors = append(neighbors, edge.CitedID)
+			}
+		} else {
+			if citations[edge.CitingID] {
+				continue
+			}
+			if edge.CitingResolved {
+				neighbors = append(neighbors, edge.CitingID)
+			}
+		}
+		if err := tx.Delete(&models.PaperCitation{}, "citing_id = ? AND cited_id = ?", edge.CitingID, edge.CitedID).Error; err != nil {
+			return err
+		}
+	}
+
+	for start := 0; start < len(neighbors); start += citationBackfillBatch {
+		var papers []models.Paper
+		err := tx.Select("id", "source_id", "doi", "arxiv_id", "pm_id", "pmc_id", "references", "citations", "source_provider").
+			Where("id IN ?", neighbors[start:min(start+citationBackfillBatch, len(neighbors))]).
+			Find(&papers).Error
+		if err != nil {
+			return err
+		}
+		if err := syncPapers(tx, papers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// citationTargets returns the IDs the edges to the external IDs are stored
+// under: the stored papers they name, or the normalized IDs
+func citationTargets(tx *gorm.DB, externalIDs []string) (map[string]bool, error) {
+	resolved, err := resolveCitationIDs(tx, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]bool, len(externalIDs))
+	for _, externalID := range externalIDs {
+		id := models.NormalizeCitationID(externalID)
+		if paperID, ok := resolved[id]; ok {
+			id = paperID
+		}
+		targets[id] = true
+	}
+	return targets, nil
+}
+
+// resolveCitations points the edges naming the paper by an external ID at it
+func resolveCitations(tx *gorm.DB, paper *models.Paper) error {
+	identifiers := paper.GetIdentifiers()
+	for i, identifier := range identifiers {
+		identifiers[i] = models.NormalizeCitationID(identifier)
+	}
+
+	var pending []models.PaperCitation
+	err := tx.Where("(cited_id IN ? AND cited_resolved = ?) OR (citing_id IN ? AND citing_resolved = ?)",
+		identifiers, false, identifiers, false).
+		Find(&pending).Error
+	if err != nil {
+		return err
+	}
+
+	for _, edge := range pending {
+		if err := tx.Delete(&models.PaperCitation{}, "citing_id = ? AND cited_id = ?", edge.CitingID, edge.CitedID).Error; err != nil {
+			return err
+		}
+		if !edge.CitedResolved && slices.Contains(identifiers, edge.CitedID) {
+			edge.CitedID, edge.CitedResolved = paper.ID, true
+		}
+		if !edge.CitingResolved && slices.Contains(identifiers, edge.CitingID) {
+			edge.CitingID, edge.CitingResolved = paper.ID, true
+		}
+		if edge.CitingID == edge.CitedID {
+			continue
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&edge).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveCitationIDs maps the normalized external IDs naming stored papers to
+// the IDs of those papers. IDs naming several papers go to the first by ID.
+func resolveCitationIDs(tx *gorm.DB, externalIDs []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for start := 0; start < len(externalIDs); start += citationLookupChunk {
+		chunk := externalIDs[start:min(start+citationLookupChunk, len(externalIDs))]
+		normalized := make([]string, len(chunk))
+		for i, id := range chunk {
+			normalized[i] = models.NormalizeCitationID(id)
+		}
+
+		var papers []models.Paper
+		err := tx.Select("id", "source_id", "doi", "arxiv_id", "pm_id", "pmc_id").
+			Where("id IN ? OR source_id IN ? OR LOWER(doi) IN ? OR arxiv_id IN ? OR pm_id IN ? OR pmc_id IN ?",
+				chunk, chunk, normalized, chunk, chunk, chunk).
+			Order("id").
+			Find(&papers).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, paper := range papers {
+			for _, identifier := range paper.GetIdentifiers() {
+				key := models.NormalizeCitationID(identifier)
+				if _, ok := resolved[key]; !ok {
+					resolved[key] = paper.ID
+				}
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// GetCiting returns the edges from the papers citing the stored paper,
+// resolved papers first
+func (r *citationRepository) GetCiting(ctx context.Context, paperID string, limit, offset int) ([]models.PaperCitation, int64, error) {
+	db := r.db.WithContext(ctx).
+		Model(&models.PaperCitation{}).
+		Where("cited_id = ? AND cited_resolved = ?", paperID, true)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewDatabaseError("count_citing", err)
+	}
+
+	var edges []models.PaperCitation
+	err := db.Order("citing_resolved DESC, citing_id").
+		Limit(limit).
+		Offset(offset).
+		Find(&edges).Error
+	if err != nil {
+		return nil, 0, errors.NewDatabaseError("get_citing", err)
+	}
+
+	return edges, total, nil
+}
+
+// GetCited returns the edges to the papers the stored paper cites, resolved
+// papers first
+func (r *citationRepository) GetCited(ctx context.Context, paperID string, limit, offset int) ([]models.PaperCitation, int64, error) {
+	db := r.db.WithContext(ctx).
+		Model(&models.PaperCitation{}).
+		Where("citing_id = ? AND citing_resolved = ?", paperID, true)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewDatabaseError("count_cited", err)
+	}
+
+	var edges []models.PaperCitation
+	err := db.Order("cited_resolved DESC, cited_id").
+		Limit(limit).
+		Offset(offset).
+		Find(&edges).Error
+	if err != nil {
+		return nil, 0, errors.NewDatabaseError("get_cited", err)
+	}
+
+	return edges, total, nil
+}
+
+// GetEdges returns up to limit edges from or to any of the stored papers
+func (r *citationRepository) GetEdges(ctx context.Context, paperIDs []string, limit int) ([]models.PaperCitation, error) {
+	if len(paperIDs) == 0 || limit <= 0 {
+		return []models.PaperCitation{}, nil
+	}
+
+	var edges []models.PaperCitation
+	err := r.db.WithContext(ctx).
+		Where("(citing_id IN ? AND citing_resolved = ?) OR (cited_id IN ? AND cited_resolved = ?)",
+			paperIDs, true, paperIDs, true).
+		Order("citing_id, cited_id").
+		Limit(limit).
+		Find(&edges).Error
+	if err != nil {
+		return nil, errors.NewDatabaseError("get_citation_edges", err)
+	}
+
+	return edges, nil
+}
+
+// backfillCitations builds the citation graph from the references and
+// citations of the stored papers when it is still empty
+func backfillCitations(db *gorm.DB, logger *slog.Logger) error {
+	var edges int64
+	if err := db.Model(&models.PaperCitation{}).Count(&edges).Error; err != nil {
+		return err
+	}
+	if edges > 0 {
+		return nil
+	}
+
+	repo := &citationRepository{db: db, logger: logger}
+	synced, lastID := 0, ""
+	for {
+		var papers []models.Paper
+		err := db.Select("id", "source_id", "doi", "arxiv_id", "pm_id", "pmc_id", "references", "citations", "source_provider").
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(citationBackfillBatch).
+			Find(&papers).Error
+		if err != nil {
+			return err
+		}
+		if len(papers) == 0 {
+			break
+		}
+
+		if err := repo.SyncPapers(context.Background(), papers); err != nil {
+			return err
+		}
+		synced += len(papers)
+		lastID = papers[len(papers)-1].ID
+	}
+
+	if synced > 0 {
+		logger.Info("Citation graph backfilled", slog.Int("papers", synced))
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ type Container struct {
 	Author   AuthorRepository
 	Category CategoryRepository
 	Search   SearchRepository
+	Citation CitationRepository
 }
 
 // NewContainer creates a new repository container
@@ -21,6 +22,7 @@ func NewContainer(db *gorm.DB, logger *slog.Logger) *Container {
 		Author:   NewAuthorRepository(db, logger),
 		Category: NewCategoryRepository(db, logger),
 		Search:   NewSearchRepository(db, logger),
+		Citation: NewCitationRepository(db, logger),
 	}
 }
 
@@ -31,5 +33,6 @@ func (c *Container) Health() map[string]bool {
 		"author":   c.Author != nil,
 		"category": c.Category != nil,
 		"search":   c.Search != nil,
+		"citation": c.Citation != nil,
 	}
 }
\ No newline at end of file
@@ -103,8 +103,10 @@ func (d *Database) Migrate() error {
 		&models.Author{},
 		&models.Category{},
 		&models.Paper{},
+		&models.PaperCitation{},
 		&models.SearchHistory{},
 		&models.SearchCache{},
+		&models.QuerySuggestion{},
 	}
 
 	for _, model := range models {
@@ -122,6 +124,11 @@ func (d *Database) Migrate() error {
 	if err := d.seedPredefinedData(); err != nil {
 		return fmt.Errorf("failed to seed data: %w", err)
 	}
+	
+	// Build the citation graph of papers stored before it existed
+	if err := backfillCitations(d.DB, d.logger); err != nil {
+		return fmt.Errorf("failed to backfill citations: %w", err)
+	}
 
 	d.logger.Info("Database migration completed successfully")
 	return nil
@@ -204,6 +211,12 @@ func (d *Database) createCustomIndexes() error {
 		}
 	}
 
+	// Paper searches fall back to LIKE matching without the full-text index
+	if err := CreatePaperSearchIndex(d.DB); err != nil {
+		d.logger.Warn("Failed to create paper search index, build with -tags sqlite_fts5 for full-text search",
+			slog.String("error", err.Error()))
+	}
+
 	return nil
 }
 
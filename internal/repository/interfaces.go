@@ -20,10 +20,12 @@ type PaperRepository interface {
 	// Search and filtering
 	Search(ctx context.Context, query string, filters *models.PaperFilter, sort *models.PaperSort, limit, offset int) ([]models.Paper, int64, error)
 	SearchByVector(ctx context.Context, embedding []float32, filters *models.PaperFilter, limit int) ([]models.Paper, error)
+	GetByVectorMatches(ctx context.Context, matches []VectorMatch) ([]models.Paper, error)
 	SearchFullText(ctx context.Context, query string, filters *models.PaperFilter, limit, offset int) ([]models.Paper, int64, error)
 	
 	// Bulk operations
 	CreateBatch(ctx context.Context, papers []models.Paper) error
+	UpsertMetadataBatch(ctx context.Context, papers []models.Paper) error
 	UpdateBatch(ctx context.Context, papers []models.Paper) error
 	
 	// Statistics and analytics
@@ -34,19 +36,35 @@ type PaperRepository interface {
 	// Processing state management
 	GetPendingPapers(ctx context.Context, limit int) ([]models.Paper, error)
 	UpdateProcessingState(ctx context.Context, paperID string, state string) error
+	
+	// Embeddings
+	GetPapersWithoutEmbedding(ctx context.Context, limit int) ([]models.Paper, error)
+	UpdateEmbedding(ctx context.Context, paperID string, embedding []float32) error
 	GetProcessingStats(ctx context.Context) (*ProcessingStats, error)
 	
 	// Relationships
 	GetAuthorPapers(ctx context.Context, authorID string, limit, offset int) ([]models.Paper, error)
 	GetCategoryPapers(ctx context.Context, categoryID string, limit, offset int) ([]models.Paper, error)
-	GetSimilarPapers(ctx context.Context, paperID string, limit int) ([]models.Paper, error)
+	GetPapersByAuthors(ctx context.Context, authorIDs []string, limit int) ([]models.Paper, error)
+	GetByIdentifiers(ctx context.Context, identifiers []string, limit int) ([]models.Paper, error)
 	
 	// Citation analysis
+	GetPapersReferencing(ctx context.Context, identifiers []string, limit int) ([]models.Paper, error)
 	GetCitations(ctx context.Context, paperID string) ([]models.Paper, error)
 	GetReferences(ctx context.Context, paperID string) ([]models.Paper, error)
 	UpdateCitationCount(ctx context.Context, paperID string, count int) error
 }
 
+// CitationRepository defines the interface for citation graph operations
+type CitationRepository interface {
+	SyncPapers(ctx context.Context, papers []models.Paper) error
+	ReplacePaper(ctx context.Context, paper *models.Paper) error
+	RemovePaper(ctx context.Context, paperID string) error
+	GetCiting(ctx context.Context, paperID string, limit, offset int) ([]models.PaperCitation, int64, error)
+	GetCited(ctx context.Context, paperID string, limit, offset int) ([]models.PaperCitation, int64, error)
+	GetEdges(ctx context.Context, paperIDs []string, limit int) ([]models.PaperCitation, error)
+}
+
 // AuthorRepository defines the interface for author database operations
 type AuthorRepository interface {
 	// Basic CRUD operations
@@ -117,7 +135,7 @@ type SearchRepository interface {
 	// Search history
 	CreateSearchHistory(ctx context.Context, history *models.SearchHistory) error
 	GetSearchHistory(ctx context.Context, userID *string, limit, offset int) ([]models.SearchHistory, error)
-	GetPopularQueries(ctx context.Context, since time.Time, limit int) ([]QueryStats, error)
+	GetPopularQueries(ctx context.Context, from, to time.Time, limit int) ([]QueryStats, error)
 	GetUserSearchStats(ctx context.Context, userID string) (*UserSearchStats, error)
 	
 	// Search cache
@@ -134,6 +152,7 @@ type SearchRepository interface {
 	// Analytics
 	GetSearchAnalytics(ctx context.Context, from, to time.Time) (*SearchAnalytics, error)
 	GetProviderPerformance(ctx context.Context, provider string, from, to time.Time) (*ProviderPerformance, error)
+	GetProvidersPerformance(ctx context.Context, from, to time.Time) ([]ProviderPerformance, error)
 }
 
 // Transaction defines the interface for database transactions
@@ -211,9 +230,11 @@ type ProcessingStats struct {
 
 // QueryStats represents search query statistics
 type QueryStats struct {
-	Query       string    `json:"query"`
-	Count       int64     `json:"count"`
-	LastQueried time.Time `json:"last_queried"`
+	Query        string    `json:"query"`
+	Count        int64     `json:"count"`
+	SuccessCount int64     `json:"success_count"`
+	AvgResults   float64   `json:"avg_results"`
+	LastQueried  time.Time `json:"last_queried"`
 }
 
 // UserSearchStats represents user search statistics
@@ -238,10 +259,13 @@ type CacheStats struct {
 type SearchAnalytics struct {
 	TotalSearches    int64   `json:"total_searches"`
 	UniqueQueries    int64   `json:"unique_queries"`
+	UniqueUsers      int64   `json:"unique_users"`
 	AvgResponseTime  float64 `json:"avg_response_time_ms"`
 	CacheHitRate     float64 `json:"cache_hit_rate"`
 	TopQueries       []QueryStats `json:"top_queries"`
 	ProviderUsage    []ProviderUsageStats `json:"provider_usage"`
+	ProviderFailures []ProviderUsageStats `json:"provider_failures"` // searches each provider failed in
+	SearchesByHour   []HourlySearchStats `json:"searches_by_hour"`
 	ErrorRate        float64 `json:"error_rate"`
 }
 
@@ -291,4 +315,9 @@ type SourceCount struct {
 type ProviderUsageStats struct {
 	Provider string `json:"provider"`
 	Usage    int64  `json:"usage"`
+}
+
+type HourlySearchStats struct {
+	Hour  int   `json:"hour"`
+	Count int64 `json:"count"`
 }
\ No newline at end of file
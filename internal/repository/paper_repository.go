@@ -2,28 +2,41 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"scifind-backend/internal/embeddings"
 	"scifind-backend/internal/errors"
 	"scifind-backend/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // paperRepository implements PaperRepository interface
 type paperRepository struct {
-	db     *gorm.DB
-	logger *slog.Logger
+	db        *gorm.DB
+	search    paperSearch
+	citations CitationRepository
+	logger    *slog.Logger
+	
+	// vectorDimensions caches the dimensions of the pgvector embedding table
+	vectorDimensions atomic.Int32
 }
 
-// NewPaperRepository creates a new paper repository
+// NewPaperRepository creates a new paper repository. Text searches use the
+// full-text search of the database dialect, and the references and
+// citations of stored papers are added to the citation graph.
 func NewPaperRepository(db *gorm.DB, logger *slog.Logger) PaperRepository {
 	return &paperRepository{
-		db:     db,
-		logger: logger,
+		db:        db,
+		search:    newPaperSearch(db),
+		citations: NewCitationRepository(db, logger),
+		logger:    logger,
 	}
 }
 
@@ -35,6 +48,7 @@ func (r *paperRepository) Create(ctx context.Context, paper *models.Paper) error
 		}
 		return errors.NewDatabaseError("create_paper", err)
 	}
+	r.syncCitations(ctx, []models.Paper{*paper})
 	return nil
 }
 
@@ -98,9 +112,27 @@ func (r *paperRepository) Update(ctx context.Context, paper *models.Paper) error
 	if result.RowsAffected == 0 {
 		return errors.NewNotFoundError("Paper not found", "paper")
 	}
+	if err := r.citations.ReplacePaper(ctx, paper); err != nil {
+		r.warnCitations(1, err)
+	}
 	return nil
 }
 
+// syncCitations adds the citations of stored papers to the citation graph
+func (r *paperRepository) syncCitations(ctx context.Context, papers []models.Paper) {
+	if err := r.citations.SyncPapers(ctx, papers); err != nil {
+		r.warnCitations(len(papers), err)
+	}
+}
+
+// warnCitations logs a failed citation graph update. The graph is derived
+// from the papers, so failing to update it only warns.
+func (r *paperRepository) warnCitations(papers int, err error) {
+	r.logger.Warn("Failed to update citation graph",
+		slog.Int("papers", papers),
+		slog.String("error", err.Error()))
+}
+
 // Delete deletes a paper
 func (r *paperRepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).Delete(&models.Paper{}, "id = ?", id)
@@ -110,10 +142,14 @@ func (r *paperRepository) Delete(ctx context.Context, id string) error {
 	if result.RowsAffected == 0 {
 		return errors.NewNotFoundError("Paper not found", "paper")
 	}
+	if err := r.citations.RemovePaper(ctx, id); err != nil {
+		r.warnCitations(1, err)
+	}
 	return nil
 }
 
-// Search searches for papers with filters and sorting
+// Search searches for papers with filters and sorting. Matches are sorted
+// by text relevance when sorting by relevance.
 func (r *paperRepository) Search(ctx context.Context, query string, filters *models.PaperFilter, sort *models.PaperSort, limit, offset int) ([]models.Paper, int64, error) {
 	db := r.db.WithContext(ctx).
 		Preload("Authors").
@@ -121,7 +157,7 @@ func (r *paperRepository) Search(ctx context.Context, query string, filters *mod
 	
 	// Apply search query
 	if query != "" {
-		db = db.Where("to_tsvector('english', title || ' ' || COALESCE(abstract, '')) @@ plainto_tsquery('english', ?)", query)
+		db = r.search.match(db, query, false)
 	}
 	
 	// Apply filters
@@ -134,7 +170,14 @@ func (r *paperRepository) Search(ctx context.Context, query string, filters *mod
 	}
 	
 	// Apply sorting
-	db = r.applyPaperSorting(db, sort)
+	if query != "" {
+		db = r.search.highlight(db, query, false)
+	}
+	if query != "" && sort != nil && sort.Field == "relevance" {
+		db = r.search.rank(db, query, false)
+	} else {
+		db = r.applyPaperSorting(db, sort)
+	}
 	
 	// Apply pagination
 	var papers []models.Paper
@@ -146,46 +189,140 @@ func (r *paperRepository) Search(ctx context.Context, query string, filters *mod
 	return papers, total, nil
 }
 
-// SearchByVector searches for papers using vector similarity
+// SearchByVector returns the papers matching the filters whose embeddings
+// are most similar to the given embedding, best first, with their cosine
+// similarity as score. Papers without an embedding of the same length are
+// skipped. On PostgreSQL with a pgvector index of the same dimensions the
+// filters are applied within the index search, other databases compare the
+// candidates in process; unfiltered searches are better served by a
+// VectorIndex.
 func (r *paperRepository) SearchByVector(ctx context.Context, embedding []float32, filters *models.PaperFilter, limit int) ([]models.Paper, error) {
-	// Convert float32 slice to PostgreSQL array format
-	embeddingStr := fmt.Sprintf("[%s]", strings.Join(func() []string {
-		strs := make([]string, len(embedding))
-		for i, v := range embedding {
-			strs[i] = fmt.Sprintf("%f", v)
+	if len(embedding) == 0 || limit <= 0 {
+		return []models.Paper{}, nil
+	}
+	if r.pgvectorDimensions(ctx) == len(embedding) {
+		return r.searchPgvector(ctx, embedding, filters, limit)
+	}
+	
+	// Score the embeddings of the candidates
+	var candidates []models.Paper
+	db := r.db.WithContext(ctx).Select("id", "embedding").Where("embedding IS NOT NULL")
+	if err := r.applyPaperFilters(db, filters).Find(&candidates).Error; err != nil {
+		return nil, errors.NewDatabaseError("search_papers_vector", err)
+	}
+	
+	matches := make([]VectorMatch, 0, len(candidates))
+	for _, candidate := range candidates {
+		if len(candidate.Embedding) == len(embedding) {
+			matches = append(matches, VectorMatch{PaperID: candidate.ID, Score: embeddings.Cosine(embedding, candidate.Embedding)})
 		}
-		return strs
-	}(), ","))
+	}
+	sortMatches(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
 	
+	return r.GetByVectorMatches(ctx, matches)
+}
+
+// searchPgvector searches the pgvector embedding table joined with the
+// papers matching the filters, see pgvectorIndex
+func (r *paperRepository) searchPgvector(ctx context.Context, embedding []float32, filters *models.PaperFilter, limit int) ([]models.Paper, error) {
+	literal := vectorLiteral(embedding)
 	db := r.db.WithContext(ctx).
-		Preload("Authors").
-		Preload("Categories")
+		Model(&models.Paper{}).
+		Select("papers.id AS paper_id, 1 - (paper_embeddings.embedding <=> ?::vector) AS score", literal).
+		Joins("JOIN paper_embeddings ON paper_embeddings.paper_id = papers.id")
+	
+	// <=> is the cosine distance, served by the HNSW index
+	var matches []VectorMatch
+	err := r.applyPaperFilters(db, filters).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "paper_embeddings.embedding <=> ?::vector",
+			Vars: []interface{}{literal},
+		}}).
+		Limit(limit).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, errors.NewDatabaseError("search_papers_vector", err)
+	}
 	
-	// Apply filters
-	db = r.applyPaperFilters(db, filters)
+	return r.GetByVectorMatches(ctx, matches)
+}
+
+// pgvectorDimensions returns the dimensions of the pgvector embedding table,
+// 0 when the database has none. The table is created by NewVectorIndex, so
+// only its presence is remembered.
+func (r *paperRepository) pgvectorDimensions(ctx context.Context) int {
+	if r.db.Dialector.Name() != "postgres" {
+		return 0
+	}
+	if dimensions := r.vectorDimensions.Load(); dimensions > 0 {
+		return int(dimensions)
+	}
 	
-	// Order by vector similarity (cosine distance)
-	db = db.Order(fmt.Sprintf("embedding <=> '%s'", embeddingStr))
+	// The type modifier of a vector column is its dimensions
+	var dimensions int32
+	err := r.db.WithContext(ctx).
+		Raw("SELECT atttypmod FROM pg_attribute WHERE attrelid = to_regclass('paper_embeddings') AND attname = 'embedding'").
+		Scan(&dimensions).Error
+	if err != nil {
+		r.logger.Warn("Failed to look up the pgvector index", slog.String("error", err.Error()))
+		return 0
+	}
+	if dimensions > 0 {
+		r.vectorDimensions.Store(dimensions)
+	}
+	return int(dimensions)
+}
+
+// GetByVectorMatches loads the papers of vector search matches in the order
+// of the matches, with their scores. Papers deleted since they were indexed
+// are skipped.
+func (r *paperRepository) GetByVectorMatches(ctx context.Context, matches []VectorMatch) ([]models.Paper, error) {
+	if len(matches) == 0 {
+		return []models.Paper{}, nil
+	}
 	
-	var papers []models.Paper
-	err := db.Limit(limit).Find(&papers).Error
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.PaperID
+	}
+	
+	var found []models.Paper
+	err := r.db.WithContext(ctx).
+		Preload("Authors").
+		Preload("Categories").
+		Where("id IN ?", ids).
+		Find(&found).Error
 	if err != nil {
-		return nil, errors.NewDatabaseError("search_papers_vector", err)
+		return nil, errors.NewDatabaseError("get_vector_matches", err)
 	}
 	
+	byID := make(map[string]models.Paper, len(found))
+	for _, paper := range found {
+		byID[paper.ID] = paper
+	}
+	papers := make([]models.Paper, 0, len(matches))
+	for _, match := range matches {
+		if paper, ok := byID[match.PaperID]; ok {
+			score := match.Score
+			paper.Score = &score
+			papers = append(papers, paper)
+		}
+	}
 	return papers, nil
 }
 
-// SearchFullText performs full-text search on papers
+// SearchFullText performs full-text search on papers, ranked by relevance
 func (r *paperRepository) SearchFullText(ctx context.Context, query string, filters *models.PaperFilter, limit, offset int) ([]models.Paper, int64, error) {
 	db := r.db.WithContext(ctx).
 		Preload("Authors").
 		Preload("Categories")
 	
-	// Full-text search with ranking
+	// Full-text search
 	if query != "" {
-		db = db.Where("to_tsvector('english', title || ' ' || COALESCE(abstract, '') || ' ' || COALESCE(full_text, '')) @@ plainto_tsquery('english', ?)", query).
-			Order("ts_rank(to_tsvector('english', title || ' ' || COALESCE(abstract, '') || ' ' || COALESCE(full_text, '')), plainto_tsquery('english', '" + query + "')) DESC")
+		db = r.search.match(db, query, true)
 	}
 	
 	// Apply filters
@@ -197,6 +334,11 @@ func (r *paperRepository) SearchFullText(ctx context.Context, query string, filt
 		return nil, 0, errors.NewDatabaseError("count_papers_fulltext", err)
 	}
 	
+	// Rank and highlight the matches
+	if query != "" {
+		db = r.search.rank(r.search.highlight(db, query, true), query, true)
+	}
+	
 	// Get results
 	var papers []models.Paper
 	err := db.Limit(limit).Offset(offset).Find(&papers).Error
@@ -224,6 +366,45 @@ func (r *paperRepository) CreateBatch(ctx context.Context, papers []models.Paper
 		if err := r.db.WithContext(ctx).CreateInBatches(batch, len(batch)).Error; err != nil {
 			return errors.NewDatabaseError("create_papers_batch", err)
 		}
+		r.syncCitations(ctx, batch)
+	}
+	
+	return nil
+}
+
+// metadataColumns are the bibliographic columns refreshed by
+// UpsertMetadataBatch
+var metadataColumns = []string{
+	"title", "abstract", "doi", "journal", "published_at",
+	"url", "pdf_url", "language", "source_url", "updated_at",
+}
+
+// UpsertMetadataBatch creates papers in a batch and refreshes only the
+// bibliographic metadata of papers that already exist. Citation counts,
+// quality scores, embeddings and full text gathered since are kept.
+func (r *paperRepository) UpsertMetadataBatch(ctx context.Context, papers []models.Paper) error {
+	if len(papers) == 0 {
+		return nil
+	}
+	
+	batchSize := 100
+	for i := 0; i < len(papers); i += batchSize {
+		end := i + batchSize
+		if end > len(papers) {
+			end = len(papers)
+		}
+		
+		batch := papers[i:end]
+		err := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns(metadataColumns),
+			}).
+			CreateInBatches(batch, len(batch)).Error
+		if err != nil {
+			return errors.NewDatabaseError("upsert_papers_batch", err)
+		}
+		r.syncCitations(ctx, batch)
 	}
 	
 	return nil
@@ -252,6 +433,7 @@ func (r *paperRepository) UpdateBatch(ctx context.Context, papers []models.Paper
 	if err := tx.Commit().Error; err != nil {
 		return errors.NewDatabaseError("commit_papers_batch", err)
 	}
+	r.syncCitations(ctx, papers)
 	
 	return nil
 }
@@ -364,6 +546,40 @@ func (r *paperRepository) UpdateProcessingState(ctx context.Context, paperID str
 	return nil
 }
 
+// GetPapersWithoutEmbedding returns papers that have not been embedded yet,
+// oldest first
+func (r *paperRepository) GetPapersWithoutEmbedding(ctx context.Context, limit int) ([]models.Paper, error) {
+	var papers []models.Paper
+	err := r.db.WithContext(ctx).
+		Where("embedding IS NULL OR embedding = ?", "[]").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&papers).Error
+	
+	if err != nil {
+		return nil, errors.NewDatabaseError("get_papers_without_embedding", err)
+	}
+	
+	return papers, nil
+}
+
+// UpdateEmbedding stores the embedding of a paper
+func (r *paperRepository) UpdateEmbedding(ctx context.Context, paperID string, embedding []float32) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Paper{ID: paperID}).
+		Select("embedding").
+		Updates(&models.Paper{Embedding: embedding})
+	
+	if result.Error != nil {
+		return errors.NewDatabaseError("update_embedding", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NewNotFoundError("Paper not found", "paper")
+	}
+	
+	return nil
+}
+
 // GetProcessingStats returns processing statistics
 func (r *paperRepository) GetProcessingStats(ctx context.Context) (*ProcessingStats, error) {
 	var stats ProcessingStats
@@ -445,56 +661,107 @@ func (r *paperRepository) GetCategoryPapers(ctx context.Context, categoryID stri
 	return papers, nil
 }
 
-// GetSimilarPapers returns papers similar to a given paper
-func (r *paperRepository) GetSimilarPapers(ctx context.Context, paperID string, limit int) ([]models.Paper, error) {
-	// Get the paper's embedding and categories
-	var paper models.Paper
+// GetByIdentifiers returns the papers known by any of the identifiers, be
+// it their ID, source ID, DOI, arXiv ID, PMID or PMCID. DOIs match
+// ignoring case.
+func (r *paperRepository) GetByIdentifiers(ctx context.Context, identifiers []string, limit int) ([]models.Paper, error) {
+	if len(identifiers) == 0 || limit <= 0 {
+		return []models.Paper{}, nil
+	}
+	
+	dois := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		dois[i] = strings.ToLower(identifier)
+	}
+	
+	var papers []models.Paper
 	err := r.db.WithContext(ctx).
+		Preload("Authors").
 		Preload("Categories").
-		First(&paper, "id = ?", paperID).Error
+		Where("id IN ? OR source_id IN ? OR LOWER(doi) IN ? OR arxiv_id IN ? OR pm_id IN ? OR pmc_id IN ?",
+			identifiers, identifiers, dois, identifiers, identifiers, identifiers).
+		Order("id").
+		Limit(limit).
+		Find(&papers).Error
 	
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.NewNotFoundError("Paper not found", "paper")
+		return nil, errors.NewDatabaseError("get_papers_by_identifiers", err)
+	}
+	
+	return papers, nil
+}
+
+// GetPapersReferencing returns the papers whose references include any of
+// the identifiers, ignoring case as DOIs do, most cited first
+func (r *paperRepository) GetPapersReferencing(ctx context.Context, identifiers []string, limit int) ([]models.Paper, error) {
+	if len(identifiers) == 0 || limit <= 0 {
+		return []models.Paper{}, nil
+	}
+	
+	// References are stored as a JSON array, so look for the quoted identifier
+	conditions := make([]string, len(identifiers))
+	vars := make([]interface{}, len(identifiers))
+	for i, identifier := range identifiers {
+		quoted, err := json.Marshal(strings.ToLower(identifier))
+		if err != nil {
+			return nil, errors.NewSerializationError("failed to encode reference", identifier)
 		}
-		return nil, errors.NewDatabaseError("get_paper_for_similarity", err)
+		conditions[i] = `LOWER("references") LIKE ? ESCAPE '\'`
+		vars[i] = "%" + escapeLike(string(quoted)) + "%"
 	}
 	
-	db := r.db.WithContext(ctx).
+	var papers []models.Paper
+	err := r.db.WithContext(ctx).
 		Preload("Authors").
 		Preload("Categories").
-		Where("id != ?", paperID)
+		Where("("+strings.Join(conditions, " OR ")+")", vars...).
+		Order("citation_count DESC, id").
+		Limit(limit).
+		Find(&papers).Error
 	
-	// If paper has categories, find papers in same categories
-	if len(paper.Categories) > 0 {
-		categoryIDs := make([]string, len(paper.Categories))
-		for i, cat := range paper.Categories {
-			categoryIDs[i] = cat.ID
-		}
-		
-		db = db.Joins("JOIN paper_categories ON papers.id = paper_categories.paper_id").
-			Where("paper_categories.category_id IN ?", categoryIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("get_papers_referencing", err)
 	}
 	
+	return papers, nil
+}
+
+// GetPapersByAuthors returns the papers written by any of the authors, best
+// quality first
+func (r *paperRepository) GetPapersByAuthors(ctx context.Context, authorIDs []string, limit int) ([]models.Paper, error) {
+	if len(authorIDs) == 0 || limit <= 0 {
+		return []models.Paper{}, nil
+	}
+	
+	authored := r.db.Table("paper_authors").Select("paper_id").Where("author_id IN ?", authorIDs)
+	
 	var papers []models.Paper
-	err = db.Order("quality_score DESC, citation_count DESC").
+	err := r.db.WithContext(ctx).
+		Preload("Authors").
+		Preload("Categories").
+		Where("id IN (?)", authored).
+		Order("quality_score DESC, citation_count DESC, id").
 		Limit(limit).
 		Find(&papers).Error
 	
 	if err != nil {
-		return nil, errors.NewDatabaseError("get_similar_papers", err)
+		return nil, errors.NewDatabaseError("get_papers_by_authors", err)
 	}
 	
 	return papers, nil
 }
 
-// GetCitations returns papers that cite the given paper
+// GetCitations returns the stored papers that cite the given paper
 func (r *paperRepository) GetCitations(ctx context.Context, paperID string) ([]models.Paper, error) {
+	citing := r.db.Model(&models.PaperCitation{}).
+		Select("citing_id").
+		Where("cited_id = ? AND cited_resolved = ? AND citing_resolved = ?", paperID, true, true)
+	
 	var papers []models.Paper
 	err := r.db.WithContext(ctx).
 		Preload("Authors").
 		Preload("Categories").
-		Where("? = ANY(references)", paperID).
+		Where("id IN (?)", citing).
 		Order("published_at DESC").
 		Find(&papers).Error
 	
@@ -505,30 +772,25 @@ func (r *paperRepository) GetCitations(ctx context.Context, paperID string) ([]m
 	return papers, nil
 }
 
-// GetReferences returns papers referenced by the given paper
+// GetReferences returns the stored papers referenced by the given paper
 func (r *paperRepository) GetReferences(ctx context.Context, paperID string) ([]models.Paper, error) {
-	// Get the paper's references
-	var paper models.Paper
-	err := r.db.WithContext(ctx).
-		Select("references").
-		First(&paper, "id = ?", paperID).Error
-	
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.NewNotFoundError("Paper not found", "paper")
-		}
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Paper{}).Where("id = ?", paperID).Count(&count).Error; err != nil {
 		return nil, errors.NewDatabaseError("get_paper_references", err)
 	}
-	
-	if len(paper.References) == 0 {
-		return []models.Paper{}, nil
+	if count == 0 {
+		return nil, errors.NewNotFoundError("Paper not found", "paper")
 	}
 	
+	cited := r.db.Model(&models.PaperCitation{}).
+		Select("cited_id").
+		Where("citing_id = ? AND citing_resolved = ? AND cited_resolved = ?", paperID, true, true)
+	
 	var papers []models.Paper
-	err = r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).
 		Preload("Authors").
 		Preload("Categories").
-		Where("id IN ?", paper.References).
+		Where("id IN (?)", cited).
 		Find(&papers).Error
 	
 	if err != nil {
@@ -576,11 +838,21 @@ func (r *paperRepository) applyPaperFilters(db *gorm.DB, filters *models.PaperFi
 	}
 	
 	if filters.Title != "" {
-		db = db.Where("title ILIKE ?", "%"+filters.Title+"%")
+		db = db.Where("title "+r.search.like()+" ?", "%"+filters.Title+"%")
+	}
+	
+	// Every listed author must match one of the paper's authors
+	for _, author := range filters.Authors {
+		db = db.Where("id IN (SELECT paper_authors.paper_id FROM paper_authors JOIN authors ON authors.id = paper_authors.author_id WHERE authors.name "+r.search.like()+" ?)", "%"+author+"%")
+	}
+	
+	// Categories match by ID, source code (e.g. cs.AI) or name
+	for _, category := range filters.Categories {
+		db = db.Where("id IN (SELECT paper_categories.paper_id FROM paper_categories JOIN categories ON categories.id = paper_categories.category_id WHERE categories.id = ? OR categories.source_code = ? OR categories.name "+r.search.like()+" ?)", category, category, category)
 	}
 	
 	if filters.Journal != "" {
-		db = db.Where("journal ILIKE ?", "%"+filters.Journal+"%")
+		db = db.Where("journal "+r.search.like()+" ?", "%"+filters.Journal+"%")
 	}
 	
 	if filters.Language != "" {
@@ -659,7 +931,7 @@ func (r *paperRepository) applyPaperSorting(db *gorm.DB, sort *models.PaperSort)
 	case "published_at":
 		orderClause = fmt.Sprintf("%s %s NULLS LAST", sort.Field, strings.ToUpper(sort.Order))
 	case "relevance":
-		// This would be handled in the search query with ts_rank
+		// Text relevance is ranked by the search, see Search
 		orderClause = "quality_score DESC, citation_count DESC"
 	}
 	
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// paperSearchTable is the SQLite FTS5 index of the paper texts
+	paperSearchTable = "papers_fts"
+
+	// Snippets mark the matched terms for highlighting
+	snippetStart = "<mark>"
+	snippetStop  = "</mark>"
+)
+
+// paperSearch is the database specific part of paper text searches.
+// Searches cover the title, abstract and keywords of papers, and their full
+// text as well if fullText is set.
+type paperSearch interface {
+	// match restricts db to the papers matching the query text
+	match(db *gorm.DB, query string, fullText bool) *gorm.DB
+
+	// highlight selects a snippet of each matched paper with the matched
+	// terms marked, and its relevance score where the database ranks matches
+	highlight(db *gorm.DB, query string, fullText bool) *gorm.DB
+
+	// rank orders the matched papers by relevance, best first
+	rank(db *gorm.DB, query string, fullText bool) *gorm.DB
+
+	// like returns the case-insensitive LIKE operator
+	like() string
+}
+
+// newPaperSearch returns the paper search of the database dialect
+func newPaperSearch(db *gorm.DB) paperSearch {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return postgresPaperSearch{}
+	case "sqlite":
+		return &sqlitePaperSearch{db: db}
+	default:
+		return likePaperSearch{}
+	}
+}
+
+// CreatePaperSearchIndex creates the SQLite FTS5 index of the paper texts,
+// kept in sync with the papers table by triggers, and indexes the papers
+// already stored. Other databases search their own indexes and need nothing.
+// SQLite must be built with FTS5, see the sqlite_fts5 build tag.
+func CreatePaperSearchIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "sqlite" {
+		return nil
+	}
+	defer paperSearchIndexRuns.Add(1)
+
+	exists, err := hasPaperSearchIndex(db)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	statements := []string{
+		"CREATE VIRTUAL TABLE papers_fts USING fts5(title, abstract, full_text, keywords, content='papers', content_rowid='rowid', tokenize='porter unicode61')",
+		`CREATE TRIGGER IF NOT EXISTS papers_fts_insert AFTER INSERT ON papers BEGIN
+			INSERT INTO papers_fts (rowid, title, abstract, full_text, keywords) VALUES (new.rowid, new.title, new.abstract, new.full_text, new.keywords);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS papers_fts_delete AFTER DELETE ON papers BEGIN
+			INSERT INTO papers_fts (papers_fts, rowid, title, abstract, full_text, keywords) VALUES ('delete', old.rowid, old.title, old.abstract, old.full_text, old.keywords);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS papers_fts_update AFTER UPDATE OF title, abstract, full_text, keywords ON papers BEGIN
+			INSERT INTO papers_fts (papers_fts, rowid, title, abstract, full_text, keywords) VALUES ('delete', old.rowid, old.title, old.abstract, old.full_text, old.keywords);
+			INSERT INTO papers_fts (rowid, title, abstract, full_text, keywords) VALUES (new.rowid, new.title, new.abstract, new.full_text, new.keywords);
+		END`,
+		"INSERT INTO papers_fts (papers_fts) VALUES ('rebuild')",
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to create paper search index: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// paperSearchIndexRuns counts the calls of CreatePaperSearchIndex, so
+// searches that found no index look again once it may have been created
+var paperSearchIndexRuns atomic.Int64
+
+// hasPaperSearchIndex reports whether the SQLite FTS5 index exists
+func hasPaperSearchIndex(db *gorm.DB) (bool, error) {
+	var count int64
+	err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", paperSearchTable).Scan(&count).Error
+	return count > 0, err
+}
+
+// searchTerms splits the query text into its words
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// postgresPaperSearch searches the tsvector of the paper texts, see the GIN
+// index created by the migration
+type postgresPaperSearch struct{}
+
+// document is the tsvector searched by a query
+func (postgresPaperSearch) document(fullText bool) string {
+	if fullText {
+		return "to_tsvector('english', title || ' ' || COALESCE(abstract, '') || ' ' || COALESCE(full_text, ''))"
+	}
+	return "to_tsvector('english', title || ' ' || COALESCE(abstract, ''))"
+}
+
+func (s postgresPaperSearch) match(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	return db.Where(s.document(fullText)+" @@ plainto_tsquery('english', ?)", query)
+}
+
+func (s postgresPaperSearch) highlight(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	text := "title || ' ' || COALESCE(abstract, '')"
+	if fullText {
+		text += " || ' ' || COALESCE(full_text, '')"
+	}
+	options := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=1", snippetStart, snippetStop)
+	return db.Select("papers.*, ts_headline('english', "+text+", plainto_tsquery('english', ?), ?) AS snippet, ts_rank("+s.document(fullText)+", plainto_tsquery('english', ?)) AS score",
+		query, options, query)
+}
+
+func (s postgresPaperSearch) rank(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	return db.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  "ts_rank(" + s.document(fullText) + ", plainto_tsquery('english', ?)) DESC",
+		Vars: []interface{}{query},
+	}})
+}
+
+func (postgresPaperSearch) like() string {
+	return "ILIKE"
+}
+
+// sqlitePaperSearch searches the FTS5 index of the paper texts, ranked by
+// bm25. Without the index, e.g. when SQLite was built without FTS5, it falls
+// back to LIKE matching.
+type sqlitePaperSearch struct {
+	db *gorm.DB
+
+	fallback likePaperSearch
+	found    atomic.Bool
+	missing  atomic.Int64 // paperSearchIndexRuns+1 when the index was found missing
+}
+
+// indexed reports whether the FTS5 index exists. Only its existence is
+// remembered; a missing index is looked up again once CreatePaperSearchIndex
+// ran, and a failed lookup on the next search.
+func (s *sqlitePaperSearch) indexed() bool {
+	if s.found.Load() {
+		return true
+	}
+	runs := paperSearchIndexRuns.Load()
+	if s.missing.Load() == runs+1 {
+		return false
+	}
+
+	exists, err := hasPaperSearchIndex(s.db)
+	if err != nil {
+		return false
+	}
+	if !exists {
+		s.missing.Store(runs + 1)
+		return false
+	}
+	s.found.Store(true)
+	return true
+}
+
+// expression builds the FTS5 query of the query text. Every word must
+// match; words are quoted so the text cannot inject FTS5 syntax.
+func (s *sqlitePaperSearch) expression(query string, fullText bool) string {
+	terms := searchTerms(query)
+	for i, term := range terms {
+		terms[i] = `"` + term + `"`
+	}
+	expression := strings.Join(terms, " ")
+	if !fullText {
+		expression = "{title abstract keywords} : (" + expression + ")"
+	}
+	return expression
+}
+
+func (s *sqlitePaperSearch) match(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	if !s.indexed() {
+		return s.fallback.match(db, query, fullText)
+	}
+	if len(searchTerms(query)) == 0 {
+		return db.Where("1 = 0")
+	}
+
+	matches := fmt.Sprintf("SELECT rowid AS fts_rowid, bm25(papers_fts) AS fts_rank, snippet(papers_fts, -1, '%s', '%s', '…', 16) AS fts_snippet FROM papers_fts WHERE papers_fts MATCH ?", snippetStart, snippetStop)
+	return db.Joins("JOIN ("+matches+") AS fts ON fts.fts_rowid = papers.rowid", s.expression(query, fullText))
+}
+
+func (s *sqlitePaperSearch) highlight(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	if !s.indexed() {
+		return s.fallback.highlight(db, query, fullText)
+	}
+	// bm25 scores better matches lower
+	return db.Select("papers.*, fts.fts_snippet AS snippet, -fts.fts_rank AS score")
+}
+
+func (s *sqlitePaperSearch) rank(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	if !s.indexed() {
+		return s.fallback.rank(db, query, fullText)
+	}
+	// bm25 scores better matches lower
+	return db.Order("fts.fts_rank")
+}
+
+func (s *sqlitePaperSearch) like() string {
+	return "LIKE"
+}
+
+// likePaperSearch matches every word of the query with LIKE. It has no
+// relevance ranking, better papers come first instead.
+type likePaperSearch struct{}
+
+func (likePaperSearch) match(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	columns := []string{"title", "abstract", "keywords"}
+	if fullText {
+		columns = append(columns, "full_text")
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return db.Where("1 = 0")
+	}
+	for _, term := range terms {
+		conditions := make([]string, len(columns))
+		vars := make([]interface{}, len(columns))
+		for i, column := range columns {
+			conditions[i] = column + " LIKE ?"
+			vars[i] = "%" + term + "%"
+		}
+		db = db.Where("("+strings.Join(conditions, " OR ")+")", vars...)
+	}
+	return db
+}
+
+func (likePaperSearch) highlight(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	return db
+}
+
+func (likePaperSearch) rank(db *gorm.DB, query string, fullText bool) *gorm.DB {
+	return db.Order("quality_score DESC, citation_count DESC")
+}
+
+func (likePaperSearch) like() string {
+	return "LIKE"
+}
@@ -6,6 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"scifind-backend/internal/errors"
@@ -57,18 +60,22 @@ func (r *searchRepository) GetSearchHistory(ctx context.Context, userID *string,
 	return history, nil
 }
 
-// GetPopularQueries returns popular search queries since a given time
-func (r *searchRepository) GetPopularQueries(ctx context.Context, since time.Time, limit int) ([]QueryStats, error) {
+// GetPopularQueries returns popular search queries within a time period
+func (r *searchRepository) GetPopularQueries(ctx context.Context, from, to time.Time, limit int) ([]QueryStats, error) {
 	var queries []struct {
-		Query       string
-		Count       int64
-		LastQueried time.Time
+		Query        string
+		Count        int64
+		SuccessCount int64
+		AvgResults   float64
+		LastQueried  dbTime
 	}
 	
 	err := r.db.WithContext(ctx).
 		Model(&models.SearchHistory{}).
-		Select("query, COUNT(*) as count, MAX(requested_at) as last_queried").
-		Where("requested_at >= ?", since).
+		Select("query, COUNT(*) as count, " +
+			"SUM(CASE WHEN error IS NULL OR error = '' THEN 1 ELSE 0 END) as success_count, " +
+			"AVG(result_count) as avg_results, MAX(requested_at) as last_queried").
+		Where("requested_at BETWEEN ? AND ?", from, to).
 		Group("query").
 		Order("count DESC").
 		Limit(limit).
@@ -81,9 +88,11 @@ func (r *searchRepository) GetPopularQueries(ctx context.Context, since time.Tim
 	stats := make([]QueryStats, len(queries))
 	for i, q := range queries {
 		stats[i] = QueryStats{
-			Query:       q.Query,
-			Count:       q.Count,
-			LastQueried: q.LastQueried,
+			Query:        q.Query,
+			Count:        q.Count,
+			SuccessCount: q.SuccessCount,
+			AvgResults:   q.AvgResults,
+			LastQueried:  time.Time(q.LastQueried),
 		}
 	}
 	
@@ -135,7 +144,7 @@ func (r *searchRepository) GetUserSearchStats(ctx context.Context, userID string
 	var topQueries []struct {
 		Query       string
 		Count       int64
-		LastQueried time.Time
+		LastQueried dbTime
 	}
 	
 	err = r.db.WithContext(ctx).
@@ -156,7 +165,7 @@ func (r *searchRepository) GetUserSearchStats(ctx context.Context, userID string
 		stats.TopQueries[i] = QueryStats{
 			Query:       q.Query,
 			Count:       q.Count,
-			LastQueried: q.LastQueried,
+			LastQueried: time.Time(q.LastQueried),
 		}
 	}
 	
@@ -302,57 +311,155 @@ func (r *searchRepository) GetCacheStats(ctx context.Context) (*CacheStats, erro
 	return &stats, nil
 }
 
-// GetSearchSuggestions returns search suggestions based on query
+// suggestionTypeWeights ranks suggestion types against each other when mixed
+var suggestionTypeWeights = map[string]float64{
+	models.SuggestionTypeQuery:    1.0,
+	models.SuggestionTypeAuthor:   0.8,
+	models.SuggestionTypeCategory: 0.7,
+	models.SuggestionTypeJournal:  0.6,
+}
+
+// suggestionCandidatePool is how many past queries per requested suggestion
+// are loaded for rescoring by their decayed weight
+const suggestionCandidatePool = 3
+
+// suggestionCandidate is a suggestion with its unnormalized popularity
+type suggestionCandidate struct {
+	Text       string
+	Popularity float64
+}
+
+// GetSearchSuggestions returns prefix matched suggestions mixing past queries
+// with stored author names, journals and category names
 func (r *searchRepository) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
-	var suggestions []models.SearchSuggestion
+	prefix := normalizeSuggestionText(query)
+	if prefix == "" || limit <= 0 {
+		return []models.SearchSuggestion{}, nil
+	}
+	startsWith := escapeLike(prefix) + "%"
+	wordStartsWith := "% " + escapeLike(prefix) + "%"
 	
-	// Get suggestions from search history
-	var historyQueries []struct {
-		Query string
-		Count int64
+	candidates := make(map[string][]suggestionCandidate)
+	
+	// Past queries, weighted by how often and how recently they found results
+	queries, err := r.querySuggestionCandidates(ctx, startsWith, limit)
+	if err != nil {
+		return nil, err
 	}
+	candidates[models.SuggestionTypeQuery] = queries
 	
-	err := r.db.WithContext(ctx).
-		Model(&models.SearchHistory{}).
-		Select("query, COUNT(*) as count").
-		Where("query ILIKE ?", query+"%").
-		Group("query").
-		Order("count DESC").
+	// Author names, matching the start of any name part
+	var authors []suggestionCandidate
+	err = r.db.WithContext(ctx).
+		Model(&models.Author{}).
+		Select("name as text, paper_count as popularity").
+		Where("LOWER(name) LIKE ? ESCAPE '\\' OR LOWER(name) LIKE ? ESCAPE '\\'", startsWith, wordStartsWith).
+		Order("paper_count DESC").
 		Limit(limit).
-		Scan(&historyQueries).Error
+		Scan(&authors).Error
+	if err != nil {
+		return nil, errors.NewDatabaseError("get_author_suggestions", err)
+	}
+	candidates[models.SuggestionTypeAuthor] = authors
 	
+	// Journals of stored papers
+	var journals []suggestionCandidate
+	err = r.db.WithContext(ctx).
+		Model(&models.Paper{}).
+		Select("journal as text, COUNT(*) as popularity").
+		Where("journal IS NOT NULL AND (LOWER(journal) LIKE ? ESCAPE '\\' OR LOWER(journal) LIKE ? ESCAPE '\\')", startsWith, wordStartsWith).
+		Group("journal").
+		Order("popularity DESC").
+		Limit(limit).
+		Scan(&journals).Error
 	if err != nil {
-		return nil, errors.NewDatabaseError("get_search_suggestions", err)
+		return nil, errors.NewDatabaseError("get_journal_suggestions", err)
 	}
+	candidates[models.SuggestionTypeJournal] = journals
 	
-	for _, hq := range historyQueries {
-		suggestions = append(suggestions, models.SearchSuggestion{
-			Text:  hq.Query,
-			Score: float64(hq.Count),
-			Type:  "query",
-		})
+	// Category names and codes such as cs.LG
+	var categories []suggestionCandidate
+	err = r.db.WithContext(ctx).
+		Model(&models.Category{}).
+		Select("name as text, paper_count as popularity").
+		Where("is_active = ? AND (LOWER(name) LIKE ? ESCAPE '\\' OR LOWER(source_code) LIKE ? ESCAPE '\\')", true, startsWith, startsWith).
+		Order("paper_count DESC").
+		Limit(limit).
+		Scan(&categories).Error
+	if err != nil {
+		return nil, errors.NewDatabaseError("get_category_suggestions", err)
 	}
+	candidates[models.SuggestionTypeCategory] = categories
 	
-	// Could also add suggestions from paper titles, author names, etc.
+	return mixSuggestions(candidates, limit), nil
+}
+
+// querySuggestionCandidates returns past queries matching the prefix scored by
+// their weight decayed to now. Stored weights are only decayed when a query is
+// searched again, so the heaviest and the most recently searched queries are
+// loaded and rescored.
+func (r *searchRepository) querySuggestionCandidates(ctx context.Context, startsWith string, limit int) ([]suggestionCandidate, error) {
+	matching := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Select("id, query, weight, last_searched_at").
+			Where("LOWER(query) LIKE ? ESCAPE '\\' AND weight > 0", startsWith).
+			Limit(limit * suggestionCandidatePool)
+	}
+	
+	var heaviest, recent []models.QuerySuggestion
+	if err := matching().Order("weight DESC").Find(&heaviest).Error; err != nil {
+		return nil, errors.NewDatabaseError("get_query_suggestions", err)
+	}
+	if err := matching().Order("last_searched_at DESC").Find(&recent).Error; err != nil {
+		return nil, errors.NewDatabaseError("get_query_suggestions", err)
+	}
+	
+	now := time.Now()
+	seen := make(map[string]bool)
+	var queries []suggestionCandidate
+	for _, suggestion := range append(heaviest, recent...) {
+		if seen[suggestion.ID] {
+			continue
+		}
+		seen[suggestion.ID] = true
+		queries = append(queries, suggestionCandidate{Text: suggestion.Query, Popularity: suggestion.DecayedWeight(now)})
+	}
 	
-	return suggestions, nil
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Popularity > queries[j].Popularity
+	})
+	if len(queries) > limit {
+		queries = queries[:limit]
+	}
+	return queries, nil
 }
 
-// UpdateSearchSuggestions updates search suggestions based on query performance
+// UpdateSearchSuggestions updates the suggestion weight of a query after a completed search
 func (r *searchRepository) UpdateSearchSuggestions(ctx context.Context, query string, resultCount int) error {
-	// This could store suggestion weights/scores in a separate table
-	// For now, we'll just record it in search history
-	
-	history := &models.SearchHistory{
-		ID:          "suggestion_" + fmt.Sprintf("%d", time.Now().UnixNano()),
-		Query:       query,
-		ResultCount: resultCount,
-		Duration:    0, // Not tracking duration for suggestions
-		Providers:   []string{},
-		RequestedAt: time.Now(),
+	normalized := normalizeSuggestionText(query)
+	if normalized == "" {
+		return nil
 	}
 	
-	return r.CreateSearchHistory(ctx, history)
+	hash := sha256.Sum256([]byte(normalized))
+	id := fmt.Sprintf("%x", hash)
+	
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Find instead of First, a new query is not an error worth logging
+		var suggestion models.QuerySuggestion
+		if err := tx.Where("id = ?", id).Limit(1).Find(&suggestion).Error; err != nil {
+			return errors.NewDatabaseError("get_query_suggestion", err)
+		}
+		
+		suggestion.ID = id
+		suggestion.Query = strings.Join(strings.Fields(query), " ")
+		suggestion.RecordSearch(resultCount, time.Now())
+		
+		if err := tx.Save(&suggestion).Error; err != nil {
+			return errors.NewDatabaseError("update_query_suggestion", err)
+		}
+		return nil
+	})
 }
 
 // GetSearchAnalytics returns search analytics for a time period
@@ -384,7 +491,7 @@ func (r *searchRepository) GetSearchAnalytics(ctx context.Context, from, to time
 	var avgDuration float64
 	err = r.db.WithContext(ctx).
 		Model(&models.SearchHistory{}).
-		Select("AVG(duration)").
+		Select("COALESCE(AVG(duration), 0)").
 		Where("requested_at BETWEEN ? AND ? AND duration > 0", from, to).
 		Scan(&avgDuration).Error
 	
@@ -393,42 +500,287 @@ func (r *searchRepository) GetSearchAnalytics(ctx context.Context, from, to time
 	}
 	analytics.AvgResponseTime = avgDuration
 	
+	// Unique users
+	err = r.db.WithContext(ctx).
+		Model(&models.SearchHistory{}).
+		Select("COUNT(DISTINCT user_id)").
+		Where("requested_at BETWEEN ? AND ? AND user_id IS NOT NULL", from, to).
+		Scan(&analytics.UniqueUsers).Error
+	
+	if err != nil {
+		return nil, errors.NewDatabaseError("get_analytics_unique_users", err)
+	}
+	
 	// Top queries
-	topQueries, err := r.GetPopularQueries(ctx, from, 10)
+	topQueries, err := r.GetPopularQueries(ctx, from, to, 10)
 	if err != nil {
 		return nil, err
 	}
 	analytics.TopQueries = topQueries
 	
-	// Provider usage stats would need more complex queries
-	// For now, returning empty slice
-	analytics.ProviderUsage = []ProviderUsageStats{}
+	// Provider usage, hourly distribution, cache hits and errors are
+	// aggregated in Go since providers are stored as serialized JSON
+	usage := make(map[string]int64)
+	failed := make(map[string]int64)
+	hourly := make([]int64, 24)
+	var total, cacheHits, failures int64
+	err = r.eachHistory(ctx, from, to, func(h *models.SearchHistory) {
+		total++
+		for _, provider := range h.Providers {
+			usage[provider]++
+		}
+		for _, provider := range h.ProvidersFailed {
+			failed[provider]++
+		}
+		hourly[h.RequestedAt.UTC().Hour()]++
+		if h.CacheHit {
+			cacheHits++
+		}
+		if !h.Succeeded() {
+			failures++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	analytics.ProviderUsage = providerUsageStats(usage)
+	analytics.ProviderFailures = providerUsageStats(failed)
+	
+	analytics.SearchesByHour = make([]HourlySearchStats, 24)
+	for hour, count := range hourly {
+		analytics.SearchesByHour[hour] = HourlySearchStats{Hour: hour, Count: count}
+	}
 	
-	// Cache hit rate and error rate would need additional tracking
-	analytics.CacheHitRate = 0.0
-	analytics.ErrorRate = 0.0
+	if total > 0 {
+		analytics.CacheHitRate = float64(cacheHits) / float64(total)
+		analytics.ErrorRate = float64(failures) / float64(total)
+	}
 	
 	return &analytics, nil
 }
 
 // GetProviderPerformance returns performance metrics for a specific provider
 func (r *searchRepository) GetProviderPerformance(ctx context.Context, provider string, from, to time.Time) (*ProviderPerformance, error) {
-	var perf ProviderPerformance
-	perf.Provider = provider
-	
-	// This would require more detailed tracking of provider-specific metrics
-	// For now, returning basic structure with zero values
-	perf.TotalRequests = 0
-	perf.SuccessRate = 0.0
-	perf.AvgResponseTime = 0.0
-	perf.TotalResults = 0
-	perf.AvgResultsPerRequest = 0.0
+	var tally providerTally
+	err := r.eachHistory(ctx, from, to, func(h *models.SearchHistory) {
+		succeeded := containsProvider(h.Providers, provider)
+		if succeeded || containsProvider(h.ProvidersFailed, provider) {
+			tally.add(h, succeeded)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
 	
+	perf := tally.performance(provider)
 	return &perf, nil
 }
 
+// GetProvidersPerformance returns performance metrics for every provider
+// searched in a time period, including those that only failed, by provider
+func (r *searchRepository) GetProvidersPerformance(ctx context.Context, from, to time.Time) ([]ProviderPerformance, error) {
+	tallies := make(map[string]*providerTally)
+	tallyOf := func(provider string) *providerTally {
+		if tallies[provider] == nil {
+			tallies[provider] = &providerTally{}
+		}
+		return tallies[provider]
+	}
+	err := r.eachHistory(ctx, from, to, func(h *models.SearchHistory) {
+		for _, provider := range h.Providers {
+			tallyOf(provider).add(h, true)
+		}
+		for _, provider := range h.ProvidersFailed {
+			if !containsProvider(h.Providers, provider) {
+				tallyOf(provider).add(h, false)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	performance := make([]ProviderPerformance, 0, len(tallies))
+	for provider, tally := range tallies {
+		performance = append(performance, tally.performance(provider))
+	}
+	sort.Slice(performance, func(i, j int) bool {
+		return performance[i].Provider < performance[j].Provider
+	})
+	return performance, nil
+}
+
+// providerTally accumulates the searches a provider took part in
+type providerTally struct {
+	requests, successes, results, duration int64
+}
+
+func (t *providerTally) add(h *models.SearchHistory, succeeded bool) {
+	t.requests++
+	t.duration += h.Duration
+	if succeeded {
+		t.successes++
+		t.results += int64(h.ResultCount)
+	}
+}
+
+// performance returns the metrics of the provider. Searches only record the
+// overall duration, so latency is the average duration of the searches the
+// provider took part in.
+func (t *providerTally) performance(provider string) ProviderPerformance {
+	perf := ProviderPerformance{Provider: provider, TotalRequests: t.requests, TotalResults: t.results}
+	if t.requests > 0 {
+		perf.SuccessRate = float64(t.successes) / float64(t.requests)
+		perf.AvgResponseTime = float64(t.duration) / float64(t.requests)
+		perf.AvgResultsPerRequest = float64(t.results) / float64(t.requests)
+	}
+	return perf
+}
+
 // Helper methods
 
+// historyBatchSize is the number of searches loaded at a time for analytics
+const historyBatchSize = 1000
+
+// eachHistory calls fn with the fields used for analytics of each search in a
+// time period, loading them in batches
+func (r *searchRepository) eachHistory(ctx context.Context, from, to time.Time, fn func(*models.SearchHistory)) error {
+	var batch []models.SearchHistory
+	err := r.db.WithContext(ctx).
+		Select("id, providers, providers_failed, result_count, duration, cache_hit, error, requested_at").
+		Where("requested_at BETWEEN ? AND ?", from, to).
+		FindInBatches(&batch, historyBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				fn(&batch[i])
+			}
+			return nil
+		}).Error
+	
+	if err != nil {
+		return errors.NewDatabaseError("get_search_history_window", err)
+	}
+	return nil
+}
+
+// providerUsageStats returns the counts per provider, most used first
+func providerUsageStats(counts map[string]int64) []ProviderUsageStats {
+	stats := make([]ProviderUsageStats, 0, len(counts))
+	for provider, count := range counts {
+		stats = append(stats, ProviderUsageStats{Provider: provider, Usage: count})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Usage != stats[j].Usage {
+			return stats[i].Usage > stats[j].Usage
+		}
+		return stats[i].Provider < stats[j].Provider
+	})
+	return stats
+}
+
+// normalizeSuggestionText lower cases text and collapses whitespace
+func normalizeSuggestionText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
+}
+
+// mixSuggestions normalizes popularity within each suggestion type, weights
+// the types against each other and returns the best suggestions overall
+func mixSuggestions(candidates map[string][]suggestionCandidate, limit int) []models.SearchSuggestion {
+	best := make(map[string]models.SearchSuggestion)
+	for suggestionType, list := range candidates {
+		maxPopularity := 0.0
+		for _, candidate := range list {
+			maxPopularity = math.Max(maxPopularity, candidate.Popularity)
+		}
+		
+		for _, candidate := range list {
+			text := strings.TrimSpace(candidate.Text)
+			if text == "" {
+				continue
+			}
+			score := suggestionTypeWeights[suggestionType] * (1 + candidate.Popularity) / (1 + maxPopularity)
+			key := strings.ToLower(text)
+			if existing, ok := best[key]; ok && existing.Score >= score {
+				continue
+			}
+			best[key] = models.SearchSuggestion{Text: text, Score: score, Type: suggestionType}
+		}
+	}
+	
+	suggestions := make([]models.SearchSuggestion, 0, len(best))
+	for _, suggestion := range best {
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// dbTime scans aggregated timestamps such as MAX(requested_at), which SQLite
+// returns as text rather than as a time value
+type dbTime time.Time
+
+// dbTimeLayouts are the text formats SQLite drivers use for timestamps
+var dbTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Scan implements sql.Scanner
+func (t *dbTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = dbTime{}
+		return nil
+	case time.Time:
+		*t = dbTime(v)
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	default:
+		return fmt.Errorf("cannot scan %T into time", value)
+	}
+}
+
+func (t *dbTime) parse(value string) error {
+	for _, layout := range dbTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			*t = dbTime(parsed)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as time", value)
+}
+
+// containsProvider checks whether a provider name is in a list
+func containsProvider(providers []string, provider string) bool {
+	for _, p := range providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
 // generateQueryHash generates a hash for a query and provider combination
 func (r *searchRepository) generateQueryHash(query, provider string) string {
 	data := fmt.Sprintf("%s:%s", query, provider)
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"scifind-backend/internal/embeddings"
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+)
+
+// VectorMatch is a paper found by a vector search
+type VectorMatch struct {
+	PaperID string  `json:"paper_id"`
+	Score   float64 `json:"score"` // cosine similarity to the query embedding
+}
+
+// VectorIndex finds the papers whose embeddings are nearest to an embedding.
+// The embeddings stored on the papers are the source of truth; the index
+// mirrors them and is kept current through Upsert and Delete.
+type VectorIndex interface {
+	// Upsert adds or replaces the embedding of a paper
+	Upsert(ctx context.Context, paperID string, embedding []float32) error
+
+	// Delete removes the embedding of a paper
+	Delete(ctx context.Context, paperID string) error
+
+	// Search returns up to limit papers by descending similarity
+	Search(ctx context.Context, embedding []float32, limit int) ([]VectorMatch, error)
+}
+
+// NewVectorIndex returns the vector index of the database: a pgvector HNSW
+// index on PostgreSQL with the vector extension, and an exact in-process
+// index over the stored embeddings otherwise. Embeddings of other lengths
+// than dimensions are ignored.
+func NewVectorIndex(db *gorm.DB, dimensions int, logger *slog.Logger) VectorIndex {
+	if db.Dialector.Name() == "postgres" {
+		index := &pgvectorIndex{db: db, dimensions: dimensions}
+		err := index.create()
+		if err == nil {
+			return index
+		}
+		logger.Warn("pgvector unavailable, using in-process vector index", slog.String("error", err.Error()))
+	}
+	return &flatVectorIndex{db: db, dimensions: dimensions, logger: logger}
+}
+
+// pgvectorIndex keeps the embeddings in a pgvector column with an HNSW
+// index, see https://github.com/pgvector/pgvector
+type pgvectorIndex struct {
+	db         *gorm.DB
+	dimensions int
+}
+
+// create creates the embedding table and its index, and copies the
+// embeddings already stored on the papers
+func (i *pgvectorIndex) create() error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS paper_embeddings (paper_id varchar(255) PRIMARY KEY REFERENCES papers(id) ON DELETE CASCADE, embedding vector(%d) NOT NULL)", i.dimensions),
+		"CREATE INDEX IF NOT EXISTS idx_paper_embeddings_hnsw ON paper_embeddings USING hnsw (embedding vector_cosine_ops)",
+		// The JSON arrays of the papers are valid vector literals
+		fmt.Sprintf(`INSERT INTO paper_embeddings (paper_id, embedding)
+			SELECT id, embedding::vector FROM papers
+			WHERE embedding IS NOT NULL AND deleted_at IS NULL AND json_array_length(embedding::json) = %d
+			ON CONFLICT (paper_id) DO NOTHING`, i.dimensions),
+	}
+
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to create vector index: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (i *pgvectorIndex) Upsert(ctx context.Context, paperID string, embedding []float32) error {
+	if len(embedding) != i.dimensions {
+		return nil
+	}
+	err := i.db.WithContext(ctx).Exec(
+		"INSERT INTO paper_embeddings (paper_id, embedding) VALUES (?, ?::vector) ON CONFLICT (paper_id) DO UPDATE SET embedding = EXCLUDED.embedding",
+		paperID, vectorLiteral(embedding)).Error
+	if err != nil {
+		return errors.NewDatabaseError("upsert_paper_embedding", err)
+	}
+	return nil
+}
+
+func (i *pgvectorIndex) Delete(ctx context.Context, paperID string) error {
+	if err := i.db.WithContext(ctx).Exec("DELETE FROM paper_embeddings WHERE paper_id = ?", paperID).Error; err != nil {
+		return errors.NewDatabaseError("delete_paper_embedding", err)
+	}
+	return nil
+}
+
+func (i *pgvectorIndex) Search(ctx context.Context, embedding []float32, limit int) ([]VectorMatch, error) {
+	if len(embedding) != i.dimensions || limit <= 0 {
+		return nil, nil
+	}
+
+	// <=> is the cosine distance, served by the HNSW index
+	var matches []VectorMatch
+	literal := vectorLiteral(embedding)
+	err := i.db.WithContext(ctx).Raw(
+		"SELECT paper_id, 1 - (embedding <=> ?::vector) AS score FROM paper_embeddings ORDER BY embedding <=> ?::vector LIMIT ?",
+		literal, literal, limit).Scan(&matches).Error
+	if err != nil {
+		return nil, errors.NewDatabaseError("search_paper_embeddings", err)
+	}
+	return matches, nil
+}
+
+// vectorLiteral formats an embedding as a pgvector literal
+func vectorLiteral(embedding []float32) string {
+	values := make([]string, len(embedding))
+	for i, v := range embedding {
+		values[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// flatVectorIndex compares the query with every embedding in memory. Exact
+// search stays fast up to some hundred thousand papers. The embeddings are
+// loaded from the papers on first use, so embeddings stored by other
+// instances show up after a restart.
+type flatVectorIndex struct {
+	db         *gorm.DB
+	dimensions int
+	logger     *slog.Logger
+
+	mu      sync.RWMutex
+	loaded  bool
+	vectors map[string][]float32
+}
+
+// load reads the stored embeddings once
+func (i *flatVectorIndex) load(ctx context.Context) error {
+	i.mu.RLock()
+	loaded := i.loaded
+	i.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.loaded {
+		return nil
+	}
+
+	rows, err := i.db.WithContext(ctx).Model(&models.Paper{}).
+		Select("id", "embedding").
+		Where("embedding IS NOT NULL").
+		Rows()
+	if err != nil {
+		return errors.NewDatabaseError("load_paper_embeddings", err)
+	}
+	defer rows.Close()
+
+	vectors := make(map[string][]float32)
+	skipped := 0
+	for rows.Next() {
+		var id, raw string
+		if err := rows.Scan(&id, &raw); err != nil {
+			return errors.NewDatabaseError("load_paper_embeddings", err)
+		}
+		var embedding []float32
+		if err := json.Unmarshal([]byte(raw), &embedding); err != nil || len(embedding) != i.dimensions {
+			skipped++
+			continue
+		}
+		vectors[id] = embedding
+	}
+	if err := rows.Err(); err != nil {
+		return errors.NewDatabaseError("load_paper_embeddings", err)
+	}
+
+	if skipped > 0 {
+		i.logger.Warn("Skipped paper embeddings of another length",
+			slog.Int("skipped", skipped),
+			slog.Int("dimensions", i.dimensions))
+	}
+	i.vectors = vectors
+	i.loaded = true
+	return nil
+}
+
+func (i *flatVectorIndex) Upsert(ctx context.Context, paperID string, embedding []float32) error {
+	if err := i.load(ctx); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(embedding) != i.dimensions {
+		delete(i.vectors, paperID)
+		return nil
+	}
+	i.vectors[paperID] = append([]float32(nil), embedding...)
+	return nil
+}
+
+func (i *flatVectorIndex) Delete(ctx context.Context, paperID string) error {
+	if err := i.load(ctx); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.vectors, paperID)
+	return nil
+}
+
+func (i *flatVectorIndex) Search(ctx context.Context, embedding []float32, limit int) ([]VectorMatch, error) {
+	if err := i.load(ctx); err != nil {
+		return nil, err
+	}
+	if len(embedding) != i.dimensions || limit <= 0 {
+		return nil, nil
+	}
+
+	i.mu.RLock()
+	matches := make([]VectorMatch, 0, len(i.vectors))
+	for id, vector := range i.vectors {
+		matches = append(matches, VectorMatch{PaperID: id, Score: embeddings.Cosine(embedding, vector)})
+	}
+	i.mu.RUnlock()
+
+	sortMatches(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// sortMatches orders matches by descending score, ties by paper ID
+func sortMatches(matches []VectorMatch) {
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].PaperID < matches[b].PaperID
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -9,6 +10,22 @@ import (
 	"scifind-backend/internal/repository"
 )
 
+const (
+	// maxUserActivityHistory bounds the search history scanned for user activity
+	maxUserActivityHistory = 1000
+
+	// unhealthyErrorRate is the error rate above which a provider is reported unhealthy
+	unhealthyErrorRate = 0.5
+)
+
+// analyticsEventSubjects maps analytics event types to their NATS subjects
+var analyticsEventSubjects = map[string]string{
+	"query":  messaging.SubjectAnalyticsQuery,
+	"click":  messaging.SubjectAnalyticsClick,
+	"filter": messaging.SubjectAnalyticsFilter,
+	"export": messaging.SubjectAnalyticsExport,
+}
+
 // AnalyticsService handles analytics-related business logic
 type AnalyticsService struct {
 	repo      repository.SearchRepository
@@ -16,38 +33,160 @@ type AnalyticsService struct {
 	logger    *slog.Logger
 }
 
-// GetPopularQueries implements AnalyticsServiceInterface.
-func (s *AnalyticsService) GetPopularQueries(ctx context.Context, limit int, from time.Time, to time.Time) ([]*PopularQuery, error) {
-	panic("unimplemented")
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(repo repository.SearchRepository, messaging *messaging.Client, logger *slog.Logger) AnalyticsServiceInterface {
+	return &AnalyticsService{
+		repo:      repo,
+		messaging: messaging,
+		logger:    logger,
+	}
 }
 
-// GetProviderPerformance implements AnalyticsServiceInterface.
-func (s *AnalyticsService) GetProviderPerformance(ctx context.Context, from time.Time, to time.Time) (map[string]*ProviderMetrics, error) {
-	panic("unimplemented")
+// GetSearchMetrics returns aggregate search metrics for a time period
+func (s *AnalyticsService) GetSearchMetrics(ctx context.Context, from, to time.Time) (*SearchMetrics, error) {
+	analytics, err := s.repo.GetSearchAnalytics(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search analytics: %w", err)
+	}
+
+	metrics := &SearchMetrics{
+		TotalSearches:     int(analytics.TotalSearches),
+		UniqueUsers:       int(analytics.UniqueUsers),
+		AverageResultTime: time.Duration(analytics.AvgResponseTime * float64(time.Millisecond)),
+		PopularProviders:  make(map[string]int, len(analytics.ProviderUsage)),
+		SearchesByHour:    make([]HourlyMetric, 0, len(analytics.SearchesByHour)),
+	}
+	if analytics.TotalSearches > 0 {
+		metrics.SuccessRate = 1 - analytics.ErrorRate
+	}
+	for _, usage := range analytics.ProviderUsage {
+		metrics.PopularProviders[usage.Provider] = int(usage.Usage)
+	}
+	for _, hourly := range analytics.SearchesByHour {
+		metrics.SearchesByHour = append(metrics.SearchesByHour, HourlyMetric{Hour: hourly.Hour, Count: int(hourly.Count)})
+	}
+
+	return metrics, nil
 }
 
-// GetSearchMetrics implements AnalyticsServiceInterface.
-func (s *AnalyticsService) GetSearchMetrics(ctx context.Context, from time.Time, to time.Time) (*SearchMetrics, error) {
-	panic("unimplemented")
+// GetPopularQueries returns the most frequent queries of a time period
+func (s *AnalyticsService) GetPopularQueries(ctx context.Context, limit int, from, to time.Time) ([]*PopularQuery, error) {
+	stats, err := s.repo.GetPopularQueries(ctx, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+
+	queries := make([]*PopularQuery, len(stats))
+	for i, stat := range stats {
+		query := &PopularQuery{
+			Query:      stat.Query,
+			Count:      stat.Count,
+			AvgResults: stat.AvgResults,
+		}
+		if stat.Count > 0 {
+			query.SuccessRate = float64(stat.SuccessCount) / float64(stat.Count)
+		}
+		queries[i] = query
+	}
+
+	return queries, nil
 }
 
-// GetUserActivity implements AnalyticsServiceInterface.
-func (s *AnalyticsService) GetUserActivity(ctx context.Context, userID string, from time.Time, to time.Time) (*UserActivity, error) {
-	panic("unimplemented")
+// GetProviderPerformance returns per provider metrics for all providers
+// searched in a time period, including those that only ever failed
+func (s *AnalyticsService) GetProviderPerformance(ctx context.Context, from, to time.Time) (map[string]*ProviderMetrics, error) {
+	performance, err := s.repo.GetProvidersPerformance(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider performance: %w", err)
+	}
+
+	result := make(map[string]*ProviderMetrics, len(performance))
+	for _, perf := range performance {
+		metrics := &ProviderMetrics{
+			Name:           perf.Provider,
+			TotalRequests:  int(perf.TotalRequests),
+			SuccessRate:    perf.SuccessRate,
+			AverageLatency: time.Duration(perf.AvgResponseTime * float64(time.Millisecond)),
+			IsHealthy:      true,
+		}
+		if perf.TotalRequests > 0 {
+			metrics.ErrorRate = 1 - perf.SuccessRate
+			metrics.IsHealthy = metrics.ErrorRate < unhealthyErrorRate
+		}
+		result[perf.Provider] = metrics
+	}
+
+	return result, nil
 }
 
-// RecordEvent implements AnalyticsServiceInterface.
-func (s *AnalyticsService) RecordEvent(ctx context.Context, event *AnalyticsEvent) error {
-	panic("unimplemented")
+// GetUserActivity returns the search activity of a user. Totals and favorite
+// topics cover the user's whole history, daily activity the requested period.
+func (s *AnalyticsService) GetUserActivity(ctx context.Context, userID string, from, to time.Time) (*UserActivity, error) {
+	stats, err := s.repo.GetUserSearchStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user search stats: %w", err)
+	}
+
+	history, err := s.repo.GetSearchHistory(ctx, &userID, maxUserActivityHistory, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user search history: %w", err)
+	}
+
+	activity := &UserActivity{
+		UserID:         userID,
+		SearchCount:    int(stats.TotalQueries),
+		UniqueQueries:  int(stats.UniqueQueries),
+		FavoriteTopics: make([]string, 0, len(stats.TopQueries)),
+		ActivityByDay:  []DailyActivity{},
+		LastActive:     stats.LastSearch,
+	}
+	for _, query := range stats.TopQueries {
+		activity.FavoriteTopics = append(activity.FavoriteTopics, query.Query)
+	}
+
+	// History is ordered newest first, so days come out in descending order
+	dayIndex := make(map[time.Time]int)
+	for _, entry := range history {
+		if entry.RequestedAt.Before(from) || entry.RequestedAt.After(to) {
+			continue
+		}
+		day := entry.RequestedAt.UTC().Truncate(24 * time.Hour)
+		if i, ok := dayIndex[day]; ok {
+			activity.ActivityByDay[i].SearchCount++
+			continue
+		}
+		dayIndex[day] = len(activity.ActivityByDay)
+		activity.ActivityByDay = append(activity.ActivityByDay, DailyActivity{Date: day, SearchCount: 1})
+	}
+
+	return activity, nil
 }
 
-// NewAnalyticsService creates a new analytics service
-func NewAnalyticsService(repo repository.SearchRepository, messaging *messaging.Client, logger *slog.Logger) AnalyticsServiceInterface {
-	return &AnalyticsService{
-		repo:      repo,
-		messaging: messaging,
-		logger:    logger,
+// RecordEvent publishes a client side analytics event
+func (s *AnalyticsService) RecordEvent(ctx context.Context, event *AnalyticsEvent) error {
+	if event == nil {
+		return NewValidationError("event is required")
+	}
+
+	subject, ok := analyticsEventSubjects[event.Type]
+	if !ok {
+		return NewValidationError("invalid event type: " + event.Type)
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
 	}
+
+	if s.messaging == nil {
+		s.logger.Debug("Messaging client not available, skipping analytics event",
+			slog.String("type", event.Type))
+		return nil
+	}
+
+	if err := s.messaging.Publish(ctx, subject, event); err != nil {
+		return fmt.Errorf("failed to publish analytics event: %w", err)
+	}
+
+	return nil
 }
 
 // Health checks the health of the analytics service
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+)
+
+// CitationDirection tells which citation edges a traversal follows
+type CitationDirection string
+
+const (
+	CitationDirectionReferences CitationDirection = "references" // from a paper to the papers it cites
+	CitationDirectionCitations  CitationDirection = "citations"  // from a paper to the papers citing it
+	CitationDirectionBoth       CitationDirection = "both"
+)
+
+const (
+	// DefaultCitationPageLimit and MaxCitationPageLimit bound the pages of
+	// citing and cited papers
+	DefaultCitationPageLimit = 20
+	MaxCitationPageLimit     = 100
+
+	// DefaultNeighborhoodDepth and MaxNeighborhoodDepth bound the hops of
+	// citation neighborhoods
+	DefaultNeighborhoodDepth = 1
+	MaxNeighborhoodDepth     = 3
+
+	// DefaultNeighborhoodNodes and MaxNeighborhoodNodes bound the papers of
+	// citation neighborhoods
+	DefaultNeighborhoodNodes = 200
+	MaxNeighborhoodNodes     = 1000
+
+	// DefaultCitationPathDepth and MaxCitationPathDepth bound the length of
+	// citation paths searched for
+	DefaultCitationPathDepth = 6
+	MaxCitationPathDepth     = 10
+
+	// citationPathMaxVisited is the most papers visited looking for a path
+	citationPathMaxVisited = 10000
+
+	// citationEdgesPerHop is the most edges followed to expand one hop
+	citationEdgesPerHop = 10000
+)
+
+// CitationLink is one end of a citation: a stored paper, or the external ID
+// a provider named an unknown paper by
+type CitationLink struct {
+	PaperID    string        `json:"paper_id"` // stored paper ID, or the normalized external ID when unresolved
+	Resolved   bool          `json:"resolved"`
+	ExternalID string        `json:"external_id,omitempty"` // as named by the provider
+	Source     string        `json:"source,omitempty"`      // provider the citation came from
+	Paper      *models.Paper `json:"paper,omitempty"`
+}
+
+// CitationPage is a page of the papers citing a paper or cited by it
+type CitationPage struct {
+	PaperID   string            `json:"paper_id"`
+	Direction CitationDirection `json:"direction"`
+	Links     []CitationLink    `json:"links"`
+	Total     int64             `json:"total"`
+	Limit     int               `json:"limit"`
+	Offset    int               `json:"offset"`
+	HasMore   bool              `json:"has_more"`
+}
+
+// CitationNode is a paper of a citation neighborhood, hops away from its center
+type CitationNode struct {
+	CitationLink
+	Distance int `json:"distance"`
+}
+
+// CitationGraph is the citation neighborhood of a paper
+type CitationGraph struct {
+	PaperID   string                 `json:"paper_id"`
+	Depth     int                    `json:"depth"`
+	Direction CitationDirection      `json:"direction"`
+	Nodes     []CitationNode         `json:"nodes"`
+	Edges     []models.PaperCitation `json:"edges"`
+	Truncated bool                   `json:"truncated"` // stopped at the most nodes or edges per hop
+}
+
+// CitationPath is a shortest chain of citations between two papers. Each
+// edge links consecutive papers, in whichever direction the citation goes.
+type CitationPath struct {
+	From      string                 `json:"from"`
+	To        string                 `json:"to"`
+	Direction CitationDirection      `json:"direction"`
+	Length    int                    `json:"length"`
+	Papers    []models.Paper         `json:"papers"`
+	Edges     []models.PaperCitation `json:"edges"`
+}
+
+// GetCitingPapers returns a page of the papers citing the paper, stored
+// papers first. Papers known only by their external ID are included.
+func (s *PaperService) GetCitingPapers(ctx context.Context, id string, limit, offset int) (*CitationPage, error) {
+	return s.citationPage(ctx, id, CitationDirectionCitations, limit, offset)
+}
+
+// GetReferencedPapers returns a page of the papers the paper cites, stored
+// papers first. Papers known only by their external ID are included.
+func (s *PaperService) GetReferencedPapers(ctx context.Context, id string, limit, offset int) (*CitationPage, error) {
+	return s.citationPage(ctx, id, CitationDirectionReferences, limit, offset)
+}
+
+func (s *PaperService) citationPage(ctx context.Context, id string, direction CitationDirection, limit, offset int) (*CitationPage, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get paper: %w", err)
+	}
+
+	var edges []models.PaperCitation
+	var total int64
+	var err error
+	if direction == CitationDirectionCitations {
+		edges, total, err = s.citations.GetCiting(ctx, id, limit, offset)
+	} else {
+		edges, total, err = s.citations.GetCited(ctx, id, limit, offset)
+	}
+	if err != nil {
+		s.logger.Error("Failed to get citations", slog.String("id", id), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get %s: %w", direction, err)
+	}
+
+	links := make([]CitationLink, len(edges))
+	for i, edge := range edges {
+		links[i] = citationLink(edge, direction == CitationDirectionCitations)
+	}
+	if err := s.loadCitationPapers(ctx, links); err != nil {
+		return nil, err
+	}
+
+	return &CitationPage{
+		PaperID:   id,
+		Direction: direction,
+		Links:     links,
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+		HasMore:   int64(offset+len(links)) < total,
+	}, nil
+}
+
+// GetCitationNeighborhood returns the papers up to depth citations away from
+// the paper, following the citations in the direction, with the citations
+// followed. Papers known only by their external ID end the traversal.
+func (s *PaperService) GetCitationNeighborhood(ctx context.Context, id string, depth int, direction CitationDirection, maxNodes int) (*CitationGraph, error) {
+	if depth <= 0 {
+		depth = DefaultNeighborhoodDepth
+	}
+	if maxNodes <= 0 {
+		maxNodes = DefaultNeighborhoodNodes
+	}
+	if direction == "" {
+		direction = CitationDirectionBoth
+	}
+	if err := validateCitationTraversal(direction, depth, MaxNeighborhoodDepth); err != nil {
+		return nil, err
+	}
+	if maxNodes > MaxNeighborhoodNodes {
+		return nil, errors.NewValidationError(fmt.Sprintf("max_nodes must be at most %d", MaxNeighborhoodNodes), "max_nodes", maxNodes)
+	}
+
+	paper, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper: %w", err)
+	}
+
+	graph := &CitationGraph{PaperID: id, Depth: depth, Direction: direction, Edges: []models.PaperCitation{}}
+	nodes := map[string]int{id: 0}
+	seen := make(map[[2]string]bool)
+	graph.Nodes = append(graph.Nodes, CitationNode{CitationLink: CitationLink{PaperID: id, Resolved: true, Paper: paper}})
+
+	frontier := []string{id}
+	for distance := 1; distance <= depth && len(frontier) > 0 && !graph.Truncated; distance++ {
+		edges, err := s.citations.GetEdges(ctx, frontier, citationEdgesPerHop+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get citation neighborhood: %w", err)
+		}
+		if len(edges) > citationEdgesPerHop {
+			edges = edges[:citationEdgesPerHop]
+			graph.Truncated = true
+		}
+
+		expanding := make(map[string]bool, len(frontier))
+		for _, paperID := range frontier {
+			expanding[paperID] = true
+		}
+		var next []string
+		for _, edge := range edges {
+			for _, neighbor := range citationNeighbors(edge, expanding, direction) {
+				if _, ok := nodes[neighbor.PaperID]; !ok {
+					if len(nodes) >= maxNodes {
+						graph.Truncated = true
+						continue
+					}
+					nodes[neighbor.PaperID] = distance
+					graph.Nodes = append(graph.Nodes, CitationNode{CitationLink: neighbor, Distance: distance})
+					if neighbor.Resolved {
+						next = append(next, neighbor.PaperID)
+					}
+				}
+				if key := [2]string{edge.CitingID, edge.CitedID}; !seen[key] {
+					seen[key] = true
+					graph.Edges = append(graph.Edges, edge)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	// Keep only the edges between the nodes of the neighborhood
+	edges := graph.Edges[:0]
+	for _, edge := range graph.Edges {
+		_, citing := nodes[edge.CitingID]
+		_, cited := nodes[edge.CitedID]
+		if citing && cited {
+			edges = append(edges, edge)
+		}
+	}
+	graph.Edges = edges
+
+	links := make([]CitationLink, len(graph.Nodes)-1)
+	for i := range links {
+		links[i] = graph.Nodes[i+1].CitationLink
+	}
+	if err := s.loadCitationPapers(ctx, links); err != nil {
+		return nil, err
+	}
+	for i := range links {
+		graph.Nodes[i+1].CitationLink = links[i]
+	}
+
+	return graph, nil
+}
+
+// GetCitationPath returns a shortest chain of citations from one stored paper
+// to another, following the citations in the direction, of at most maxDepth
+// citations. It returns a not found error when there is none.
+func (s *PaperService) GetCitationPath(ctx context.Context, fromID, toID string, direction CitationDirection, maxDepth int) (*CitationPath, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultCitationPathDepth
+	}
+	if direction == "" {
+		direction = CitationDirectionBoth
+	}
+	if err := validateCitationTraversal(direction, maxDepth, MaxCitationPathDepth); err != nil {
+		return nil, err
+	}
+
+	from, err := s.repo.GetByID(ctx, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper: %w", err)
+	}
+	if _, err := s.repo.GetByID(ctx, toID); err != nil {
+		return nil, fmt.Errorf("failed to get paper: %w", err)
+	}
+
+	path := &CitationPath{From: fromID, To: toID, Direction: direction, Papers: []models.Paper{*from}, Edges: []models.PaperCitation{}}
+	if fromID == toID {
+		return path, nil
+	}
+
+	// Breadth first over the stored papers, remembering how each was reached
+	parents := map[string]models.PaperCitation{}
+	visited := map[string]bool{fromID: true}
+	frontier := []string{fromID}
+	found := false
+	for hops := 1; hops <= maxDepth && len(frontier) > 0 && !found; hops++ {
+		edges, err := s.citations.GetEdges(ctx, frontier, citationEdgesPerHop)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find citation path: %w", err)
+		}
+
+		expanding := make(map[string]bool, len(frontier))
+		for _, paperID := range frontier {
+			expanding[paperID] = true
+		}
+		var next []string
+		for _, edge := range edges {
+			for _, neighbor := range citationNeighbors(edge, expanding, direction) {
+				if !neighbor.Resolved || visited[neighbor.PaperID] || len(visited) >= citationPathMaxVisited {
+					continue
+				}
+				visited[neighbor.PaperID] = true
+				parents[neighbor.PaperID] = edge
+				next = append(next, neighbor.PaperID)
+				found = found || neighbor.PaperID == toID
+			}
+		}
+		frontier = next
+	}
+	if !found {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("Citation path within %d citations", maxDepth), toID)
+	}
+
+	// Walk back from the target
+	var chain []models.PaperCitation
+	ids := []string{toID}
+	for current := toID; current != fromID; {
+		edge := parents[current]
+		chain = append(chain, edge)
+		if edge.CitingID == current {
+			current = edge.CitedID
+		} else {
+			current = edge.CitingID
+		}
+		ids = append(ids, current)
+	}
+
+	links := make([]CitationLink, 0, len(ids)-1)
+	for i := len(ids) - 2; i >= 0; i-- {
+		links = append(links, CitationLink{PaperID: ids[i], Resolved: true})
+	}
+	if err := s.loadCitationPapers(ctx, links); err != nil {
+		return nil, err
+	}
+	for i, link := range links {
+		if link.Paper == nil {
+			return nil, errors.NewNotFoundError("Paper on citation path", link.PaperID)
+		}
+		path.Papers = append(path.Papers, *link.Paper)
+		path.Edges = append(path.Edges, chain[len(chain)-1-i])
+	}
+	path.Length = len(path.Edges)
+
+	return path, nil
+}
+
+// loadCitationPapers fills in the stored papers of resolved links
+func (s *PaperService) loadCitationPapers(ctx context.Context, links []CitationLink) error {
+	var ids []string
+	for _, link := range links {
+		if link.Resolved {
+			ids = append(ids, link.PaperID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	papers, err := s.repo.GetByIdentifiers(ctx, ids, len(ids)*2)
+	if err != nil {
+		return fmt.Errorf("failed to load cited papers: %w", err)
+	}
+	byID := make(map[string]*models.Paper, len(papers))
+	for i := range papers {
+		byID[papers[i].ID] = &papers[i]
+	}
+	for i := range links {
+		if links[i].Resolved {
+			links[i].Paper = byID[links[i].PaperID]
+		}
+	}
+	return nil
+}
+
+// citationLink returns the citing end of the edge, or the cited end
+func citationLink(edge models.PaperCitation, citing bool) CitationLink {
+	if citing {
+		return CitationLink{PaperID: edge.CitingID, Resolved: edge.CitingResolved, ExternalID: edge.CitingExternalID, Source: edge.Source}
+	}
+	return CitationLink{PaperID: edge.CitedID, Resolved: edge.CitedResolved, ExternalID: edge.CitedExternalID, Source: edge.Source}
+}
+
+// citationNeighbors returns the ends of the edge reached from the papers
+// being expanded in the direction
+func citationNeighbors(edge models.PaperCitation, expanding map[string]bool, direction CitationDirection) []CitationLink {
+	var neighbors []CitationLink
+	if direction != CitationDirectionCitations && edge.CitingResolved && expanding[edge.CitingID] {
+		neighbors = append(neighbors, citationLink(edge, false))
+	}
+	if direction != CitationDirectionReferences && edge.CitedResolved && expanding[edge.CitedID] {
+		neighbors = append(neighbors, citationLink(edge, true))
+	}
+	return neighbors
+}
+
+// validateCitationTraversal checks the direction and depth of a traversal
+func validateCitationTraversal(direction CitationDirection, depth, maxDepth int) error {
+	switch direction {
+	case CitationDirectionReferences, CitationDirectionCitations, CitationDirectionBoth:
+	default:
+		return errors.NewValidationError("direction must be references, citations or both", "direction", direction)
+	}
+	if depth > maxDepth {
+		return errors.NewValidationError(fmt.Sprintf("depth must be at most %d", maxDepth), "depth", depth)
+	}
+	return nil
+}
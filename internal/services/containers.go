@@ -21,7 +21,7 @@ type Container struct {
 // NewContainer creates a new service container
 func NewContainer(repos *repository.Container, messaging *messaging.Client, providerManager providers.ProviderManager, logger *slog.Logger) *Container {
 	return &Container{
-		Paper:     NewPaperService(repos.Paper, messaging, logger),
+		Paper:     NewPaperService(repos.Paper, repos.Citation, messaging, logger),
 		Search:    NewSearchService(repos.Search, repos.Paper, messaging, providerManager, logger),
 		Analytics: NewAnalyticsService(repos.Search, messaging, logger),
 		Health:    NewHealthService(repos, messaging, logger),
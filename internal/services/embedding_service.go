@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"scifind-backend/internal/embeddings"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+)
+
+// EmbeddingService embeds the stored papers and searches them by meaning
+type EmbeddingService struct {
+	embedder  embeddings.Embedder
+	paperRepo repository.PaperRepository
+	index     repository.VectorIndex
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewEmbeddingService creates a new embedding service. Papers are embedded
+// batchSize at a time.
+func NewEmbeddingService(
+	embedder embeddings.Embedder,
+	paperRepo repository.PaperRepository,
+	index repository.VectorIndex,
+	batchSize int,
+	logger *slog.Logger,
+) *EmbeddingService {
+	if batchSize <= 0 {
+		batchSize = embeddings.DefaultBatchSize
+	}
+	return &EmbeddingService{
+		embedder:  embedder,
+		paperRepo: paperRepo,
+		index:     index,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// EmbedPending embeds the papers that have no embedding yet and returns how
+// many were embedded. It stops at the first failing batch; the papers
+// embedded before it are kept.
+func (s *EmbeddingService) EmbedPending(ctx context.Context) (int, error) {
+	embedded := 0
+	for {
+		papers, err := s.paperRepo.GetPapersWithoutEmbedding(ctx, s.batchSize)
+		if err != nil {
+			return embedded, err
+		}
+		if len(papers) == 0 {
+			return embedded, nil
+		}
+
+		texts := make([]string, len(papers))
+		for i := range papers {
+			texts[i] = embeddings.PaperText(&papers[i])
+		}
+		vectors, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return embedded, fmt.Errorf("failed to embed papers: %w", err)
+		}
+		if len(vectors) != len(papers) {
+			return embedded, fmt.Errorf("failed to embed papers: got %d embeddings for %d papers", len(vectors), len(papers))
+		}
+
+		for i := range papers {
+			if err := s.paperRepo.UpdateEmbedding(ctx, papers[i].ID, vectors[i]); err != nil {
+				return embedded, err
+			}
+			if err := s.index.Upsert(ctx, papers[i].ID, vectors[i]); err != nil {
+				return embedded, err
+			}
+			embedded++
+		}
+
+		if len(papers) < s.batchSize {
+			return embedded, nil
+		}
+	}
+}
+
+// StartBackgroundEmbedding embeds the pending papers now and then every
+// interval, picking up papers stored in the meantime
+func (s *EmbeddingService) StartBackgroundEmbedding(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	run := func() {
+		embedded, err := s.EmbedPending(ctx)
+		if err != nil {
+			s.logger.Warn("Background embedding failed",
+				slog.Int("embedded", embedded),
+				slog.String("error", err.Error()))
+			return
+		}
+		if embedded > 0 {
+			s.logger.Info("Embedded papers", slog.Int("embedded", embedded))
+		}
+	}
+
+	go func() {
+		run()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+}
+
+// Search returns the stored papers nearest in meaning to the query text,
+// best first, with their similarity as score. Searches without filters use
+// the vector index; filtered searches compare the matching papers exactly.
+func (s *EmbeddingService) Search(ctx context.Context, query string, filters *models.PaperFilter, limit, offset int) ([]models.Paper, error) {
+	vectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	var papers []models.Paper
+	if filters == nil {
+		papers, err = s.Nearest(ctx, vectors[0], offset+limit)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		papers, err = s.paperRepo.SearchByVector(ctx, vectors[0], filters, offset+limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if offset >= len(papers) {
+		return []models.Paper{}, nil
+	}
+	return papers[offset:], nil
+}
+
+// Nearest returns up to limit stored papers whose embeddings are nearest to
+// the embedding, best first, with their similarity as score
+func (s *EmbeddingService) Nearest(ctx context.Context, embedding []float32, limit int) ([]models.Paper, error) {
+	matches, err := s.index.Search(ctx, embedding, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.paperRepo.GetByVectorMatches(ctx, matches)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"scifind-backend/internal/errors"
+)
+
+// harvestCheckpointPrefix prefixes the checkpoint keys of all sets
+const harvestCheckpointPrefix = "harvest.arxiv."
+
+// HarvestCheckpoint records the progress of the harvest of one set, so an
+// interrupted harvest continues from its last resumption token and the next
+// scheduled harvest starts where the previous one ended
+type HarvestCheckpoint struct {
+	Set             string     `json:"set"`
+	From            *time.Time `json:"from,omitempty"`
+	Until           *time.Time `json:"until,omitempty"`
+	ResumptionToken string     `json:"resumption_token,omitempty"` // empty once the window is complete
+	Harvested       int        `json:"harvested"`
+	StartedAt       time.Time  `json:"started_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	HarvestedUntil  *time.Time `json:"harvested_until,omitempty"` // end of the last completed window
+}
+
+// HarvestCheckpointStore keeps harvest checkpoints in a JetStream KV bucket.
+// When the bucket is unavailable, checkpoints are kept in memory instead.
+type HarvestCheckpointStore struct {
+	kv     jetstream.KeyValue
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	fallback map[string][]byte
+}
+
+// NewHarvestCheckpointStore creates a checkpoint store. kv may be nil, in
+// which case checkpoints only survive until the process exits.
+func NewHarvestCheckpointStore(kv jetstream.KeyValue, logger *slog.Logger) *HarvestCheckpointStore {
+	return &HarvestCheckpointStore{
+		kv:       kv,
+		logger:   logger,
+		fallback: make(map[string][]byte),
+	}
+}
+
+// Load returns the checkpoint of a set, or nil if the set was never harvested
+func (s *HarvestCheckpointStore) Load(ctx context.Context, set string) (*HarvestCheckpoint, error) {
+	key := harvestCheckpointKey(set)
+
+	var data []byte
+	if s.kv != nil {
+		entry, err := s.kv.Get(ctx, key)
+		switch {
+		case err == nil:
+			data = entry.Value()
+		case stderrors.Is(err, jetstream.ErrKeyNotFound):
+		default:
+			s.logger.Warn("Checkpoint store unavailable, using in-memory checkpoints",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+		}
+	}
+	if data == nil {
+		s.mu.Lock()
+		data = s.fallback[key]
+		s.mu.Unlock()
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var checkpoint HarvestCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, errors.NewSerializationError("failed to decode harvest checkpoint", key)
+	}
+	return &checkpoint, nil
+}
+
+// Save stores the checkpoint of a set
+func (s *HarvestCheckpointStore) Save(ctx context.Context, checkpoint *HarvestCheckpoint) error {
+	key := harvestCheckpointKey(checkpoint.Set)
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.NewSerializationError("failed to encode harvest checkpoint", key)
+	}
+
+	s.mu.Lock()
+	s.fallback[key] = data
+	s.mu.Unlock()
+
+	if s.kv != nil {
+		if _, err := s.kv.Put(ctx, key, data); err != nil {
+			s.logger.Warn("Checkpoint store unavailable, using in-memory checkpoints",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+		}
+	}
+	return nil
+}
+
+// harvestCheckpointKey returns the KV key of a set. KV keys cannot contain
+// colons, so physics:hep-th is stored as physics.hep-th.
+func harvestCheckpointKey(set string) string {
+	if set == "" {
+		set = "all"
+	}
+	return harvestCheckpointPrefix + strings.ReplaceAll(set, ":", ".")
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/arxiv"
+	"scifind-backend/internal/repository"
+)
+
+// ArxivHarvester fetches pages of arXiv OAI-PMH records
+type ArxivHarvester interface {
+	ListRecords(ctx context.Context, req arxiv.HarvestRequest) (*arxiv.HarvestPage, error)
+}
+
+// IndexingEventPublisher publishes the indexing.* lifecycle events
+type IndexingEventPublisher interface {
+	PublishIndexingStarted(ctx context.Context, provider string, metadata map[string]interface{}) error
+	PublishIndexingProgress(ctx context.Context, provider string, papersIndexed int, metadata map[string]interface{}) error
+	PublishIndexingCompleted(ctx context.Context, provider string, papersIndexed int, duration time.Duration, metadata map[string]interface{}) error
+	PublishIndexingFailed(ctx context.Context, provider string, err error, metadata map[string]interface{}) error
+}
+
+// HarvestRequest selects an arXiv set and date window to harvest. Without
+// From, the harvest continues where the last completed harvest of the set
+// ended; without Until, it runs up to today.
+type HarvestRequest struct {
+	Set   string     `json:"set"`
+	From  *time.Time `json:"from,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// HarvestResult summarizes a harvest
+type HarvestResult struct {
+	Set       string        `json:"set"`
+	From      *time.Time    `json:"from,omitempty"`
+	Until     *time.Time    `json:"until,omitempty"`
+	Harvested int           `json:"harvested"`
+	Skipped   int           `json:"skipped"` // stored under another ID by a different provider
+	Deleted   int           `json:"deleted"`
+	Pages     int           `json:"pages"`
+	Resumed   bool          `json:"resumed"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// HarvestService mirrors arXiv sets into the local paper store through the
+// OAI-PMH interface
+type HarvestService struct {
+	harvester   ArxivHarvester
+	paperRepo   repository.PaperRepository
+	checkpoints *HarvestCheckpointStore
+	events      IndexingEventPublisher
+	logger      *slog.Logger
+}
+
+// NewHarvestService creates a new harvest service. events may be nil when
+// messaging is unavailable.
+func NewHarvestService(
+	harvester ArxivHarvester,
+	paperRepo repository.PaperRepository,
+	checkpoints *HarvestCheckpointStore,
+	events IndexingEventPublisher,
+	logger *slog.Logger,
+) *HarvestService {
+	return &HarvestService{
+		harvester:   harvester,
+		paperRepo:   paperRepo,
+		checkpoints: checkpoints,
+		events:      events,
+		logger:      logger,
+	}
+}
+
+// Harvest harvests a set page by page, upserting the papers and saving the
+// resumption token after every page. An interrupted harvest of the same
+// window resumes from its checkpoint.
+func (s *HarvestService) Harvest(ctx context.Context, req HarvestRequest) (*HarvestResult, error) {
+	start := time.Now()
+
+	previous, err := s.checkpoints.Load(ctx, req.Set)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &HarvestCheckpoint{Set: req.Set, From: req.From, Until: req.Until, StartedAt: start.UTC()}
+	resumed := previous != nil && previous.ResumptionToken != "" && sameHarvestWindow(req, previous)
+	switch {
+	case resumed:
+		checkpoint = previous
+	case previous != nil:
+		checkpoint.HarvestedUntil = previous.HarvestedUntil
+		if checkpoint.From == nil {
+			checkpoint.From = previous.HarvestedUntil
+		}
+	}
+
+	result := &HarvestResult{
+		Set:       checkpoint.Set,
+		From:      checkpoint.From,
+		Until:     checkpoint.Until,
+		Harvested: checkpoint.Harvested,
+		Resumed:   resumed,
+	}
+	metadata := harvestMetadata(checkpoint, resumed)
+	s.publish(ctx, "started", func() error {
+		return s.events.PublishIndexingStarted(ctx, providers.ProviderArxiv, metadata)
+	})
+
+	pageReq := arxiv.HarvestRequest{Set: checkpoint.Set, From: checkpoint.From, Until: checkpoint.Until, ResumptionToken: checkpoint.ResumptionToken}
+	for {
+		page, err := s.harvester.ListRecords(ctx, pageReq)
+		if err != nil && resumed && result.Pages == 0 && errors.IsValidationError(err) {
+			// Resumption tokens expire, start the window over
+			s.logger.Warn("Harvest checkpoint expired, restarting window",
+				slog.String("set", checkpoint.Set),
+				slog.String("error", err.Error()))
+			checkpoint.ResumptionToken = ""
+			checkpoint.Harvested = 0
+			result.Harvested = 0
+			pageReq.ResumptionToken = ""
+			resumed = false
+			result.Resumed = false
+			continue
+		}
+		if err != nil {
+			return nil, s.fail(ctx, checkpoint, result.Resumed, fmt.Errorf("failed to harvest %s: %w", harvestSetName(checkpoint.Set), err))
+		}
+
+		stored, err := s.storePapers(ctx, page.Papers)
+		if err != nil {
+			return nil, s.fail(ctx, checkpoint, result.Resumed, err)
+		}
+
+		result.Pages++
+		result.Harvested += stored
+		result.Skipped += len(page.Papers) - stored
+		result.Deleted += len(page.Deleted)
+
+		checkpoint.ResumptionToken = page.ResumptionToken
+		checkpoint.Harvested = result.Harvested
+		checkpoint.UpdatedAt = time.Now().UTC()
+		if page.ResumptionToken == "" {
+			checkpoint.HarvestedUntil = laterTime(checkpoint.HarvestedUntil, harvestEnd(checkpoint))
+		}
+		if err := s.checkpoints.Save(ctx, checkpoint); err != nil {
+			return nil, s.fail(ctx, checkpoint, result.Resumed, err)
+		}
+
+		progress := harvestMetadata(checkpoint, result.Resumed)
+		progress["pages"] = result.Pages
+		progress["cursor"] = page.Cursor
+		progress["complete_list_size"] = page.CompleteListSize
+		s.publish(ctx, "progress", func() error {
+			return s.events.PublishIndexingProgress(ctx, providers.ProviderArxiv, result.Harvested, progress)
+		})
+
+		if page.ResumptionToken == "" {
+			break
+		}
+		pageReq = arxiv.HarvestRequest{ResumptionToken: page.ResumptionToken}
+	}
+
+	result.Duration = time.Since(start)
+	s.publish(ctx, "completed", func() error {
+		return s.events.PublishIndexingCompleted(ctx, providers.ProviderArxiv, result.Harvested, result.Duration, metadata)
+	})
+
+	s.logger.Info("ArXiv harvest completed",
+		slog.String("set", harvestSetName(result.Set)),
+		slog.Int("harvested", result.Harvested),
+		slog.Int("skipped", result.Skipped),
+		slog.Int("pages", result.Pages),
+		slog.Bool("resumed", result.Resumed),
+		slog.Duration("duration", result.Duration))
+
+	return result, nil
+}
+
+// StartScheduledHarvest harvests the sets now and then every interval, each
+// run continuing where the previous one ended
+func (s *HarvestService) StartScheduledHarvest(ctx context.Context, sets []string, interval time.Duration) {
+	if len(sets) == 0 || interval <= 0 {
+		return
+	}
+
+	run := func() {
+		for _, set := range sets {
+			if _, err := s.Harvest(ctx, HarvestRequest{Set: set}); err != nil {
+				s.logger.Warn("Scheduled arXiv harvest failed",
+					slog.String("set", harvestSetName(set)),
+					slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	go func() {
+		run()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+}
+
+// storePapers upserts harvested papers and returns how many were stored.
+// Stored papers only get their metadata refreshed.
+// Papers whose arXiv ID or DOI is already stored under another ID, e.g. by
+// an import from a different provider, are skipped to keep those unique.
+func (s *HarvestService) storePapers(ctx context.Context, papers []models.Paper) (int, error) {
+	if len(papers) == 0 {
+		return 0, nil
+	}
+
+	var arxivIDs, dois []string
+	for _, paper := range papers {
+		if paper.ArxivID != nil {
+			arxivIDs = append(arxivIDs, *paper.ArxivID)
+		}
+		if paper.DOI != nil {
+			dois = append(dois, *paper.DOI)
+		}
+	}
+
+	owners := make(map[string]string)
+	for _, filter := range []*models.PaperFilter{{ArxivIDs: arxivIDs}, {DOIs: dois}} {
+		if len(filter.ArxivIDs) == 0 && len(filter.DOIs) == 0 {
+			continue
+		}
+		existing, _, err := s.paperRepo.Search(ctx, "", filter, nil, len(papers), 0)
+		if err != nil {
+			return 0, err
+		}
+		for _, paper := range existing {
+			if paper.ArxivID != nil {
+				owners["arxiv:"+*paper.ArxivID] = paper.ID
+			}
+			if paper.DOI != nil {
+				owners["doi:"+*paper.DOI] = paper.ID
+			}
+		}
+	}
+
+	batch := make([]models.Paper, 0, len(papers))
+	for _, paper := range papers {
+		if paper.ArxivID != nil && !ownedBy(owners, "arxiv:"+*paper.ArxivID, paper.ID) {
+			continue
+		}
+		if paper.DOI != nil && !ownedBy(owners, "doi:"+*paper.DOI, paper.ID) {
+			continue
+		}
+		batch = append(batch, paper)
+	}
+
+	if err := s.paperRepo.UpsertMetadataBatch(ctx, batch); err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
+
+// fail publishes an indexing failed event. The checkpoint of the last
+// stored page is kept so the next harvest resumes from there.
+func (s *HarvestService) fail(ctx context.Context, checkpoint *HarvestCheckpoint, resumed bool, err error) error {
+	failed := harvestMetadata(checkpoint, resumed)
+	failed["harvested"] = checkpoint.Harvested
+
+	s.publish(ctx, "failed", func() error {
+		return s.events.PublishIndexingFailed(ctx, providers.ProviderArxiv, err, failed)
+	})
+	return err
+}
+
+// publish sends an indexing event, logging instead of failing the harvest
+func (s *HarvestService) publish(ctx context.Context, event string, send func() error) {
+	if s.events == nil {
+		return
+	}
+	if err := send(); err != nil {
+		s.logger.Warn("Failed to publish indexing event",
+			slog.String("event", event),
+			slog.String("error", err.Error()))
+	}
+}
+
+// harvestMetadata describes a harvest window in event metadata
+func harvestMetadata(checkpoint *HarvestCheckpoint, resumed bool) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"source":  "oai-pmh",
+		"set":     checkpoint.Set,
+		"resumed": resumed,
+	}
+	if checkpoint.From != nil {
+		metadata["from"] = checkpoint.From.UTC().Format("2006-01-02")
+	}
+	if checkpoint.Until != nil {
+		metadata["until"] = checkpoint.Until.UTC().Format("2006-01-02")
+	}
+	return metadata
+}
+
+// sameHarvestWindow reports whether a request continues the window of a
+// checkpoint. Requests without dates continue any pending window.
+func sameHarvestWindow(req HarvestRequest, checkpoint *HarvestCheckpoint) bool {
+	if req.From == nil && req.Until == nil {
+		return true
+	}
+	return sameDay(req.From, checkpoint.From) && sameDay(req.Until, checkpoint.Until)
+}
+
+// harvestEnd returns the last day covered by a completed window
+func harvestEnd(checkpoint *HarvestCheckpoint) *time.Time {
+	if checkpoint.Until != nil {
+		return checkpoint.Until
+	}
+	started := checkpoint.StartedAt.UTC().Truncate(24 * time.Hour)
+	return &started
+}
+
+// ownedBy reports whether an identifier is unclaimed or belongs to the paper
+func ownedBy(owners map[string]string, key, id string) bool {
+	owner, ok := owners[key]
+	return !ok || owner == id
+}
+
+// harvestSetName names a set in logs, the empty set harvests everything
+func harvestSetName(set string) string {
+	if set == "" {
+		return "all"
+	}
+	return set
+}
+
+// sameDay reports whether two optional dates fall on the same UTC day
+func sameDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+// laterTime returns the later of two optional times
+func laterTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
@@ -90,6 +90,9 @@ func (s *HealthService) ExternalServicesHealth(ctx context.Context) map[string]e
 	results["semantic_scholar"] = s.checkExternalService(ctx, "semantic_scholar")
 	results["exa"] = s.checkExternalService(ctx, "exa")
 	results["tavily"] = s.checkExternalService(ctx, "tavily")
+	results["crossref"] = s.checkExternalService(ctx, "crossref")
+	results["openalex"] = s.checkExternalService(ctx, "openalex")
+	results["pubmed"] = s.checkExternalService(ctx, "pubmed")
 	
 	return results
 }
@@ -147,6 +150,15 @@ func (s *HealthService) checkExternalService(ctx context.Context, serviceName st
 	case "semantic_scholar":
 		// Could make a simple request to Semantic Scholar API
 		return nil
+	case "crossref":
+		// Could make a simple request to Crossref API
+		return nil
+	case "openalex":
+		// Could make a simple request to OpenAlex API
+		return nil
+	case "pubmed":
+		// Could make a simple request to the NCBI E-utilities
+		return nil
 	case "exa", "tavily":
 		// These require API keys, so might just check configuration
 		return nil
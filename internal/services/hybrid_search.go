@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+const (
+	// DefaultHybridAlpha weighs full-text and vector similarity equally
+	DefaultHybridAlpha = 0.5
+
+	// hybridMinDepth is the least number of papers taken from each ranking,
+	// so papers ranked low by one search can still be lifted by the other
+	hybridMinDepth = 50
+)
+
+// HybridScore explains the fused score of a paper found by a hybrid search
+type HybridScore struct {
+	LexicalRank  int      `json:"lexical_rank,omitempty"`  // rank in the full-text search, 0 when not matched
+	LexicalScore *float64 `json:"lexical_score,omitempty"` // full-text relevance, where the database ranks matches
+	VectorRank   int      `json:"vector_rank,omitempty"`   // rank in the vector search
+	VectorScore  *float64 `json:"vector_score,omitempty"`  // cosine similarity to the query
+	Lexical      float64  `json:"lexical"`                 // contribution of the full-text search
+	Vector       float64  `json:"vector"`                  // contribution of the vector search
+	Fused        float64  `json:"fused"`
+}
+
+// hybridSearch searches the stored papers by full text and by embedding
+// similarity and fuses both rankings, weighing the vector ranking by alpha.
+// Like semantic searches, hybrid searches bypass the result cache.
+func (s *SearchService) hybridSearch(ctx context.Context, req *SearchRequest, start time.Time) (*SearchResponse, error) {
+	if s.embeddings == nil {
+		return nil, fmt.Errorf("invalid search request: %w",
+			errors.NewValidationError("hybrid search needs embeddings, which are not enabled", "mode", req.Mode))
+	}
+
+	fusion := req.Fusion
+	if fusion == "" {
+		fusion = FusionReciprocalRank
+	}
+	alpha := DefaultHybridAlpha
+	if req.Alpha != nil {
+		alpha = *req.Alpha
+	}
+
+	if err := s.publishSearchRequestEvent(ctx, req); err != nil {
+		s.logger.Warn("Failed to publish search request event", slog.String("error", err.Error()))
+	}
+
+	fail := func(err error) (*SearchResponse, error) {
+		if storeErr := s.storeSearchResult(ctx, req, nil, time.Since(start), err); storeErr != nil {
+			s.logger.Warn("Failed to store search result", slog.String("error", storeErr.Error()))
+		}
+		s.publishSearchCompletedEvent(ctx, req, nil, time.Since(start), err)
+		return nil, fmt.Errorf("hybrid search failed: %w", err)
+	}
+
+	filters := localFilter(req)
+	depth := max(hybridMinDepth, 2*(req.Offset+req.Limit))
+	lexical, _, err := s.paperRepo.SearchFullText(ctx, req.Query, filters, depth, 0)
+	if err != nil {
+		return fail(err)
+	}
+	vector, err := s.embeddings.Search(ctx, req.Query, filters, depth, 0)
+	if err != nil {
+		return fail(err)
+	}
+
+	fused, scores := fuseHybrid(lexical, vector, fusion, alpha)
+	papers := []models.Paper{}
+	if req.Offset < len(fused) {
+		papers = fused[req.Offset:min(req.Offset+req.Limit, len(fused))]
+	}
+	pageScores := make(map[string]HybridScore, len(papers))
+	for _, paper := range papers {
+		pageScores[paper.ID] = scores[paper.ID]
+	}
+
+	response := &SearchResponse{
+		RequestID:           req.RequestID,
+		Query:               req.Query,
+		Papers:              papers,
+		TotalCount:          len(fused),
+		ResultCount:         len(papers),
+		ProvidersUsed:       []string{providers.ProviderLocal},
+		Duration:            time.Since(start),
+		AggregationStrategy: string(SearchModeHybrid),
+		StrategyMetadata:    map[string]interface{}{"fusion": fusion, "alpha": alpha},
+		HasMore:             req.Offset+len(papers) < len(fused),
+		Scores:              pageScores,
+		Facets:              models.NewSearchFacets(papers),
+		Timestamp:           time.Now(),
+	}
+	applyFacetFilters(response, req.FacetFilters)
+
+	if err := s.storeSearchResult(ctx, req, response, time.Since(start), nil); err != nil {
+		s.logger.Warn("Failed to store search result", slog.String("error", err.Error()))
+	}
+	if err := s.publishSearchCompletedEvent(ctx, req, response, time.Since(start), nil); err != nil {
+		s.logger.Warn("Failed to publish search completed event", slog.String("error", err.Error()))
+	}
+
+	s.logger.Info("Hybrid search completed",
+		slog.String("query", req.Query),
+		slog.String("fusion", string(fusion)),
+		slog.Float64("alpha", alpha),
+		slog.Int("lexical", len(lexical)),
+		slog.Int("vector", len(vector)),
+		slog.Int("results", len(papers)),
+		slog.Duration("duration", time.Since(start)))
+
+	return response, nil
+}
+
+// fuseHybrid merges the full-text and vector rankings into one, best first,
+// scoring each paper with the fusion method and alpha as the weight of the
+// vector ranking. The papers keep their fused score and the scores explain
+// it by paper ID. Ties go to the better single rank, then the paper ID.
+func fuseHybrid(lexical, vector []models.Paper, fusion FusionMethod, alpha float64) ([]models.Paper, map[string]HybridScore) {
+	scores := make(map[string]HybridScore, len(lexical)+len(vector))
+	papers := make(map[string]models.Paper, len(lexical)+len(vector))
+
+	lexicalNorm := normalizedScores(lexical)
+	for i, paper := range lexical {
+		score := scores[paper.ID]
+		score.LexicalRank = i + 1
+		score.LexicalScore = paper.Score
+		if fusion == FusionWeighted {
+			score.Lexical = (1 - alpha) * lexicalNorm[i]
+		} else {
+			score.Lexical = (1 - alpha) / float64(providers.DefaultRRFK+i+1)
+		}
+		scores[paper.ID] = score
+		papers[paper.ID] = paper
+	}
+
+	vectorNorm := normalizedScores(vector)
+	for i, paper := range vector {
+		score := scores[paper.ID]
+		score.VectorRank = i + 1
+		score.VectorScore = paper.Score
+		if fusion == FusionWeighted {
+			score.Vector = alpha * vectorNorm[i]
+		} else {
+			score.Vector = alpha / float64(providers.DefaultRRFK+i+1)
+		}
+		scores[paper.ID] = score
+		if _, ok := papers[paper.ID]; !ok {
+			papers[paper.ID] = paper
+		}
+	}
+
+	fused := make([]models.Paper, 0, len(papers))
+	for id, paper := range papers {
+		score := scores[id]
+		score.Fused = score.Lexical + score.Vector
+		scores[id] = score
+
+		fusedScore := score.Fused
+		paper.Score = &fusedScore
+		fused = append(fused, paper)
+	}
+
+	bestRank := func(score HybridScore) int {
+		if score.LexicalRank == 0 || (score.VectorRank != 0 && score.VectorRank < score.LexicalRank) {
+			return score.VectorRank
+		}
+		return score.LexicalRank
+	}
+	sort.Slice(fused, func(i, j int) bool {
+		a, b := scores[fused[i].ID], scores[fused[j].ID]
+		if a.Fused != b.Fused {
+			return a.Fused > b.Fused
+		}
+		if bestRank(a) != bestRank(b) {
+			return bestRank(a) < bestRank(b)
+		}
+		return fused[i].ID < fused[j].ID
+	})
+
+	return fused, scores
+}
+
+// normalizedScores min-max normalizes the scores of a ranking to [0, 1].
+// Rankings without scores, like unranked text matches, are scored by rank.
+func normalizedScores(ranking []models.Paper) []float64 {
+	normalized := make([]float64, len(ranking))
+	low, high := 0.0, 0.0
+	for i, paper := range ranking {
+		if paper.Score == nil {
+			for j := range ranking {
+				normalized[j] = 1 - float64(j)/float64(len(ranking))
+			}
+			return normalized
+		}
+		if i == 0 || *paper.Score < low {
+			low = *paper.Score
+		}
+		if i == 0 || *paper.Score > high {
+			high = *paper.Score
+		}
+	}
+
+	for i, paper := range ranking {
+		if high == low {
+			normalized[i] = 1
+		} else {
+			normalized[i] = (*paper.Score - low) / (high - low)
+		}
+	}
+	return normalized
+}
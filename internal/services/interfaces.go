@@ -5,16 +5,21 @@ import (
 	"time"
 
 	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
 )
 
 
 // SearchServiceInterface defines the contract for search service
 type SearchServiceInterface interface {
 	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
+	SearchStream(ctx context.Context, req *SearchRequest) (<-chan providers.StreamEvent, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]models.SearchSuggestion, error)
 	GetPaper(ctx context.Context, providerName, paperID string) (*models.Paper, error)
 	GetProviderStatus(ctx context.Context) (map[string]interface{}, error)
 	GetProviderMetrics(ctx context.Context) (map[string]interface{}, error)
 	ConfigureProvider(ctx context.Context, name string, config interface{}) error
+	OpenCircuit(ctx context.Context, name string) error
+	ResetCircuit(ctx context.Context, name string) error
 	Health(ctx context.Context) error
 }
 
@@ -27,6 +32,11 @@ type PaperServiceInterface interface {
 	List(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]*models.Paper, int, error)
 	Search(ctx context.Context, query string, limit, offset int) ([]*models.Paper, int, error)
 	GetByProvider(ctx context.Context, provider, sourceID string) (*models.Paper, error)
+	GetSimilar(ctx context.Context, id string, limit int) ([]SimilarPaper, error)
+	GetCitingPapers(ctx context.Context, id string, limit, offset int) (*CitationPage, error)
+	GetReferencedPapers(ctx context.Context, id string, limit, offset int) (*CitationPage, error)
+	GetCitationNeighborhood(ctx context.Context, id string, depth int, direction CitationDirection, maxNodes int) (*CitationGraph, error)
+	GetCitationPath(ctx context.Context, fromID, toID string, direction CitationDirection, maxDepth int) (*CitationPath, error)
 	Health(ctx context.Context) error
 }
 
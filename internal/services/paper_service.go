@@ -12,20 +12,29 @@ import (
 
 // PaperService handles paper-related business logic
 type PaperService struct {
-	repo      repository.PaperRepository
-	messaging *messaging.Client
-	logger    *slog.Logger
+	repo       repository.PaperRepository
+	citations  repository.CitationRepository
+	embeddings *EmbeddingService // nil when embeddings are disabled
+	messaging  *messaging.Client
+	logger     *slog.Logger
 }
 
 // NewPaperService creates a new paper service
-func NewPaperService(repo repository.PaperRepository, messaging *messaging.Client, logger *slog.Logger) PaperServiceInterface {
+func NewPaperService(repo repository.PaperRepository, citations repository.CitationRepository, messaging *messaging.Client, logger *slog.Logger) PaperServiceInterface {
 	return &PaperService{
 		repo:      repo,
+		citations: citations,
 		messaging: messaging,
 		logger:    logger,
 	}
 }
 
+// EnableEmbeddingSimilarity finds papers with similar embeddings through the
+// vector index of the embedding service
+func (s *PaperService) EnableEmbeddingSimilarity(embeddings *EmbeddingService) {
+	s.embeddings = embeddings
+}
+
 // GetByID retrieves a paper by its ID
 func (s *PaperService) GetByID(ctx context.Context, id string) (*models.Paper, error) {
 	paper, err := s.repo.GetByID(ctx, id)
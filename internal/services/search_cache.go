@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"scifind-backend/internal/models"
+)
+
+const (
+	// defaultSearchCacheTTL is how long search responses stay in the database cache
+	defaultSearchCacheTTL = time.Hour
+
+	// searchCacheTier identifies responses served from the database cache
+	searchCacheTier = "database"
+)
+
+// searchCacheKey returns the query hash of a search request. Requests that
+// only differ in case, whitespace or provider and filter ordering share a key.
+func searchCacheKey(req *SearchRequest) string {
+	var b strings.Builder
+
+	b.WriteString(strings.Join(strings.Fields(strings.ToLower(req.Query)), " "))
+	fmt.Fprintf(&b, "|limit=%d|offset=%d", req.Limit, req.Offset)
+	if req.Cursor != "" {
+		fmt.Fprintf(&b, "|cursor=%s", req.Cursor)
+	}
+	fmt.Fprintf(&b, "|providers=%s", searchCacheProvider(req))
+	if req.DateFrom != nil {
+		fmt.Fprintf(&b, "|from=%s", req.DateFrom.UTC().Format("2006-01-02"))
+	}
+	if req.DateTo != nil {
+		fmt.Fprintf(&b, "|to=%s", req.DateTo.UTC().Format("2006-01-02"))
+	}
+
+	filterKeys := make([]string, 0, len(req.Filters))
+	for key := range req.Filters {
+		filterKeys = append(filterKeys, key)
+	}
+	sort.Strings(filterKeys)
+	for _, key := range filterKeys {
+		fmt.Fprintf(&b, "|f:%s=%s", strings.ToLower(key), strings.ToLower(strings.TrimSpace(req.Filters[key])))
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// searchCacheProvider returns the provider column value of a cache entry
+func searchCacheProvider(req *SearchRequest) string {
+	if len(req.Providers) == 0 {
+		return "all"
+	}
+
+	names := append([]string(nil), req.Providers...)
+	sort.Strings(names)
+	provider := strings.Join(names, ",")
+	if len(provider) > 50 {
+		provider = provider[:50]
+	}
+	return provider
+}
+
+// getCachedSearch returns the cached response for the request, or nil on a
+// miss. Lookup failures are logged and treated as misses.
+func (s *SearchService) getCachedSearch(ctx context.Context, req *SearchRequest, key string) *SearchResponse {
+	if s.searchRepo == nil || req.CacheMode != CacheModeDefault {
+		return nil
+	}
+
+	cached, err := s.searchRepo.GetCachedSearch(ctx, key)
+	if err != nil {
+		s.logger.Warn("Failed to read search cache", slog.String("error", err.Error()))
+		return nil
+	}
+	if cached == nil {
+		return nil
+	}
+
+	var response SearchResponse
+	if err := json.Unmarshal([]byte(cached.Results), &response); err != nil {
+		s.logger.Warn("Failed to decode cached search", slog.String("query_hash", key), slog.String("error", err.Error()))
+		return nil
+	}
+
+	response.RequestID = req.RequestID
+	response.CacheHits = len(response.ProvidersUsed)
+	response.CacheTier = searchCacheTier
+	response.Timestamp = time.Now()
+
+	return &response
+}
+
+// setCachedSearch stores a complete search response in the database cache
+func (s *SearchService) setCachedSearch(ctx context.Context, req *SearchRequest, key string, resp *SearchResponse) {
+	if s.searchRepo == nil || req.CacheMode == CacheModeBypass {
+		return
+	}
+	// Partial results would hide providers that recover before the entry expires
+	if resp.PartialFailure || len(resp.ProvidersFailed) > 0 || len(resp.ProvidersPending) > 0 {
+		return
+	}
+
+	stored := *resp
+	stored.Errors = nil
+	stored.CacheHits = 0
+	stored.CacheTier = ""
+
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		s.logger.Warn("Failed to encode search response for cache", slog.String("error", err.Error()))
+		return
+	}
+
+	entry := &models.SearchCache{
+		ID:          "cache_" + key[:32],
+		QueryHash:   key,
+		Query:       req.Query,
+		Results:     string(data),
+		ResultCount: resp.ResultCount,
+		Provider:    searchCacheProvider(req),
+		ExpiresAt:   time.Now().Add(s.cacheTTL),
+	}
+	if err := s.searchRepo.SetSearchCache(ctx, entry); err != nil {
+		s.logger.Warn("Failed to store search cache", slog.String("error", err.Error()))
+	}
+}
+
+// StartCacheCleanup periodically removes expired search cache entries until
+// the context is cancelled
+func (s *SearchService) StartCacheCleanup(ctx context.Context, interval time.Duration) {
+	if s.searchRepo == nil || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.searchRepo.CleanupExpiredCache(ctx); err != nil {
+					s.logger.Warn("Failed to clean up expired search cache", slog.String("error", err.Error()))
+				}
+			}
+		}
+	}()
+}
@@ -2,23 +2,38 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"scifind-backend/internal/errors"
 	"scifind-backend/internal/messaging"
 	"scifind-backend/internal/models"
 	"scifind-backend/internal/providers"
 	"scifind-backend/internal/repository"
 )
 
+const (
+	// defaultSuggestionLimit is the number of suggestions returned by default
+	defaultSuggestionLimit = 10
+
+	// maxSuggestionLimit bounds the number of suggestions per request
+	maxSuggestionLimit = 50
+)
+
 // SearchService handles search-related business logic
 type SearchService struct {
 	searchRepo      repository.SearchRepository
 	paperRepo       repository.PaperRepository
 	providerManager providers.ProviderManager
 	messaging       *messaging.Client
+	embeddings      *EmbeddingService
+	cacheTTL        time.Duration
 	logger          *slog.Logger
 }
 
@@ -35,11 +50,19 @@ func NewSearchService(
 		paperRepo:       paperRepo,
 		messaging:       messaging,
 		providerManager: providerManager,
+		cacheTTL:        defaultSearchCacheTTL,
 		logger:          logger,
 	}
 }
 
-// Search performs a search across configured providers
+// EnableSemanticSearch serves the semantic search mode from the embeddings
+// of the stored papers
+func (s *SearchService) EnableSemanticSearch(embeddings *EmbeddingService) {
+	s.embeddings = embeddings
+}
+
+// Search performs a search across configured providers, or over the stored
+// papers in semantic and hybrid mode
 func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
 	start := time.Now()
 
@@ -47,18 +70,43 @@ func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (*Search
 	if err := s.validateSearchRequest(req); err != nil {
 		return nil, fmt.Errorf("invalid search request: %v", err)
 	}
+	switch req.Mode {
+	case SearchModeSemantic:
+		return s.semanticSearch(ctx, req, start)
+	case SearchModeHybrid:
+		return s.hybridSearch(ctx, req, start)
+	}
+	parsedQuery, err := providers.ParseUserQuery(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search request: %w", err)
+	}
 
-	// Build provider search query
-	searchQuery := &providers.SearchQuery{
-		RequestID: req.RequestID,
-		Query:     req.Query,
-		Limit:     req.Limit,
-		Offset:    req.Offset,
-		Filters:   req.Filters,
-		DateFrom:  req.DateFrom,
-		DateTo:    req.DateTo,
+	// Serve repeated queries from the database cache
+	cacheKey := searchCacheKey(req)
+	if cached := s.getCachedSearch(ctx, req, cacheKey); cached != nil {
+		if cached.Facets == nil {
+			cached.Facets = models.NewSearchFacets(cached.Papers)
+		}
+		applyFacetFilters(cached, req.FacetFilters)
+
+		if err := s.storeSearchResult(ctx, req, cached, time.Since(start), nil); err != nil {
+			s.logger.Warn("Failed to store search result", slog.String("error", err.Error()))
+		}
+		if err := s.publishSearchCompletedEvent(ctx, req, cached, time.Since(start), nil); err != nil {
+			s.logger.Warn("Failed to publish search completed event", slog.String("error", err.Error()))
+		}
+
+		s.logger.Info("Search served from cache",
+			slog.String("query", req.Query),
+			slog.Int("results", cached.ResultCount),
+			slog.Duration("duration", time.Since(start)))
+
+		return cached, nil
 	}
 
+	// Build provider search query
+	searchQuery := buildSearchQuery(req, parsedQuery)
+
 	// Publish search request event
 	if err := s.publishSearchRequestEvent(ctx, req); err != nil {
 		s.logger.Warn("Failed to publish search request event", slog.String("error", err.Error()))
@@ -66,7 +114,6 @@ func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (*Search
 
 	// Execute search
 	var result *providers.AggregatedResult
-	var err error
 
 	if len(req.Providers) > 0 {
 		// Search specific providers
@@ -77,6 +124,10 @@ func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (*Search
 	}
 
 	if err != nil {
+		if storeErr := s.storeSearchResult(ctx, req, nil, time.Since(start), err); storeErr != nil {
+			s.logger.Warn("Failed to store search result", slog.String("error", storeErr.Error()))
+		}
+
 		// Publish search failure event
 		s.publishSearchCompletedEvent(ctx, req, nil, time.Since(start), err)
 		return nil, fmt.Errorf("search failed: %w", err)
@@ -99,16 +150,26 @@ func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (*Search
 		ResultCount:         len(enhancedPapers),
 		ProvidersUsed:       result.SuccessfulProviders,
 		ProvidersFailed:     result.FailedProviders,
+		ProvidersPending:    result.PendingProviders,
+		FilterSupport:       result.FilterSupport,
 		Duration:            result.TotalDuration,
 		AggregationStrategy: result.AggregationStrategy,
+		StrategyMetadata:    result.StrategyMetadata,
 		CacheHits:           result.CacheHits,
+		HasMore:             result.HasMore,
+		NextCursor:          result.NextPageToken,
+		Facets:              models.NewSearchFacets(enhancedPapers),
 		PartialFailure:      result.PartialFailure,
 		Errors:              result.Errors,
 		Timestamp:           time.Now(),
 	}
 
+	// Cache the unfiltered results so facet drill-down is served from cache
+	s.setCachedSearch(ctx, req, cacheKey, response)
+	applyFacetFilters(response, req.FacetFilters)
+
 	// Store search in database for analytics
-	if err := s.storeSearchResult(ctx, req, response); err != nil {
+	if err := s.storeSearchResult(ctx, req, response, time.Since(start), nil); err != nil {
 		s.logger.Warn("Failed to store search result", slog.String("error", err.Error()))
 	}
 
@@ -126,14 +187,214 @@ func (s *SearchService) Search(ctx context.Context, req *SearchRequest) (*Search
 	return response, nil
 }
 
+// semanticSearch searches the stored papers by embedding similarity to the
+// query text, which is embedded as is without query syntax. Semantic
+// searches bypass the result cache.
+func (s *SearchService) semanticSearch(ctx context.Context, req *SearchRequest, start time.Time) (*SearchResponse, error) {
+	if s.embeddings == nil {
+		return nil, fmt.Errorf("invalid search request: %w",
+			errors.NewValidationError("semantic search is not enabled", "mode", req.Mode))
+	}
+
+	if err := s.publishSearchRequestEvent(ctx, req); err != nil {
+		s.logger.Warn("Failed to publish search request event", slog.String("error", err.Error()))
+	}
+
+	// One extra paper tells whether there are more
+	papers, err := s.embeddings.Search(ctx, req.Query, localFilter(req), req.Limit+1, req.Offset)
+	if err != nil {
+		if storeErr := s.storeSearchResult(ctx, req, nil, time.Since(start), err); storeErr != nil {
+			s.logger.Warn("Failed to store search result", slog.String("error", storeErr.Error()))
+		}
+		s.publishSearchCompletedEvent(ctx, req, nil, time.Since(start), err)
+		return nil, fmt.Errorf("semantic search failed: %w", err)
+	}
+	hasMore := len(papers) > req.Limit
+	if hasMore {
+		papers = papers[:req.Limit]
+	}
+
+	response := &SearchResponse{
+		RequestID:           req.RequestID,
+		Query:               req.Query,
+		Papers:              papers,
+		TotalCount:          req.Offset + len(papers),
+		ResultCount:         len(papers),
+		ProvidersUsed:       []string{providers.ProviderLocal},
+		Duration:            time.Since(start),
+		AggregationStrategy: string(SearchModeSemantic),
+		HasMore:             hasMore,
+		Facets:              models.NewSearchFacets(papers),
+		Timestamp:           time.Now(),
+	}
+	applyFacetFilters(response, req.FacetFilters)
+
+	if err := s.storeSearchResult(ctx, req, response, time.Since(start), nil); err != nil {
+		s.logger.Warn("Failed to store search result", slog.String("error", err.Error()))
+	}
+	if err := s.publishSearchCompletedEvent(ctx, req, response, time.Since(start), nil); err != nil {
+		s.logger.Warn("Failed to publish search completed event", slog.String("error", err.Error()))
+	}
+
+	s.logger.Info("Semantic search completed",
+		slog.String("query", req.Query),
+		slog.Int("results", len(papers)),
+		slog.Duration("duration", time.Since(start)))
+
+	return response, nil
+}
+
+// localFilter returns the repository filter of the request filters that
+// searches of the stored papers apply, nil when there are none
+func localFilter(req *SearchRequest) *models.PaperFilter {
+	filter := &models.PaperFilter{
+		PublishedFrom: req.DateFrom,
+		PublishedTo:   req.DateTo,
+		Journal:       strings.TrimSpace(req.Filters[providers.FilterJournal]),
+	}
+	for _, author := range strings.Split(req.Filters[providers.FilterAuthor], ",") {
+		if author = strings.TrimSpace(author); author != "" {
+			filter.Authors = append(filter.Authors, author)
+		}
+	}
+	for _, category := range strings.Split(req.Filters[providers.FilterCategory], ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			filter.Categories = append(filter.Categories, category)
+		}
+	}
+
+	if filter.PublishedFrom == nil && filter.PublishedTo == nil && filter.Journal == "" &&
+		len(filter.Authors) == 0 && len(filter.Categories) == 0 {
+		return nil
+	}
+	return filter
+}
+
+// SearchStream searches the providers like Search but emits the papers of
+// each provider as soon as it finishes, deduplicated against the papers
+// already sent, followed by a summary event. Streamed results bypass the
+// result cache.
+func (s *SearchService) SearchStream(ctx context.Context, req *SearchRequest) (<-chan providers.StreamEvent, error) {
+	start := time.Now()
+
+	if err := s.validateSearchRequest(req); err != nil {
+		return nil, fmt.Errorf("invalid search request: %v", err)
+	}
+	if req.Cursor != "" {
+		return nil, fmt.Errorf("invalid search request: cursor is not supported by streamed searches")
+	}
+	if req.Mode != SearchModeKeyword {
+		return nil, fmt.Errorf("invalid search request: %s mode is not supported by streamed searches", req.Mode)
+	}
+	parsedQuery, err := providers.ParseUserQuery(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search request: %w", err)
+	}
+
+	if err := s.publishSearchRequestEvent(ctx, req); err != nil {
+		s.logger.Warn("Failed to publish search request event", slog.String("error", err.Error()))
+	}
+
+	events, err := s.providerManager.SearchStream(ctx, buildSearchQuery(req, parsedQuery), req.Providers)
+	if err != nil {
+		if storeErr := s.storeSearchResult(ctx, req, nil, time.Since(start), err); storeErr != nil {
+			s.logger.Warn("Failed to store search result", slog.String("error", storeErr.Error()))
+		}
+		s.publishSearchCompletedEvent(ctx, req, nil, time.Since(start), err)
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	out := make(chan providers.StreamEvent)
+	go s.relayStream(ctx, req, start, events, out)
+	return out, nil
+}
+
+// relayStream forwards stream events narrowed to the facet selections and
+// records the search once the summary arrives. It stops forwarding when the
+// client goes away but still records the search.
+func (s *SearchService) relayStream(ctx context.Context, req *SearchRequest, start time.Time, events <-chan providers.StreamEvent, out chan<- providers.StreamEvent) {
+	defer close(out)
+
+	forwarding := true
+	for event := range events {
+		if event.Type == providers.StreamEventSummary && event.Summary != nil {
+			s.recordStream(context.WithoutCancel(ctx), req, event.Summary, time.Since(start))
+		}
+		if len(req.FacetFilters) > 0 && len(event.Papers) > 0 {
+			event.Papers = filterFacets(event.Papers, req.FacetFilters)
+		}
+
+		if !forwarding {
+			continue
+		}
+		select {
+		case out <- event:
+		case <-ctx.Done():
+			forwarding = false
+		}
+	}
+}
+
+// recordStream stores and publishes a finished streamed search
+func (s *SearchService) recordStream(ctx context.Context, req *SearchRequest, summary *providers.StreamSummary, duration time.Duration) {
+	var resp *SearchResponse
+	var searchErr error
+	if len(summary.SuccessfulProviders) > 0 {
+		resp = &SearchResponse{
+			RequestID:       req.RequestID,
+			Query:           req.Query,
+			TotalCount:      summary.TotalCount,
+			ResultCount:     summary.TotalCount,
+			ProvidersUsed:   summary.SuccessfulProviders,
+			ProvidersFailed: summary.FailedProviders,
+			Duration:        duration,
+			CacheHits:       summary.CacheHits,
+			PartialFailure:  summary.PartialFailure,
+			Timestamp:       time.Now(),
+		}
+	} else {
+		searchErr = fmt.Errorf("search failed: all providers failed")
+	}
+
+	if err := s.storeSearchResult(ctx, req, resp, duration, searchErr); err != nil {
+		s.logger.Warn("Failed to store search result", slog.String("error", err.Error()))
+	}
+	if err := s.publishSearchCompletedEvent(ctx, req, resp, duration, searchErr); err != nil {
+		s.logger.Warn("Failed to publish search completed event", slog.String("error", err.Error()))
+	}
+}
+
+// Suggest returns query-as-you-type suggestions for a prefix, mixing past
+// popular queries with stored author names, journals and categories
+func (s *SearchService) Suggest(ctx context.Context, prefix string, limit int) ([]models.SearchSuggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil, NewValidationError("prefix is required")
+	}
+	if len(prefix) > 200 {
+		return nil, NewValidationError("prefix too long (max 200 characters)")
+	}
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+	if limit > maxSuggestionLimit {
+		return nil, NewValidationError(fmt.Sprintf("limit cannot exceed %d", maxSuggestionLimit))
+	}
+
+	suggestions, err := s.searchRepo.GetSearchSuggestions(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	return suggestions, nil
+}
+
 // GetPaper retrieves a specific paper by ID from a provider
 func (s *SearchService) GetPaper(ctx context.Context, providerName, paperID string) (*models.Paper, error) {
-	provider, err := s.providerManager.GetProvider(providerName)
-	if err != nil {
+	if _, err := s.providerManager.GetProvider(providerName); err != nil {
 		return nil, fmt.Errorf("provider not found: %w", err)
 	}
 
-	paper, err := provider.GetPaper(ctx, paperID)
+	paper, err := s.providerManager.GetPaper(ctx, providerName, paperID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get paper: %w", err)
 	}
@@ -151,16 +412,32 @@ func (s *SearchService) GetPaper(ctx context.Context, providerName, paperID stri
 
 // GetProviderStatus returns the status of all providers
 func (s *SearchService) GetProviderStatus(ctx context.Context) (map[string]interface{}, error) {
-	providers := s.providerManager.GetAllProviders()
 	status := make(map[string]interface{})
-
-	for name, provider := range providers {
-		status[name] = provider.GetStatus()
+	for name, providerStatus := range s.providerManager.GetProviderStatus() {
+		status[name] = providerStatus
 	}
 
 	return status, nil
 }
 
+// OpenCircuit opens a provider's circuit breaker until it is reset
+func (s *SearchService) OpenCircuit(ctx context.Context, name string) error {
+	if err := s.providerManager.OpenCircuit(name); err != nil {
+		return err
+	}
+	s.logger.Warn("Provider circuit opened by request", slog.String("provider", name))
+	return nil
+}
+
+// ResetCircuit closes a provider's circuit breaker
+func (s *SearchService) ResetCircuit(ctx context.Context, name string) error {
+	if err := s.providerManager.ResetCircuit(name); err != nil {
+		return err
+	}
+	s.logger.Info("Provider circuit reset by request", slog.String("provider", name))
+	return nil
+}
+
 // GetProviderMetrics returns metrics for all providers
 func (s *SearchService) GetProviderMetrics(ctx context.Context) (map[string]interface{}, error) {
 	metrics := s.providerManager.GetProviderMetrics()
@@ -218,9 +495,60 @@ func (s *SearchService) validateSearchRequest(req *SearchRequest) error {
 		req.Offset = 0
 	}
 
+	if req.Cursor != "" && req.Offset > 0 {
+		return fmt.Errorf("offset cannot be combined with cursor")
+	}
+
+	switch req.CacheMode {
+	case CacheModeDefault, CacheModeBypass, CacheModeRefresh:
+	default:
+		return fmt.Errorf("invalid cache mode: %s", req.CacheMode)
+	}
+
+	for facet := range req.FacetFilters {
+		if !models.IsValidFacet(facet) {
+			return fmt.Errorf("invalid facet: %s", facet)
+		}
+	}
+
+	switch req.Mode {
+	case SearchModeKeyword:
+	case SearchModeSemantic, SearchModeHybrid:
+		if req.Cursor != "" || len(req.Providers) > 0 {
+			return fmt.Errorf("%s searches take neither cursor nor providers", req.Mode)
+		}
+	default:
+		return fmt.Errorf("invalid search mode: %s", req.Mode)
+	}
+
+	switch req.Fusion {
+	case "", FusionReciprocalRank, FusionWeighted:
+	default:
+		return fmt.Errorf("invalid fusion method: %s", req.Fusion)
+	}
+
+	if req.Alpha != nil && (*req.Alpha < 0 || *req.Alpha > 1) {
+		return fmt.Errorf("alpha must be between 0 and 1")
+	}
+
 	return nil
 }
 
+// buildSearchQuery builds the provider query of a search request
+func buildSearchQuery(req *SearchRequest, parsedQuery *providers.QueryNode) *providers.SearchQuery {
+	return &providers.SearchQuery{
+		RequestID: req.RequestID,
+		Query:     req.Query,
+		Parsed:    parsedQuery,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+		PageToken: req.Cursor,
+		Filters:   req.Filters,
+		DateFrom:  req.DateFrom,
+		DateTo:    req.DateTo,
+	}
+}
+
 func (s *SearchService) enhanceSearchResults(ctx context.Context, papers []models.Paper) ([]models.Paper, error) {
 	// For now, just return papers as-is
 	// Future enhancements could include:
@@ -231,10 +559,83 @@ func (s *SearchService) enhanceSearchResults(ctx context.Context, papers []model
 	return papers, nil
 }
 
-func (s *SearchService) storeSearchResult(ctx context.Context, req *SearchRequest, resp *SearchResponse) error {
-	// TODO: Implement search result storage for analytics
-	// This would store search queries, results, and metadata for analysis
-	return nil
+// applyFacetFilters narrows the response papers to the facet selections.
+// Facet counts keep describing the full merged result set.
+func applyFacetFilters(resp *SearchResponse, selections map[string][]string) {
+	if len(selections) == 0 {
+		return
+	}
+
+	resp.Papers = filterFacets(resp.Papers, selections)
+	resp.ResultCount = len(resp.Papers)
+	resp.AppliedFacets = selections
+}
+
+// filterFacets returns the papers matching the facet selections
+func filterFacets(papers []models.Paper, selections map[string][]string) []models.Paper {
+	filtered := make([]models.Paper, 0, len(papers))
+	for i := range papers {
+		if papers[i].MatchesFacets(selections) {
+			filtered = append(filtered, papers[i])
+		}
+	}
+	return filtered
+}
+
+func (s *SearchService) storeSearchResult(ctx context.Context, req *SearchRequest, resp *SearchResponse, duration time.Duration, searchErr error) error {
+	if s.searchRepo == nil {
+		return nil
+	}
+
+	filters, err := json.Marshal(req.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode search filters: %w", err)
+	}
+
+	history := &models.SearchHistory{
+		ID:          "search_" + uuid.New().String(),
+		Query:       req.Query,
+		UserID:      req.UserID,
+		Duration:    duration.Milliseconds(),
+		Filters:     string(filters),
+		RequestedAt: time.Now().Add(-duration),
+	}
+
+	if resp != nil {
+		history.ResultCount = resp.ResultCount
+		history.Providers = resp.ProvidersUsed
+		history.ProvidersFailed = resp.ProvidersFailed
+		history.CacheHit = resp.CacheHits > 0
+
+		// Completed searches feed the query suggestions
+		if err := s.searchRepo.UpdateSearchSuggestions(ctx, req.Query, resp.ResultCount); err != nil {
+			s.logger.Warn("Failed to update search suggestions", slog.String("error", err.Error()))
+		}
+	} else {
+		history.Providers = []string{}
+		history.ProvidersFailed = s.attemptedProviders(req)
+		if searchErr != nil {
+			history.Error = searchErr.Error()
+		}
+	}
+
+	return s.searchRepo.CreateSearchHistory(ctx, history)
+}
+
+// attemptedProviders returns the providers a search was sent to: the ones
+// requested, or every enabled provider
+func (s *SearchService) attemptedProviders(req *SearchRequest) []string {
+	if len(req.Providers) > 0 || s.providerManager == nil {
+		return req.Providers
+	}
+
+	enabled := s.providerManager.GetEnabledProviders()
+	names := make([]string, 0, len(enabled))
+	for _, provider := range enabled {
+		names = append(names, provider.Name())
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (s *SearchService) publishSearchRequestEvent(ctx context.Context, req *SearchRequest) error {
@@ -282,7 +683,7 @@ func (s *SearchService) publishSearchCompletedEvent(ctx context.Context, req *Se
 		ResultCount:   resultCount,
 		Duration:      duration.Milliseconds(),
 		ProvidersUsed: providersUsed,
-		CacheHit:      false, // TODO: Implement cache detection
+		CacheHit:      resp != nil && resp.CacheHits > 0,
 		CompletedAt:   time.Now().UnixMilli(),
 		Success:       success,
 		Error:         errorMsg,
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	"scifind-backend/internal/embeddings"
+	"scifind-backend/internal/models"
+)
+
+const (
+	// DefaultSimilarLimit is the number of similar papers returned by default
+	DefaultSimilarLimit = 10
+
+	// MaxSimilarLimit is the most similar papers returned at once
+	MaxSimilarLimit = 100
+
+	// Weights of the similarity signals. Signals the paper has no data for,
+	// like an embedding or references, are left out and the rest reweighted.
+	similarEmbeddingWeight  = 0.4
+	similarCoCitationWeight = 0.2
+	similarCouplingWeight   = 0.2
+	similarAuthorWeight     = 0.2
+
+	// similarCandidateDepth is the most candidates taken from each signal
+	similarCandidateDepth = 100
+
+	// similarMaxReferences is the most references looked up for coupling
+	similarMaxReferences = 200
+)
+
+// SimilarPaper is a paper recommended as similar to another, with why
+type SimilarPaper struct {
+	Paper   models.Paper      `json:"paper"`
+	Score   float64           `json:"score"`
+	Signals SimilaritySignals `json:"signals"`
+	Reasons []string          `json:"reasons"`
+}
+
+// SimilaritySignals are the similarities a recommendation combines, each
+// from 0 to 1, with the evidence behind them
+type SimilaritySignals struct {
+	Embedding        float64  `json:"embedding"`              // cosine similarity of the embeddings
+	CoCitation       float64  `json:"co_citation"`            // how often both papers are cited together
+	Coupling         float64  `json:"bibliographic_coupling"` // how many references both papers share
+	AuthorOverlap    float64  `json:"author_overlap"`
+	CoCitations      int      `json:"co_citations,omitempty"`      // papers citing both
+	SharedReferences int      `json:"shared_references,omitempty"` // references of both
+	SharedAuthors    []string `json:"shared_authors,omitempty"`
+}
+
+// similarityContext holds what is known about the paper recommendations are made for
+type similarityContext struct {
+	paper      *models.Paper
+	references map[string]bool
+	authors    map[string]bool
+
+	// The references of the stored papers citing the paper by their ID, the
+	// IDs of all citing papers, including those only listed in its citations,
+	// and the stored citing papers by each of their identifiers
+	citers      map[string]map[string]bool
+	citerIDs    map[string]bool
+	citerAlias  map[string]string
+	hasCitation bool
+}
+
+// GetSimilar recommends stored papers similar to the paper, best first. It
+// combines the cosine similarity of the embeddings, co-citation, bibliographic
+// coupling and author overlap, and explains each recommendation.
+func (s *PaperService) GetSimilar(ctx context.Context, id string, limit int) ([]SimilarPaper, error) {
+	if limit <= 0 {
+		limit = DefaultSimilarLimit
+	}
+	if limit > MaxSimilarLimit {
+		limit = MaxSimilarLimit
+	}
+
+	paper, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get paper for similarity", slog.String("id", id), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get paper: %w", err)
+	}
+
+	sc, candidates, err := s.similarityCandidates(ctx, paper)
+	if err != nil {
+		s.logger.Error("Failed to get similar papers", slog.String("id", id), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get similar papers: %w", err)
+	}
+
+	similar := make([]SimilarPaper, 0, len(candidates))
+	for _, candidate := range candidates {
+		if recommendation, ok := sc.score(candidate); ok {
+			similar = append(similar, recommendation)
+		}
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].Paper.ID < similar[j].Paper.ID
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	return similar, nil
+}
+
+// similarityCandidates collects the papers sharing any signal with the paper:
+// the nearest embeddings, papers cited together with it, papers sharing its
+// references and papers by its authors
+func (s *PaperService) similarityCandidates(ctx context.Context, paper *models.Paper) (*similarityContext, []models.Paper, error) {
+	sc := &similarityContext{
+		paper:      paper,
+		references: identifierSet(paper.References),
+		authors:    make(map[string]bool, len(paper.Authors)),
+		citers:     make(map[string]map[string]bool),
+		citerIDs:   make(map[string]bool),
+		citerAlias: make(map[string]string),
+	}
+	for _, author := range paper.Authors {
+		sc.authors[author.ID] = true
+	}
+
+	candidates := make(map[string]models.Paper)
+	add := func(papers []models.Paper) {
+		for _, candidate := range papers {
+			if candidate.ID != paper.ID {
+				candidates[candidate.ID] = candidate
+			}
+		}
+	}
+
+	// Nearest embeddings from the vector index, compared directly when
+	// embeddings are disabled
+	if len(paper.Embedding) > 0 {
+		var nearest []models.Paper
+		var err error
+		if s.embeddings != nil {
+			nearest, err = s.embeddings.Nearest(ctx, paper.Embedding, similarCandidateDepth+1)
+		} else {
+			nearest, err = s.repo.SearchByVector(ctx, paper.Embedding, nil, similarCandidateDepth+1)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		add(nearest)
+	}
+
+	// Papers citing this one, by their references or its citations
+	citing, err := s.repo.GetPapersReferencing(ctx, paper.GetIdentifiers(), similarCandidateDepth)
+	if err != nil {
+		return nil, nil, err
+	}
+	listed, err := s.repo.GetByIdentifiers(ctx, paper.Citations, similarCandidateDepth)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The references are looked up as cited, as they are compared normalized
+	cited := make(map[string]string)
+	for _, citer := range append(citing, listed...) {
+		if citer.ID == paper.ID {
+			continue
+		}
+		for _, reference := range citer.References {
+			cited[models.NormalizeCitationID(reference)] = reference
+		}
+		sc.citers[citer.ID] = identifierSet(citer.References)
+		sc.citerIDs[citer.ID] = true
+		for _, identifier := range citer.GetIdentifiers() {
+			sc.citerAlias[models.NormalizeCitationID(identifier)] = citer.ID
+		}
+	}
+	for _, citation := range paper.Citations {
+		sc.citerIDs[sc.citerKey(citation)] = true
+	}
+	sc.hasCitation = len(sc.citerIDs) > 0
+
+	// Papers cited together with this one, most often co-cited first
+	own := identifierSet(paper.GetIdentifiers())
+	coCited := make(map[string]int)
+	for _, references := range sc.citers {
+		for reference := range references {
+			if !own[reference] {
+				coCited[reference]++
+			}
+		}
+	}
+	if len(coCited) > 0 {
+		references := make([]string, 0, len(coCited))
+		for reference := range coCited {
+			references = append(references, reference)
+		}
+		sort.Slice(references, func(i, j int) bool {
+			if coCited[references[i]] != coCited[references[j]] {
+				return coCited[references[i]] > coCited[references[j]]
+			}
+			return references[i] < references[j]
+		})
+		if len(references) > similarCandidateDepth {
+			references = references[:similarCandidateDepth]
+		}
+		for i, reference := range references {
+			references[i] = cited[reference]
+		}
+		papers, err := s.repo.GetByIdentifiers(ctx, references, similarCandidateDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(papers)
+	}
+
+	// Papers sharing references
+	if len(paper.References) > 0 {
+		references := paper.References
+		if len(references) > similarMaxReferences {
+			references = references[:similarMaxReferences]
+		}
+		coupled, err := s.repo.GetPapersReferencing(ctx, references, similarCandidateDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(coupled)
+	}
+
+	// Papers by the same authors
+	if len(sc.authors) > 0 {
+		authorIDs := make([]string, 0, len(sc.authors))
+		for _, author := range paper.Authors {
+			authorIDs = append(authorIDs, author.ID)
+		}
+		authored, err := s.repo.GetPapersByAuthors(ctx, authorIDs, similarCandidateDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(authored)
+	}
+
+	ordered := make([]models.Paper, 0, len(candidates))
+	for _, candidate := range candidates {
+		ordered = append(ordered, candidate)
+	}
+	return sc, ordered, nil
+}
+
+// citerKey maps the identifier of a citing paper to the ID of the stored paper
+// it names, if any
+func (sc *similarityContext) citerKey(identifier string) string {
+	normalized := models.NormalizeCitationID(identifier)
+	if id, ok := sc.citerAlias[normalized]; ok {
+		return id
+	}
+	return normalized
+}
+
+// score weighs the similarity signals of a candidate and explains them. It
+// reports false for candidates that share nothing with the paper.
+func (sc *similarityContext) score(candidate models.Paper) (SimilarPaper, bool) {
+	var signals SimilaritySignals
+	type reason struct {
+		weight float64
+		text   string
+	}
+	var reasons []reason
+	totalWeight := 0.0
+
+	if len(sc.paper.Embedding) > 0 {
+		totalWeight += similarEmbeddingWeight
+		if len(candidate.Embedding) == len(sc.paper.Embedding) {
+			signals.Embedding = math.Max(0, embeddings.Cosine(sc.paper.Embedding, candidate.Embedding))
+		}
+		if signals.Embedding > 0 {
+			reasons = append(reasons, reason{similarEmbeddingWeight * signals.Embedding,
+				fmt.Sprintf("similar content (embedding cosine %.2f)", signals.Embedding)})
+		}
+	}
+
+	if sc.hasCitation {
+		totalWeight += similarCoCitationWeight
+		identifiers := identifierSet(candidate.GetIdentifiers())
+		shared := make(map[string]bool)
+		for citerID, references := range sc.citers {
+			if citerID == candidate.ID {
+				continue
+			}
+			for identifier := range identifiers {
+				if references[identifier] {
+					shared[citerID] = true
+					break
+				}
+			}
+		}
+		for _, citation := range candidate.Citations {
+			if key := sc.citerKey(citation); sc.citerIDs[key] {
+				shared[key] = true
+			}
+		}
+		signals.CoCitations = len(shared)
+		if signals.CoCitations > 0 {
+			candidateCiters := max(len(candidate.Citations), signals.CoCitations)
+			signals.CoCitation = float64(signals.CoCitations) / math.Sqrt(float64(len(sc.citerIDs)*candidateCiters))
+			reasons = append(reasons, reason{similarCoCitationWeight * signals.CoCitation,
+				fmt.Sprintf("cited together with it by %s", plural(signals.CoCitations, "paper"))})
+		}
+	}
+
+	if len(sc.references) > 0 {
+		totalWeight += similarCouplingWeight
+		references := identifierSet(candidate.References)
+		for reference := range references {
+			if sc.references[reference] {
+				signals.SharedReferences++
+			}
+		}
+		if signals.SharedReferences > 0 {
+			signals.Coupling = float64(signals.SharedReferences) / math.Sqrt(float64(len(sc.references)*len(references)))
+			reasons = append(reasons, reason{similarCouplingWeight * signals.Coupling,
+				fmt.Sprintf("shares %s with it", plural(signals.SharedReferences, "reference"))})
+		}
+	}
+
+	if len(sc.authors) > 0 {
+		totalWeight += similarAuthorWeight
+		for _, author := range candidate.Authors {
+			if sc.authors[author.ID] {
+				signals.SharedAuthors = append(signals.SharedAuthors, author.Name)
+			}
+		}
+		if len(signals.SharedAuthors) > 0 {
+			signals.AuthorOverlap = float64(len(signals.SharedAuthors)) / math.Sqrt(float64(len(sc.authors)*len(candidate.Authors)))
+			reasons = append(reasons, reason{similarAuthorWeight * signals.AuthorOverlap,
+				fmt.Sprintf("shares authors %s", strings.Join(signals.SharedAuthors, ", "))})
+		}
+	}
+
+	if len(reasons) == 0 || totalWeight == 0 {
+		return SimilarPaper{}, false
+	}
+
+	score := 0.0
+	for _, r := range reasons {
+		score += r.weight
+	}
+	sort.SliceStable(reasons, func(i, j int) bool {
+		return reasons[i].weight > reasons[j].weight
+	})
+	texts := make([]string, len(reasons))
+	for i, r := range reasons {
+		texts[i] = r.text
+	}
+
+	candidate.Score = nil
+	return SimilarPaper{
+		Paper:   candidate,
+		Score:   score / totalWeight,
+		Signals: signals,
+		Reasons: texts,
+	}, true
+}
+
+// identifierSet returns the normalized identifiers as a set
+func identifierSet(identifiers []string) map[string]bool {
+	set := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		if normalized := models.NormalizeCitationID(identifier); normalized != "" {
+			set[normalized] = true
+		}
+	}
+	return set
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
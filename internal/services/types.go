@@ -9,17 +9,66 @@ import (
 
 // SearchRequest represents a search request from the API layer
 type SearchRequest struct {
-	RequestID string            `json:"request_id" validate:"required"`
-	Query     string            `json:"query" validate:"required,min=1,max=1000"`
-	Limit     int               `json:"limit,omitempty" validate:"min=1,max=100"`
-	Offset    int               `json:"offset,omitempty" validate:"min=0"`
-	Providers []string          `json:"providers,omitempty"`
-	Filters   map[string]string `json:"filters,omitempty"`
-	DateFrom  *time.Time        `json:"date_from,omitempty"`
-	DateTo    *time.Time        `json:"date_to,omitempty"`
-	UserID    *string           `json:"user_id,omitempty"`
+	RequestID    string              `json:"request_id" validate:"required"`
+	Query        string              `json:"query" validate:"required,min=1,max=1000"`
+	Limit        int                 `json:"limit,omitempty" validate:"min=1,max=100"`
+	Offset       int                 `json:"offset,omitempty" validate:"min=0"`
+	Cursor       string              `json:"cursor,omitempty"` // NextCursor of the previous page, continues every provider where it left off
+	Providers    []string            `json:"providers,omitempty"`
+	Filters      map[string]string   `json:"filters,omitempty"`
+	FacetFilters map[string][]string `json:"facet_filters,omitempty"` // applied to the merged results, see models.FacetNames
+	DateFrom     *time.Time          `json:"date_from,omitempty"`
+	DateTo       *time.Time          `json:"date_to,omitempty"`
+	UserID       *string             `json:"user_id,omitempty"`
+	CacheMode    CacheMode           `json:"cache_mode,omitempty"`
+	Mode         SearchMode          `json:"mode,omitempty"`
+	Fusion       FusionMethod        `json:"fusion,omitempty"` // how hybrid searches combine their rankings
+	Alpha        *float64            `json:"alpha,omitempty"`  // weight of vector similarity in hybrid searches, 0 to 1
 }
 
+// SearchMode selects how a search request finds papers
+type SearchMode string
+
+const (
+	// SearchModeKeyword searches the providers for the query terms
+	SearchModeKeyword SearchMode = ""
+
+	// SearchModeSemantic searches the stored papers by embedding similarity
+	// to the query
+	SearchModeSemantic SearchMode = "semantic"
+
+	// SearchModeHybrid searches the stored papers by full text and by
+	// embedding similarity and fuses both rankings
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// FusionMethod selects how a hybrid search combines the full-text and
+// vector rankings
+type FusionMethod string
+
+const (
+	// FusionReciprocalRank scores papers by their ranks, weight / (k + rank)
+	FusionReciprocalRank FusionMethod = "rrf"
+
+	// FusionWeighted scores papers by the weighted sum of their min-max
+	// normalized full-text and vector scores
+	FusionWeighted FusionMethod = "weighted"
+)
+
+// CacheMode controls how a search request uses the durable result cache
+type CacheMode string
+
+const (
+	// CacheModeDefault serves cached results when available and caches new ones
+	CacheModeDefault CacheMode = ""
+
+	// CacheModeBypass neither reads nor writes the result cache
+	CacheModeBypass CacheMode = "bypass"
+
+	// CacheModeRefresh skips the cache lookup but stores the fresh results
+	CacheModeRefresh CacheMode = "refresh"
+)
+
 // SearchResponse represents a search response to the API layer
 type SearchResponse struct {
 	RequestID            string                   `json:"request_id"`
@@ -29,9 +78,18 @@ type SearchResponse struct {
 	ResultCount         int                      `json:"result_count"`
 	ProvidersUsed       []string                 `json:"providers_used"`
 	ProvidersFailed     []string                 `json:"providers_failed,omitempty"`
+	ProvidersPending    []string                 `json:"providers_pending,omitempty"` // had not answered when the results were returned
+	FilterSupport       map[string]providers.FilterSupport `json:"filter_support,omitempty"` // how each provider applied the filters
 	Duration            time.Duration            `json:"duration"`
 	AggregationStrategy string                   `json:"aggregation_strategy"`
+	StrategyMetadata    map[string]interface{}   `json:"strategy_metadata,omitempty"`
 	CacheHits           int                      `json:"cache_hits"`
+	CacheTier           string                   `json:"cache_tier,omitempty"`
+	HasMore             bool                     `json:"has_more"`
+	NextCursor          *string                  `json:"next_cursor,omitempty"` // pass as cursor to fetch the next page
+	Scores              map[string]HybridScore   `json:"scores,omitempty"` // score breakdown of each paper by ID, set by hybrid searches
+	Facets              *models.SearchFacets     `json:"facets,omitempty"`
+	AppliedFacets       map[string][]string      `json:"applied_facets,omitempty"`
 	PartialFailure      bool                     `json:"partial_failure"`
 	Errors              []providers.ProviderError `json:"errors,omitempty"`
 	Timestamp           time.Time                `json:"timestamp"`
@@ -202,6 +260,10 @@ func (r *SearchRequest) ValidateSearchRequest() error {
 		return NewValidationError("offset must be non-negative")
 	}
 
+	if r.Cursor != "" && r.Offset > 0 {
+		return NewValidationError("offset cannot be combined with cursor")
+	}
+
 	// Validate date range
 	if r.DateFrom != nil && r.DateTo != nil {
 		if r.DateFrom.After(*r.DateTo) {
@@ -215,6 +277,10 @@ func (r *SearchRequest) ValidateSearchRequest() error {
 		"semantic_scholar": true,
 		"exa":              true,
 		"tavily":           true,
+		"crossref":         true,
+		"openalex":         true,
+		"pubmed":           true,
+		"local":            true,
 	}
 
 	for _, provider := range r.Providers {
@@ -223,6 +289,34 @@ func (r *SearchRequest) ValidateSearchRequest() error {
 		}
 	}
 
+	switch r.CacheMode {
+	case CacheModeDefault, CacheModeBypass, CacheModeRefresh:
+	default:
+		return NewValidationError("invalid cache mode: " + string(r.CacheMode))
+	}
+
+	switch r.Mode {
+	case SearchModeKeyword, SearchModeSemantic, SearchModeHybrid:
+	default:
+		return NewValidationError("invalid search mode: " + string(r.Mode))
+	}
+
+	switch r.Fusion {
+	case "", FusionReciprocalRank, FusionWeighted:
+	default:
+		return NewValidationError("invalid fusion method: " + string(r.Fusion))
+	}
+
+	if r.Alpha != nil && (*r.Alpha < 0 || *r.Alpha > 1) {
+		return NewValidationError("alpha must be between 0 and 1")
+	}
+
+	for facet := range r.FacetFilters {
+		if !models.IsValidFacet(facet) {
+			return NewValidationError("invalid facet: " + facet)
+		}
+	}
+
 	return nil
 }
 
@@ -243,7 +337,7 @@ func (r *SearchRequest) SetDefaults() {
 
 // GetValidProviders returns the list of valid provider names
 func GetValidProviders() []string {
-	return []string{"arxiv", "semantic_scholar", "exa", "tavily"}
+	return []string{"arxiv", "semantic_scholar", "exa", "tavily", "crossref", "openalex", "pubmed", "local"}
 }
 
 // GetValidTimeRanges returns the list of valid time ranges for analytics
@@ -0,0 +1,165 @@
+package mocks
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+)
+
+// StubSearchProvider is a configurable in-memory search provider for tests
+type StubSearchProvider struct {
+	ProviderName string
+	Enabled      bool
+	Papers       []models.Paper
+	Err          error
+	Errs         []error // errors of the first calls, Err applies afterwards
+	Delay        time.Duration
+	Delays       []time.Duration // delays of the first calls, Delay applies afterwards
+	Capabilities providers.ProviderCapabilities
+	Status       providers.ProviderStatus
+
+	// Paged makes Search return Limit papers from Offset, or from the
+	// position in Cursor when CursorPaging is set, and report HasMore
+	Paged        bool
+	CursorPaging bool
+
+	mu      sync.Mutex
+	calls   int
+	queries []*providers.SearchQuery
+}
+
+// NewStubSearchProvider creates an enabled stub provider returning the given papers
+func NewStubSearchProvider(name string, papers ...models.Paper) *StubSearchProvider {
+	return &StubSearchProvider{
+		ProviderName: name,
+		Enabled:      true,
+		Papers:       papers,
+		Status:       providers.ProviderStatus{Name: name, Enabled: true, Healthy: true, CircuitState: "closed"},
+	}
+}
+
+func (s *StubSearchProvider) Name() string    { return s.ProviderName }
+func (s *StubSearchProvider) IsEnabled() bool { return s.Enabled }
+
+func (s *StubSearchProvider) GetCapabilities() providers.ProviderCapabilities {
+	return s.Capabilities
+}
+
+func (s *StubSearchProvider) Search(ctx context.Context, query *providers.SearchQuery) (*providers.SearchResult, error) {
+	s.mu.Lock()
+	delay, err := s.Delay, s.Err
+	if s.calls < len(s.Delays) {
+		delay = s.Delays[s.calls]
+	}
+	if s.calls < len(s.Errs) {
+		err = s.Errs[s.calls]
+	}
+	s.calls++
+	s.queries = append(s.queries, query)
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Paged {
+		return s.page(query), nil
+	}
+
+	papers := make([]models.Paper, len(s.Papers))
+	copy(papers, s.Papers)
+
+	return &providers.SearchResult{
+		Papers:      papers,
+		TotalCount:  len(papers),
+		ResultCount: len(papers),
+		Query:       query.Query,
+		Provider:    s.ProviderName,
+		Duration:    delay,
+		RequestID:   query.RequestID,
+		Timestamp:   time.Now(),
+		Success:     true,
+	}, nil
+}
+
+// page returns one page of the stub papers
+func (s *StubSearchProvider) page(query *providers.SearchQuery) *providers.SearchResult {
+	start := query.Offset
+	if s.CursorPaging {
+		start, _ = strconv.Atoi(strings.TrimPrefix(query.Cursor, "pos-"))
+	}
+	start = min(start, len(s.Papers))
+	end := min(start+query.Limit, len(s.Papers))
+
+	papers := make([]models.Paper, end-start)
+	copy(papers, s.Papers[start:end])
+
+	result := &providers.SearchResult{
+		Papers:      papers,
+		TotalCount:  len(s.Papers),
+		ResultCount: len(papers),
+		Query:       query.Query,
+		Provider:    s.ProviderName,
+		RequestID:   query.RequestID,
+		Timestamp:   time.Now(),
+		HasMore:     end < len(s.Papers),
+		Success:     true,
+	}
+	if s.CursorPaging && result.HasMore {
+		next := "pos-" + strconv.Itoa(end)
+		result.NextCursor = &next
+	}
+	return result
+}
+
+func (s *StubSearchProvider) GetPaper(ctx context.Context, id string) (*models.Paper, error) {
+	for i := range s.Papers {
+		if s.Papers[i].ID == id {
+			paper := s.Papers[i]
+			return &paper, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("paper", id)
+}
+
+func (s *StubSearchProvider) HealthCheck(ctx context.Context) error { return s.Err }
+
+func (s *StubSearchProvider) GetStatus() providers.ProviderStatus { return s.Status }
+
+func (s *StubSearchProvider) GetMetrics() providers.ProviderMetrics {
+	return providers.ProviderMetrics{}
+}
+
+func (s *StubSearchProvider) Configure(config providers.ProviderConfig) error {
+	s.Enabled = config.Enabled
+	return nil
+}
+
+func (s *StubSearchProvider) ValidateConfig(config providers.ProviderConfig) error { return nil }
+
+// Calls returns how many times Search was called
+func (s *StubSearchProvider) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// Queries returns the queries received by Search
+func (s *StubSearchProvider) Queries() []*providers.SearchQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*providers.SearchQuery(nil), s.queries...)
+}
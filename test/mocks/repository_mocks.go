@@ -63,6 +63,11 @@ func (m *MockPaperRepository) SearchByVector(ctx context.Context, embedding []fl
 	return args.Get(0).([]models.Paper), args.Error(1)
 }
 
+func (m *MockPaperRepository) GetByVectorMatches(ctx context.Context, matches []repository.VectorMatch) ([]models.Paper, error) {
+	args := m.Called(ctx, matches)
+	return args.Get(0).([]models.Paper), args.Error(1)
+}
+
 func (m *MockPaperRepository) SearchFullText(ctx context.Context, query string, filters *models.PaperFilter, limit, offset int) ([]models.Paper, int64, error) {
 	args := m.Called(ctx, query, filters, limit, offset)
 	return args.Get(0).([]models.Paper), args.Get(1).(int64), args.Error(2)
@@ -73,6 +78,11 @@ func (m *MockPaperRepository) CreateBatch(ctx context.Context, papers []models.P
 	return args.Error(0)
 }
 
+func (m *MockPaperRepository) UpsertMetadataBatch(ctx context.Context, papers []models.Paper) error {
+	args := m.Called(ctx, papers)
+	return args.Error(0)
+}
+
 func (m *MockPaperRepository) UpdateBatch(ctx context.Context, papers []models.Paper) error {
 	args := m.Called(ctx, papers)
 	return args.Error(0)
@@ -106,6 +116,16 @@ func (m *MockPaperRepository) UpdateProcessingState(ctx context.Context, paperID
 	return args.Error(0)
 }
 
+func (m *MockPaperRepository) GetPapersWithoutEmbedding(ctx context.Context, limit int) ([]models.Paper, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.Paper), args.Error(1)
+}
+
+func (m *MockPaperRepository) UpdateEmbedding(ctx context.Context, paperID string, embedding []float32) error {
+	args := m.Called(ctx, paperID, embedding)
+	return args.Error(0)
+}
+
 func (m *MockPaperRepository) GetProcessingStats(ctx context.Context) (*repository.ProcessingStats, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -124,8 +144,18 @@ func (m *MockPaperRepository) GetCategoryPapers(ctx context.Context, categoryID
 	return args.Get(0).([]models.Paper), args.Error(1)
 }
 
-func (m *MockPaperRepository) GetSimilarPapers(ctx context.Context, paperID string, limit int) ([]models.Paper, error) {
-	args := m.Called(ctx, paperID, limit)
+func (m *MockPaperRepository) GetPapersByAuthors(ctx context.Context, authorIDs []string, limit int) ([]models.Paper, error) {
+	args := m.Called(ctx, authorIDs, limit)
+	return args.Get(0).([]models.Paper), args.Error(1)
+}
+
+func (m *MockPaperRepository) GetByIdentifiers(ctx context.Context, identifiers []string, limit int) ([]models.Paper, error) {
+	args := m.Called(ctx, identifiers, limit)
+	return args.Get(0).([]models.Paper), args.Error(1)
+}
+
+func (m *MockPaperRepository) GetPapersReferencing(ctx context.Context, identifiers []string, limit int) ([]models.Paper, error) {
+	args := m.Called(ctx, identifiers, limit)
 	return args.Get(0).([]models.Paper), args.Error(1)
 }
 
@@ -452,8 +482,8 @@ func (m *MockSearchRepository) GetSearchHistory(ctx context.Context, userID *str
 	return args.Get(0).([]models.SearchHistory), args.Error(1)
 }
 
-func (m *MockSearchRepository) GetPopularQueries(ctx context.Context, since time.Time, limit int) ([]repository.QueryStats, error) {
-	args := m.Called(ctx, since, limit)
+func (m *MockSearchRepository) GetPopularQueries(ctx context.Context, from, to time.Time, limit int) ([]repository.QueryStats, error) {
+	args := m.Called(ctx, from, to, limit)
 	return args.Get(0).([]repository.QueryStats), args.Error(1)
 }
 
@@ -520,4 +550,12 @@ func (m *MockSearchRepository) GetProviderPerformance(ctx context.Context, provi
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*repository.ProviderPerformance), args.Error(1)
+}
+
+func (m *MockSearchRepository) GetProvidersPerformance(ctx context.Context, from, to time.Time) ([]repository.ProviderPerformance, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ProviderPerformance), args.Error(1)
 }
\ No newline at end of file
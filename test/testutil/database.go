@@ -66,11 +66,13 @@ func setupPostgresContainer(t *testing.T, ctx context.Context) *DatabaseTestUtil
 	// Auto-migrate models
 	err = db.AutoMigrate(
 		&models.Paper{},
+		&models.PaperCitation{},
 		&models.Author{},
 		&models.Category{},
 		&models.SearchHistory{},
 		&models.SearchCache{},
 		&models.SearchSuggestion{},
+		&models.QuerySuggestion{},
 	)
 	require.NoError(t, err)
 
@@ -96,11 +98,13 @@ func setupSQLiteInMemory(t *testing.T) *DatabaseTestUtil {
 	// Auto-migrate models
 	err = db.AutoMigrate(
 		&models.Paper{},
+		&models.PaperCitation{},
 		&models.Author{},
 		&models.Category{},
 		&models.SearchHistory{},
 		&models.SearchCache{},
 		&models.SearchSuggestion{},
+		&models.QuerySuggestion{},
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,73 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scifind-backend/internal/models"
+)
+
+func facetPaper(id string, year int, journal string, provider string, categories ...string) models.Paper {
+	published := time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+	paper := models.Paper{
+		ID:             id,
+		Title:          id,
+		PublishedAt:    &published,
+		Language:       "en",
+		SourceProvider: provider,
+		Authors:        []models.Author{{Name: "Ada Lovelace"}},
+	}
+	if journal != "" {
+		paper.Journal = &journal
+	}
+	for _, code := range categories {
+		paper.Categories = append(paper.Categories, models.Category{ID: "arxiv_" + code, Name: code, SourceCode: code})
+	}
+	return paper
+}
+
+func TestNewSearchFacets(t *testing.T) {
+	merged := facetPaper("b", 2021, "", "semantic_scholar")
+	merged.AddSource(models.PaperSource{Provider: "arxiv", SourceID: "2101.00001"})
+	merged.AddSource(models.PaperSource{Provider: "semantic_scholar", SourceID: "abc"})
+
+	papers := []models.Paper{
+		facetPaper("a", 2021, "Nature", "arxiv", "cs.LG", "cs.AI"),
+		merged,
+		facetPaper("c", 2020, "Nature", "arxiv", "cs.LG"),
+	}
+
+	facets := models.NewSearchFacets(papers)
+
+	assert.Equal(t, map[string]int{"2021": 2, "2020": 1}, facets.Years)
+	assert.Equal(t, map[string]int{"cs.LG": 2, "cs.AI": 1}, facets.Categories)
+	assert.Equal(t, map[string]int{"Nature": 2}, facets.Journals)
+	assert.Equal(t, map[string]int{"Ada Lovelace": 3}, facets.Authors)
+	assert.Equal(t, map[string]int{"en": 3}, facets.Languages)
+	assert.Equal(t, map[string]int{"arxiv": 3, "semantic_scholar": 1}, facets.Providers)
+}
+
+func TestPaper_MatchesFacets(t *testing.T) {
+	paper := facetPaper("a", 2021, "Nature", "arxiv", "cs.LG")
+
+	tests := []struct {
+		name       string
+		selections map[string][]string
+		expected   bool
+	}{
+		{"no selections", nil, true},
+		{"matching year", map[string][]string{models.FacetYear: {"2021"}}, true},
+		{"any value within a facet", map[string][]string{models.FacetYear: {"2019", "2021"}}, true},
+		{"case insensitive", map[string][]string{models.FacetCategory: {"CS.lg"}}, true},
+		{"all facets must match", map[string][]string{models.FacetYear: {"2021"}, models.FacetJournal: {"Science"}}, false},
+		{"missing value", map[string][]string{models.FacetProvider: {"exa"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, paper.MatchesFacets(tt.selections))
+		})
+	}
+}
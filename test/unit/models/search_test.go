@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scifind-backend/internal/models"
+)
+
+func TestQuerySuggestion_RecordSearch(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	suggestion := &models.QuerySuggestion{Query: "graph networks"}
+	suggestion.RecordSearch(5, start)
+	assert.Equal(t, 1.0, suggestion.Weight)
+	assert.EqualValues(t, 1, suggestion.SearchCount)
+	assert.Equal(t, 5, suggestion.LastResultCount)
+
+	// A week later the previous weight has halved before the new boost
+	suggestion.RecordSearch(3, start.Add(7*24*time.Hour))
+	assert.InDelta(t, 1.5, suggestion.Weight, 1e-9)
+	assert.EqualValues(t, 2, suggestion.SearchCount)
+
+	// Searches without results decay the weight but do not boost it
+	suggestion.RecordSearch(0, start.Add(14*24*time.Hour))
+	assert.InDelta(t, 0.75, suggestion.Weight, 1e-9)
+	assert.EqualValues(t, 3, suggestion.SearchCount)
+	assert.Equal(t, 0, suggestion.LastResultCount)
+}
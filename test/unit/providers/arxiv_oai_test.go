@@ -0,0 +1,155 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/arxiv"
+)
+
+// newOAIServer replays the recorded OAI-PMH responses in testdata/arxiv. The
+// econ set is unavailable for the first unavailable requests.
+func newOAIServer(t *testing.T, unavailable int) (*httptest.Server, func() []*url.URL) {
+	t.Helper()
+	fixture := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("testdata", "arxiv", name))
+		require.NoError(t, err)
+		return data
+	}
+
+	var mu sync.Mutex
+	var requests []*url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL)
+		count := len(requests)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/xml")
+
+		query := r.URL.Query()
+		switch {
+		case query.Get("set") == "econ" && count <= unavailable:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case query.Get("resumptionToken") == "6960524|1001":
+			_, _ = w.Write(fixture("listrecords_page2.xml"))
+		case query.Get("resumptionToken") != "":
+			_, _ = w.Write(fixture("bad_token.xml"))
+		case query.Get("set") == "math":
+			_, _ = w.Write(fixture("no_records.xml"))
+		default:
+			_, _ = w.Write(fixture("listrecords_page1.xml"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []*url.URL {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*url.URL(nil), requests...)
+	}
+}
+
+func newOAIHarvester(baseURL string) *arxiv.Harvester {
+	return arxiv.NewHarvester(providers.ProviderConfig{
+		Enabled:    true,
+		BaseURL:    baseURL,
+		Timeout:    5 * time.Second,
+		MaxRetries: 2,
+		RateLimit:  fastLimits,
+	}, newTestLogger())
+}
+
+func TestArxivHarvester_ListRecords(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newOAIServer(t, 0)
+	harvester := newOAIHarvester(server.URL)
+
+	day := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	page, err := harvester.ListRecords(ctx, arxiv.HarvestRequest{Set: "cs", From: &day, Until: &day})
+	require.NoError(t, err)
+
+	params := requests()[0].Query()
+	assert.Equal(t, "ListRecords", params.Get("verb"))
+	assert.Equal(t, "arXiv", params.Get("metadataPrefix"))
+	assert.Equal(t, "cs", params.Get("set"))
+	assert.Equal(t, "2024-05-01", params.Get("from"))
+	assert.Equal(t, "2024-05-01", params.Get("until"))
+
+	assert.Equal(t, "6960524|1001", page.ResumptionToken)
+	assert.Equal(t, 3, page.CompleteListSize)
+	assert.Equal(t, []string{"cs/0112017"}, page.Deleted)
+	require.Len(t, page.Papers, 1)
+
+	paper := page.Papers[0]
+	assert.Equal(t, "arxiv_1706.03762", paper.ID)
+	assert.Equal(t, "1706.03762", *paper.ArxivID)
+	assert.Equal(t, "Attention Is All You Need", paper.Title)
+	assert.Equal(t, "10.5555/3295222.3295349", *paper.DOI)
+	assert.Equal(t, "Advances in Neural Information Processing Systems 30 (2017)", *paper.Journal)
+	assert.Equal(t, time.Date(2017, time.June, 12, 0, 0, 0, 0, time.UTC), *paper.PublishedAt)
+	assert.Equal(t, "https://arxiv.org/pdf/1706.03762", *paper.PDFURL)
+	assert.Equal(t, "arxiv", paper.SourceProvider)
+	require.Len(t, paper.Authors, 2)
+	assert.Equal(t, "Ashish Vaswani", paper.Authors[0].Name)
+	assert.Equal(t, "Google Brain", *paper.Authors[0].Affiliation)
+	require.Len(t, paper.Categories, 2)
+	assert.Equal(t, "arxiv_cs.CL", paper.Categories[0].ID)
+	assert.Equal(t, "Computation and Language", paper.Categories[0].Name)
+
+	// Resumption tokens replace the other arguments
+	page, err = harvester.ListRecords(ctx, arxiv.HarvestRequest{Set: "cs", ResumptionToken: page.ResumptionToken})
+	require.NoError(t, err)
+	params = requests()[1].Query()
+	assert.Equal(t, "6960524|1001", params.Get("resumptionToken"))
+	assert.Empty(t, params.Get("set"))
+	assert.Empty(t, params.Get("metadataPrefix"))
+
+	assert.Empty(t, page.ResumptionToken)
+	require.Len(t, page.Papers, 1)
+	assert.Equal(t, "Jane Smith Jr", page.Papers[0].Authors[0].Name)
+}
+
+func TestArxivHarvester_ListRecordsErrors(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newOAIServer(t, 0)
+	harvester := newOAIHarvester(server.URL)
+
+	page, err := harvester.ListRecords(ctx, arxiv.HarvestRequest{Set: "math"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Papers)
+	assert.Empty(t, page.ResumptionToken)
+
+	_, err = harvester.ListRecords(ctx, arxiv.HarvestRequest{ResumptionToken: "expired|1"})
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = harvester.ListRecords(ctx, arxiv.HarvestRequest{Set: "cs&verb=Identify"})
+	assert.True(t, errors.IsValidationError(err))
+	assert.Len(t, requests(), 2)
+}
+
+func TestArxivHarvester_RetriesWhenUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	server, requests := newOAIServer(t, 2)
+	page, err := newOAIHarvester(server.URL).ListRecords(ctx, arxiv.HarvestRequest{Set: "econ"})
+	require.NoError(t, err)
+	assert.Len(t, page.Papers, 1)
+	assert.Len(t, requests(), 3)
+
+	server, _ = newOAIServer(t, 3)
+	_, err = newOAIHarvester(server.URL).ListRecords(ctx, arxiv.HarvestRequest{Set: "econ"})
+	assert.True(t, errors.IsNetworkError(err))
+}
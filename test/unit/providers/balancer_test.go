@@ -0,0 +1,117 @@
+package providers_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func TestManager_RoundRobin(t *testing.T) {
+	ctx := context.Background()
+	config := providers.ManagerConfig{AggregationStrategy: providers.StrategyRoundRobin}
+
+	t.Run("cycles through providers across requests", func(t *testing.T) {
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", testPaper("a", "A")),
+			mocks.NewStubSearchProvider("exa", testPaper("e", "E")),
+			mocks.NewStubSearchProvider("semantic_scholar", testPaper("s", "S")))
+
+		var selected []string
+		for i := 0; i < 4; i++ {
+			result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+			require.NoError(t, err)
+			selected = append(selected, result.StrategyMetadata["selected_provider"].(string))
+		}
+
+		assert.Equal(t, []string{"arxiv", "exa", "semantic_scholar", "arxiv"}, selected)
+	})
+
+	t.Run("gives concurrent requests different providers", func(t *testing.T) {
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", testPaper("a", "A")),
+			mocks.NewStubSearchProvider("exa", testPaper("e", "E")),
+			mocks.NewStubSearchProvider("semantic_scholar", testPaper("s", "S")))
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		var selected []string
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+				if !assert.NoError(t, err) {
+					return
+				}
+				mu.Lock()
+				selected = append(selected, result.StrategyMetadata["selected_provider"].(string))
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.ElementsMatch(t, []string{"arxiv", "exa", "semantic_scholar"}, selected)
+	})
+
+	t.Run("skips open circuits and rate limited providers", func(t *testing.T) {
+		arxiv := mocks.NewStubSearchProvider("arxiv", testPaper("a", "A"))
+		arxiv.Status.CircuitState = string(providers.CircuitOpen)
+		exa := mocks.NewStubSearchProvider("exa", testPaper("e", "E"))
+		reset := time.Now().Add(time.Minute)
+		exa.Status.RateLimited = true
+		exa.Status.ResetTime = &reset
+		ss := mocks.NewStubSearchProvider("semantic_scholar", testPaper("s", "S"))
+
+		manager := newTestManager(config, arxiv, exa, ss)
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "semantic_scholar", result.StrategyMetadata["selected_provider"])
+		assert.Equal(t, map[string]string{"arxiv": "circuit_open", "exa": "rate_limited"}, result.StrategyMetadata["skipped_providers"])
+		assert.Zero(t, arxiv.Calls())
+		assert.Zero(t, exa.Calls())
+	})
+
+	t.Run("falls back to the next provider on failure", func(t *testing.T) {
+		arxiv := mocks.NewStubSearchProvider("arxiv")
+		arxiv.Err = assert.AnError
+		ss := mocks.NewStubSearchProvider("semantic_scholar", testPaper("s", "S"))
+
+		manager := newTestManager(config, arxiv, ss)
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "semantic_scholar", result.StrategyMetadata["selected_provider"])
+		assert.Equal(t, []string{"arxiv"}, result.FailedProviders)
+		assert.True(t, result.PartialFailure)
+	})
+}
+
+func TestManager_WeightedRandom(t *testing.T) {
+	ctx := context.Background()
+
+	arxiv := mocks.NewStubSearchProvider("arxiv", testPaper("a", "A"))
+	exa := mocks.NewStubSearchProvider("exa", testPaper("e", "E"))
+	manager := newTestManager(providers.ManagerConfig{
+		AggregationStrategy: providers.StrategyWeightedRandom,
+		SelectionWeights:    map[string]float64{"arxiv": 9, "exa": 1},
+	}, arxiv, exa)
+
+	counts := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+		assert.Equal(t, string(providers.StrategyWeightedRandom), result.AggregationStrategy)
+		counts[result.StrategyMetadata["selected_provider"].(string)]++
+	}
+
+	assert.Greater(t, counts["arxiv"], counts["exa"])
+	assert.Equal(t, 200, counts["arxiv"]+counts["exa"])
+}
@@ -0,0 +1,113 @@
+package providers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func TestCacheKey(t *testing.T) {
+	t.Run("normalizes equivalent queries", func(t *testing.T) {
+		first := providers.NewSearchQuery("  Deep   Learning ")
+		first.Categories = []string{"cs.LG", "cs.AI"}
+		first.Filters["journal"] = "Nature"
+
+		second := providers.NewSearchQuery("deep learning")
+		second.Categories = []string{"cs.ai", "cs.lg"}
+		second.Filters["journal"] = "nature"
+
+		assert.Equal(t, providers.CacheKey(first, "arxiv"), providers.CacheKey(second, "arxiv"))
+		assert.True(t, strings.HasPrefix(providers.CacheKey(first, "arxiv"), "search.arxiv."))
+	})
+
+	t.Run("distinguishes providers and pages", func(t *testing.T) {
+		query := providers.NewSearchQuery("deep learning")
+		paged := providers.NewSearchQuery("deep learning")
+		paged.Offset = 20
+
+		assert.NotEqual(t, providers.CacheKey(query, "arxiv"), providers.CacheKey(query, "exa"))
+		assert.NotEqual(t, providers.CacheKey(query, "arxiv"), providers.CacheKey(paged, "arxiv"))
+	})
+}
+
+func TestCacheManager_InMemory(t *testing.T) {
+	ctx := context.Background()
+	result := &providers.SearchResult{Papers: nil, TotalCount: 3, Provider: "arxiv", Success: true}
+
+	t.Run("stores and expires results", func(t *testing.T) {
+		cache := providers.NewCacheManager(nil, providers.CacheConfig{}, newTestLogger())
+		require.NoError(t, cache.Set(ctx, "search.arxiv.a", result, 20*time.Millisecond))
+
+		cached, err := cache.Get(ctx, "search.arxiv.a")
+		require.NoError(t, err)
+		assert.Equal(t, 3, cached.Result.TotalCount)
+
+		time.Sleep(30 * time.Millisecond)
+		_, err = cache.Get(ctx, "search.arxiv.a")
+		assert.Error(t, err)
+
+		stats := cache.GetStats()
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(1), stats.Misses)
+	})
+
+	t.Run("evicts least recently used entries", func(t *testing.T) {
+		cache := providers.NewCacheManager(nil, providers.CacheConfig{MaxSize: 2}, newTestLogger())
+		require.NoError(t, cache.Set(ctx, "a", result, 0))
+		require.NoError(t, cache.Set(ctx, "b", result, 0))
+		_, err := cache.Get(ctx, "a")
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(ctx, "c", result, 0))
+
+		_, err = cache.Get(ctx, "b")
+		assert.Error(t, err)
+		_, err = cache.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), cache.GetStats().Evictions)
+	})
+
+	t.Run("clears keys matching a pattern", func(t *testing.T) {
+		cache := providers.NewCacheManager(nil, providers.CacheConfig{}, newTestLogger())
+		require.NoError(t, cache.Set(ctx, "search.arxiv.a", result, 0))
+		require.NoError(t, cache.Set(ctx, "search.exa.b", result, 0))
+
+		require.NoError(t, cache.Clear(ctx, "search.arxiv.*"))
+		_, err := cache.Get(ctx, "search.arxiv.a")
+		assert.Error(t, err)
+		_, err = cache.Get(ctx, "search.exa.b")
+		assert.NoError(t, err)
+
+		require.NoError(t, cache.Clear(ctx, ""))
+		assert.Equal(t, 0, cache.GetStats().Size)
+	})
+}
+
+func TestManager_ServesCacheHits(t *testing.T) {
+	ctx := context.Background()
+	stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "A"))
+	manager := newTestManager(providers.ManagerConfig{
+		AggregationStrategy: providers.StrategyReciprocalRank,
+		Cache:               providers.NewCacheManager(nil, providers.CacheConfig{}, newTestLogger()),
+	}, stub)
+
+	first, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, first.CacheHits)
+
+	second, err := manager.SearchAll(ctx, providers.NewSearchQuery("TEST"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.CacheHits)
+	assert.True(t, second.ProviderResults["arxiv"].CacheHit)
+	assert.Equal(t, paperTitles(first.Papers), paperTitles(second.Papers))
+
+	assert.Equal(t, 1, stub.Calls())
+	assert.Equal(t, int64(1), manager.GetProviderMetrics()["arxiv"].CachedRequests)
+	assert.Equal(t, int64(1), manager.GetCacheStats().Hits)
+}
@@ -0,0 +1,147 @@
+package providers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func datedPaper(id, title string, year int, author, category string) models.Paper {
+	published := time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC)
+	paper := testPaper(id, title)
+	paper.PublishedAt = &published
+	paper.Authors = []models.Author{{Name: author}}
+	paper.Categories = []models.Category{{SourceCode: category}}
+	return paper
+}
+
+func TestNegotiateFilters(t *testing.T) {
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	query := providers.NewSearchQuery("graphs")
+	query.Limit = 50
+	query.DateFrom = &from
+	query.Authors = []string{"Hinton"}
+	query.Filters = map[string]string{providers.FilterCategory: "cs.LG", providers.FilterJournal: "Nature"}
+
+	t.Run("passes supported filters to the provider", func(t *testing.T) {
+		caps := providers.ProviderCapabilities{SupportsDateFilter: true, SupportsAuthFilter: true, MaxResults: 20}
+		negotiated, support, err := providers.NegotiateFilters("arxiv", caps, query)
+		require.NoError(t, err)
+
+		assert.Equal(t, 20, negotiated.Limit)
+		assert.Equal(t, &from, negotiated.DateFrom)
+		assert.Nil(t, negotiated.Authors)
+		assert.Equal(t, map[string]string{providers.FilterAuthor: "Hinton", providers.FilterJournal: "Nature"}, negotiated.Filters)
+		assert.Equal(t, []string{providers.FilterDateFrom, providers.FilterAuthor}, support.Native)
+		assert.Equal(t, []string{providers.FilterCategory}, support.PostFiltered)
+
+		// The caller's query is left alone
+		assert.Equal(t, 50, query.Limit)
+		assert.Equal(t, "cs.LG", query.Filters[providers.FilterCategory])
+	})
+
+	t.Run("queries without filters have no support", func(t *testing.T) {
+		_, support, err := providers.NegotiateFilters("arxiv", providers.ProviderCapabilities{}, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Nil(t, support)
+	})
+
+	t.Run("rejects queries longer than the provider accepts", func(t *testing.T) {
+		caps := providers.ProviderCapabilities{MaxQueryLength: 10}
+		_, _, err := providers.NegotiateFilters("arxiv", caps, providers.NewSearchQuery(strings.Repeat("graph ", 3)))
+		assert.True(t, errors.IsUnsupportedQueryError(err))
+	})
+}
+
+func TestManager_FilterNegotiation(t *testing.T) {
+	ctx := context.Background()
+	config := providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge}
+	papers := []models.Paper{
+		datedPaper("a", "Old", 2015, "Geoffrey Hinton", "cs.LG"),
+		datedPaper("b", "New", 2022, "Yann LeCun", "cs.CV"),
+		testPaper("c", "Undated"),
+	}
+
+	t.Run("post-filters what the provider cannot apply", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("tavily", papers...)
+		manager := newTestManager(config, stub)
+
+		from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+		query := providers.NewSearchQuery("graphs")
+		query.DateFrom = &from
+		result, err := manager.SearchAll(ctx, query)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"New"}, paperTitles(result.Papers))
+		assert.Equal(t, providers.FilterSupport{PostFiltered: []string{providers.FilterDateFrom}, Dropped: 2}, result.FilterSupport["tavily"])
+		assert.Nil(t, stub.Queries()[0].DateFrom)
+	})
+
+	t.Run("leaves supported filters to the provider", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", papers...)
+		stub.Capabilities.SupportsAuthFilter = true
+		other := mocks.NewStubSearchProvider("tavily", papers...)
+		manager := newTestManager(config, stub, other)
+
+		query := providers.NewSearchQuery("graphs")
+		query.Filters = map[string]string{providers.FilterAuthor: "lecun"}
+		result, err := manager.SearchProviders(ctx, query, []string{"arxiv"})
+		require.NoError(t, err)
+		assert.Len(t, result.Papers, 3)
+		assert.Equal(t, []string{providers.FilterAuthor}, result.FilterSupport["arxiv"].Native)
+		assert.Equal(t, "lecun", stub.Queries()[0].Filters[providers.FilterAuthor])
+
+		result, err = manager.SearchProviders(ctx, query, []string{"tavily"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"New"}, paperTitles(result.Papers))
+		assert.Empty(t, other.Queries()[0].Filters)
+	})
+
+	t.Run("skips providers that cannot run the query", func(t *testing.T) {
+		short := mocks.NewStubSearchProvider("arxiv", papers...)
+		short.Capabilities.MaxQueryLength = 5
+		long := mocks.NewStubSearchProvider("pubmed", testPaper("p", "Graphs"))
+		manager := newTestManager(config, short, long)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graph neural networks"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pubmed"}, result.SuccessfulProviders)
+		assert.Equal(t, []string{"arxiv"}, result.FailedProviders)
+		assert.Equal(t, "unsupported", result.Errors[0].Type)
+		assert.Zero(t, short.Calls())
+	})
+
+	t.Run("pages past papers removed by post-filters", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv",
+			datedPaper("a", "A", 2022, "Yann LeCun", "cs.CV"),
+			datedPaper("b", "B", 2015, "Yann LeCun", "cs.CV"),
+			datedPaper("c", "C", 2022, "Yann LeCun", "cs.CV"),
+			datedPaper("d", "D", 2023, "Yann LeCun", "cs.CV"))
+		stub.Paged = true
+		manager := newTestManager(config, stub)
+
+		from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+		query := providers.NewSearchQuery("graphs")
+		query.Limit = 2
+		query.DateFrom = &from
+		first, err := manager.SearchAll(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A"}, paperTitles(first.Papers))
+		require.NotNil(t, first.NextPageToken)
+
+		query.PageToken = *first.NextPageToken
+		second, err := manager.SearchAll(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"C", "D"}, paperTitles(second.Papers))
+		assert.Equal(t, 2, stub.Queries()[1].Offset)
+	})
+}
@@ -0,0 +1,202 @@
+package providers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func testBreakers() *providers.ProviderBreakers {
+	return providers.NewProviderBreakers(errors.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+		Timeout:          50 * time.Millisecond,
+		MaxRequests:      1,
+		MinRequestCount:  2,
+		SlidingWindow:    time.Minute,
+	}, newTestLogger())
+}
+
+func TestManager_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	config := providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge}
+
+	t.Run("opens after failures and closes after a trial success", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Graphs"))
+		stub.Err = errors.NewNetworkError("offline", nil)
+		config := config
+		config.CircuitBreaker = testBreakers()
+		manager := newTestManager(config, stub)
+
+		for i := 0; i < 2; i++ {
+			result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+			require.NoError(t, err)
+			assert.Equal(t, "network", result.Errors[0].Type)
+		}
+
+		// The open circuit rejects calls without reaching the provider
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, "circuit_open", result.Errors[0].Type)
+		assert.Equal(t, 2, stub.Calls())
+		assert.Equal(t, "open", manager.GetProviderStatus()["arxiv"].CircuitState)
+		assert.Equal(t, int64(1), manager.GetProviderMetrics()["arxiv"].CircuitOpenCount)
+
+		// After the timeout a trial request closes the circuit again
+		stub.Err = nil
+		time.Sleep(60 * time.Millisecond)
+		result, err = manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Equal(t, "closed", manager.GetProviderStatus()["arxiv"].CircuitState)
+		assert.Equal(t, int64(1), manager.GetProviderMetrics()["arxiv"].CircuitCloseCount)
+	})
+
+	t.Run("rejected queries do not count as failures", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv")
+		stub.Err = errors.NewValidationError("bad query", "query", "")
+		config := config
+		config.CircuitBreaker = testBreakers()
+		manager := newTestManager(config, stub)
+
+		for i := 0; i < 4; i++ {
+			_, _ = manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		}
+		assert.Equal(t, 4, stub.Calls())
+		assert.Equal(t, "closed", manager.GetProviderStatus()["arxiv"].CircuitState)
+	})
+
+	t.Run("rate limited trials leave the circuit half-open", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Graphs"))
+		stub.Err = errors.NewNetworkError("offline", nil)
+		config := config
+		config.CircuitBreaker = testBreakers()
+		manager := newTestManager(config, stub)
+
+		for i := 0; i < 2; i++ {
+			_, _ = manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		}
+		require.Equal(t, "open", manager.GetProviderStatus()["arxiv"].CircuitState)
+
+		// A rate limited trial neither closes nor reopens the circuit
+		stub.Err = errors.NewRateLimitError("slow down", time.Second)
+		time.Sleep(60 * time.Millisecond)
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, "rate_limit", result.Errors[0].Type)
+		assert.Equal(t, "half_open", manager.GetProviderStatus()["arxiv"].CircuitState)
+		assert.Equal(t, int64(0), manager.GetProviderMetrics()["arxiv"].CircuitCloseCount)
+
+		// The trial it took is free for the next request
+		stub.Err = nil
+		result, err = manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Equal(t, "closed", manager.GetProviderStatus()["arxiv"].CircuitState)
+	})
+
+	t.Run("circuits can be opened and reset by hand", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Graphs"))
+		config := config
+		config.CircuitBreaker = testBreakers()
+		manager := newTestManager(config, stub)
+
+		require.NoError(t, manager.OpenCircuit("arxiv"))
+		time.Sleep(60 * time.Millisecond)
+
+		// A forced circuit stays open past its timeout
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.FailedProviders)
+		assert.Zero(t, stub.Calls())
+
+		require.NoError(t, manager.ResetCircuit("arxiv"))
+		result, err = manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+
+		var notFound *errors.SciFindError
+		require.ErrorAs(t, manager.OpenCircuit("unknown"), &notFound)
+		assert.Equal(t, "NOT_FOUND", notFound.Code)
+	})
+
+	t.Run("circuits cannot be changed without a breaker", func(t *testing.T) {
+		manager := newTestManager(config, mocks.NewStubSearchProvider("arxiv"))
+		assert.True(t, errors.IsValidationError(manager.OpenCircuit("arxiv")))
+		assert.Equal(t, "closed", manager.GetProviderStatus()["arxiv"].CircuitState)
+	})
+}
+
+func TestManager_Retry(t *testing.T) {
+	ctx := context.Background()
+	config := providers.ManagerConfig{
+		AggregationStrategy: providers.StrategyMerge,
+		Retry:               errors.WithFixedDelay(3, time.Millisecond),
+	}
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Graphs"))
+		stub.Errs = []error{errors.NewNetworkError("reset", nil), errors.NewNetworkError("reset", nil)}
+		manager := newTestManager(config, stub)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Equal(t, 3, stub.Calls())
+	})
+
+	t.Run("reports the last error once retries are exhausted", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv")
+		stub.Err = errors.NewNetworkError("offline", nil)
+		manager := newTestManager(config, stub)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, 3, stub.Calls())
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "network", result.Errors[0].Type)
+	})
+
+	t.Run("does not retry rejected queries", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv")
+		stub.Err = errors.NewValidationError("bad query", "query", "")
+		manager := newTestManager(config, stub)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, stub.Calls())
+		assert.Equal(t, "validation", result.Errors[0].Type)
+	})
+
+	t.Run("provider config sets the attempts", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv")
+		stub.Err = errors.NewNetworkError("offline", nil)
+		manager := newTestManager(config, stub)
+		require.NoError(t, manager.UpdateProviderConfig("arxiv", providers.ProviderConfig{Enabled: true, MaxRetries: 1}))
+
+		_, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, stub.Calls())
+	})
+
+	t.Run("paper lookups go through the policy", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Graphs"))
+		manager := newTestManager(config, stub)
+
+		paper, err := manager.GetPaper(ctx, "arxiv", "a")
+		require.NoError(t, err)
+		assert.Equal(t, "Graphs", paper.Title)
+
+		_, err = manager.GetPaper(ctx, "arxiv", "missing")
+		var notFound *errors.SciFindError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, "NOT_FOUND", notFound.Code)
+	})
+}
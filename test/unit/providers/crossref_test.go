@@ -0,0 +1,154 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/crossref"
+)
+
+const crossrefWork = `{
+	"DOI": "10.1038/NATURE14539",
+	"URL": "https://doi.org/10.1038/nature14539",
+	"type": "journal-article",
+	"title": ["Deep learning"],
+	"abstract": "<jats:p>Deep learning allows <jats:italic>computational models</jats:italic> to learn.</jats:p>",
+	"author": [
+		{"given": "Yann", "family": "LeCun", "ORCID": "http://orcid.org/0000-0002-1825-0097", "affiliation": [{"name": "New York University"}]},
+		{"given": "Yoshua", "family": "Bengio"}
+	],
+	"container-title": ["Nature"],
+	"volume": "521",
+	"issue": "7553",
+	"page": "436-444",
+	"published-print": {"date-parts": [[2015, 5]]},
+	"issued": {"date-parts": [[2015, 5, 27]]},
+	"is-referenced-by-count": 50000,
+	"reference": [{"key": "ref1", "DOI": "10.1162/NECO.2006.18.7.1527"}, {"key": "ref2", "unstructured": "Some book"}],
+	"link": [{"URL": "https://www.nature.com/articles/nature14539.pdf", "content-type": "application/pdf"}]
+}`
+
+func newCrossrefServer(t *testing.T, requests *[]*url.URL, userAgents *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL)
+		*userAgents = append(*userAgents, r.UserAgent())
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/works":
+			nextCursor := "cursor-2"
+			if r.URL.Query().Get("cursor") == "cursor-2" {
+				_, _ = w.Write([]byte(`{"status":"ok","message-type":"work-list","message":{"total-results":2,"next-cursor":"cursor-3","items":[]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"ok","message-type":"work-list","message":{"total-results":2,"items-per-page":1,"next-cursor":"` + nextCursor + `","items":[` + crossrefWork + `]}}`))
+		case r.URL.Path == "/works/10.1038/nature14539":
+			_, _ = w.Write([]byte(`{"status":"ok","message-type":"work","message":` + crossrefWork + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Resource not found."))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newCrossrefProvider(baseURL string) *crossref.Provider {
+	return crossref.NewProvider(providers.ProviderConfig{
+		Enabled: true,
+		BaseURL: baseURL,
+		Timeout: 5 * time.Second,
+		Custom:  map[string]interface{}{"mailto": "team@example.org"},
+	}, newTestLogger())
+}
+
+func TestCrossrefProvider_Search(t *testing.T) {
+	ctx := context.Background()
+	var requests []*url.URL
+	var userAgents []string
+	provider := newCrossrefProvider(newCrossrefServer(t, &requests, &userAgents).URL)
+
+	query := providers.NewSearchQuery("deep learning author:lecun year:2015")
+	query.Limit = 1
+	result, err := provider.Search(ctx, query)
+	require.NoError(t, err)
+
+	params := requests[0].Query()
+	assert.Equal(t, "deep learning", params.Get("query"))
+	assert.Equal(t, "lecun", params.Get("query.author"))
+	assert.Equal(t, "from-pub-date:2015,until-pub-date:2015", params.Get("filter"))
+	assert.Equal(t, "*", params.Get("cursor"))
+	assert.Equal(t, "team@example.org", params.Get("mailto"))
+	assert.Contains(t, userAgents[0], "mailto:team@example.org")
+
+	require.Len(t, result.Papers, 1)
+	paper := result.Papers[0]
+	assert.Equal(t, "crossref_10.1038/nature14539", paper.ID)
+	assert.Equal(t, "10.1038/nature14539", *paper.DOI)
+	assert.Equal(t, "Deep learning", paper.Title)
+	assert.Equal(t, "Deep learning allows computational models to learn.", *paper.Abstract)
+	assert.Equal(t, "Nature", *paper.Journal)
+	assert.Equal(t, "521", *paper.Volume)
+	assert.Equal(t, "7553", *paper.Issue)
+	assert.Equal(t, "436-444", *paper.Pages)
+	assert.Equal(t, time.Date(2015, time.May, 1, 0, 0, 0, 0, time.UTC), *paper.PublishedAt)
+	assert.Equal(t, "https://www.nature.com/articles/nature14539.pdf", *paper.PDFURL)
+	assert.Equal(t, 50000, paper.CitationCount)
+	assert.Equal(t, []string{"10.1162/neco.2006.18.7.1527"}, paper.References)
+	assert.Equal(t, "crossref", paper.SourceProvider)
+
+	require.Len(t, paper.Authors, 2)
+	assert.Equal(t, "Yann LeCun", paper.Authors[0].Name)
+	assert.Equal(t, "0000-0002-1825-0097", *paper.Authors[0].ORCID)
+	assert.Equal(t, "New York University", *paper.Authors[0].Affiliation)
+
+	// The next page continues from the returned cursor
+	assert.True(t, result.HasMore)
+	require.NotNil(t, result.NextCursor)
+	next := providers.NewSearchQuery("deep learning")
+	next.Limit = 1
+	next.Cursor = *result.NextCursor
+	page, err := provider.Search(ctx, next)
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-2", requests[1].Query().Get("cursor"))
+	assert.Empty(t, page.Papers)
+	assert.False(t, page.HasMore)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestCrossrefProvider_GetPaper(t *testing.T) {
+	ctx := context.Background()
+	var requests []*url.URL
+	var userAgents []string
+	provider := newCrossrefProvider(newCrossrefServer(t, &requests, &userAgents).URL)
+
+	for _, id := range []string{"10.1038/nature14539", "https://doi.org/10.1038/NATURE14539", "doi:10.1038/nature14539"} {
+		paper, err := provider.GetPaper(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, "Deep learning", paper.Title)
+	}
+
+	_, err := provider.GetPaper(ctx, "10.1000/missing")
+	require.Error(t, err)
+	var sciErr *errors.SciFindError
+	require.ErrorAs(t, err, &sciErr)
+	assert.Equal(t, "NOT_FOUND", sciErr.Code)
+}
+
+func TestCrossrefProvider_RejectsUnsupportedQueries(t *testing.T) {
+	provider := crossref.NewProvider(providers.ProviderConfig{Enabled: true, Timeout: time.Second}, newTestLogger())
+
+	node, err := providers.ParseQuery("title:graphs OR author:lecun")
+	require.NoError(t, err)
+	assert.True(t, errors.IsUnsupportedQueryError(providers.CheckQuerySupport(provider.Name(), provider.GetCapabilities(), node)))
+}
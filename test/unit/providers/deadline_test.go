@@ -0,0 +1,155 @@
+package providers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func TestManager_ProviderTimeouts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("each provider gets its own budget", func(t *testing.T) {
+		fast := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Fast"))
+		slow := mocks.NewStubSearchProvider("tavily", testPaper("b", "Slow"))
+		slow.Delay = 200 * time.Millisecond
+
+		manager := newTestManager(providers.ManagerConfig{
+			AggregationStrategy: providers.StrategyMerge,
+			ProviderTimeouts:    map[string]time.Duration{"tavily": 50 * time.Millisecond},
+		}, fast, slow)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Equal(t, []string{"tavily"}, result.FailedProviders)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "timeout", result.Errors[0].Type)
+	})
+
+	t.Run("provider config replaces the budget", func(t *testing.T) {
+		slow := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Slow"))
+		slow.Delay = 200 * time.Millisecond
+
+		manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyFirst}, slow)
+		require.NoError(t, manager.UpdateProviderConfig("arxiv", providers.ProviderConfig{Enabled: true, Timeout: 50 * time.Millisecond}))
+
+		start := time.Now()
+		_, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("fastest reports the configured timeout", func(t *testing.T) {
+		slow := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Slow"))
+		slow.Delay = time.Second
+
+		manager := newTestManager(providers.ManagerConfig{
+			AggregationStrategy: providers.StrategyFastest,
+			Timeout:             50 * time.Millisecond,
+		}, slow)
+
+		_, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.Error(t, err)
+		var timeoutErr *errors.SciFindError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, "50ms", timeoutErr.Details["timeout"])
+	})
+}
+
+func TestManager_SoftDeadline(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns what arrived and lists pending providers", func(t *testing.T) {
+		fast := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Fast"))
+		slow := mocks.NewStubSearchProvider("tavily", testPaper("b", "Slow"))
+		slow.Delay = time.Second
+
+		manager := newTestManager(providers.ManagerConfig{
+			AggregationStrategy: providers.StrategyMerge,
+			SoftDeadline:        50 * time.Millisecond,
+		}, fast, slow)
+
+		start := time.Now()
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Empty(t, result.FailedProviders)
+		assert.Equal(t, []string{"tavily"}, result.PendingProviders)
+		assert.True(t, result.PartialFailure)
+		assert.Equal(t, []string{"Fast"}, paperTitles(result.Papers))
+	})
+
+	t.Run("waits past the deadline for a first success", func(t *testing.T) {
+		slow := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Slow"))
+		slow.Delay = 100 * time.Millisecond
+		slower := mocks.NewStubSearchProvider("tavily", testPaper("b", "Slower"))
+		slower.Delay = time.Second
+
+		manager := newTestManager(providers.ManagerConfig{
+			AggregationStrategy: providers.StrategyMerge,
+			SoftDeadline:        20 * time.Millisecond,
+		}, slow, slower)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Equal(t, []string{"tavily"}, result.PendingProviders)
+	})
+
+	t.Run("reports providers that miss their budget as failed", func(t *testing.T) {
+		fast := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Fast"))
+		slow := mocks.NewStubSearchProvider("tavily", testPaper("b", "Slow"))
+		slow.Delay = time.Second
+
+		manager := newTestManager(providers.ManagerConfig{
+			AggregationStrategy: providers.StrategyMerge,
+			ProviderTimeouts:    map[string]time.Duration{"arxiv": 50 * time.Millisecond, "tavily": 50 * time.Millisecond},
+		}, fast, slow)
+
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"arxiv"}, result.SuccessfulProviders)
+		assert.Equal(t, []string{"tavily"}, result.FailedProviders)
+		assert.Empty(t, result.PendingProviders)
+	})
+}
+
+func TestManager_Hedging(t *testing.T) {
+	ctx := context.Background()
+
+	stub := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Hedged"))
+	stub.Delays = []time.Duration{0, 0, 0, 0, 0, time.Second}
+
+	manager := newTestManager(providers.ManagerConfig{
+		AggregationStrategy: providers.StrategyFirst,
+		Hedging:             true,
+		HedgeMinSamples:     5,
+	}, stub)
+
+	// Record enough latencies to hedge
+	for i := 0; i < 5; i++ {
+		_, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+		require.NoError(t, err)
+	}
+	assert.Zero(t, manager.GetProviderMetrics()["arxiv"].HedgedRequests)
+
+	// The sixth request stalls, the duplicate answers
+	start := time.Now()
+	result, err := manager.SearchAll(ctx, providers.NewSearchQuery("graphs"))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, []string{"Hedged"}, paperTitles(result.Papers))
+
+	assert.Equal(t, 7, stub.Calls())
+	assert.Equal(t, int64(1), manager.GetProviderMetrics()["arxiv"].HedgedRequests)
+}
@@ -0,0 +1,141 @@
+package providers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func datePtr(year int) *time.Time {
+	date := time.Date(year, 6, 1, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+func authors(names ...string) []models.Author {
+	result := make([]models.Author, len(names))
+	for i, name := range names {
+		result[i] = models.Author{Name: name}
+	}
+	return result
+}
+
+func TestManager_Deduplication(t *testing.T) {
+	ctx := context.Background()
+	config := providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge}
+
+	t.Run("merges arxiv preprint with published record", func(t *testing.T) {
+		arxivPaper := models.Paper{
+			ID:             "arxiv_1706.03762",
+			Title:          "Attention Is All You Need",
+			ArxivID:        stringPtr("1706.03762v5"),
+			PDFURL:         stringPtr("https://arxiv.org/pdf/1706.03762"),
+			Authors:        authors("Ashish Vaswani", "Noam Shazeer"),
+			PublishedAt:    datePtr(2017),
+			SourceProvider: "arxiv",
+			SourceID:       "1706.03762v5",
+		}
+		ssPaper := models.Paper{
+			ID:             "ss_204e3073",
+			Title:          "Attention is All you Need.",
+			DOI:            stringPtr("10.5555/3295222.3295349"),
+			Authors:        authors("Vaswani, A.", "Shazeer, N."),
+			PublishedAt:    datePtr(2017),
+			CitationCount:  90000,
+			SourceProvider: "semantic_scholar",
+			SourceID:       "204e3073",
+		}
+
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", arxivPaper),
+			mocks.NewStubSearchProvider("semantic_scholar", ssPaper))
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("attention"))
+		require.NoError(t, err)
+		require.Len(t, result.Papers, 1)
+
+		merged := result.Papers[0]
+		require.NotNil(t, merged.DOI)
+		assert.Equal(t, "10.5555/3295222.3295349", *merged.DOI)
+		require.NotNil(t, merged.PDFURL)
+		assert.Equal(t, 90000, merged.CitationCount)
+		assert.ElementsMatch(t, []string{"arxiv", "semantic_scholar"}, merged.GetSourceProviders())
+		assert.Len(t, merged.Sources, 2)
+	})
+
+	t.Run("keeps papers with matching titles but different authors", func(t *testing.T) {
+		first := models.Paper{ID: "a", Title: "A Survey of Deep Learning", Authors: authors("Jane Smith"), SourceProvider: "arxiv", SourceID: "a"}
+		second := models.Paper{ID: "b", Title: "A survey of deep learning", Authors: authors("Wei Zhang"), SourceProvider: "semantic_scholar", SourceID: "b"}
+
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", first),
+			mocks.NewStubSearchProvider("semantic_scholar", second))
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("survey"))
+		require.NoError(t, err)
+		assert.Len(t, result.Papers, 2)
+	})
+
+	t.Run("keeps papers with conflicting DOIs", func(t *testing.T) {
+		first := models.Paper{ID: "a", Title: "Erratum", DOI: stringPtr("10.1/a"), SourceProvider: "arxiv", SourceID: "a"}
+		second := models.Paper{ID: "b", Title: "Erratum", DOI: stringPtr("10.1/b"), SourceProvider: "semantic_scholar", SourceID: "b"}
+
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", first),
+			mocks.NewStubSearchProvider("semantic_scholar", second))
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("erratum"))
+		require.NoError(t, err)
+		assert.Len(t, result.Papers, 2)
+	})
+
+	t.Run("does not chain records with conflicting DOIs through one without", func(t *testing.T) {
+		first := models.Paper{ID: "a", Title: "Erratum", DOI: stringPtr("10.1/a"), SourceProvider: "arxiv", SourceID: "a"}
+		bridge := models.Paper{ID: "b", Title: "Erratum", SourceProvider: "exa", SourceID: "b"}
+		second := models.Paper{ID: "c", Title: "Erratum", DOI: stringPtr("10.1/c"), SourceProvider: "semantic_scholar", SourceID: "c"}
+
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", first),
+			mocks.NewStubSearchProvider("exa", bridge),
+			mocks.NewStubSearchProvider("semantic_scholar", second))
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("erratum"))
+		require.NoError(t, err)
+		require.Len(t, result.Papers, 2)
+
+		dois := []string{*result.Papers[0].DOI, *result.Papers[1].DOI}
+		assert.ElementsMatch(t, []string{"10.1/a", "10.1/c"}, dois)
+	})
+
+	t.Run("merges records sharing a PMID", func(t *testing.T) {
+		first := models.Paper{ID: "pubmed_31452104", Title: "Genome-wide CRISPR screens", PMID: stringPtr("31452104"), PMCID: stringPtr("PMC6710245"), SourceProvider: "pubmed", SourceID: "31452104"}
+		second := models.Paper{ID: "b", Title: "Genome wide CRISPR screens reveal host factors", PMID: stringPtr("31452104"), DOI: stringPtr("10.1038/s41467-019-11771-8"), SourceProvider: "openalex", SourceID: "W1"}
+
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("pubmed", first),
+			mocks.NewStubSearchProvider("openalex", second))
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("crispr"))
+		require.NoError(t, err)
+		require.Len(t, result.Papers, 1)
+		assert.Equal(t, "PMC6710245", *result.Papers[0].PMCID)
+		assert.Equal(t, "10.1038/s41467-019-11771-8", *result.Papers[0].DOI)
+	})
+
+	t.Run("rejects matches more than a year apart", func(t *testing.T) {
+		first := models.Paper{ID: "a", Title: "Annual Report", PublishedAt: datePtr(2018), SourceProvider: "arxiv", SourceID: "a"}
+		second := models.Paper{ID: "b", Title: "Annual Report", PublishedAt: datePtr(2021), SourceProvider: "semantic_scholar", SourceID: "b"}
+
+		manager := newTestManager(config,
+			mocks.NewStubSearchProvider("arxiv", first),
+			mocks.NewStubSearchProvider("semantic_scholar", second))
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("report"))
+		require.NoError(t, err)
+		assert.Len(t, result.Papers, 2)
+	})
+}
@@ -0,0 +1,224 @@
+package providers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/local"
+	"scifind-backend/internal/repository"
+	"scifind-backend/test/mocks"
+	"scifind-backend/test/testutil"
+)
+
+func newLocalProvider(repo *mocks.MockPaperRepository) *local.Provider {
+	return local.NewProvider(providers.ProviderConfig{Enabled: true}, repo, newTestLogger())
+}
+
+func TestLocalProvider_Search(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("maps query and filters to the repository search", func(t *testing.T) {
+		repo := &mocks.MockPaperRepository{}
+		provider := newLocalProvider(repo)
+
+		stored := models.Paper{ID: "arxiv_1706.03762", Title: "Attention Is All You Need", SourceProvider: "arxiv", SourceID: "1706.03762"}
+		var filter *models.PaperFilter
+		repo.On("Search", ctx, "transformer", mock.AnythingOfType("*models.PaperFilter"), &models.PaperSort{Field: "published_at", Order: "asc"}, 10, 20).
+			Run(func(args mock.Arguments) { filter = args.Get(2).(*models.PaperFilter) }).
+			Return([]models.Paper{stored}, int64(21), nil)
+
+		dateFrom := time.Date(2016, time.July, 1, 0, 0, 0, 0, time.UTC)
+		query := providers.NewSearchQuery("transformer author:vaswani year:2015..2018")
+		query.Limit = 10
+		query.Offset = 20
+		query.SortBy = providers.SortDate
+		query.SortOrder = "asc"
+		query.DateFrom = &dateFrom
+		query.Filters = map[string]string{
+			providers.FilterCategory:     "cs.CL, cs.LG",
+			providers.FilterJournal:      "NeurIPS",
+			providers.FilterMinCitations: "100",
+		}
+
+		result, err := provider.Search(ctx, query)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+
+		assert.Equal(t, []string{"vaswani"}, filter.Authors)
+		assert.Equal(t, []string{"cs.CL", "cs.LG"}, filter.Categories)
+		assert.Equal(t, "NeurIPS", filter.Journal)
+		assert.Equal(t, 100, *filter.MinCitations)
+		assert.Nil(t, filter.MaxCitations)
+		// The tighter of the date filter and the year range wins
+		assert.Equal(t, dateFrom, *filter.PublishedFrom)
+		assert.Equal(t, 2018, filter.PublishedTo.Year())
+		assert.Equal(t, time.December, filter.PublishedTo.Month())
+
+		assert.Equal(t, "local", result.Provider)
+		assert.Equal(t, 21, result.TotalCount)
+		assert.False(t, result.HasMore)
+		require.Len(t, result.Papers, 1)
+		assert.Equal(t, "arxiv", result.Papers[0].SourceProvider)
+	})
+
+	t.Run("searches full text when requested", func(t *testing.T) {
+		repo := &mocks.MockPaperRepository{}
+		provider := newLocalProvider(repo)
+
+		repo.On("SearchFullText", ctx, "protein folding", mock.AnythingOfType("*models.PaperFilter"), 20, 0).
+			Return([]models.Paper{}, int64(0), nil)
+
+		query := providers.NewSearchQuery("protein folding")
+		query.IncludeFullText = true
+		_, err := provider.Search(ctx, query)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects queries the repository filters cannot express", func(t *testing.T) {
+		provider := newLocalProvider(&mocks.MockPaperRepository{})
+
+		for _, input := range []string{"graph OR network", "title:graph title:network"} {
+			node, err := providers.ParseQuery(input)
+			require.NoError(t, err)
+			err = providers.CheckQuerySupport(provider.Name(), provider.GetCapabilities(), node)
+			if err == nil {
+				_, err = provider.Search(ctx, &providers.SearchQuery{Query: input, Parsed: node})
+			}
+			assert.True(t, errors.IsUnsupportedQueryError(err), input)
+		}
+
+		_, err := provider.Search(ctx, &providers.SearchQuery{Query: "graph", Filters: map[string]string{providers.FilterMinCitations: "many"}})
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
+
+func TestLocalProvider_SearchStoredPapers(t *testing.T) {
+	ctx := context.Background()
+
+	// The default database is SQLite, searched with FTS5 when it is built in
+	// and with LIKE otherwise
+	for _, indexed := range []bool{false, true} {
+		name := "like"
+		if indexed {
+			name = "fts5"
+		}
+		t.Run(name, func(t *testing.T) {
+			database := testutil.SetupTestDatabase(t, false)
+			defer database.Cleanup()
+
+			// Every connection to :memory: opens a new database
+			sqlDB, err := database.DB().DB()
+			require.NoError(t, err)
+			sqlDB.SetMaxOpenConns(1)
+
+			if indexed && repository.CreatePaperSearchIndex(database.DB()) != nil {
+				t.Skip("SQLite was built without FTS5, run with -tags sqlite_fts5")
+			}
+
+			repo := repository.NewPaperRepository(database.DB(), newTestLogger())
+			journal := "Journal of Graph Theory"
+			published := func(year int) *time.Time {
+				at := time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC)
+				return &at
+			}
+			for _, paper := range []models.Paper{
+				{ID: "p1", Title: "Graph Neural Networks", Journal: &journal, PublishedAt: published(2019), CitationCount: 120},
+				{ID: "p2", Title: "Graph Attention Networks", PublishedAt: published(2017), CitationCount: 40},
+				{ID: "p3", Title: "Protein Folding", PublishedAt: published(2020)},
+			} {
+				paper.Language, paper.SourceProvider, paper.SourceID = "en", "manual", paper.ID
+				require.NoError(t, repo.Create(ctx, &paper))
+			}
+
+			provider := local.NewProvider(providers.ProviderConfig{Enabled: true}, repo, newTestLogger())
+			require.NoError(t, provider.HealthCheck(ctx))
+
+			paperIDs := func(query *providers.SearchQuery) []string {
+				result, err := provider.Search(ctx, query)
+				require.NoError(t, err)
+				ids := make([]string, len(result.Papers))
+				for i, paper := range result.Papers {
+					ids[i] = paper.ID
+				}
+				return ids
+			}
+
+			assert.ElementsMatch(t, []string{"p1", "p2"}, paperIDs(providers.NewSearchQuery("graph networks")))
+			assert.Equal(t, []string{"p1"}, paperIDs(providers.NewSearchQuery("graph year:2018..")))
+			assert.Equal(t, []string{"p2"}, paperIDs(providers.NewSearchQuery("title:attention")))
+
+			query := providers.NewSearchQuery("networks")
+			query.Filters = map[string]string{providers.FilterJournal: "graph theory", providers.FilterMinCitations: "100"}
+			assert.Equal(t, []string{"p1"}, paperIDs(query))
+
+			query = providers.NewSearchQuery("folding")
+			query.IncludeFullText = true
+			assert.Equal(t, []string{"p3"}, paperIDs(query))
+		})
+	}
+}
+
+func TestLocalProvider_GetPaper(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.MockPaperRepository{}
+	provider := newLocalProvider(repo)
+
+	paper := &models.Paper{ID: "arxiv_1706.03762", Title: "Attention Is All You Need"}
+	repo.On("GetByID", ctx, "arxiv_1706.03762").Return(paper, nil)
+	repo.On("GetByDOI", ctx, "10.5555/3295222.3295349").Return(paper, nil)
+	repo.On("GetByArxivID", ctx, "1706.03762").Return(paper, nil)
+
+	for _, id := range []string{"arxiv_1706.03762", "10.5555/3295222.3295349", "doi:10.5555/3295222.3295349", "arxiv:1706.03762"} {
+		found, err := provider.GetPaper(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, paper.ID, found.ID, id)
+	}
+}
+
+func TestLocalProvider_Aggregation(t *testing.T) {
+	ctx := context.Background()
+
+	stored := []models.Paper{
+		{ID: "arxiv_1706.03762", Title: "Attention Is All You Need", ArxivID: stringPtr("1706.03762"), SourceProvider: "arxiv", SourceID: "1706.03762"},
+		{ID: "manual_1", Title: "Internal Report on Attention Mechanisms", SourceProvider: "manual", SourceID: "1"},
+	}
+	remote := models.Paper{ID: "arxiv_1706.03762v5", Title: "Attention Is All You Need", ArxivID: stringPtr("1706.03762v5"), SourceProvider: "arxiv", SourceID: "1706.03762v5"}
+
+	newManager := func(remoteErr error) *providers.Manager {
+		repo := &mocks.MockPaperRepository{}
+		repo.On("Search", mock.Anything, "attention", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(stored, int64(len(stored)), nil)
+
+		arxivStub := mocks.NewStubSearchProvider("arxiv", remote)
+		arxivStub.Err = remoteErr
+		manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge}, arxivStub)
+		require.NoError(t, manager.RegisterProvider(providers.ProviderLocal, newLocalProvider(repo)))
+		return manager
+	}
+
+	t.Run("merges stored papers with external results", func(t *testing.T) {
+		result, err := newManager(nil).SearchAll(ctx, providers.NewSearchQuery("attention"))
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"arxiv", "local"}, result.SuccessfulProviders)
+		assert.Len(t, result.Papers, 2)
+	})
+
+	t.Run("keeps returning stored papers when external providers fail", func(t *testing.T) {
+		result, err := newManager(errors.NewNetworkError("offline", nil)).SearchAll(ctx, providers.NewSearchQuery("attention"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"local"}, result.SuccessfulProviders)
+		assert.Equal(t, []string{"arxiv"}, result.FailedProviders)
+		assert.Len(t, result.Papers, 2)
+	})
+}
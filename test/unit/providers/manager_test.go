@@ -0,0 +1,121 @@
+package providers_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestManager(config providers.ManagerConfig, stubs ...*mocks.StubSearchProvider) *providers.Manager {
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Second
+	}
+	manager := providers.NewManager(newTestLogger(), config)
+	for _, stub := range stubs {
+		_ = manager.RegisterProvider(stub.Name(), stub)
+	}
+	return manager
+}
+
+func testPaper(id, title string) models.Paper {
+	return models.Paper{ID: id, Title: title}
+}
+
+func paperTitles(papers []models.Paper) []string {
+	titles := make([]string, len(papers))
+	for i, paper := range papers {
+		titles[i] = paper.Title
+	}
+	return titles
+}
+
+func TestManager_ReciprocalRankFusion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("papers ranked by both providers come first", func(t *testing.T) {
+		arxiv := mocks.NewStubSearchProvider("arxiv",
+			testPaper("a1", "Alpha"), testPaper("a2", "Shared"), testPaper("a3", "Gamma"))
+		ss := mocks.NewStubSearchProvider("semantic_scholar",
+			testPaper("s1", "Shared"), testPaper("s2", "Delta"))
+
+		manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyReciprocalRank}, arxiv, ss)
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Shared", "Alpha", "Delta", "Gamma"}, paperTitles(result.Papers))
+		assert.Equal(t, string(providers.StrategyReciprocalRank), result.AggregationStrategy)
+		assert.Equal(t, []string{"arxiv", "semantic_scholar"}, result.SuccessfulProviders)
+	})
+
+	t.Run("order is stable regardless of completion order", func(t *testing.T) {
+		arxiv := mocks.NewStubSearchProvider("arxiv", testPaper("a1", "Alpha"), testPaper("a2", "Beta"))
+		ss := mocks.NewStubSearchProvider("semantic_scholar", testPaper("s1", "Gamma"), testPaper("s2", "Delta"))
+
+		var previous []string
+		for i := 0; i < 5; i++ {
+			// Alternate which provider answers first
+			arxiv.Delay, ss.Delay = 0, 0
+			if i%2 == 0 {
+				arxiv.Delay = 5 * time.Millisecond
+			} else {
+				ss.Delay = 5 * time.Millisecond
+			}
+
+			manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyReciprocalRank}, arxiv, ss)
+			result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+			require.NoError(t, err)
+
+			titles := paperTitles(result.Papers)
+			if previous != nil {
+				assert.Equal(t, previous, titles)
+			}
+			previous = titles
+		}
+	})
+
+	t.Run("provider weights change the ranking", func(t *testing.T) {
+		arxiv := mocks.NewStubSearchProvider("arxiv", testPaper("a1", "Alpha"))
+		ss := mocks.NewStubSearchProvider("semantic_scholar", testPaper("s1", "Gamma"))
+
+		manager := newTestManager(providers.ManagerConfig{
+			AggregationStrategy: providers.StrategyReciprocalRank,
+			ProviderWeights:     map[string]float64{"semantic_scholar": 2},
+		}, arxiv, ss)
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Gamma", "Alpha"}, paperTitles(result.Papers))
+
+		manager.SetProviderWeight("semantic_scholar", 0)
+		manager.SetProviderWeight("arxiv", 3)
+		result, err = manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alpha", "Gamma"}, paperTitles(result.Papers))
+	})
+
+	t.Run("failed providers are reported", func(t *testing.T) {
+		arxiv := mocks.NewStubSearchProvider("arxiv", testPaper("a1", "Alpha"))
+		ss := mocks.NewStubSearchProvider("semantic_scholar")
+		ss.Err = assert.AnError
+
+		manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyReciprocalRank}, arxiv, ss)
+		result, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Alpha"}, paperTitles(result.Papers))
+		assert.Equal(t, []string{"semantic_scholar"}, result.FailedProviders)
+		assert.True(t, result.PartialFailure)
+	})
+}
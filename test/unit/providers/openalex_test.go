@@ -0,0 +1,264 @@
+package providers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/openalex"
+)
+
+const openalexWork = `{
+	"id": "https://openalex.org/W1919168311",
+	"doi": "https://doi.org/10.1038/NATURE14539",
+	"title": "Deep learning",
+	"display_name": "Deep learning",
+	"publication_year": 2015,
+	"publication_date": "2015-05-27",
+	"language": "en",
+	"primary_location": {
+		"is_oa": false,
+		"landing_page_url": "https://www.nature.com/articles/nature14539",
+		"source": {"id": "https://openalex.org/S137773608", "display_name": "Nature", "type": "journal"}
+	},
+	"best_oa_location": {"is_oa": true, "pdf_url": "https://example.org/nature14539.pdf"},
+	"authorships": [
+		{
+			"author_position": "first",
+			"author": {"id": "https://openalex.org/A5001226970", "display_name": "Yann LeCun", "orcid": "https://orcid.org/0000-0002-1825-0097"},
+			"institutions": [{"id": "https://openalex.org/I57206974", "display_name": "New York University", "ror": "https://ror.org/0190ak572"}]
+		},
+		{
+			"author_position": "middle",
+			"author": {"id": "https://openalex.org/A5086198262", "display_name": "Yoshua Bengio"},
+			"institutions": [],
+			"raw_affiliation_strings": ["Université de Montréal"]
+		}
+	],
+	"biblio": {"volume": "521", "issue": "7553", "first_page": "436", "last_page": "444"},
+	"cited_by_count": 50000,
+	"concepts": [
+		{"id": "https://openalex.org/C108583219", "display_name": "Deep learning", "level": 2, "score": 0.9},
+		{"id": "https://openalex.org/C41008148", "display_name": "Computer science", "level": 0, "score": 0.1}
+	],
+	"referenced_works": ["https://openalex.org/W2100495367", "https://openalex.org/W2136922672"],
+	"abstract_inverted_index": {"Deep": [0], "learning": [1], "allows": [2], "models": [3], "to": [4], "learn.": [5]}
+}`
+
+func newOpenAlexServer(t *testing.T, requests *[]*url.URL) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/works" && r.URL.Query().Get("filter") == "cites:W1919168311":
+			_, _ = w.Write([]byte(`{"meta":{"count":2},"results":[{"id":"https://openalex.org/W3001"},{"id":"https://openalex.org/W3002"}]}`))
+		case r.URL.Path == "/works" && r.URL.Query().Get("cursor") == "cursor-2":
+			_, _ = w.Write([]byte(`{"meta":{"count":2,"per_page":1,"next_cursor":null},"results":[]}`))
+		case r.URL.Path == "/works":
+			_, _ = w.Write([]byte(`{"meta":{"count":2,"per_page":1,"next_cursor":"cursor-2"},"results":[` + openalexWork + `]}`))
+		case r.URL.Path == "/works/W1919168311", r.URL.Path == "/works/doi:10.1038/nature14539":
+			_, _ = w.Write([]byte(openalexWork))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Not Found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newOpenAlexProvider(baseURL string) *openalex.Provider {
+	return openalex.NewProvider(providers.ProviderConfig{
+		Enabled: true,
+		BaseURL: baseURL,
+		Timeout: 5 * time.Second,
+		Custom:  map[string]interface{}{"mailto": "team@example.org"},
+	}, newTestLogger())
+}
+
+func TestOpenAlexProvider_Search(t *testing.T) {
+	ctx := context.Background()
+	var requests []*url.URL
+	provider := newOpenAlexProvider(newOpenAlexServer(t, &requests).URL)
+
+	query := providers.NewSearchQuery("deep learning year:2014..2016 cat:c108583219")
+	query.Limit = 1
+	query.Filters[providers.FilterInstitution] = "I57206974, https://ror.org/0190ak572"
+	query.Filters[providers.FilterMinCitations] = "100"
+	result, err := provider.Search(ctx, query)
+	require.NoError(t, err)
+
+	params := requests[0].Query()
+	assert.Equal(t, "deep learning", params.Get("search"))
+	assert.Equal(t, "publication_year:2014-2016,concepts.id:C108583219,"+
+		"authorships.institutions.id:I57206974,authorships.institutions.ror:https://ror.org/0190ak572,"+
+		"cited_by_count:>99", params.Get("filter"))
+	assert.Equal(t, "*", params.Get("cursor"))
+	assert.Equal(t, "1", params.Get("per-page"))
+	assert.Equal(t, "team@example.org", params.Get("mailto"))
+
+	require.Len(t, result.Papers, 1)
+	paper := result.Papers[0]
+	assert.Equal(t, "openalex_W1919168311", paper.ID)
+	assert.Equal(t, "W1919168311", paper.SourceID)
+	assert.Equal(t, "10.1038/nature14539", *paper.DOI)
+	assert.Equal(t, "Deep learning allows models to learn.", *paper.Abstract)
+	assert.Equal(t, "Nature", *paper.Journal)
+	assert.Equal(t, "436-444", *paper.Pages)
+	assert.Equal(t, time.Date(2015, time.May, 27, 0, 0, 0, 0, time.UTC), *paper.PublishedAt)
+	assert.Equal(t, "https://www.nature.com/articles/nature14539", *paper.URL)
+	assert.Equal(t, "https://example.org/nature14539.pdf", *paper.PDFURL)
+	assert.Equal(t, 50000, paper.CitationCount)
+	assert.Equal(t, []string{"W2100495367", "W2136922672"}, paper.References)
+	assert.Equal(t, []string{"Deep learning"}, paper.Keywords)
+	assert.Equal(t, "openalex", paper.SourceProvider)
+
+	require.Len(t, paper.Authors, 2)
+	assert.Equal(t, "0000-0002-1825-0097", *paper.Authors[0].ORCID)
+	assert.Equal(t, "New York University", *paper.Authors[0].Affiliation)
+	assert.Equal(t, "Université de Montréal", *paper.Authors[1].Affiliation)
+
+	// The next page continues from the returned cursor
+	assert.True(t, result.HasMore)
+	require.NotNil(t, result.NextCursor)
+	next := providers.NewSearchQuery("deep learning")
+	next.Limit = 1
+	next.Cursor = *result.NextCursor
+	page, err := provider.Search(ctx, next)
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-2", requests[1].Query().Get("cursor"))
+	assert.Empty(t, page.Papers)
+	assert.False(t, page.HasMore)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestOpenAlexProvider_SearchOffsetAndSort(t *testing.T) {
+	var requests []*url.URL
+	provider := newOpenAlexProvider(newOpenAlexServer(t, &requests).URL)
+
+	query := providers.NewSearchQuery("graphs")
+	query.Limit = 10
+	query.Offset = 20
+	query.SortBy = providers.SortCitations
+	_, err := provider.Search(context.Background(), query)
+	require.NoError(t, err)
+
+	params := requests[0].Query()
+	assert.Equal(t, "3", params.Get("page"))
+	assert.Empty(t, params.Get("cursor"))
+	assert.Equal(t, "cited_by_count:desc", params.Get("sort"))
+}
+
+func TestOpenAlexProvider_SearchOffsetReturnsFullPages(t *testing.T) {
+	const total = 500
+	var requests []*url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per-page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		works := make([]string, 0, perPage)
+		for i := (page - 1) * perPage; i < min(page*perPage, total); i++ {
+			works = append(works, fmt.Sprintf(`{"id":"https://openalex.org/W%d","title":"Work %d"}`, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"meta":{"count":%d,"per_page":%d},"results":[%s]}`, total, perPage, strings.Join(works, ","))
+	}))
+	t.Cleanup(server.Close)
+	provider := newOpenAlexProvider(server.URL)
+
+	search := func(offset, limit int) []string {
+		requests = nil
+		query := providers.NewSearchQuery("graphs")
+		query.Offset, query.Limit = offset, limit
+		result, err := provider.Search(context.Background(), query)
+		require.NoError(t, err)
+
+		titles := make([]string, len(result.Papers))
+		for i, paper := range result.Papers {
+			titles[i] = paper.Title
+		}
+		return titles
+	}
+
+	t.Run("sizes the page to hold the offset window", func(t *testing.T) {
+		titles := search(30, 20)
+		require.Len(t, titles, 20)
+		assert.Equal(t, "Work 30", titles[0])
+		assert.Equal(t, "Work 49", titles[19])
+		require.Len(t, requests, 1)
+		assert.Equal(t, "25", requests[0].Query().Get("per-page"))
+		assert.Equal(t, "2", requests[0].Query().Get("page"))
+	})
+
+	t.Run("continues on the next page when no size fits", func(t *testing.T) {
+		titles := search(1, 200)
+		require.Len(t, titles, 200)
+		assert.Equal(t, "Work 1", titles[0])
+		assert.Equal(t, "Work 200", titles[199])
+		assert.Len(t, requests, 2)
+	})
+
+	t.Run("stops at the last result", func(t *testing.T) {
+		titles := search(490, 20)
+		assert.Len(t, titles, 10)
+		assert.Len(t, requests, 1)
+	})
+}
+
+func TestOpenAlexProvider_SearchRejectsInvalidFilters(t *testing.T) {
+	var requests []*url.URL
+	provider := newOpenAlexProvider(newOpenAlexServer(t, &requests).URL)
+
+	query := providers.NewSearchQuery("graphs")
+	query.Filters[providers.FilterConcept] = "machine learning"
+	_, err := provider.Search(context.Background(), query)
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+	assert.Empty(t, requests)
+}
+
+func TestOpenAlexProvider_GetPaper(t *testing.T) {
+	ctx := context.Background()
+	var requests []*url.URL
+	provider := newOpenAlexProvider(newOpenAlexServer(t, &requests).URL)
+
+	for _, id := range []string{"W1919168311", "https://openalex.org/W1919168311", "openalex_W1919168311", "10.1038/NATURE14539", "https://doi.org/10.1038/nature14539"} {
+		paper, err := provider.GetPaper(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, "Deep learning", paper.Title)
+		assert.Equal(t, []string{"W3001", "W3002"}, paper.Citations)
+	}
+
+	_, err := provider.GetPaper(ctx, "W1")
+	require.Error(t, err)
+	var sciErr *errors.SciFindError
+	require.ErrorAs(t, err, &sciErr)
+	assert.Equal(t, "NOT_FOUND", sciErr.Code)
+
+	_, err = provider.GetPaper(ctx, "not an id")
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestOpenAlexProvider_RejectsUnsupportedQueries(t *testing.T) {
+	provider := openalex.NewProvider(providers.ProviderConfig{Enabled: true, Timeout: time.Second}, newTestLogger())
+
+	for _, input := range []string{"title:graphs OR abstract:trees", "author:lecun"} {
+		node, err := providers.ParseQuery(input)
+		require.NoError(t, err)
+		assert.True(t, errors.IsUnsupportedQueryError(providers.CheckQuerySupport(provider.Name(), provider.GetCapabilities(), node)), input)
+	}
+}
@@ -0,0 +1,188 @@
+package providers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+func pagedStub(name string, count int) *mocks.StubSearchProvider {
+	papers := make([]models.Paper, count)
+	for i := range papers {
+		id := fmt.Sprintf("%s-%d", name, i)
+		papers[i] = models.Paper{ID: id, Title: "Paper " + id, SourceProvider: name, SourceID: id}
+	}
+	stub := mocks.NewStubSearchProvider(name, papers...)
+	stub.Paged = true
+	return stub
+}
+
+// nextPage searches the page after the given token
+func nextPage(t *testing.T, manager *providers.Manager, token *string) *providers.AggregatedResult {
+	t.Helper()
+	query := providers.NewSearchQuery("graphs")
+	query.Limit = 2
+	if token != nil {
+		query.PageToken = *token
+	}
+	result, err := manager.SearchAll(context.Background(), query)
+	require.NoError(t, err)
+	return result
+}
+
+func TestManager_PageTokens(t *testing.T) {
+	config := providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge}
+
+	t.Run("continues every provider where it left off", func(t *testing.T) {
+		arxiv := pagedStub("arxiv", 5)
+		openalex := pagedStub("openalex", 3)
+		openalex.CursorPaging = true
+		// The second OpenAlex page repeats a paper arXiv returned first
+		doi := "10.1/shared"
+		arxiv.Papers[0].DOI = &doi
+		openalex.Papers[2].DOI = &doi
+
+		manager := newTestManager(config, arxiv, openalex)
+
+		var ids []string
+		var token *string
+		pages := 0
+		for {
+			result := nextPage(t, manager, token)
+			pages++
+			for _, paper := range result.Papers {
+				ids = append(ids, paper.ID)
+			}
+			if !result.HasMore {
+				assert.Nil(t, result.NextPageToken)
+				break
+			}
+			require.NotNil(t, result.NextPageToken)
+			token = result.NextPageToken
+		}
+
+		assert.Equal(t, 3, pages)
+		assert.ElementsMatch(t, []string{"arxiv-0", "arxiv-1", "arxiv-2", "arxiv-3", "arxiv-4", "openalex-0", "openalex-1"}, ids)
+
+		arxivQueries := arxiv.Queries()
+		require.Len(t, arxivQueries, 3)
+		assert.Equal(t, []int{0, 2, 4}, []int{arxivQueries[0].Offset, arxivQueries[1].Offset, arxivQueries[2].Offset})
+
+		// OpenAlex is continued from its own cursor and not asked again once exhausted
+		openalexQueries := openalex.Queries()
+		require.Len(t, openalexQueries, 2)
+		assert.Equal(t, "pos-2", openalexQueries[1].Cursor)
+		assert.Empty(t, openalexQueries[1].PageToken)
+	})
+
+	t.Run("retries failed providers from the same position", func(t *testing.T) {
+		arxiv := pagedStub("arxiv", 4)
+		pubmed := pagedStub("pubmed", 2)
+		pubmed.Err = errors.NewNetworkError("offline", nil)
+		manager := newTestManager(config, arxiv, pubmed)
+
+		first := nextPage(t, manager, nil)
+		assert.Equal(t, []string{"pubmed"}, first.FailedProviders)
+
+		pubmed.Err = nil
+		second := nextPage(t, manager, first.NextPageToken)
+		assert.ElementsMatch(t, []string{"arxiv", "pubmed"}, second.SuccessfulProviders)
+		assert.Equal(t, 0, pubmed.Queries()[1].Offset)
+		assert.Equal(t, 2, arxiv.Queries()[1].Offset)
+	})
+
+	t.Run("single provider strategies keep paging the provider that answered", func(t *testing.T) {
+		arxiv := pagedStub("arxiv", 4)
+		openalex := pagedStub("openalex", 4)
+		arxiv.Err = errors.NewNetworkError("offline", nil)
+		manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyFirst}, openalex, arxiv)
+
+		first := nextPage(t, manager, nil)
+		require.NotNil(t, first.NextPageToken)
+		calls := arxiv.Calls()
+
+		arxiv.Err = nil
+		second := nextPage(t, manager, first.NextPageToken)
+		assert.Equal(t, []string{"openalex"}, second.SuccessfulProviders)
+		assert.Equal(t, []string{"openalex-2", "openalex-3"}, []string{second.Papers[0].ID, second.Papers[1].ID})
+		assert.Equal(t, calls, arxiv.Calls())
+		assert.False(t, second.HasMore)
+	})
+
+	t.Run("best quality keeps the papers it did not return for later pages", func(t *testing.T) {
+		arxiv := pagedStub("arxiv", 5)
+		openalex := pagedStub("openalex", 4)
+		openalex.CursorPaging = true
+		for i, quality := range []float64{0.9, 0.8, 0.3, 0.2, 0.1} {
+			arxiv.Papers[i].QualityScore = quality
+		}
+		for i, quality := range []float64{0.7, 0.6, 0.5, 0.4} {
+			openalex.Papers[i].QualityScore = quality
+		}
+		manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyBestQuality}, arxiv, openalex)
+
+		var ids []string
+		var token *string
+		for {
+			result := nextPage(t, manager, token)
+			assert.LessOrEqual(t, len(result.Papers), 2)
+			for _, paper := range result.Papers {
+				ids = append(ids, paper.ID)
+			}
+			if !result.HasMore {
+				break
+			}
+			require.NotNil(t, result.NextPageToken)
+			token = result.NextPageToken
+		}
+
+		// Every paper is returned once, in quality order across pages
+		assert.Equal(t, []string{
+			"arxiv-0", "arxiv-1", "openalex-0", "openalex-1", "openalex-2",
+			"openalex-3", "arxiv-2", "arxiv-3", "arxiv-4",
+		}, ids)
+
+		// The second page continues arXiv after its returned papers and
+		// fetches the OpenAlex page again as none of it was returned
+		second := arxiv.Queries()[1]
+		assert.Equal(t, 2, second.Offset)
+		assert.Empty(t, openalex.Queries()[1].Cursor)
+	})
+
+	t.Run("keeps page tokens small", func(t *testing.T) {
+		manager := newTestManager(config, pagedStub("arxiv", 400), pagedStub("pubmed", 400), pagedStub("openalex", 400))
+
+		query := providers.NewSearchQuery("graphs")
+		query.Limit = 100
+		for page := 0; page < 3; page++ {
+			result, err := manager.SearchAll(context.Background(), query)
+			require.NoError(t, err)
+			require.NotNil(t, result.NextPageToken)
+			assert.Less(t, len(*result.NextPageToken), 3*1024)
+			query.PageToken = *result.NextPageToken
+		}
+	})
+
+	t.Run("rejects tokens of other searches", func(t *testing.T) {
+		manager := newTestManager(config, pagedStub("arxiv", 4))
+		first := nextPage(t, manager, nil)
+		require.NotNil(t, first.NextPageToken)
+
+		query := providers.NewSearchQuery("other query")
+		query.PageToken = *first.NextPageToken
+		_, err := manager.SearchAll(context.Background(), query)
+		assert.True(t, errors.IsValidationError(err))
+
+		query.PageToken = "not-a-token"
+		_, err = manager.SearchAll(context.Background(), query)
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
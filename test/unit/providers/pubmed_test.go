@@ -0,0 +1,213 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/pubmed"
+)
+
+// pubmedRequest records an E-utilities request and when it arrived
+type pubmedRequest struct {
+	url *url.URL
+	at  time.Time
+}
+
+// newPubMedServer replays the recorded E-utilities responses in testdata/pubmed
+func newPubMedServer(t *testing.T) (*httptest.Server, func() []pubmedRequest) {
+	t.Helper()
+	fixture := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("testdata", "pubmed", name))
+		require.NoError(t, err)
+		return data
+	}
+
+	var mu sync.Mutex
+	var requests []pubmedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, pubmedRequest{url: r.URL, at: time.Now()})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/xml")
+
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/esearch.fcgi" && query.Get("term") == "PMC6710245[pmcid]":
+			_, _ = w.Write(fixture("esearch_pmcid.xml"))
+		case r.URL.Path == "/esearch.fcgi" && query.Get("term") == "(invalid":
+			_, _ = w.Write(fixture("esearch_error.xml"))
+		case r.URL.Path == "/esearch.fcgi" && query.Get("term") == "throttled":
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"API rate limit exceeded","count":"4"}`))
+		case r.URL.Path == "/esearch.fcgi":
+			_, _ = w.Write(fixture("esearch.xml"))
+		case r.URL.Path == "/efetch.fcgi" && query.Get("id") == "404":
+			_, _ = w.Write([]byte(`<?xml version="1.0" ?><PubmedArticleSet></PubmedArticleSet>`))
+		case r.URL.Path == "/efetch.fcgi":
+			_, _ = w.Write(fixture("efetch.xml"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []pubmedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]pubmedRequest(nil), requests...)
+	}
+}
+
+func newPubMedProvider(baseURL string, limits providers.RateLimitConfig) *pubmed.Provider {
+	return pubmed.NewProvider(providers.ProviderConfig{
+		Enabled:   true,
+		BaseURL:   baseURL,
+		Timeout:   5 * time.Second,
+		RateLimit: limits,
+		Custom:    map[string]interface{}{"email": "team@example.org"},
+	}, newTestLogger())
+}
+
+// fastLimits keeps the E-utilities throttle out of the way of tests that do not cover it
+var fastLimits = providers.RateLimitConfig{RequestsPerSecond: 1000, BurstSize: 10}
+
+func TestPubMedProvider_Search(t *testing.T) {
+	server, requests := newPubMedServer(t)
+	provider := newPubMedProvider(server.URL, fastLimits)
+
+	query := providers.NewSearchQuery("crispr cat:Humans year:2018..2020")
+	query.Limit = 2
+	query.SortBy = providers.SortDate
+	result, err := provider.Search(context.Background(), query)
+	require.NoError(t, err)
+
+	recorded := requests()
+	require.Len(t, recorded, 2)
+	search := recorded[0].url.Query()
+	assert.Equal(t, "/esearch.fcgi", recorded[0].url.Path)
+	assert.Equal(t, "(crispr AND Humans[mh] AND 2018:2020[dp])", search.Get("term"))
+	assert.Equal(t, "2", search.Get("retmax"))
+	assert.Equal(t, "pub_date", search.Get("sort"))
+	assert.Equal(t, "scifind", search.Get("tool"))
+	assert.Equal(t, "team@example.org", search.Get("email"))
+	assert.Equal(t, "31452104,31812345", recorded[1].url.Query().Get("id"))
+
+	assert.Equal(t, 1342, result.TotalCount)
+	assert.True(t, result.HasMore)
+	require.Len(t, result.Papers, 2)
+
+	paper := result.Papers[0]
+	assert.Equal(t, "pubmed_31452104", paper.ID)
+	assert.Equal(t, "31452104", *paper.PMID)
+	assert.Equal(t, "PMC6710245", *paper.PMCID)
+	assert.Equal(t, "10.1038/s41467-019-11771-8", *paper.DOI)
+	assert.Equal(t, "Genome-wide CRISPR screens reveal host factors & drug targets", paper.Title)
+	assert.Equal(t, "BACKGROUND: Host factors of Flaviviridae remain poorly understood.\n\nRESULTS: We identify 12 factors with p<0.05.", *paper.Abstract)
+	assert.Equal(t, "Nature communications", *paper.Journal)
+	assert.Equal(t, "10", *paper.Volume)
+	assert.Equal(t, "3826", *paper.Pages)
+	assert.Equal(t, time.Date(2019, time.August, 26, 0, 0, 0, 0, time.UTC), *paper.PublishedAt)
+	assert.Equal(t, "https://pubmed.ncbi.nlm.nih.gov/31452104/", *paper.URL)
+	assert.Equal(t, "https://pmc.ncbi.nlm.nih.gov/articles/PMC6710245/pdf/", *paper.PDFURL)
+	assert.Equal(t, []string{"genetic screens"}, paper.Keywords)
+	assert.Equal(t, []string{"27383987"}, paper.References)
+	assert.Equal(t, "en", paper.Language)
+
+	require.Len(t, paper.Categories, 2)
+	assert.Equal(t, "mesh_D064113", paper.Categories[0].ID)
+	assert.Equal(t, "CRISPR-Cas Systems", paper.Categories[0].Name)
+	assert.Equal(t, "mesh", paper.Categories[0].Source)
+	assert.Equal(t, "D064113", paper.Categories[0].SourceCode)
+
+	require.Len(t, paper.Authors, 2)
+	assert.Equal(t, "Rong Zhang", paper.Authors[0].Name)
+	assert.Equal(t, "0000-0002-3456-7890", *paper.Authors[0].ORCID)
+	assert.Equal(t, "Department of Microbiology, Stanford University, Stanford, CA, USA.", *paper.Authors[0].Affiliation)
+	assert.Equal(t, "JE Carette", paper.Authors[1].Name)
+
+	// MedlineDate ranges, collective authors and non-English records
+	other := result.Papers[1]
+	assert.Equal(t, time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC), *other.PublishedAt)
+	assert.Equal(t, "German Ethics Council", other.Authors[0].Name)
+	assert.Equal(t, "de", other.Language)
+	assert.Equal(t, "10.1007/s00103-019-03052-y", *other.DOI)
+	assert.Nil(t, other.PMCID)
+	assert.Nil(t, other.PDFURL)
+}
+
+func TestPubMedProvider_SearchErrors(t *testing.T) {
+	server, _ := newPubMedServer(t)
+	provider := newPubMedProvider(server.URL, fastLimits)
+
+	_, err := provider.Search(context.Background(), &providers.SearchQuery{Query: "(invalid", Limit: 10, Parsed: &providers.QueryNode{Op: providers.QueryTerm, Value: "(invalid"}})
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = provider.Search(context.Background(), providers.NewSearchQuery("throttled"))
+	require.Error(t, err)
+	assert.True(t, errors.IsRateLimitError(err))
+}
+
+func TestPubMedProvider_GetPaper(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newPubMedServer(t)
+	provider := newPubMedProvider(server.URL, fastLimits)
+
+	for _, id := range []string{"31452104", "pubmed_31452104", "https://pubmed.ncbi.nlm.nih.gov/31452104/", "PMC6710245"} {
+		paper, err := provider.GetPaper(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, "31452104", *paper.PMID, id)
+	}
+
+	// PMCIDs are resolved with an extra esearch request
+	recorded := requests()
+	assert.Equal(t, "/esearch.fcgi", recorded[len(recorded)-2].url.Path)
+
+	_, err := provider.GetPaper(ctx, "404")
+	require.Error(t, err)
+	var sciErr *errors.SciFindError
+	require.ErrorAs(t, err, &sciErr)
+	assert.Equal(t, "NOT_FOUND", sciErr.Code)
+
+	_, err = provider.GetPaper(ctx, "not an id")
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestPubMedProvider_ThrottlesRequests(t *testing.T) {
+	server, requests := newPubMedServer(t)
+
+	// Without an API key NCBI allows 3 requests per second
+	provider := newPubMedProvider(server.URL, providers.RateLimitConfig{})
+	_, err := provider.Search(context.Background(), providers.NewSearchQuery("crispr"))
+	require.NoError(t, err)
+
+	recorded := requests()
+	require.Len(t, recorded, 2)
+	assert.GreaterOrEqual(t, recorded[1].at.Sub(recorded[0].at), 300*time.Millisecond)
+}
+
+func TestPubMedProvider_RejectsUnsupportedQueries(t *testing.T) {
+	provider := pubmed.NewProvider(providers.ProviderConfig{Enabled: true, Timeout: time.Second}, newTestLogger())
+
+	for _, input := range []string{"abstract:crispr", "NOT crispr"} {
+		node, err := providers.ParseQuery(input)
+		require.NoError(t, err)
+		err = providers.CheckQuerySupport(provider.Name(), provider.GetCapabilities(), node)
+		if err == nil {
+			_, err = provider.Search(context.Background(), &providers.SearchQuery{Query: input, Parsed: node})
+		}
+		assert.True(t, errors.IsUnsupportedQueryError(err), input)
+	}
+}
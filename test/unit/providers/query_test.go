@@ -0,0 +1,194 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/arxiv"
+	"scifind-backend/test/mocks"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain words", "graph neural networks", "graph AND neural AND networks"},
+		{"phrase", `"graph neural" networks`, `"graph neural" AND networks`},
+		{"fields", `title:"attention is all" author:vaswani`, `title:"attention is all" AND author:vaswani`},
+		{"year range", "transformers year:2019..2022", "transformers AND year:2019..2022"},
+		{"open year range", "year:..2020 cat:cs.LG", "year:..2020 AND cat:cs.LG"},
+		{"precedence", "a OR b c", "a OR b AND c"},
+		{"grouping", "(a OR b) NOT c", "(a OR b) AND NOT c"},
+		{"field on group", "title:(graph OR network)", "title:graph OR title:network"},
+		{"colon in plain word", "COVID-19: review", "COVID-19: AND review"},
+		{"lower case operators are words", "cats and dogs", "cats AND and AND dogs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := providers.ParseQuery(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, node.String())
+		})
+	}
+
+	t.Run("blank query", func(t *testing.T) {
+		node, err := providers.ParseQuery("  ")
+		require.NoError(t, err)
+		assert.Nil(t, node)
+	})
+
+	t.Run("year bounds", func(t *testing.T) {
+		node, err := providers.ParseQuery("year:2019..2022")
+		require.NoError(t, err)
+		assert.Equal(t, 2019, node.YearFrom)
+		assert.Equal(t, 2022, node.YearTo)
+	})
+
+	invalid := []string{`"unterminated`, "(a OR b", "a)", "a AND", "OR a", "title:", "year:19", "year:2022..2019", "title:(author:x)"}
+	for _, input := range invalid {
+		t.Run("rejects "+input, func(t *testing.T) {
+			_, err := providers.ParseQuery(input)
+			require.Error(t, err)
+			assert.True(t, errors.IsValidationError(err))
+		})
+	}
+}
+
+func TestParseUserQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"valid grammar", `"graph neural" OR trees`, `"graph neural" OR trees`},
+		{"unterminated quote", `"deep learning`, "deep AND learning"},
+		{"unbalanced parenthesis", "(graphs OR trees", "graphs AND trees"},
+		{"stray closing parenthesis", "graphs) trees", "graphs AND trees"},
+		{"trailing operator", "graphs AND", "graphs"},
+		{"only operators", "AND NOT", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := providers.ParseUserQuery(tt.input)
+			if tt.expected == "" {
+				require.Error(t, err)
+				assert.True(t, errors.IsValidationError(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, node.String())
+		})
+	}
+
+	for _, input := range []string{"title:", "year:19", "title:(graphs OR trees", `author:"hinton`} {
+		t.Run("rejects field syntax "+input, func(t *testing.T) {
+			_, err := providers.ParseUserQuery(input)
+			require.Error(t, err)
+			assert.True(t, errors.IsValidationError(err))
+		})
+	}
+}
+
+func TestCheckQuerySupport(t *testing.T) {
+	freeText := providers.ProviderCapabilities{SupportsExactMatch: true, QueryFields: []string{"year"}}
+	boolean := providers.ProviderCapabilities{SupportsExactMatch: true, SupportsBooleanQuery: true, QueryFields: providers.QueryFields}
+
+	tests := []struct {
+		name      string
+		query     string
+		caps      providers.ProviderCapabilities
+		supported bool
+	}{
+		{"plain terms", "graph networks", freeText, true},
+		{"supported field", "graph year:2020", freeText, true},
+		{"unsupported field", "author:lecun", freeText, false},
+		{"boolean operators", "a OR b", freeText, false},
+		{"negation", "a NOT b", freeText, false},
+		{"phrase without exact match", `"graph networks"`, providers.ProviderCapabilities{}, false},
+		{"full grammar", `(title:graph OR abstract:"graph network") NOT author:x`, boolean, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := providers.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			err = providers.CheckQuerySupport("stub", tt.caps, node)
+			if tt.supported {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.IsUnsupportedQueryError(err))
+			}
+		})
+	}
+}
+
+func TestManager_RejectsUnsupportedQueries(t *testing.T) {
+	supported := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Alpha"))
+	supported.Capabilities = providers.ProviderCapabilities{SupportsBooleanQuery: true, QueryFields: providers.QueryFields}
+	unsupported := mocks.NewStubSearchProvider("tavily", testPaper("t", "Tango"))
+
+	manager := newTestManager(providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge}, supported, unsupported)
+	result, err := manager.SearchAll(context.Background(), providers.NewSearchQuery("author:hinton OR author:lecun"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Alpha"}, paperTitles(result.Papers))
+	assert.Equal(t, []string{"tavily"}, result.FailedProviders)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "unsupported", result.Errors[0].Type)
+	assert.Equal(t, 0, unsupported.Calls())
+}
+
+func TestArxivProvider_TranslatesQuery(t *testing.T) {
+	var searchQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searchQuery = r.URL.Query().Get("search_query")
+		w.Header().Set("Content-Type", "application/atom+xml")
+		_, _ = w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	provider := arxiv.NewProvider(providers.ProviderConfig{
+		Enabled: true,
+		BaseURL: server.URL,
+		Timeout: 5 * time.Second,
+	}, newTestLogger())
+
+	t.Run("fields, phrases and negation", func(t *testing.T) {
+		_, err := provider.Search(context.Background(), providers.NewSearchQuery(`title:"graph neural" year:2019..2022 NOT cat:cs.CV`))
+		require.NoError(t, err)
+		assert.Equal(t, `(ti:"graph neural" AND submittedDate:[20190101 TO 20221231] ANDNOT cat:cs.CV)`, searchQuery)
+	})
+
+	t.Run("unfielded terms search title and abstract", func(t *testing.T) {
+		_, err := provider.Search(context.Background(), providers.NewSearchQuery("author:hinton OR capsules"))
+		require.NoError(t, err)
+		assert.Equal(t, "(au:hinton OR (ti:capsules OR abs:capsules))", searchQuery)
+	})
+
+	t.Run("reports the unsupported negation", func(t *testing.T) {
+		tests := map[string]string{
+			"NOT cat:cs.CV":       "NOT without a positive term",
+			"graphs OR NOT cs.CV": "NOT inside OR",
+			"graphs NOT NOT cs":   "nested NOT",
+			"NOT NOT cs":          "nested NOT",
+		}
+		for query, feature := range tests {
+			_, err := provider.Search(context.Background(), providers.NewSearchQuery(query))
+			require.True(t, errors.IsUnsupportedQueryError(err), query)
+			assert.ErrorContains(t, err, "does not support "+feature, query)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package providers_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+// memoryKV is an in-memory stand-in for the few JetStream KV operations the
+// shared rate limiter uses, counting the writes
+type memoryKV struct {
+	jetstream.KeyValue
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	writes  int
+}
+
+type memoryEntry struct {
+	jetstream.KeyValueEntry
+	value    []byte
+	revision uint64
+}
+
+func (e memoryEntry) Value() []byte    { return e.value }
+func (e memoryEntry) Revision() uint64 { return e.revision }
+
+func (kv *memoryKV) Get(ctx context.Context, key string) (jetstream.KeyValueEntry, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	entry, ok := kv.entries[key]
+	if !ok {
+		return nil, jetstream.ErrKeyNotFound
+	}
+	return entry, nil
+}
+
+func (kv *memoryKV) Create(ctx context.Context, key string, value []byte, opts ...jetstream.KVCreateOpt) (uint64, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if _, ok := kv.entries[key]; ok {
+		return 0, jetstream.ErrKeyExists
+	}
+	kv.writes++
+	kv.entries[key] = memoryEntry{value: value, revision: 1}
+	return 1, nil
+}
+
+func (kv *memoryKV) Update(ctx context.Context, key string, value []byte, revision uint64) (uint64, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.entries[key].revision != revision {
+		return 0, jetstream.ErrKeyExists
+	}
+	kv.writes++
+	kv.entries[key] = memoryEntry{value: value, revision: revision + 1}
+	return revision + 1, nil
+}
+
+func (kv *memoryKV) Writes() int {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	return kv.writes
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unconfigured providers are not limited", func(t *testing.T) {
+		limiter := providers.NewTokenBucketLimiter(newTestLogger())
+		for i := 0; i < 10; i++ {
+			assert.True(t, limiter.Allow(ctx, "arxiv"))
+		}
+		assert.Equal(t, providers.RateLimitInfo{}, limiter.GetLimits("arxiv"))
+	})
+
+	t.Run("allows burst then denies", func(t *testing.T) {
+		limiter := providers.NewTokenBucketLimiter(newTestLogger())
+		require.NoError(t, limiter.UpdateLimits("arxiv", providers.RateLimitConfig{RequestsPerMinute: 1, BurstSize: 2}))
+
+		assert.True(t, limiter.Allow(ctx, "arxiv"))
+		assert.True(t, limiter.Allow(ctx, "arxiv"))
+		assert.False(t, limiter.Allow(ctx, "arxiv"))
+
+		info := limiter.GetLimits("arxiv")
+		assert.Equal(t, 0, info.Remaining)
+		assert.Equal(t, 2, info.Limit)
+		assert.Greater(t, info.RetryAfter, 50*time.Second)
+	})
+
+	t.Run("wait blocks until a token is refilled", func(t *testing.T) {
+		limiter := providers.NewTokenBucketLimiter(newTestLogger())
+		require.NoError(t, limiter.UpdateLimits("fast", providers.RateLimitConfig{RequestsPerSecond: 20}))
+
+		require.True(t, limiter.Allow(ctx, "fast"))
+		start := time.Now()
+		require.NoError(t, limiter.Wait(ctx, "fast"))
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	})
+
+	t.Run("wait fails fast when the deadline is too close", func(t *testing.T) {
+		limiter := providers.NewTokenBucketLimiter(newTestLogger())
+		require.NoError(t, limiter.UpdateLimits("arxiv", providers.RateLimitFromInterval(3*time.Second)))
+		require.True(t, limiter.Allow(ctx, "arxiv"))
+
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		err := limiter.Wait(waitCtx, "arxiv")
+		require.Error(t, err)
+		assert.True(t, errors.IsRateLimitError(err))
+	})
+
+	t.Run("cancelled waits give their token back", func(t *testing.T) {
+		kv := &memoryKV{entries: make(map[string]memoryEntry)}
+		limiter := providers.NewSharedTokenBucketLimiter(kv, newTestLogger())
+		require.NoError(t, limiter.UpdateLimits("arxiv", providers.RateLimitConfig{RequestsPerMinute: 1, BurstSize: 1}))
+		require.True(t, limiter.Allow(ctx, "arxiv"))
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(20*time.Millisecond, cancel)
+		assert.ErrorIs(t, limiter.Wait(waitCtx, "arxiv"), context.Canceled)
+
+		// Only the token taken by Allow is still owed
+		assert.LessOrEqual(t, limiter.GetLimits("arxiv").RetryAfter, time.Minute)
+	})
+
+	t.Run("reading shared limits does not write the bucket", func(t *testing.T) {
+		kv := &memoryKV{entries: make(map[string]memoryEntry)}
+		limiter := providers.NewSharedTokenBucketLimiter(kv, newTestLogger())
+		require.NoError(t, limiter.UpdateLimits("arxiv", providers.RateLimitConfig{RequestsPerMinute: 1, BurstSize: 2}))
+
+		assert.Equal(t, 2, limiter.GetLimits("arxiv").Remaining)
+		assert.Zero(t, kv.Writes())
+
+		require.True(t, limiter.Allow(ctx, "arxiv"))
+		assert.Equal(t, 1, kv.Writes())
+
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, 1, limiter.GetLimits("arxiv").Remaining)
+		}
+		assert.Equal(t, 1, kv.Writes())
+	})
+
+	t.Run("rejects negative limits", func(t *testing.T) {
+		limiter := providers.NewTokenBucketLimiter(newTestLogger())
+		assert.Error(t, limiter.UpdateLimits("arxiv", providers.RateLimitConfig{RequestsPerMinute: -1}))
+	})
+}
+
+func TestManager_RateLimiting(t *testing.T) {
+	ctx := context.Background()
+
+	limiter := providers.NewTokenBucketLimiter(newTestLogger())
+	stub := mocks.NewStubSearchProvider("fast", testPaper("a", "A"))
+	stub.Capabilities.RateLimit = 60 * 20 // 20 requests per second
+
+	manager := newTestManager(providers.ManagerConfig{
+		AggregationStrategy: providers.StrategyFirst,
+		RateLimiter:         limiter,
+	}, stub)
+
+	assert.Equal(t, 1, limiter.GetLimits("fast").Limit)
+
+	for i := 0; i < 3; i++ {
+		_, err := manager.SearchAll(ctx, providers.NewSearchQuery("test"))
+		require.NoError(t, err)
+	}
+
+	metrics := manager.GetProviderMetrics()["fast"]
+	assert.Equal(t, int64(2), metrics.RateLimitHits)
+	assert.Equal(t, int64(2), metrics.RateLimitResets)
+	assert.Equal(t, 3, stub.Calls())
+}
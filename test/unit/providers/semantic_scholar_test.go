@@ -0,0 +1,69 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/providers/semantic_scholar"
+)
+
+func newSemanticScholarServer(t *testing.T, citationsStatus int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/paper/s2":
+			_, _ = w.Write([]byte(`{"paperId":"s2","title":"Attention","year":2017,"citationCount":2,"referenceCount":3,
+				"externalIds":{"DOI":"10.1000/attention"}}`))
+		case "/paper/s2/references":
+			assert.Equal(t, "paperId", r.URL.Query().Get("fields"))
+			_, _ = w.Write([]byte(`{"offset":0,"data":[{"citedPaper":{"paperId":"r1"}},{"citedPaper":{"paperId":null}},{"citedPaper":{"paperId":"r2"}}]}`))
+		case "/paper/s2/citations":
+			w.WriteHeader(citationsStatus)
+			_, _ = w.Write([]byte(`{"offset":0,"data":[{"citingPaper":{"paperId":"c1"},"intents":["background"]},{"citingPaper":{"paperId":"c2"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Paper not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSemanticScholarProvider_GetPaperCitations(t *testing.T) {
+	ctx := context.Background()
+	newProvider := func(baseURL string) *semantic_scholar.Provider {
+		return semantic_scholar.NewProvider(providers.ProviderConfig{
+			Enabled: true,
+			BaseURL: baseURL,
+			Timeout: 5 * time.Second,
+		}, newTestLogger())
+	}
+
+	t.Run("fetches references and citing papers", func(t *testing.T) {
+		provider := newProvider(newSemanticScholarServer(t, http.StatusOK).URL)
+
+		paper, err := provider.GetPaper(ctx, "s2")
+		require.NoError(t, err)
+		assert.Equal(t, "Attention", paper.Title)
+		assert.Equal(t, []string{"r1", "r2"}, []string(paper.References))
+		assert.Equal(t, []string{"c1", "c2"}, []string(paper.Citations))
+	})
+
+	t.Run("keeps the paper when citations fail", func(t *testing.T) {
+		provider := newProvider(newSemanticScholarServer(t, http.StatusBadRequest).URL)
+
+		paper, err := provider.GetPaper(ctx, "s2")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r1", "r2"}, []string(paper.References))
+		assert.Empty(t, paper.Citations)
+	})
+}
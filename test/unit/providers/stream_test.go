@@ -0,0 +1,120 @@
+package providers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/test/mocks"
+)
+
+// collectStream reads a stream until it is closed
+func collectStream(t *testing.T, events <-chan providers.StreamEvent) []providers.StreamEvent {
+	t.Helper()
+	var collected []providers.StreamEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return collected
+			}
+			collected = append(collected, event)
+		case <-timeout:
+			t.Fatal("stream was not closed")
+		}
+	}
+}
+
+func TestManager_SearchStream(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("emits providers as they finish with incremental dedup", func(t *testing.T) {
+		doi := "10.1/attention"
+		arxiv := mocks.NewStubSearchProvider("arxiv",
+			models.Paper{ID: "arxiv_1", Title: "Attention Is All You Need", DOI: &doi, SourceProvider: "arxiv", SourceID: "1"},
+			models.Paper{ID: "arxiv_2", Title: "Graph Attention Networks", SourceProvider: "arxiv", SourceID: "2"})
+		tavily := mocks.NewStubSearchProvider("tavily",
+			models.Paper{ID: "tavily_1", Title: "Attention is all you need", DOI: &doi, SourceProvider: "tavily", SourceID: "1"},
+			models.Paper{ID: "tavily_2", Title: "Sparse Attention", SourceProvider: "tavily", SourceID: "2"})
+		tavily.Delay = 100 * time.Millisecond
+		failing := mocks.NewStubSearchProvider("exa")
+		failing.Err = errors.NewNetworkError("offline", nil)
+
+		manager := newTestManager(providers.ManagerConfig{}, arxiv, tavily, failing)
+		events, err := manager.SearchStream(ctx, providers.NewSearchQuery("attention"), []string{"arxiv", "tavily", "exa"})
+		require.NoError(t, err)
+
+		collected := collectStream(t, events)
+		require.Len(t, collected, 4)
+
+		var arxivEvent, tavilyEvent, exaEvent providers.StreamEvent
+		for i, event := range collected[:3] {
+			assert.Equal(t, providers.StreamEventProvider, event.Type)
+			switch event.Provider {
+			case "arxiv":
+				arxivEvent = event
+			case "tavily":
+				tavilyEvent = event
+				assert.Equal(t, 2, i, "the slow provider finishes last")
+			case "exa":
+				exaEvent = event
+			}
+		}
+
+		assert.Len(t, arxivEvent.Papers, 2)
+		require.Len(t, tavilyEvent.Papers, 1)
+		assert.Equal(t, "tavily_2", tavilyEvent.Papers[0].ID)
+		assert.Equal(t, 1, tavilyEvent.Duplicates)
+		assert.Equal(t, "network", exaEvent.ErrorType)
+		assert.Empty(t, exaEvent.Papers)
+
+		summary := collected[3]
+		assert.Equal(t, providers.StreamEventSummary, summary.Type)
+		require.NotNil(t, summary.Summary)
+		assert.Equal(t, 3, summary.Summary.TotalCount)
+		assert.Equal(t, []string{"arxiv", "tavily"}, summary.Summary.SuccessfulProviders)
+		assert.Equal(t, []string{"exa"}, summary.Summary.FailedProviders)
+		assert.Contains(t, summary.Summary.Errors["exa"], "offline")
+		assert.True(t, summary.Summary.PartialFailure)
+	})
+
+	t.Run("reports providers that miss the timeout as failed", func(t *testing.T) {
+		fast := mocks.NewStubSearchProvider("arxiv", testPaper("a", "Fast"))
+		slow := mocks.NewStubSearchProvider("tavily", testPaper("b", "Slow"))
+		slow.Delay = time.Second
+
+		manager := newTestManager(providers.ManagerConfig{Timeout: 50 * time.Millisecond}, fast, slow)
+		events, err := manager.SearchStream(ctx, providers.NewSearchQuery("graphs"), nil)
+		require.NoError(t, err)
+
+		collected := collectStream(t, events)
+		require.Len(t, collected, 3)
+		assert.Equal(t, "arxiv", collected[0].Provider)
+		assert.Equal(t, "tavily", collected[1].Provider)
+		assert.Equal(t, "timeout", collected[1].ErrorType)
+
+		summary := collected[2].Summary
+		require.NotNil(t, summary)
+		assert.Equal(t, []string{"tavily"}, summary.FailedProviders)
+		assert.Equal(t, []string{"arxiv"}, summary.SuccessfulProviders)
+	})
+
+	t.Run("rejects page tokens and unknown providers", func(t *testing.T) {
+		manager := newTestManager(providers.ManagerConfig{}, mocks.NewStubSearchProvider("arxiv"))
+
+		query := providers.NewSearchQuery("graphs")
+		query.PageToken = "token"
+		_, err := manager.SearchStream(ctx, query, nil)
+		assert.True(t, errors.IsValidationError(err))
+
+		_, err = manager.SearchStream(ctx, providers.NewSearchQuery("graphs"), []string{"unknown"})
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
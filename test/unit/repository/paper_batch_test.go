@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+)
+
+func TestPaperRepository_UpsertMetadataBatch(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB, _ bool) {
+		ctx := context.Background()
+		repo := repository.NewPaperRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		// A harvested paper enriched since, e.g. by citation updates and embedding
+		enriched := storedPaper("arxiv_1706.03762", "Attention", "Old abstract")
+		fullText := "Full text of the paper"
+		enriched.FullText = &fullText
+		enriched.CitationCount = 90000
+		enriched.QualityScore = 0.95
+		enriched.Embedding = []float32{0.1, 0.2}
+		storePapers(t, repo, enriched)
+
+		journal := "NeurIPS 2017"
+		harvested := storedPaper("arxiv_1706.03762", "Attention Is All You Need", "The dominant sequence transduction models")
+		harvested.Journal = &journal
+		harvested.QualityScore = 0.3
+		require.NoError(t, repo.UpsertMetadataBatch(ctx, []models.Paper{
+			harvested,
+			storedPaper("arxiv_1810.04805", "BERT", "Pre-training of deep bidirectional transformers"),
+		}))
+
+		paper, err := repo.GetByID(ctx, "arxiv_1706.03762")
+		require.NoError(t, err)
+		assert.Equal(t, "Attention Is All You Need", paper.Title)
+		assert.Equal(t, "The dominant sequence transduction models", *paper.Abstract)
+		assert.Equal(t, "NeurIPS 2017", *paper.Journal)
+
+		// Data the harvest does not provide is kept
+		assert.Equal(t, 90000, paper.CitationCount)
+		assert.Equal(t, 0.95, paper.QualityScore)
+		assert.Equal(t, []float32{0.1, 0.2}, paper.Embedding)
+		require.NotNil(t, paper.FullText)
+		assert.Equal(t, fullText, *paper.FullText)
+
+		_, err = repo.GetByID(ctx, "arxiv_1810.04805")
+		require.NoError(t, err)
+
+		// CreateBatch does not overwrite stored papers
+		assert.Error(t, repo.CreateBatch(ctx, []models.Paper{harvested}))
+	})
+}
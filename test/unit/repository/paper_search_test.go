@@ -0,0 +1,190 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"gorm.io/gorm"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+	"scifind-backend/test/testutil"
+)
+
+// forEachDatabase runs the test against SQLite and, unless testing short or
+// Docker is unavailable, PostgreSQL. ranked reports whether the database
+// has a full-text index; SQLite needs the sqlite_fts5 build tag for it.
+func forEachDatabase(t *testing.T, test func(t *testing.T, db *gorm.DB, ranked bool)) {
+	t.Run("sqlite", func(t *testing.T) {
+		database := testutil.SetupTestDatabase(t, false)
+		defer database.Cleanup()
+
+		// Every connection to :memory: opens a new database
+		sqlDB, err := database.DB().DB()
+		require.NoError(t, err)
+		sqlDB.SetMaxOpenConns(1)
+
+		ranked := repository.CreatePaperSearchIndex(database.DB()) == nil
+		test(t, database.DB(), ranked)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("Skipping PostgreSQL test in short mode")
+		}
+		testcontainers.SkipIfProviderIsNotHealthy(t)
+
+		database := testutil.SetupTestDatabase(t, true)
+		defer database.Cleanup()
+		test(t, database.DB(), true)
+	})
+}
+
+func requireRanked(t *testing.T, ranked bool) {
+	if !ranked {
+		t.Skip("SQLite was built without FTS5, run with -tags sqlite_fts5")
+	}
+}
+
+func storePapers(t *testing.T, repo repository.PaperRepository, papers ...models.Paper) {
+	for i := range papers {
+		require.NoError(t, repo.Create(context.Background(), &papers[i]))
+	}
+}
+
+func storedPaper(id, title, abstract string) models.Paper {
+	return models.Paper{
+		ID:              id,
+		Title:           title,
+		Abstract:        &abstract,
+		Language:        "en",
+		SourceProvider:  "arxiv",
+		SourceID:        id,
+		ProcessingState: "completed",
+	}
+}
+
+func titles(papers []models.Paper) []string {
+	titles := make([]string, len(papers))
+	for i, paper := range papers {
+		titles[i] = paper.Title
+	}
+	return titles
+}
+
+func TestPaperRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	relevance := &models.PaperSort{Field: "relevance", Order: "desc"}
+
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB, ranked bool) {
+		repo := repository.NewPaperRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		attention := storedPaper("p1", "Attention Is All You Need", "The transformer relies entirely on attention, dispensing with recurrence.")
+		transformers := storedPaper("p2", "Transformer Models for Transformer Translation", "Transformer architectures improve translation.")
+		graphs := storedPaper("p3", "Graph Neural Networks", "Message passing on graphs.")
+		fullText := "We train the network with a transformer decoder."
+		graphs.FullText = &fullText
+		storePapers(t, repo, attention, transformers, graphs)
+
+		t.Run("matches every word of the query", func(t *testing.T) {
+			papers, total, err := repo.Search(ctx, "transformer attention", nil, nil, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total)
+			assert.Equal(t, []string{"Attention Is All You Need"}, titles(papers))
+		})
+
+		t.Run("applies filters", func(t *testing.T) {
+			papers, total, err := repo.Search(ctx, "transformer", &models.PaperFilter{Title: "attention"}, nil, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total)
+			assert.Equal(t, []string{"Attention Is All You Need"}, titles(papers))
+		})
+
+		t.Run("ranks by relevance and highlights matches", func(t *testing.T) {
+			requireRanked(t, ranked)
+
+			papers, total, err := repo.Search(ctx, "transformer", nil, relevance, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), total)
+			assert.Equal(t, []string{"Transformer Models for Transformer Translation", "Attention Is All You Need"}, titles(papers))
+			for _, paper := range papers {
+				assert.Contains(t, strings.ToLower(paper.Snippet), "<mark>transformer</mark>")
+			}
+			require.NotNil(t, papers[0].Score)
+			require.NotNil(t, papers[1].Score)
+			assert.Greater(t, *papers[0].Score, *papers[1].Score)
+		})
+
+		t.Run("stems words", func(t *testing.T) {
+			requireRanked(t, ranked)
+
+			papers, _, err := repo.Search(ctx, "network", nil, nil, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Graph Neural Networks"}, titles(papers))
+		})
+
+		t.Run("searches the full text only when asked", func(t *testing.T) {
+			papers, _, err := repo.Search(ctx, "decoder", nil, nil, 10, 0)
+			require.NoError(t, err)
+			assert.Empty(t, papers)
+
+			papers, total, err := repo.SearchFullText(ctx, "decoder", nil, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total)
+			assert.Equal(t, []string{"Graph Neural Networks"}, titles(papers))
+		})
+
+		t.Run("ignores query syntax", func(t *testing.T) {
+			_, _, err := repo.Search(ctx, `"attention" AND (NOT*`, nil, nil, 10, 0)
+			require.NoError(t, err)
+		})
+
+		t.Run("follows updates and deletes", func(t *testing.T) {
+			paper, err := repo.GetByID(ctx, "p3")
+			require.NoError(t, err)
+			paper.Title = "Graph Attention Networks"
+			require.NoError(t, repo.Update(ctx, paper))
+
+			papers, _, err := repo.Search(ctx, "graph attention", nil, nil, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Graph Attention Networks"}, titles(papers))
+
+			require.NoError(t, db.Unscoped().Delete(&models.Paper{}, "id = ?", "p3").Error)
+			papers, _, err = repo.Search(ctx, "graph", nil, nil, 10, 0)
+			require.NoError(t, err)
+			assert.Empty(t, papers)
+		})
+	})
+}
+
+func TestPaperRepository_SearchIndexCreatedLater(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.SetupTestDatabase(t, false)
+	defer database.Cleanup()
+
+	// Every connection to :memory: opens a new database
+	sqlDB, err := database.DB().DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	repo := repository.NewPaperRepository(database.DB(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	storePapers(t, repo, storedPaper("p1", "Attention Is All You Need", "The transformer relies entirely on attention."))
+
+	// Searched by LIKE until the index exists
+	papers, _, err := repo.Search(ctx, "transformer", nil, nil, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, papers, 1)
+	assert.Empty(t, papers[0].Snippet)
+
+	requireRanked(t, repository.CreatePaperSearchIndex(database.DB()) == nil)
+	papers, _, err = repo.Search(ctx, "transformer", nil, nil, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, papers, 1)
+	assert.Contains(t, strings.ToLower(papers[0].Snippet), "<mark>transformer</mark>")
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+)
+
+func TestSearchRepository_GetProvidersPerformance(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB, _ bool) {
+		ctx := context.Background()
+		repo := repository.NewSearchRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		now := time.Now()
+		for i, history := range []models.SearchHistory{
+			{Providers: []string{"arxiv", "exa"}, ResultCount: 10, Duration: 100},
+			{Providers: []string{"arxiv"}, ProvidersFailed: []string{"tavily"}, ResultCount: 4, Duration: 300},
+			{ProvidersFailed: []string{"arxiv", "tavily"}, Error: "all providers failed", Duration: 200},
+			{Providers: []string{"exa"}, ResultCount: 1, Duration: 50, RequestedAt: now.Add(-48 * time.Hour)},
+		} {
+			history.ID, history.Query = fmt.Sprintf("search-%d", i), "graphs"
+			if history.RequestedAt.IsZero() {
+				history.RequestedAt = now.Add(-time.Duration(i) * time.Minute)
+			}
+			require.NoError(t, repo.CreateSearchHistory(ctx, &history))
+		}
+
+		from, to := now.Add(-24*time.Hour), now.Add(time.Minute)
+		performance, err := repo.GetProvidersPerformance(ctx, from, to)
+		require.NoError(t, err)
+		require.Len(t, performance, 3)
+
+		arxiv := performance[0]
+		assert.Equal(t, "arxiv", arxiv.Provider)
+		assert.Equal(t, int64(3), arxiv.TotalRequests)
+		assert.InDelta(t, 2.0/3, arxiv.SuccessRate, 1e-9)
+		assert.InDelta(t, 200, arxiv.AvgResponseTime, 1e-9)
+		assert.Equal(t, int64(14), arxiv.TotalResults)
+
+		exa := performance[1]
+		assert.Equal(t, "exa", exa.Provider)
+		assert.Equal(t, int64(1), exa.TotalRequests)
+
+		tavily := performance[2]
+		assert.Equal(t, "tavily", tavily.Provider)
+		assert.Equal(t, int64(2), tavily.TotalRequests)
+		assert.Zero(t, tavily.SuccessRate)
+
+		// The single provider metrics agree
+		single, err := repo.GetProviderPerformance(ctx, "arxiv", from, to)
+		require.NoError(t, err)
+		assert.Equal(t, arxiv, *single)
+	})
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+)
+
+func TestSearchRepository_GetSearchSuggestions(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB, _ bool) {
+		ctx := context.Background()
+		repo := repository.NewSearchRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		// Queries that were popular two months ago outweigh a fresh query by
+		// their stored weight, but not once decayed to now
+		now := time.Now()
+		for i := 0; i < 4; i++ {
+			require.NoError(t, db.Create(&models.QuerySuggestion{
+				ID:             fmt.Sprintf("stale-%d", i),
+				Query:          fmt.Sprintf("graph theory %d", i),
+				Weight:         float64(8 + i),
+				SearchCount:    int64(8 + i),
+				LastSearchedAt: now.Add(-8 * 7 * 24 * time.Hour),
+			}).Error)
+		}
+		require.NoError(t, db.Create(&models.QuerySuggestion{
+			ID:             "fresh",
+			Query:          "graph databases",
+			Weight:         1,
+			SearchCount:    1,
+			LastSearchedAt: now.Add(-time.Hour),
+		}).Error)
+
+		suggestions, err := repo.GetSearchSuggestions(ctx, "graph", 1)
+		require.NoError(t, err)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, "graph databases", suggestions[0].Text)
+
+		suggestions, err = repo.GetSearchSuggestions(ctx, "graph", 3)
+		require.NoError(t, err)
+		require.Len(t, suggestions, 3)
+		assert.Equal(t, "graph databases", suggestions[0].Text)
+		assert.Equal(t, "graph theory 3", suggestions[1].Text)
+		assert.Greater(t, suggestions[0].Score, suggestions[1].Score)
+	})
+}
@@ -0,0 +1,102 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"scifind-backend/internal/embeddings"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+)
+
+func embed(t *testing.T, embedder embeddings.Embedder, text string) []float32 {
+	vectors, err := embedder.Embed(context.Background(), []string{text})
+	require.NoError(t, err)
+	return vectors[0]
+}
+
+func TestVectorSearch(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	embedder := embeddings.NewHashingEmbedder(64)
+
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB, _ bool) {
+		repo := repository.NewPaperRepository(db, logger)
+
+		graphs := storedPaper("p1", "Graph Neural Networks", "Message passing on graphs.")
+		graphs.Language = "de"
+		storePapers(t, repo,
+			graphs,
+			storedPaper("p2", "Graph Attention Networks", "Attention over graph neighbourhoods."),
+			storedPaper("p3", "Protein Folding", "Predicting protein structures."),
+			storedPaper("p4", "Unembedded", "Never embedded."))
+
+		pending, err := repo.GetPapersWithoutEmbedding(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, pending, 4)
+
+		for _, id := range []string{"p1", "p2", "p3"} {
+			paper, err := repo.GetByID(ctx, id)
+			require.NoError(t, err)
+			require.NoError(t, repo.UpdateEmbedding(ctx, id, embed(t, embedder, embeddings.PaperText(paper))))
+		}
+
+		pending, err = repo.GetPapersWithoutEmbedding(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Unembedded"}, titles(pending))
+		assert.Error(t, repo.UpdateEmbedding(ctx, "missing", embed(t, embedder, "graphs")))
+
+		query := embed(t, embedder, "graph networks")
+
+		t.Run("index finds the nearest papers", func(t *testing.T) {
+			index := repository.NewVectorIndex(db, embedder.Dimensions(), logger)
+
+			matches, err := index.Search(ctx, query, 2)
+			require.NoError(t, err)
+			require.Len(t, matches, 2)
+			assert.ElementsMatch(t, []string{"p1", "p2"}, []string{matches[0].PaperID, matches[1].PaperID})
+			assert.Greater(t, matches[0].Score, 0.5)
+
+			papers, err := repo.GetByVectorMatches(ctx, matches)
+			require.NoError(t, err)
+			assert.Equal(t, matches[0].PaperID, papers[0].ID)
+			require.NotNil(t, papers[0].Score)
+			assert.InDelta(t, matches[0].Score, *papers[0].Score, 1e-6)
+
+			require.NoError(t, index.Delete(ctx, matches[0].PaperID))
+			require.NoError(t, index.Upsert(ctx, "p3", query))
+			matches, err = index.Search(ctx, query, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "p3", matches[0].PaperID)
+			assert.InDelta(t, 1, matches[0].Score, 1e-6)
+		})
+
+		t.Run("search by vector applies filters", func(t *testing.T) {
+			papers, err := repo.SearchByVector(ctx, query, nil, 10)
+			require.NoError(t, err)
+			assert.Len(t, papers, 3)
+			assert.Equal(t, "Protein Folding", papers[2].Title)
+
+			papers, err = repo.SearchByVector(ctx, query, &models.PaperFilter{Language: "en"}, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Graph Attention Networks", "Protein Folding"}, titles(papers))
+		})
+
+		t.Run("embeddings of another length are ignored", func(t *testing.T) {
+			papers, err := repo.SearchByVector(ctx, []float32{1, 0}, nil, 10)
+			require.NoError(t, err)
+			assert.Empty(t, papers)
+
+			index := repository.NewVectorIndex(db, 2, logger)
+			matches, err := index.Search(ctx, []float32{1, 0}, 10)
+			require.NoError(t, err)
+			assert.Empty(t, matches)
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/repository"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+func TestSearchService_RecordsSearchHistory(t *testing.T) {
+	ctx := context.Background()
+	userID := "user-1"
+
+	t.Run("successful search", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		var history *models.SearchHistory
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("SetSearchCache", mock.Anything, mock.Anything).Return(nil)
+		repo.On("CreateSearchHistory", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			history = args.Get(1).(*models.SearchHistory)
+		}).Return(nil).Once()
+
+		stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+		service := newTestSearchService(repo, stub)
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "graphs", UserID: &userID})
+		require.NoError(t, err)
+
+		require.NotNil(t, history)
+		assert.Equal(t, "graphs", history.Query)
+		assert.Equal(t, &userID, history.UserID)
+		assert.Equal(t, 1, history.ResultCount)
+		assert.Equal(t, []string{"arxiv"}, history.Providers)
+		assert.True(t, history.Succeeded())
+		assert.False(t, history.CacheHit)
+	})
+
+	t.Run("failed search", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		var history *models.SearchHistory
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("CreateSearchHistory", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			history = args.Get(1).(*models.SearchHistory)
+		}).Return(nil).Once()
+
+		stub := mocks.NewStubSearchProvider("arxiv")
+		stub.Enabled = false
+		service := newTestSearchService(repo, stub)
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "graphs", Providers: []string{"arxiv"}})
+		require.Error(t, err)
+
+		require.NotNil(t, history)
+		assert.False(t, history.Succeeded())
+		assert.Equal(t, []string{"arxiv"}, history.ProvidersFailed)
+	})
+
+	t.Run("failed search of all providers", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		var history *models.SearchHistory
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("CreateSearchHistory", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			history = args.Get(1).(*models.SearchHistory)
+		}).Return(nil).Once()
+
+		logger := newTestLogger()
+		manager := providers.NewManager(logger, providers.ManagerConfig{AggregationStrategy: providers.StrategyFirst, Timeout: 5 * time.Second})
+		for _, name := range []string{"exa", "arxiv"} {
+			stub := mocks.NewStubSearchProvider(name)
+			stub.Err = assert.AnError
+			require.NoError(t, manager.RegisterProvider(name, stub))
+		}
+		service := services.NewSearchService(repo, &mocks.MockPaperRepository{}, nil, manager, logger)
+
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "graphs"})
+		require.Error(t, err)
+
+		require.NotNil(t, history)
+		assert.Empty(t, history.Providers)
+		assert.Equal(t, []string{"arxiv", "exa"}, history.ProvidersFailed)
+	})
+}
+
+func TestAnalyticsService(t *testing.T) {
+	ctx := context.Background()
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+
+	t.Run("search metrics", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		repo.On("GetSearchAnalytics", ctx, from, to).Return(&repository.SearchAnalytics{
+			TotalSearches:   4,
+			UniqueUsers:     2,
+			AvgResponseTime: 250,
+			ErrorRate:       0.25,
+			ProviderUsage:   []repository.ProviderUsageStats{{Provider: "arxiv", Usage: 3}},
+			SearchesByHour:  []repository.HourlySearchStats{{Hour: 9, Count: 4}},
+		}, nil)
+
+		service := services.NewAnalyticsService(repo, nil, newTestLogger())
+		metrics, err := service.GetSearchMetrics(ctx, from, to)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, metrics.TotalSearches)
+		assert.Equal(t, 2, metrics.UniqueUsers)
+		assert.Equal(t, 250*time.Millisecond, metrics.AverageResultTime)
+		assert.InDelta(t, 0.75, metrics.SuccessRate, 1e-9)
+		assert.Equal(t, map[string]int{"arxiv": 3}, metrics.PopularProviders)
+		assert.Equal(t, []services.HourlyMetric{{Hour: 9, Count: 4}}, metrics.SearchesByHour)
+	})
+
+	t.Run("popular queries", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		repo.On("GetPopularQueries", ctx, from, to, 5).Return([]repository.QueryStats{
+			{Query: "graphs", Count: 4, SuccessCount: 3, AvgResults: 12},
+		}, nil)
+
+		service := services.NewAnalyticsService(repo, nil, newTestLogger())
+		queries, err := service.GetPopularQueries(ctx, 5, from, to)
+		require.NoError(t, err)
+
+		require.Len(t, queries, 1)
+		assert.Equal(t, "graphs", queries[0].Query)
+		assert.InDelta(t, 0.75, queries[0].SuccessRate, 1e-9)
+		assert.Equal(t, 12.0, queries[0].AvgResults)
+	})
+
+	t.Run("provider performance", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		repo.On("GetProvidersPerformance", ctx, from, to).Return([]repository.ProviderPerformance{
+			{Provider: "arxiv", TotalRequests: 4, SuccessRate: 0.75, AvgResponseTime: 120},
+			{Provider: "exa", TotalRequests: 4, SuccessRate: 0.25},
+			{Provider: "tavily", TotalRequests: 2, SuccessRate: 0},
+		}, nil)
+
+		service := services.NewAnalyticsService(repo, nil, newTestLogger())
+		performance, err := service.GetProviderPerformance(ctx, from, to)
+		require.NoError(t, err)
+
+		require.Contains(t, performance, "arxiv")
+		assert.Equal(t, 4, performance["arxiv"].TotalRequests)
+		assert.Equal(t, 120*time.Millisecond, performance["arxiv"].AverageLatency)
+		assert.True(t, performance["arxiv"].IsHealthy)
+		assert.False(t, performance["exa"].IsHealthy)
+
+		// Providers that never succeeded are reported too
+		require.Contains(t, performance, "tavily")
+		assert.Equal(t, 1.0, performance["tavily"].ErrorRate)
+		assert.False(t, performance["tavily"].IsHealthy)
+		repo.AssertNumberOfCalls(t, "GetProvidersPerformance", 1)
+	})
+
+	t.Run("user activity", func(t *testing.T) {
+		userID := "user-1"
+
+		repo := &mocks.MockSearchRepository{}
+		repo.On("GetUserSearchStats", ctx, userID).Return(&repository.UserSearchStats{
+			UserID:        userID,
+			TotalQueries:  10,
+			UniqueQueries: 4,
+			TopQueries:    []repository.QueryStats{{Query: "graphs"}, {Query: "transformers"}},
+		}, nil)
+		repo.On("GetSearchHistory", ctx, &userID, mock.Anything, 0).Return([]models.SearchHistory{
+			{Query: "graphs", RequestedAt: to.Add(-time.Minute)},
+			{Query: "graphs", RequestedAt: to.Add(-2 * time.Minute)},
+			{Query: "old", RequestedAt: from.Add(-time.Hour)},
+		}, nil)
+
+		service := services.NewAnalyticsService(repo, nil, newTestLogger())
+		activity, err := service.GetUserActivity(ctx, userID, from, to)
+		require.NoError(t, err)
+
+		assert.Equal(t, 10, activity.SearchCount)
+		assert.Equal(t, []string{"graphs", "transformers"}, activity.FavoriteTopics)
+		require.NotEmpty(t, activity.ActivityByDay)
+		total := 0
+		for _, daily := range activity.ActivityByDay {
+			total += daily.SearchCount
+		}
+		assert.Equal(t, 2, total)
+	})
+
+	t.Run("rejects unknown event types", func(t *testing.T) {
+		service := services.NewAnalyticsService(&mocks.MockSearchRepository{}, nil, newTestLogger())
+		assert.Error(t, service.RecordEvent(ctx, &services.AnalyticsEvent{Type: "hover"}))
+		assert.NoError(t, service.RecordEvent(ctx, &services.AnalyticsEvent{Type: "click"}))
+	})
+}
@@ -0,0 +1,227 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/testutil"
+)
+
+func TestPaperService_CitationGraph(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.SetupTestDatabase(t, false)
+	defer database.Cleanup()
+
+	// Every connection to :memory: opens a new database
+	sqlDB, err := database.DB().DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	logger := newTestLogger()
+	repo := repository.NewPaperRepository(database.DB(), logger)
+	service := services.NewPaperService(repo, repository.NewCitationRepository(database.DB(), logger), nil, logger)
+
+	// a cites b, c by its DOI, d and an unknown paper; b cites c. c is stored
+	// last, and d lists a among the papers citing it.
+	doi := "10.1000/C"
+	for _, paper := range []models.Paper{
+		{ID: "a", Title: "A", References: []string{"b", "10.1000/c", "EXT-1"}},
+		{ID: "b", Title: "B", References: []string{"c"}},
+		{ID: "d", Title: "D", Citations: []string{"a"}},
+		{ID: "c", Title: "C", DOI: &doi},
+		{ID: "e", Title: "E"},
+	} {
+		paper.Language, paper.SourceProvider, paper.SourceID = "en", "semantic_scholar", paper.ID
+		require.NoError(t, repo.Create(ctx, &paper))
+	}
+
+	linkIDs := func(links []services.CitationLink) []string {
+		ids := make([]string, len(links))
+		for i, link := range links {
+			ids[i] = link.PaperID
+		}
+		return ids
+	}
+
+	t.Run("references resolve papers stored later and keep unknown ones", func(t *testing.T) {
+		page, err := service.GetReferencedPapers(ctx, "a", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), page.Total)
+		assert.Equal(t, []string{"b", "c", "d", "ext-1"}, linkIDs(page.Links))
+		assert.False(t, page.HasMore)
+
+		for _, link := range page.Links[:3] {
+			assert.True(t, link.Resolved)
+			require.NotNil(t, link.Paper)
+			assert.Equal(t, link.PaperID, link.Paper.ID)
+		}
+		unknown := page.Links[3]
+		assert.False(t, unknown.Resolved)
+		assert.Equal(t, "EXT-1", unknown.ExternalID)
+		assert.Nil(t, unknown.Paper)
+	})
+
+	t.Run("pages references", func(t *testing.T) {
+		page, err := service.GetReferencedPapers(ctx, "a", 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c"}, linkIDs(page.Links))
+		assert.True(t, page.HasMore)
+
+		page, err = service.GetReferencedPapers(ctx, "a", 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d", "ext-1"}, linkIDs(page.Links))
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("citing papers", func(t *testing.T) {
+		page, err := service.GetCitingPapers(ctx, "c", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+		assert.Equal(t, []string{"a", "b"}, linkIDs(page.Links))
+
+		page, err = service.GetCitingPapers(ctx, "d", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, linkIDs(page.Links))
+
+		_, err = service.GetCitingPapers(ctx, "missing", 10, 0)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("repository citations and references", func(t *testing.T) {
+		paperIDs := func(papers []models.Paper) []string {
+			ids := make([]string, len(papers))
+			for i, paper := range papers {
+				ids[i] = paper.ID
+			}
+			return ids
+		}
+
+		citing, err := repo.GetCitations(ctx, "c")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, paperIDs(citing))
+
+		cited, err := repo.GetReferences(ctx, "a")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"b", "c", "d"}, paperIDs(cited))
+
+		_, err = repo.GetReferences(ctx, "missing")
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("neighborhood", func(t *testing.T) {
+		distances := func(graph *services.CitationGraph) map[string]int {
+			byID := make(map[string]int)
+			for _, node := range graph.Nodes {
+				byID[node.PaperID] = node.Distance
+			}
+			return byID
+		}
+
+		graph, err := service.GetCitationNeighborhood(ctx, "d", 2, services.CitationDirectionBoth, 0)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"d": 0, "a": 1, "b": 2, "c": 2, "ext-1": 2}, distances(graph))
+		assert.Len(t, graph.Edges, 4) // b cites c, but neither is expanded
+		assert.False(t, graph.Truncated)
+		for _, node := range graph.Nodes {
+			assert.Equal(t, node.Resolved, node.Paper != nil, node.PaperID)
+		}
+
+		graph, err = service.GetCitationNeighborhood(ctx, "c", 3, services.CitationDirectionCitations, 0)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"c": 0, "a": 1, "b": 1}, distances(graph))
+
+		graph, err = service.GetCitationNeighborhood(ctx, "d", 3, services.CitationDirectionReferences, 0)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"d": 0}, distances(graph))
+		assert.Empty(t, graph.Edges)
+
+		graph, err = service.GetCitationNeighborhood(ctx, "a", 1, services.CitationDirectionBoth, 2)
+		require.NoError(t, err)
+		assert.Len(t, graph.Nodes, 2)
+		assert.Len(t, graph.Edges, 1)
+		assert.True(t, graph.Truncated)
+	})
+
+	t.Run("shortest path", func(t *testing.T) {
+		path, err := service.GetCitationPath(ctx, "d", "c", services.CitationDirectionBoth, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, path.Length)
+		require.Len(t, path.Papers, 3)
+		assert.Equal(t, []string{"d", "a", "c"}, []string{path.Papers[0].ID, path.Papers[1].ID, path.Papers[2].ID})
+		assert.Equal(t, [2]string{"a", "d"}, [2]string{path.Edges[0].CitingID, path.Edges[0].CitedID})
+		assert.Equal(t, [2]string{"a", "c"}, [2]string{path.Edges[1].CitingID, path.Edges[1].CitedID})
+
+		path, err = service.GetCitationPath(ctx, "a", "c", services.CitationDirectionReferences, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, path.Length)
+
+		_, err = service.GetCitationPath(ctx, "d", "c", services.CitationDirectionReferences, 0)
+		assert.ErrorContains(t, err, "not found")
+
+		_, err = service.GetCitationPath(ctx, "d", "c", services.CitationDirectionBoth, 1)
+		assert.ErrorContains(t, err, "not found")
+
+		_, err = service.GetCitationPath(ctx, "a", "e", services.CitationDirectionBoth, 0)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("deleted and updated papers leave the graph", func(t *testing.T) {
+		// x cites w and y, both citing z; z lists w among the papers citing it
+		for _, paper := range []models.Paper{
+			{ID: "x", Title: "X", References: []string{"w", "y"}},
+			{ID: "w", Title: "W", References: []string{"z"}},
+			{ID: "y", Title: "Y", References: []string{"z"}},
+			{ID: "z", Title: "Z", Citations: []string{"w"}},
+		} {
+			paper.Language, paper.SourceProvider, paper.SourceID = "en", "semantic_scholar", paper.ID
+			require.NoError(t, repo.Create(ctx, &paper))
+		}
+		require.NoError(t, repo.Delete(ctx, "w"))
+
+		page, err := service.GetCitingPapers(ctx, "z", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"y", "w"}, linkIDs(page.Links))
+		assert.False(t, page.Links[1].Resolved)
+		assert.Nil(t, page.Links[1].Paper)
+
+		page, err = service.GetReferencedPapers(ctx, "x", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"y", "w"}, linkIDs(page.Links))
+		assert.False(t, page.Links[1].Resolved)
+
+		path, err := service.GetCitationPath(ctx, "x", "z", services.CitationDirectionBoth, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"x", "y", "z"}, []string{path.Papers[0].ID, path.Papers[1].ID, path.Papers[2].ID})
+
+		// y no longer cites z
+		y, err := repo.GetByID(ctx, "y")
+		require.NoError(t, err)
+		y.References = nil
+		require.NoError(t, repo.Update(ctx, y))
+
+		page, err = service.GetReferencedPapers(ctx, "y", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, page.Links)
+
+		_, err = service.GetCitationPath(ctx, "x", "z", services.CitationDirectionBoth, 0)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("rejects invalid traversals", func(t *testing.T) {
+		_, err := service.GetCitationNeighborhood(ctx, "a", 1, "sideways", 0)
+		assert.True(t, errors.IsValidationError(err))
+
+		_, err = service.GetCitationNeighborhood(ctx, "a", services.MaxNeighborhoodDepth+1, services.CitationDirectionBoth, 0)
+		assert.True(t, errors.IsValidationError(err))
+
+		_, err = service.GetCitationPath(ctx, "a", "c", services.CitationDirectionBoth, services.MaxCitationPathDepth+1)
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/embeddings"
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+	"scifind-backend/test/testutil"
+)
+
+func TestHTTPEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "nomic-embed-text", req.Model)
+		batches = append(batches, req.Input)
+
+		// Answer in reverse order, embedding texts by the parity of their length
+		type data struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		var resp struct {
+			Data []data `json:"data"`
+		}
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			embedding := []float32{0, 2}
+			if len(req.Input[i])%2 == 0 {
+				embedding = []float32{2, 0}
+			}
+			resp.Data = append(resp.Data, data{Index: i, Embedding: embedding})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	embedder, err := embeddings.NewHTTPEmbedder(embeddings.HTTPConfig{
+		BaseURL:    server.URL + "/",
+		Model:      "nomic-embed-text",
+		APIKey:     "secret",
+		Dimensions: 2,
+		BatchSize:  2,
+	})
+	require.NoError(t, err)
+
+	t.Run("embeds in batches and keeps the order", func(t *testing.T) {
+		vectors, err := embedder.Embed(ctx, []string{"a", "bb", "ccc"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"a", "bb"}, {"ccc"}}, batches)
+		assert.Equal(t, [][]float32{{0, 1}, {1, 0}, {0, 1}}, vectors)
+	})
+
+	t.Run("rejects embeddings of another length", func(t *testing.T) {
+		wrong, err := embeddings.NewHTTPEmbedder(embeddings.HTTPConfig{BaseURL: server.URL, APIKey: "secret", Model: "nomic-embed-text", Dimensions: 3})
+		require.NoError(t, err)
+		_, err = wrong.Embed(ctx, []string{"a"})
+		assert.Error(t, err)
+	})
+
+	t.Run("requires a server", func(t *testing.T) {
+		_, err := embeddings.NewHTTPEmbedder(embeddings.HTTPConfig{Dimensions: 2})
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
+
+func TestEmbeddingService(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.SetupTestDatabase(t, false)
+	defer database.Cleanup()
+
+	// Every connection to :memory: opens a new database
+	sqlDB, err := database.DB().DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	logger := newTestLogger()
+	repo := repository.NewPaperRepository(database.DB(), logger)
+	for _, paper := range []models.Paper{
+		{ID: "p1", Title: "Graph Neural Networks", Keywords: []string{"graphs"}},
+		{ID: "p2", Title: "Protein Structure Prediction", Keywords: []string{"proteins"}},
+		{ID: "p3", Title: "Graph Attention Networks", Keywords: []string{"graphs", "attention"}},
+	} {
+		paper.Language, paper.SourceProvider, paper.SourceID = "en", "arxiv", paper.ID
+		require.NoError(t, repo.Create(ctx, &paper))
+	}
+
+	embedder := embeddings.NewHashingEmbedder(128)
+	index := repository.NewVectorIndex(database.DB(), embedder.Dimensions(), logger)
+	embeddingService := services.NewEmbeddingService(embedder, repo, index, 2, logger)
+
+	embedded, err := embeddingService.EmbedPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, embedded)
+
+	embedded, err = embeddingService.EmbedPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, embedded)
+
+	searchRepo := &mocks.MockSearchRepository{}
+	service := newTestSearchService(searchRepo)
+
+	t.Run("semantic mode needs embeddings", func(t *testing.T) {
+		_, err := service.Search(ctx, &services.SearchRequest{RequestID: "r1", Query: "graph networks", Mode: services.SearchModeSemantic})
+		assert.True(t, errors.IsValidationError(err))
+	})
+
+	service.EnableSemanticSearch(embeddingService)
+
+	t.Run("semantic mode ranks stored papers by similarity", func(t *testing.T) {
+		resp, err := service.Search(ctx, &services.SearchRequest{RequestID: "r2", Query: "graph networks", Limit: 2, Mode: services.SearchModeSemantic})
+		require.NoError(t, err)
+		require.Len(t, resp.Papers, 2)
+		assert.ElementsMatch(t, []string{"Graph Neural Networks", "Graph Attention Networks"}, []string{resp.Papers[0].Title, resp.Papers[1].Title})
+		assert.True(t, resp.HasMore)
+		assert.Equal(t, "semantic", resp.AggregationStrategy)
+		require.NotNil(t, resp.Papers[0].Score)
+		assert.GreaterOrEqual(t, *resp.Papers[0].Score, *resp.Papers[1].Score)
+
+		next, err := service.Search(ctx, &services.SearchRequest{RequestID: "r3", Query: "graph networks", Limit: 2, Offset: 2, Mode: services.SearchModeSemantic})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Protein Structure Prediction"}, []string{next.Papers[0].Title})
+		assert.False(t, next.HasMore)
+	})
+
+	t.Run("semantic mode applies filters", func(t *testing.T) {
+		require.NoError(t, database.DB().Model(&models.Paper{}).Where("id = ?", "p1").Update("journal", "Nature").Error)
+
+		resp, err := service.Search(ctx, &services.SearchRequest{
+			RequestID: "r4",
+			Query:     "graph networks",
+			Filters:   map[string]string{"journal": "Nature"},
+			Mode:      services.SearchModeSemantic,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Papers, 1)
+		assert.Equal(t, "Graph Neural Networks", resp.Papers[0].Title)
+	})
+
+	t.Run("semantic mode does not take providers", func(t *testing.T) {
+		_, err := service.Search(ctx, &services.SearchRequest{RequestID: "r5", Query: "graphs", Providers: []string{"arxiv"}, Mode: services.SearchModeSemantic})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers/arxiv"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+// fakeHarvester serves pages by resumption token, the first page under ""
+type fakeHarvester struct {
+	pages    map[string]*arxiv.HarvestPage
+	errs     map[string]error // returned once
+	requests []arxiv.HarvestRequest
+}
+
+func (h *fakeHarvester) ListRecords(ctx context.Context, req arxiv.HarvestRequest) (*arxiv.HarvestPage, error) {
+	h.requests = append(h.requests, req)
+	if err, ok := h.errs[req.ResumptionToken]; ok {
+		delete(h.errs, req.ResumptionToken)
+		return nil, err
+	}
+	if page, ok := h.pages[req.ResumptionToken]; ok {
+		return page, nil
+	}
+	return nil, errors.NewValidationError("unknown resumption token", "resumption_token", req.ResumptionToken)
+}
+
+// recordingPublisher records the names of the published indexing events
+type recordingPublisher struct {
+	events   []string
+	progress []int
+}
+
+func (p *recordingPublisher) PublishIndexingStarted(ctx context.Context, provider string, metadata map[string]interface{}) error {
+	p.events = append(p.events, "started")
+	return nil
+}
+
+func (p *recordingPublisher) PublishIndexingProgress(ctx context.Context, provider string, papersIndexed int, metadata map[string]interface{}) error {
+	p.events = append(p.events, "progress")
+	p.progress = append(p.progress, papersIndexed)
+	return nil
+}
+
+func (p *recordingPublisher) PublishIndexingCompleted(ctx context.Context, provider string, papersIndexed int, duration time.Duration, metadata map[string]interface{}) error {
+	p.events = append(p.events, "completed")
+	return nil
+}
+
+func (p *recordingPublisher) PublishIndexingFailed(ctx context.Context, provider string, err error, metadata map[string]interface{}) error {
+	p.events = append(p.events, "failed")
+	return nil
+}
+
+func harvestedPaper(arxivID string, doi string) models.Paper {
+	paper := models.Paper{ID: "arxiv_" + arxivID, ArxivID: &arxivID, Title: "Paper " + arxivID, SourceProvider: "arxiv", SourceID: arxivID}
+	if doi != "" {
+		paper.DOI = &doi
+	}
+	return paper
+}
+
+func newHarvestFixture() *fakeHarvester {
+	return &fakeHarvester{
+		pages: map[string]*arxiv.HarvestPage{
+			"": {
+				Papers:           []models.Paper{harvestedPaper("2405.00001", ""), harvestedPaper("2405.00002", "10.1000/taken")},
+				Deleted:          []string{"cs/0112017"},
+				ResumptionToken:  "token-1",
+				CompleteListSize: 3,
+			},
+			"token-1": {
+				Papers:           []models.Paper{harvestedPaper("2405.00003", "")},
+				Cursor:           2,
+				CompleteListSize: 3,
+			},
+		},
+		errs: map[string]error{},
+	}
+}
+
+// expectStore accepts every batch and reports the DOI 10.1000/taken as
+// stored by another provider
+func expectStore(repo *mocks.MockPaperRepository, stored *[]string) {
+	owner := models.Paper{ID: "crossref_10.1000/taken", DOI: ptr("10.1000/taken")}
+	repo.On("Search", mock.Anything, "", mock.MatchedBy(func(f *models.PaperFilter) bool { return len(f.DOIs) > 0 }), mock.Anything, mock.Anything, 0).
+		Return([]models.Paper{owner}, int64(1), nil)
+	repo.On("Search", mock.Anything, "", mock.Anything, mock.Anything, mock.Anything, 0).
+		Return([]models.Paper{}, int64(0), nil)
+	repo.On("UpsertMetadataBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, paper := range args.Get(1).([]models.Paper) {
+			*stored = append(*stored, paper.ID)
+		}
+	}).Return(nil)
+}
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestHarvestService_Harvest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("walks all pages and skips identifiers owned by other papers", func(t *testing.T) {
+		harvester := newHarvestFixture()
+		repo := &mocks.MockPaperRepository{}
+		var stored []string
+		expectStore(repo, &stored)
+		events := &recordingPublisher{}
+		checkpoints := services.NewHarvestCheckpointStore(nil, newTestLogger())
+		service := services.NewHarvestService(harvester, repo, checkpoints, events, newTestLogger())
+
+		day := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+		result, err := service.Harvest(ctx, services.HarvestRequest{Set: "cs", From: &day, Until: &day})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"arxiv_2405.00001", "arxiv_2405.00003"}, stored)
+		assert.Equal(t, 2, result.Harvested)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, 1, result.Deleted)
+		assert.Equal(t, 2, result.Pages)
+		assert.False(t, result.Resumed)
+		assert.Equal(t, []string{"started", "progress", "progress", "completed"}, events.events)
+		assert.Equal(t, []int{1, 2}, events.progress)
+
+		require.Len(t, harvester.requests, 2)
+		assert.Equal(t, "cs", harvester.requests[0].Set)
+		assert.Equal(t, "token-1", harvester.requests[1].ResumptionToken)
+
+		checkpoint, err := checkpoints.Load(ctx, "cs")
+		require.NoError(t, err)
+		assert.Empty(t, checkpoint.ResumptionToken)
+		assert.Equal(t, day, *checkpoint.HarvestedUntil)
+
+		// The next harvest continues where this one ended
+		_, err = service.Harvest(ctx, services.HarvestRequest{Set: "cs"})
+		require.NoError(t, err)
+		assert.Equal(t, day, *harvester.requests[2].From)
+	})
+
+	t.Run("resumes an interrupted harvest from its checkpoint", func(t *testing.T) {
+		harvester := newHarvestFixture()
+		harvester.errs["token-1"] = errors.NewNetworkError("connection reset", nil)
+		repo := &mocks.MockPaperRepository{}
+		var stored []string
+		expectStore(repo, &stored)
+		events := &recordingPublisher{}
+		checkpoints := services.NewHarvestCheckpointStore(nil, newTestLogger())
+		service := services.NewHarvestService(harvester, repo, checkpoints, events, newTestLogger())
+
+		_, err := service.Harvest(ctx, services.HarvestRequest{Set: "cs"})
+		require.Error(t, err)
+		assert.Equal(t, []string{"started", "progress", "failed"}, events.events)
+
+		checkpoint, err := checkpoints.Load(ctx, "cs")
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", checkpoint.ResumptionToken)
+		assert.Equal(t, 1, checkpoint.Harvested)
+
+		result, err := service.Harvest(ctx, services.HarvestRequest{Set: "cs"})
+		require.NoError(t, err)
+		assert.True(t, result.Resumed)
+		assert.Equal(t, 2, result.Harvested)
+		assert.Equal(t, 1, result.Pages)
+		assert.Equal(t, "token-1", harvester.requests[len(harvester.requests)-1].ResumptionToken)
+		assert.Equal(t, []string{"arxiv_2405.00001", "arxiv_2405.00003"}, stored)
+	})
+
+	t.Run("restarts the window when the resumption token expired", func(t *testing.T) {
+		harvester := newHarvestFixture()
+		repo := &mocks.MockPaperRepository{}
+		var stored []string
+		expectStore(repo, &stored)
+		checkpoints := services.NewHarvestCheckpointStore(nil, newTestLogger())
+		require.NoError(t, checkpoints.Save(ctx, &services.HarvestCheckpoint{Set: "cs", ResumptionToken: "expired", Harvested: 40}))
+		service := services.NewHarvestService(harvester, repo, checkpoints, nil, newTestLogger())
+
+		result, err := service.Harvest(ctx, services.HarvestRequest{Set: "cs"})
+		require.NoError(t, err)
+		assert.False(t, result.Resumed)
+		assert.Equal(t, 2, result.Harvested)
+		require.Len(t, harvester.requests, 3)
+		assert.Empty(t, harvester.requests[1].ResumptionToken)
+	})
+}
@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/repository"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+	"scifind-backend/test/testutil"
+)
+
+// fixedEmbedder embeds the texts it knows as given and others as zero
+type fixedEmbedder map[string][]float32
+
+func (e fixedEmbedder) Dimensions() int {
+	return 2
+}
+
+func (e fixedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		if vectors[i] = e[text]; vectors[i] == nil {
+			vectors[i] = make([]float32, 2)
+		}
+	}
+	return vectors, nil
+}
+
+func TestSearchService_HybridSearch(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.SetupTestDatabase(t, false)
+	defer database.Cleanup()
+
+	// Every connection to :memory: opens a new database
+	sqlDB, err := database.DB().DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	_ = repository.CreatePaperSearchIndex(database.DB())
+
+	logger := newTestLogger()
+	repo := repository.NewPaperRepository(database.DB(), logger)
+	for _, paper := range []models.Paper{
+		{ID: "a", Title: "Zeolite catalysis", QualityScore: 0.9},
+		{ID: "b", Title: "Porous catalysts"},
+		{ID: "c", Title: "Zeolite catalysis in porous catalysts", QualityScore: 0.5},
+		{ID: "d", Title: "Unrelated"},
+	} {
+		paper.Language, paper.SourceProvider, paper.SourceID = "en", "arxiv", paper.ID
+		require.NoError(t, repo.Create(ctx, &paper))
+	}
+
+	// Only a and c contain the query terms, b is nearest in meaning
+	embedder := fixedEmbedder{
+		"zeolite catalysis":                     {1, 0},
+		"Zeolite catalysis":                     {0, 1},
+		"Porous catalysts":                      {1, 0},
+		"Zeolite catalysis in porous catalysts": {1, 1},
+		"Unrelated":                             {-1, 0},
+	}
+	index := repository.NewVectorIndex(database.DB(), embedder.Dimensions(), logger)
+	embeddingService := services.NewEmbeddingService(embedder, repo, index, 10, logger)
+	_, err = embeddingService.EmbedPending(ctx)
+	require.NoError(t, err)
+
+	searchRepo := &mocks.MockSearchRepository{}
+	searchRepo.On("CreateSearchHistory", mock.Anything, mock.Anything).Return(nil).Maybe()
+	searchRepo.On("UpdateSearchSuggestions", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	manager := providers.NewManager(logger, providers.ManagerConfig{AggregationStrategy: providers.StrategyMerge})
+	service := services.NewSearchService(searchRepo, repo, nil, manager, logger).(*services.SearchService)
+	search := func(fusion services.FusionMethod, alpha float64) *services.SearchResponse {
+		resp, err := service.Search(ctx, &services.SearchRequest{
+			RequestID: "r",
+			Query:     "zeolite catalysis",
+			Mode:      services.SearchModeHybrid,
+			Fusion:    fusion,
+			Alpha:     &alpha,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	ids := func(papers []models.Paper) []string {
+		ids := make([]string, len(papers))
+		for i, paper := range papers {
+			ids[i] = paper.ID
+		}
+		return ids
+	}
+
+	t.Run("needs embeddings", func(t *testing.T) {
+		_, err := service.Search(ctx, &services.SearchRequest{RequestID: "r", Query: "zeolite", Mode: services.SearchModeHybrid})
+		assert.True(t, errors.IsValidationError(err))
+	})
+
+	service.EnableSemanticSearch(embeddingService)
+
+	t.Run("reciprocal rank fusion favours papers found by both", func(t *testing.T) {
+		resp := search("", 0.5)
+		assert.ElementsMatch(t, []string{"a", "c"}, ids(resp.Papers[:2]))
+		assert.Equal(t, []string{"b", "d"}, ids(resp.Papers[2:]))
+		assert.Equal(t, "rrf", string(resp.StrategyMetadata["fusion"].(services.FusionMethod)))
+
+		c := resp.Scores["c"]
+		assert.NotZero(t, c.LexicalRank)
+		assert.Equal(t, 2, c.VectorRank)
+		require.NotNil(t, c.VectorScore)
+		assert.InDelta(t, 0.707, *c.VectorScore, 0.001)
+		assert.InDelta(t, c.Lexical+c.Vector, c.Fused, 1e-9)
+
+		b := resp.Scores["b"]
+		assert.Zero(t, b.LexicalRank)
+		assert.Zero(t, b.Lexical)
+		assert.Equal(t, 1, b.VectorRank)
+	})
+
+	t.Run("alpha weighs the vector ranking", func(t *testing.T) {
+		resp := search(services.FusionWeighted, 1)
+		assert.Equal(t, []string{"b", "c", "a", "d"}, ids(resp.Papers))
+		assert.InDelta(t, 1, *resp.Papers[0].Score, 1e-9)
+
+		// Min-max normalization leaves the weakest text match at zero too
+		resp = search(services.FusionWeighted, 0)
+		assert.Equal(t, "a", resp.Papers[0].ID)
+		assert.NotZero(t, resp.Scores["c"].LexicalRank)
+		assert.Zero(t, resp.Scores["b"].Fused)
+	})
+
+	t.Run("pages through the fused ranking", func(t *testing.T) {
+		alpha := 1.0
+		resp, err := service.Search(ctx, &services.SearchRequest{
+			RequestID: "r",
+			Query:     "zeolite catalysis",
+			Limit:     2,
+			Offset:    1,
+			Mode:      services.SearchModeHybrid,
+			Fusion:    services.FusionWeighted,
+			Alpha:     &alpha,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c", "a"}, ids(resp.Papers))
+		assert.Len(t, resp.Scores, 2)
+		assert.Equal(t, 4, resp.TotalCount)
+		assert.True(t, resp.HasMore)
+	})
+
+	t.Run("rejects alpha out of range", func(t *testing.T) {
+		alpha := 1.5
+		_, err := service.Search(ctx, &services.SearchRequest{RequestID: "r", Query: "zeolite", Mode: services.SearchModeHybrid, Alpha: &alpha})
+		assert.Error(t, err)
+	})
+}
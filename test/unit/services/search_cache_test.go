@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestSearchService(searchRepo *mocks.MockSearchRepository, stubs ...*mocks.StubSearchProvider) *services.SearchService {
+	logger := newTestLogger()
+	manager := providers.NewManager(logger, providers.ManagerConfig{
+		AggregationStrategy: providers.StrategyMerge,
+		Timeout:             5 * time.Second,
+	})
+	for _, stub := range stubs {
+		_ = manager.RegisterProvider(stub.Name(), stub)
+	}
+	searchRepo.On("CreateSearchHistory", mock.Anything, mock.Anything).Return(nil).Maybe()
+	searchRepo.On("UpdateSearchSuggestions", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return services.NewSearchService(searchRepo, &mocks.MockPaperRepository{}, nil, manager, logger).(*services.SearchService)
+}
+
+func TestSearchService_DatabaseCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves repeated queries from the cache", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+		repo := &mocks.MockSearchRepository{}
+
+		var stored *models.SearchCache
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil).Once()
+		repo.On("SetSearchCache", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.SearchCache)
+		}).Return(nil).Once()
+
+		service := newTestSearchService(repo, stub)
+		first, err := service.Search(ctx, &services.SearchRequest{RequestID: "r1", Query: "Deep Learning"})
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.Empty(t, first.CacheTier)
+		assert.Len(t, stored.QueryHash, 64)
+		assert.Equal(t, "all", stored.Provider)
+		assert.True(t, stored.ExpiresAt.After(time.Now()))
+
+		repo.On("GetCachedSearch", mock.Anything, stored.QueryHash).Return(stored, nil).Once()
+		second, err := service.Search(ctx, &services.SearchRequest{RequestID: "r2", Query: "  deep learning "})
+		require.NoError(t, err)
+
+		assert.Equal(t, "r2", second.RequestID)
+		assert.Equal(t, "database", second.CacheTier)
+		assert.Equal(t, 1, second.CacheHits)
+		assert.Equal(t, first.ResultCount, second.ResultCount)
+		assert.Equal(t, 1, stub.Calls())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("bypass neither reads nor writes the cache", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+		repo := &mocks.MockSearchRepository{}
+
+		service := newTestSearchService(repo, stub)
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "test", CacheMode: services.CacheModeBypass})
+		require.NoError(t, err)
+
+		repo.AssertNotCalled(t, "GetCachedSearch", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "SetSearchCache", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refresh skips the lookup and stores fresh results", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+		repo := &mocks.MockSearchRepository{}
+		repo.On("SetSearchCache", mock.Anything, mock.Anything).Return(nil).Once()
+
+		service := newTestSearchService(repo, stub)
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "test", CacheMode: services.CacheModeRefresh})
+		require.NoError(t, err)
+
+		repo.AssertNotCalled(t, "GetCachedSearch", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+		assert.Equal(t, 1, stub.Calls())
+	})
+
+	t.Run("facet selections drill down into cached results", func(t *testing.T) {
+		stub := mocks.NewStubSearchProvider("arxiv",
+			models.Paper{ID: "a", Title: "A", Language: "en"},
+			models.Paper{ID: "b", Title: "B", Language: "fr"})
+		repo := &mocks.MockSearchRepository{}
+
+		var stored *models.SearchCache
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil).Once()
+		repo.On("SetSearchCache", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.SearchCache)
+		}).Return(nil).Once()
+
+		service := newTestSearchService(repo, stub)
+		first, err := service.Search(ctx, &services.SearchRequest{Query: "test"})
+		require.NoError(t, err)
+		require.NotNil(t, first.Facets)
+		assert.Equal(t, map[string]int{"en": 1, "fr": 1}, first.Facets.Languages)
+
+		repo.On("GetCachedSearch", mock.Anything, stored.QueryHash).Return(stored, nil).Once()
+		second, err := service.Search(ctx, &services.SearchRequest{
+			Query:        "test",
+			FacetFilters: map[string][]string{models.FacetLanguage: {"fr"}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, second.Papers, 1)
+		assert.Equal(t, "B", second.Papers[0].Title)
+		assert.Equal(t, 1, second.ResultCount)
+		assert.Equal(t, first.Facets.Languages, second.Facets.Languages)
+		assert.Equal(t, 1, stub.Calls())
+	})
+
+	t.Run("rejects unknown facets", func(t *testing.T) {
+		service := newTestSearchService(&mocks.MockSearchRepository{})
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "test", FacetFilters: map[string][]string{"color": {"red"}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects unknown cache modes", func(t *testing.T) {
+		service := newTestSearchService(&mocks.MockSearchRepository{})
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "test", CacheMode: "sometimes"})
+		assert.Error(t, err)
+	})
+}
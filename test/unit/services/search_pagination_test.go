@@ -0,0 +1,52 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+func TestSearchService_Cursor(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mocks.MockSearchRepository{}
+	var cachedKeys []string
+	repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("SetSearchCache", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		cachedKeys = append(cachedKeys, args.Get(1).(*models.SearchCache).QueryHash)
+	}).Return(nil)
+
+	stub := mocks.NewStubSearchProvider("arxiv",
+		models.Paper{ID: "a", Title: "Graph A"},
+		models.Paper{ID: "b", Title: "Graph B"},
+		models.Paper{ID: "c", Title: "Graph C"})
+	stub.Paged = true
+	service := newTestSearchService(repo, stub)
+
+	first, err := service.Search(ctx, &services.SearchRequest{Query: "graphs", Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, first.Papers, 2)
+	assert.True(t, first.HasMore)
+	require.NotNil(t, first.NextCursor)
+
+	second, err := service.Search(ctx, &services.SearchRequest{Query: "graphs", Limit: 2, Cursor: *first.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, second.Papers, 1)
+	assert.Equal(t, "c", second.Papers[0].ID)
+	assert.False(t, second.HasMore)
+	assert.Nil(t, second.NextCursor)
+
+	// Pages are cached separately
+	require.Len(t, cachedKeys, 2)
+	assert.NotEqual(t, cachedKeys[0], cachedKeys[1])
+
+	_, err = service.Search(ctx, &services.SearchRequest{Query: "graphs", Offset: 2, Cursor: *first.NextCursor})
+	assert.Error(t, err)
+}
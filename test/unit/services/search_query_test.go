@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/errors"
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+func TestSearchService_ParsesQueryGrammar(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes the parsed query to providers", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("SetSearchCache", mock.Anything, mock.Anything).Return(nil)
+
+		stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+		stub.Capabilities = providers.ProviderCapabilities{SupportsBooleanQuery: true, QueryFields: providers.QueryFields}
+		service := newTestSearchService(repo, stub)
+
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "author:hinton year:2017.."})
+		require.NoError(t, err)
+
+		require.Len(t, stub.Queries(), 1)
+		parsed := stub.Queries()[0].Parsed
+		require.NotNil(t, parsed)
+		assert.Equal(t, "author:hinton AND year:2017..", parsed.String())
+	})
+
+	t.Run("searches malformed free text as plain words", func(t *testing.T) {
+		for _, query := range []string{`"deep learning`, "(graphs OR trees", "graphs AND", "graphs) trees"} {
+			repo := &mocks.MockSearchRepository{}
+			repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+			repo.On("SetSearchCache", mock.Anything, mock.Anything).Return(nil)
+
+			stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+			service := newTestSearchService(repo, stub)
+
+			result, err := service.Search(ctx, &services.SearchRequest{Query: query})
+			require.NoError(t, err, query)
+			assert.Equal(t, 1, result.ResultCount, query)
+
+			require.Len(t, stub.Queries(), 1, query)
+			parsed := stub.Queries()[0].Parsed
+			require.NotNil(t, parsed, query)
+			_, plain := parsed.Conjuncts()
+			assert.True(t, plain, query)
+		}
+	})
+
+	t.Run("rejects malformed field syntax", func(t *testing.T) {
+		service := newTestSearchService(&mocks.MockSearchRepository{})
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "title:(graphs OR trees"})
+		require.Error(t, err)
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
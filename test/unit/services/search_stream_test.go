@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/providers"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+func TestSearchService_SearchStream(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mocks.MockSearchRepository{}
+	var history *models.SearchHistory
+	repo.On("CreateSearchHistory", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		history = args.Get(1).(*models.SearchHistory)
+	}).Return(nil).Once()
+
+	stub := mocks.NewStubSearchProvider("arxiv",
+		models.Paper{ID: "a", Title: "Graph A", Language: "en"},
+		models.Paper{ID: "b", Title: "Graph B", Language: "de"})
+	service := newTestSearchService(repo, stub)
+
+	events, err := service.SearchStream(ctx, &services.SearchRequest{
+		Query:        "graphs",
+		FacetFilters: map[string][]string{models.FacetLanguage: {"en"}},
+	})
+	require.NoError(t, err)
+
+	var collected []providers.StreamEvent
+	for event := range events {
+		collected = append(collected, event)
+	}
+	require.Len(t, collected, 2)
+
+	assert.Equal(t, providers.StreamEventProvider, collected[0].Type)
+	require.Len(t, collected[0].Papers, 1)
+	assert.Equal(t, "a", collected[0].Papers[0].ID)
+	assert.Equal(t, providers.StreamEventSummary, collected[1].Type)
+
+	// The search is recorded once the stream finished
+	require.NotNil(t, history)
+	assert.Equal(t, []string{"arxiv"}, history.Providers)
+	repo.AssertNotCalled(t, "GetCachedSearch", mock.Anything, mock.Anything)
+
+	_, err = service.SearchStream(ctx, &services.SearchRequest{Query: "graphs", Cursor: "token"})
+	assert.Error(t, err)
+}
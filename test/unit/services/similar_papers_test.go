@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/repository"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/testutil"
+)
+
+func TestPaperService_GetSimilar(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.SetupTestDatabase(t, false)
+	defer database.Cleanup()
+
+	// Every connection to :memory: opens a new database
+	sqlDB, err := database.DB().DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	logger := newTestLogger()
+	repo := repository.NewPaperRepository(database.DB(), logger)
+
+	alice := models.Author{ID: "alice", Name: "Alice"}
+	bob := models.Author{ID: "bob", Name: "Bob"}
+	carol := models.Author{ID: "carol", Name: "Carol"}
+	doi := "10.1000/Target"
+	for _, paper := range []models.Paper{
+		{ID: "target", DOI: &doi, Title: "Target", Authors: []models.Author{alice, bob}, References: []string{"r1", "r2", "r3"}},
+		{ID: "coupled", Title: "Coupled", Authors: []models.Author{carol}, References: []string{"R1", "r2", "r9"}},
+		{ID: "citer1", Title: "First citer", Authors: []models.Author{carol}, References: []string{"10.1000/target", "cocited"}},
+		{ID: "citer2", Title: "Second citer", Authors: []models.Author{carol}, References: []string{"target", "cocited"}},
+		{ID: "cocited", Title: "Co-cited", Authors: []models.Author{carol}},
+		{ID: "coauthored", Title: "Co-authored", Authors: []models.Author{alice, carol}},
+		{ID: "near", Title: "Near", Authors: []models.Author{carol}},
+		{ID: "far", Title: "Far", Authors: []models.Author{carol}},
+	} {
+		paper.Language, paper.SourceProvider, paper.SourceID = "en", "arxiv", paper.ID
+		require.NoError(t, repo.Create(ctx, &paper))
+	}
+	require.NoError(t, repo.UpdateEmbedding(ctx, "target", []float32{1, 0}))
+	require.NoError(t, repo.UpdateEmbedding(ctx, "near", []float32{1, 0.1}))
+	require.NoError(t, repo.UpdateEmbedding(ctx, "far", []float32{-1, 0}))
+
+	service := services.NewPaperService(repo, repository.NewCitationRepository(database.DB(), logger), nil, logger)
+
+	similar, err := service.GetSimilar(ctx, "target", 10)
+	require.NoError(t, err)
+
+	byID := make(map[string]services.SimilarPaper)
+	for _, paper := range similar {
+		byID[paper.Paper.ID] = paper
+	}
+
+	t.Run("recommends papers sharing any signal", func(t *testing.T) {
+		assert.Len(t, similar, 4)
+		assert.Contains(t, byID, "coupled")
+		assert.Contains(t, byID, "cocited")
+		assert.Contains(t, byID, "coauthored")
+		assert.Contains(t, byID, "near")
+		assert.NotContains(t, byID, "far")
+		assert.NotContains(t, byID, "target")
+		for i := 1; i < len(similar); i++ {
+			assert.GreaterOrEqual(t, similar[i-1].Score, similar[i].Score)
+		}
+	})
+
+	t.Run("explains each recommendation", func(t *testing.T) {
+		near := byID["near"]
+		assert.InDelta(t, 0.995, near.Signals.Embedding, 0.001)
+		assert.Equal(t, []string{"similar content (embedding cosine 1.00)"}, near.Reasons)
+
+		coupled := byID["coupled"]
+		assert.Equal(t, 2, coupled.Signals.SharedReferences)
+		assert.InDelta(t, 2.0/3, coupled.Signals.Coupling, 1e-9)
+		assert.Equal(t, []string{"shares 2 references with it"}, coupled.Reasons)
+
+		cocited := byID["cocited"]
+		assert.Equal(t, 2, cocited.Signals.CoCitations)
+		assert.Equal(t, []string{"cited together with it by 2 papers"}, cocited.Reasons)
+
+		coauthored := byID["coauthored"]
+		assert.Equal(t, []string{"Alice"}, coauthored.Signals.SharedAuthors)
+		assert.InDelta(t, 0.5, coauthored.Signals.AuthorOverlap, 1e-9)
+		assert.Equal(t, []string{"shares authors Alice"}, coauthored.Reasons)
+	})
+
+	t.Run("limits the recommendations", func(t *testing.T) {
+		top, err := service.GetSimilar(ctx, "target", 1)
+		require.NoError(t, err)
+		require.Len(t, top, 1)
+		assert.Equal(t, similar[0].Paper.ID, top[0].Paper.ID)
+	})
+
+	t.Run("unknown paper", func(t *testing.T) {
+		_, err := service.GetSimilar(ctx, "missing", 10)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("finds nearest embeddings through the vector index", func(t *testing.T) {
+		index := repository.NewVectorIndex(database.DB(), 2, logger)
+		require.NoError(t, index.Delete(ctx, "near"))
+		service.(*services.PaperService).EnableEmbeddingSimilarity(services.NewEmbeddingService(fixedEmbedder{}, repo, index, 10, logger))
+
+		similar, err := service.GetSimilar(ctx, "target", 10)
+		require.NoError(t, err)
+		ids := make([]string, len(similar))
+		for i, paper := range similar {
+			ids[i] = paper.Paper.ID
+		}
+		assert.ElementsMatch(t, []string{"coupled", "cocited", "coauthored"}, ids)
+	})
+}
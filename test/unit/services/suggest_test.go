@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"scifind-backend/internal/models"
+	"scifind-backend/internal/services"
+	"scifind-backend/test/mocks"
+)
+
+func TestSearchService_Suggest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns repository suggestions", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		expected := []models.SearchSuggestion{
+			{Text: "neural networks", Score: 1, Type: models.SuggestionTypeQuery},
+			{Text: "Yann LeCun", Score: 0.8, Type: models.SuggestionTypeAuthor},
+		}
+		repo.On("GetSearchSuggestions", ctx, "neur", 10).Return(expected, nil)
+
+		service := newTestSearchService(repo)
+		suggestions, err := service.Suggest(ctx, "  neur ", 0)
+		require.NoError(t, err)
+		assert.Equal(t, expected, suggestions)
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		service := newTestSearchService(&mocks.MockSearchRepository{})
+
+		_, err := service.Suggest(ctx, " ", 10)
+		assert.Error(t, err)
+		_, err = service.Suggest(ctx, "neur", 500)
+		assert.Error(t, err)
+	})
+
+	t.Run("completed searches update suggestion weights", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{}
+		repo.On("GetCachedSearch", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("SetSearchCache", mock.Anything, mock.Anything).Return(nil)
+		repo.On("UpdateSearchSuggestions", mock.Anything, "graph networks", 1).Return(nil).Once()
+
+		stub := mocks.NewStubSearchProvider("arxiv", models.Paper{ID: "a", Title: "A"})
+		service := newTestSearchService(repo, stub)
+		_, err := service.Search(ctx, &services.SearchRequest{Query: "graph networks"})
+		require.NoError(t, err)
+
+		repo.AssertExpectations(t)
+	})
+}